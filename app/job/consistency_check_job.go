@@ -0,0 +1,41 @@
+package job
+
+import (
+	"context"
+	"crud-app/app/service"
+	"database/sql"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ConsistencyCheckJob membandingkan MongoDB dan PostgreSQL secara berkala dan mencatat hasilnya
+// sebagai metrics. Repair hanya dijalankan jika diaktifkan (CONSISTENCY_REPAIR).
+type ConsistencyCheckJob struct {
+	consistencyService *service.ConsistencyService
+	repair             bool
+	sourceOfTruth      string
+}
+
+func NewConsistencyCheckJob(mongoDB *mongo.Database, db *sql.DB, repair bool, sourceOfTruth string) *ConsistencyCheckJob {
+	return &ConsistencyCheckJob{
+		consistencyService: service.NewConsistencyService(mongoDB, db),
+		repair:             repair,
+		sourceOfTruth:      sourceOfTruth,
+	}
+}
+
+// Run menjalankan satu kali pemeriksaan konsistensi
+func (j *ConsistencyCheckJob) Run(ctx context.Context) error {
+	report, err := j.consistencyService.Check(ctx, !j.repair, j.sourceOfTruth)
+	if err != nil {
+		return err
+	}
+	service.RecordConsistencyMetrics(report)
+
+	if len(report.Drifts) > 0 {
+		log.Printf("Consistency: %d drift ditemukan (%v), %d diperbaiki, %d dilewati, %d gagal diperbaiki",
+			len(report.Drifts), report.Counts, report.Repaired, report.Skipped, report.Unrepairable)
+	}
+	return nil
+}
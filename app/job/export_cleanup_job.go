@@ -0,0 +1,32 @@
+package job
+
+import (
+	"context"
+	"crud-app/app/service"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// ExportCleanupJob menghapus file export background yang sudah melewati masa berlaku
+type ExportCleanupJob struct {
+	exports *service.ExportService
+}
+
+func NewExportCleanupJob(db *sql.DB) *ExportCleanupJob {
+	return &ExportCleanupJob{
+		exports: service.NewExportService(db),
+	}
+}
+
+// Run menghapus file dan data job export yang kedaluwarsa
+func (j *ExportCleanupJob) Run(ctx context.Context) error {
+	purged, err := j.exports.PurgeExpired()
+	if err != nil {
+		return fmt.Errorf("gagal mengambil export yang kedaluwarsa: %w", err)
+	}
+	if purged > 0 {
+		log.Printf("Export: %d file export kedaluwarsa dihapus", purged)
+	}
+	return nil
+}
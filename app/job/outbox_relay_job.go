@@ -0,0 +1,36 @@
+package job
+
+import (
+	"context"
+	"crud-app/app/service"
+	"database/sql"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OutboxRelayJob menerapkan event outbox yang belum sampai ke MongoDB
+// (misalnya karena proses berhenti sebelum dispatch langsung selesai)
+type OutboxRelayJob struct {
+	relay     *service.OutboxRelay
+	batchSize int
+}
+
+func NewOutboxRelayJob(mongoDB *mongo.Database, db *sql.DB, batchSize int) *OutboxRelayJob {
+	return &OutboxRelayJob{
+		relay:     service.NewOutboxRelay(mongoDB, db),
+		batchSize: batchSize,
+	}
+}
+
+// Run menerapkan satu batch event pending lalu memperbarui metrics outbox
+func (j *OutboxRelayJob) Run(ctx context.Context) error {
+	applied, err := j.relay.RelayPending(ctx, j.batchSize)
+	if err != nil {
+		return err
+	}
+	if applied > 0 {
+		log.Printf("Outbox: %d event diterapkan ke MongoDB", applied)
+	}
+	return j.relay.RecordMetrics()
+}
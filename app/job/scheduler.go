@@ -0,0 +1,73 @@
+package job
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Task pekerjaan background yang dijalankan periodik
+type Task struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler menjalankan task background di dalam proses server
+type Scheduler struct {
+	tasks  []Task
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Register menambahkan task. Harus dipanggil sebelum Start.
+func (s *Scheduler) Register(name string, interval time.Duration, run func(ctx context.Context) error) {
+	s.tasks = append(s.tasks, Task{
+		Name:     name,
+		Interval: interval,
+		Run:      run,
+	})
+}
+
+// Start menjalankan semua task, masing-masing di goroutine sendiri
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	for _, task := range s.tasks {
+		s.wg.Add(1)
+		go s.loop(ctx, task)
+		log.Printf("Job '%s' dijadwalkan setiap %s", task.Name, task.Interval)
+	}
+}
+
+// Stop menghentikan semua task dan menunggu eksekusi yang sedang berjalan selesai
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, task Task) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(task.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := task.Run(ctx); err != nil {
+				log.Printf("Job '%s' gagal: %v", task.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package job
+
+import (
+	"context"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"crud-app/app/utils"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TrashPurgeJob menghapus permanen achievement dan user yang sudah berada di trash
+// melewati masa retensi, termasuk dokumen yang diupload
+type TrashPurgeJob struct {
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	commentRepo     *repository.CommentRepository
+	userRepo        *repository.UserRepository
+	retention       time.Duration
+}
+
+func NewTrashPurgeJob(mongoDB *mongo.Database, db *sql.DB) *TrashPurgeJob {
+	return &TrashPurgeJob{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(db),
+		commentRepo:     repository.NewCommentRepository(mongoDB),
+		userRepo:        repository.NewUserRepository(db),
+		retention:       service.TrashRetention(),
+	}
+}
+
+// Run menghapus permanen semua data di trash yang dihapus sebelum batas retensi
+func (j *TrashPurgeJob) Run(ctx context.Context) error {
+	cutoff := time.Now().Add(-j.retention)
+
+	achievements, err := j.achievementRepo.FindDeletedBefore(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil achievement di trash: %w", err)
+	}
+
+	for _, achievement := range achievements {
+		// PostgreSQL dihapus lebih dulu: jika MongoDB gagal, achievement tetap di trash
+		// dan akan dicoba lagi pada eksekusi berikutnya
+		if err := j.referenceRepo.Purge(achievement.AchievementID); err != nil {
+			log.Printf("Trash: gagal purge reference achievement %s: %v", achievement.AchievementID, err)
+			continue
+		}
+		if err := j.commentRepo.DeleteByAchievement(ctx, achievement.AchievementID); err != nil {
+			log.Printf("Trash: gagal purge komentar achievement %s: %v", achievement.AchievementID, err)
+			continue
+		}
+		if err := j.achievementRepo.Delete(ctx, achievement.AchievementID); err != nil {
+			log.Printf("Trash: gagal purge achievement %s: %v", achievement.AchievementID, err)
+			continue
+		}
+
+		for _, doc := range achievement.Documents {
+			if err := utils.DeleteFile(doc.Filepath); err != nil {
+				log.Printf("Trash: gagal menghapus file %s: %v", doc.Filepath, err)
+			}
+		}
+	}
+
+	userIDs, err := j.userRepo.FindDeletedIDsBefore(cutoff)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil user di trash: %w", err)
+	}
+
+	for _, userID := range userIDs {
+		if err := j.userRepo.Purge(userID); err != nil {
+			if err == repository.ErrStillReferenced {
+				log.Printf("Trash: user %s masih direferensikan data lain, tidak dihapus permanen", userID)
+				continue
+			}
+			log.Printf("Trash: gagal purge user %s: %v", userID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package job
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// VerificationSLAJob mengirim reminder ke verifikator tahap yang sedang berjalan lalu eskalasi ke
+// admin departemen untuk submission yang menunggu di satu tahap verifikasi melewati SLA
+type VerificationSLAJob struct {
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	approvalRepo    *repository.AchievementApprovalRepository
+	slaRepo         *repository.VerificationSLARepository
+	userRepo        *repository.UserRepository
+	lecturerRepo    *repository.LecturerRepository
+	delegationRepo  *repository.VerificationDelegationRepository
+	notifier        *service.NotificationService
+}
+
+func NewVerificationSLAJob(mongoDB *mongo.Database, db *sql.DB) *VerificationSLAJob {
+	return &VerificationSLAJob{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(db),
+		approvalRepo:    repository.NewAchievementApprovalRepository(db),
+		slaRepo:         repository.NewVerificationSLARepository(db),
+		userRepo:        repository.NewUserRepository(db),
+		lecturerRepo:    repository.NewLecturerRepository(db),
+		delegationRepo:  repository.NewVerificationDelegationRepository(db),
+		notifier:        service.NewNotificationService(db),
+	}
+}
+
+// Run memeriksa semua submission yang sudah menunggu di tahapnya melewati batas reminder terpendek
+func (j *VerificationSLAJob) Run(ctx context.Context) error {
+	rules, err := j.slaRepo.FindAllRules()
+	if err != nil {
+		return fmt.Errorf("gagal mengambil aturan SLA: %w", err)
+	}
+
+	minReminder := service.ResolveSLARule(rules, "").ReminderAfterHours
+	for _, rule := range rules {
+		if rule.ReminderAfterHours < minReminder {
+			minReminder = rule.ReminderAfterHours
+		}
+	}
+
+	now := time.Now()
+	references, err := j.referenceRepo.FindStagePendingBefore(now.Add(-time.Duration(minReminder) * time.Hour))
+	if err != nil {
+		return fmt.Errorf("gagal mengambil submission pending: %w", err)
+	}
+	if len(references) == 0 {
+		return nil
+	}
+
+	achievementIDs := make([]string, len(references))
+	for i, ref := range references {
+		achievementIDs[i] = ref.MongoAchievementID
+	}
+
+	achievements, err := j.achievementRepo.FindByAchievementIDs(ctx, achievementIDs)
+	if err != nil {
+		return fmt.Errorf("gagal mengambil detail achievements: %w", err)
+	}
+
+	achievementMap := make(map[string]models.Achievement, len(achievements))
+	for _, achievement := range achievements {
+		achievementMap[achievement.AchievementID] = achievement
+	}
+
+	for _, ref := range references {
+		achievement, ok := achievementMap[ref.MongoAchievementID]
+		if !ok {
+			continue
+		}
+		j.CheckStage(ref, achievement, service.ResolveSLARule(rules, achievement.Level), now)
+	}
+
+	return nil
+}
+
+// CheckStage mengirim reminder atau eskalasi untuk satu submission sesuai lama menunggu di tahapnya.
+// Masing-masing dikirim sekali per tahap.
+func (j *VerificationSLAJob) CheckStage(ref models.AchievementReferences, achievement models.Achievement, rule models.VerificationSLARule, now time.Time) {
+	if ref.StageEnteredAt == nil {
+		return
+	}
+
+	pendingHours := int(now.Sub(*ref.StageEnteredAt).Hours())
+	switch service.SLAAction(rule, pendingHours) {
+	case "escalation":
+		j.escalate(ref, achievement, pendingHours)
+	case "reminder":
+		j.remind(ref, achievement, pendingHours)
+	}
+}
+
+// remind mengirim reminder ke verifikator tahap yang sedang berjalan (sekali per tahap): dosen wali dan
+// dosen yang sedang menerima delegasinya untuk tahap dosen wali, selain itu semua user yang memegang
+// permission tahap tersebut
+func (j *VerificationSLAJob) remind(ref models.AchievementReferences, achievement models.Achievement, pendingHours int) {
+	sent, err := j.slaRepo.HasEscalation(ref.MongoAchievementID, "reminder", *ref.StageEnteredAt)
+	if err != nil || sent {
+		return
+	}
+
+	approvals, err := j.approvalRepo.FindByMongoID(ref.MongoAchievementID)
+	if err != nil {
+		log.Printf("SLA: gagal mengambil tahap verifikasi achievement %s: %v", ref.MongoAchievementID, err)
+		return
+	}
+	stage := service.PendingApprovalStage(approvals)
+	if stage == nil {
+		return
+	}
+
+	var recipients []string
+	if stage.Permission == service.DefaultVerificationStage.Permission {
+		advisorID, err := j.userRepo.FindAdvisorByStudentID(ref.StudentID.String())
+		if err != nil || advisorID == "" {
+			log.Printf("SLA: dosen wali untuk achievement %s tidak ditemukan", ref.MongoAchievementID)
+			return
+		}
+		recipients = []string{advisorID}
+
+		delegates, err := j.delegationRepo.FindActiveDelegateIDs(advisorID)
+		if err != nil {
+			log.Printf("SLA: gagal mengambil delegasi dosen wali untuk achievement %s: %v", ref.MongoAchievementID, err)
+		}
+		recipients = append(recipients, delegates...)
+	} else {
+		recipients, err = j.userRepo.FindActiveIDsByPermission(stage.Permission)
+		if err != nil {
+			log.Printf("SLA: gagal mengambil verifikator tahap '%s' untuk achievement %s: %v", stage.StageName, ref.MongoAchievementID, err)
+			return
+		}
+	}
+
+	message := fmt.Sprintf("Prestasi '%s' sudah menunggu verifikasi tahap '%s' selama %d jam", achievement.Title, stage.StageName, pendingHours)
+	for _, recipient := range recipients {
+		j.notifyAndRecord(ref.MongoAchievementID, "reminder", recipient, "Pengingat verifikasi prestasi", message, pendingHours)
+	}
+}
+
+// escalate mengirim eskalasi ke admin departemen dosen wali (sekali per tahap).
+// Jika departemen tidak punya admin, eskalasi dikirim ke semua admin.
+func (j *VerificationSLAJob) escalate(ref models.AchievementReferences, achievement models.Achievement, pendingHours int) {
+	sent, err := j.slaRepo.HasEscalation(ref.MongoAchievementID, "escalation", *ref.StageEnteredAt)
+	if err != nil || sent {
+		return
+	}
+
+	var recipients []string
+	if advisorID, err := j.userRepo.FindAdvisorByStudentID(ref.StudentID.String()); err == nil && advisorID != "" {
+		if lecturer, err := j.lecturerRepo.FindByUserID(advisorID); err == nil && lecturer != nil {
+			recipients, _ = j.userRepo.FindDepartmentAdminIDs(lecturer.Department)
+		}
+	}
+	if len(recipients) == 0 {
+		recipients, err = j.userRepo.FindActiveIDsByRole("1")
+		if err != nil {
+			log.Printf("SLA: gagal mengambil admin untuk eskalasi achievement %s: %v", ref.MongoAchievementID, err)
+			return
+		}
+	}
+
+	message := fmt.Sprintf("Prestasi '%s' belum diverifikasi setelah %d jam dan memerlukan tindak lanjut", achievement.Title, pendingHours)
+	for _, recipient := range recipients {
+		j.notifyAndRecord(ref.MongoAchievementID, "escalation", recipient, "Eskalasi verifikasi prestasi", message, pendingHours)
+	}
+}
+
+func (j *VerificationSLAJob) notifyAndRecord(achievementID, escalationType, userID, title, message string, pendingHours int) {
+	notifiedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		log.Printf("SLA: user ID '%s' tidak valid untuk %s achievement %s", userID, escalationType, achievementID)
+		return
+	}
+
+	if err := j.notifier.Notify(userID, "verification_"+escalationType, title, message, achievementID); err != nil {
+		log.Printf("SLA: gagal mengirim %s untuk achievement %s: %v", escalationType, achievementID, err)
+		return
+	}
+
+	escalation := &models.VerificationEscalation{
+		ID:                 uuid.New(),
+		MongoAchievementID: achievementID,
+		EscalationType:     escalationType,
+		NotifiedUserID:     notifiedUserID,
+		PendingHours:       pendingHours,
+		CreatedAt:          time.Now(),
+	}
+	if err := j.slaRepo.CreateEscalation(escalation); err != nil {
+		log.Printf("SLA: gagal mencatat %s untuk achievement %s: %v", escalationType, achievementID, err)
+	}
+}
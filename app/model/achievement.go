@@ -8,20 +8,25 @@ import (
 
 // Achievement model untuk MongoDB
 type Achievement struct {
-	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	AchievementID string             `bson:"achievement_id" json:"achievement_id"`
-	StudentID     string             `bson:"student_id" json:"student_id"`
-	Title         string             `bson:"title" json:"title"`
-	Category      string             `bson:"category" json:"category"`
-	Level         string             `bson:"level" json:"level"`
-	Date          time.Time          `bson:"date" json:"date"`
-	Description   string             `bson:"description" json:"description"`
-	Documents     []Document         `bson:"documents" json:"documents"`
-	Status        string             `bson:"status" json:"status"`
-	IsDeleted     bool               `bson:"is_deleted" json:"is_deleted"`
-	DeletedAt     *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
-	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
-	UpdatedAt     time.Time          `bson:"updated_at" json:"updated_at"`
+	ID            primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	AchievementID string                 `bson:"achievement_id" json:"achievement_id"`
+	StudentID     string                 `bson:"student_id" json:"student_id"`
+	Title         string                 `bson:"title" json:"title"`
+	Category      string                 `bson:"category" json:"category"`
+	Level         string                 `bson:"level" json:"level"`
+	Date          time.Time              `bson:"date" json:"date"`
+	Description   string                 `bson:"description" json:"description"`
+	Details       map[string]interface{} `bson:"details,omitempty" json:"details,omitempty"`
+	Documents     []Document             `bson:"documents" json:"documents"`
+	Members       []AchievementMember    `bson:"members,omitempty" json:"members,omitempty"`
+	Tags          []string               `bson:"tags,omitempty" json:"tags,omitempty"` // label bebas dari mahasiswa, ikut diindeks pencarian
+	Status        string                 `bson:"status" json:"status"`
+	Version       int                    `bson:"version" json:"version"`        // optimistic concurrency, dikirim sebagai ETag
+	OutboxSeq     int64                  `bson:"outbox_seq,omitempty" json:"-"` // ID event outbox terakhir yang diterapkan
+	IsDeleted     bool                   `bson:"is_deleted" json:"is_deleted"`
+	DeletedAt     *time.Time             `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	CreatedAt     time.Time              `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time              `bson:"updated_at" json:"updated_at"`
 }
 
 // Document model untuk file upload
@@ -30,6 +35,7 @@ type Document struct {
 	Filepath   string    `bson:"filepath" json:"filepath"`
 	Filesize   int64     `bson:"filesize" json:"filesize"`
 	Mimetype   string    `bson:"mimetype" json:"mimetype"`
+	Hash       string    `bson:"hash,omitempty" json:"hash,omitempty"` // SHA-256 isi file
 	UploadedAt time.Time `bson:"uploaded_at" json:"uploaded_at"`
 }
 
@@ -40,22 +46,30 @@ type SubmitAchievementRequest struct {
 	Level       string `json:"level" form:"level"`
 	Date        string `json:"date" form:"date"` // Format: YYYY-MM-DD
 	Description string `json:"description" form:"description"`
+	// Tags label bebas; di form-data dikirim sebagai field tags berulang
+	Tags []string `json:"tags" form:"tags"`
+	// Details objek terstruktur sesuai schema kategori; di form-data dikirim sebagai string JSON
+	Details map[string]interface{} `json:"details" form:"-"`
 }
 
 // AchievementResponse untuk response
 type AchievementResponse struct {
-	ID            string     `json:"id"`
-	AchievementID string     `json:"achievement_id"`
-	StudentID     string     `json:"student_id"`
-	Title         string     `json:"title"`
-	Category      string     `json:"category"`
-	Level         string     `json:"level"`
-	Date          time.Time  `json:"date"`
-	Description   string     `json:"description"`
-	Documents     []Document `json:"documents"`
-	Status        string     `json:"status"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID            string                 `json:"id"`
+	AchievementID string                 `json:"achievement_id"`
+	StudentID     string                 `json:"student_id"`
+	Title         string                 `json:"title"`
+	Category      string                 `json:"category"`
+	Level         string                 `json:"level"`
+	Date          time.Time              `json:"date"`
+	Description   string                 `json:"description"`
+	Details       map[string]interface{} `json:"details,omitempty"`
+	Documents     []Document             `json:"documents"`
+	Tags          []string               `json:"tags,omitempty"`
+	Status        string                 `json:"status"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	// DuplicateWarnings kemungkinan duplikat yang terdeteksi saat submit (hanya peringatan)
+	DuplicateWarnings []DuplicateMatch `json:"duplicate_warnings,omitempty"`
 }
 
 // PaginationMeta untuk metadata pagination
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DuplicateMatch kemungkinan duplikat dari sebuah achievement beserta alasannya
+type DuplicateMatch struct {
+	AchievementID string    `json:"achievement_id,omitempty"`
+	StudentID     string    `json:"student_id,omitempty"`
+	Title         string    `json:"title,omitempty"`
+	Status        string    `json:"status,omitempty"`
+	Score         float64   `json:"score"`
+	Reasons       []string  `json:"reasons"`
+	OtherStudent  bool      `json:"other_student"`
+	DetectedAt    time.Time `json:"detected_at,omitempty"`
+}
+
+// AchievementDuplicateFlag pasangan kemungkinan duplikat yang disimpan untuk verifikator
+type AchievementDuplicateFlag struct {
+	MongoAchievementID string    `json:"achievement_id"`
+	DuplicateOfID      string    `json:"duplicate_of_id"`
+	Score              float64   `json:"score"`
+	Reasons            []string  `json:"reasons"`
+	DetectedAt         time.Time `json:"detected_at"`
+}
+
+// DuplicateCluster sekelompok achievement yang saling terdeteksi sebagai kemungkinan duplikat
+type DuplicateCluster struct {
+	AchievementIDs []string                   `json:"achievement_ids"`
+	Achievements   []Achievement              `json:"achievements"`
+	Flags          []AchievementDuplicateFlag `json:"flags"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status job import achievement
+const (
+	ImportJobPending   = "pending"
+	ImportJobRunning   = "running"
+	ImportJobCompleted = "completed"
+	ImportJobFailed    = "failed"
+)
+
+// ImportRow satu baris spreadsheet import apa adanya (belum divalidasi)
+type ImportRow struct {
+	Line        int // nomor baris di file (header = baris 1)
+	NIM         string
+	Title       string
+	Category    string
+	Level       string
+	Date        string
+	Description string
+	Tags        string            // dipisah koma atau titik koma
+	Details     map[string]string // kolom "details.<field>"
+}
+
+// ImportSheet isi file import: baris data dan kolom yang tidak dikenali (diabaikan)
+type ImportSheet struct {
+	Rows           []ImportRow
+	IgnoredColumns []string
+}
+
+// ImportRowResult hasil validasi satu baris; Errors kosong berarti baris siap dibuat
+type ImportRowResult struct {
+	Line        int                    `json:"line"`
+	NIM         string                 `json:"nim"`
+	Title       string                 `json:"title"`
+	StudentID   string                 `json:"student_id,omitempty"` // user ID mahasiswa pemilik NIM
+	StudentName string                 `json:"student_name,omitempty"`
+	Category    string                 `json:"category,omitempty"` // code kanonik master data
+	Level       string                 `json:"level,omitempty"`    // code kanonik master data
+	Date        *time.Time             `json:"date,omitempty"`
+	Description string                 `json:"-"`
+	Tags        []string               `json:"tags,omitempty"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	Errors      []string               `json:"errors,omitempty"`
+}
+
+// ImportPreview hasil dry-run import
+type ImportPreview struct {
+	Filename       string            `json:"filename"`
+	IgnoredColumns []string          `json:"ignored_columns"`
+	TotalRows      int               `json:"total_rows"`
+	ValidRows      int               `json:"valid_rows"`
+	InvalidRows    int               `json:"invalid_rows"`
+	Rows           []ImportRowResult `json:"rows"`
+}
+
+// ImportRowError kesalahan satu baris yang tidak ikut dibuat saat import dijalankan
+type ImportRowError struct {
+	Line   int      `json:"line"`
+	NIM    string   `json:"nim"`
+	Errors []string `json:"errors"`
+}
+
+// AchievementImportJob job import achievement yang berjalan di background; progress bisa di-poll
+type AchievementImportJob struct {
+	ID            uuid.UUID        `json:"id"`
+	Filename      string           `json:"filename"`
+	Status        string           `json:"status"`
+	PreVerified   bool             `json:"pre_verified"`
+	TotalRows     int              `json:"total_rows"`
+	ProcessedRows int              `json:"processed_rows"`
+	CreatedCount  int              `json:"created_count"`
+	FailedCount   int              `json:"failed_count"`
+	Errors        []ImportRowError `json:"errors"`
+	CreatedBy     *uuid.UUID       `json:"created_by"`
+	CreatedAt     time.Time        `json:"created_at"`
+	StartedAt     *time.Time       `json:"started_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
+
+// AchievementTitleKey judul dan tanggal achievement milik seorang mahasiswa (untuk cek import ganda)
+type AchievementTitleKey struct {
+	StudentID string
+	Title     string
+	Date      *time.Time
+}
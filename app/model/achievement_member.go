@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// AchievementMember anggota tim pada achievement (prestasi tim).
+// Pemilik achievement tercatat sebagai leader dengan status confirmed.
+type AchievementMember struct {
+	StudentID   string     `bson:"student_id" json:"student_id"`
+	Role        string     `bson:"role" json:"role"`     // leader, member
+	Status      string     `bson:"status" json:"status"` // invited, confirmed, declined
+	InvitedAt   time.Time  `bson:"invited_at" json:"invited_at"`
+	RespondedAt *time.Time `bson:"responded_at,omitempty" json:"responded_at,omitempty"`
+}
+
+// TeamMemberRequest untuk request body undangan anggota tim
+type TeamMemberRequest struct {
+	StudentID string `json:"student_id"` // user ID mahasiswa
+	Role      string `json:"role"`
+}
+
+// TeamInvitationResponse untuk request body konfirmasi undangan anggota tim
+type TeamInvitationResponse struct {
+	Accept bool `json:"accept"`
+}
@@ -15,7 +15,34 @@ type AchievementReferences struct {
 	VerifiedAt         *time.Time `json:"verified_at"`
 	VerifiedBy         *uuid.UUID `json:"verified_by"`
 	RejectionNote      *string    `json:"rejection_note"`
+	CurrentStage       int        `json:"current_stage"`
+	TotalStages        int        `json:"total_stages"`
+	StageEnteredAt     *time.Time `json:"stage_entered_at"` // waktu masuk tahap verifikasi saat ini
 	DeletedAt          *time.Time `json:"deleted_at"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// AchievementListFilter filter listing semua achievement (admin). Semua field tersedia di
+// achievement_read_model sehingga filter lintas store cukup dijalankan dengan satu query.
+type AchievementListFilter struct {
+	Status        string
+	StudentID     string
+	Category      string
+	Level         string
+	DateFrom      *time.Time // tanggal prestasi, inklusif
+	DateTo        *time.Time // tanggal prestasi, eksklusif
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+	VerifiedFrom  *time.Time
+	VerifiedTo    *time.Time
+	VerifiedBy    string
+	AdvisorID     string
+	ProgramStudy  string
+	AcademicYear  string
+	SortBy        string
+	SortOrder     string
+
+	// AchievementIDs membatasi hasil ke achievement tertentu; nil berarti tanpa batasan
+	AchievementIDs []string
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementRevocation catatan pencabutan achievement yang sudah diverifikasi
+type AchievementRevocation struct {
+	ID                 uuid.UUID `json:"id"`
+	MongoAchievementID string    `json:"mongo_achievement_id"`
+	PreviousStatus     string    `json:"previous_status"`
+	Reason             string    `json:"reason"`
+	RevokedBy          uuid.UUID `json:"revoked_by"`
+	RevokedAt          time.Time `json:"revoked_at"`
+}
+
+// RevokeRequest untuk request body pencabutan achievement
+type RevokeRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AchievementCategorySchema JSON Schema untuk field details achievement per kategori
+type AchievementCategorySchema struct {
+	Category  string          `json:"category"`
+	Schema    json.RawMessage `json:"schema" swaggertype:"object"`
+	UpdatedBy *uuid.UUID      `json:"updated_by"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,29 @@
+package models
+
+// AchievementSearchHit satu hasil pencarian full-text beserta skor relevansi dan potongan teks yang cocok
+type AchievementSearchHit struct {
+	Achievement Achievement       `json:"achievement"`
+	Score       float64           `json:"score"`
+	Highlights  map[string]string `json:"highlights,omitempty"` // title, description, tags dengan kata yang cocok ditandai <mark>
+}
+
+// FacetCount jumlah hasil pencarian untuk satu nilai facet
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets ringkasan hasil pencarian per category, level, status dan tahun
+type SearchFacets struct {
+	Category []FacetCount `json:"category"`
+	Level    []FacetCount `json:"level"`
+	Status   []FacetCount `json:"status"`
+	Year     []FacetCount `json:"year"`
+}
+
+// AchievementSearchResult hasil pencarian satu halaman
+type AchievementSearchResult struct {
+	Hits   []AchievementSearchHit
+	Total  int64
+	Facets SearchFacets
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// AchievementComment komentar pada achievement (MongoDB), bisa berupa balasan (ParentID)
+// dan bisa ditautkan ke dokumen tertentu (DocumentFilepath)
+type AchievementComment struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	CommentID        string             `bson:"comment_id" json:"comment_id"`
+	AchievementID    string             `bson:"achievement_id" json:"achievement_id"`
+	ParentID         *string            `bson:"parent_id,omitempty" json:"parent_id"`
+	AuthorID         string             `bson:"author_id" json:"author_id"`
+	DocumentFilepath *string            `bson:"document_filepath,omitempty" json:"document_filepath"`
+	Content          string             `bson:"content" json:"content"`
+	Mentions         []string           `bson:"mentions" json:"mentions"`
+	IsDeleted        bool               `bson:"is_deleted" json:"is_deleted"`
+	EditedAt         *time.Time         `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+	DeletedAt        *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// CommentRequest untuk request body create/update komentar
+type CommentRequest struct {
+	Content          string `json:"content"`
+	ParentID         string `json:"parent_id"`
+	DocumentFilepath string `json:"document_filepath"`
+}
+
+// CommentThread komentar beserta balasannya
+type CommentThread struct {
+	AchievementComment
+	Replies []CommentThread `json:"replies"`
+}
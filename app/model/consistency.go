@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Kategori drift antara MongoDB dan PostgreSQL
+const (
+	DriftMissingReference = "missing_reference" // dokumen MongoDB tanpa baris achievement_references
+	DriftOrphanReference  = "orphan_reference"  // reference aktif yang dokumen MongoDB-nya tidak ada
+	DriftStatusMismatch   = "status_mismatch"   // status berbeda di kedua store
+	DriftDeletedMismatch  = "deleted_mismatch"  // hanya salah satu store yang menandai terhapus
+)
+
+// AchievementState ringkasan status satu achievement di salah satu store
+type AchievementState struct {
+	AchievementID string
+	StudentID     string
+	Status        string
+	IsDeleted     bool
+}
+
+// ConsistencyDrift satu perbedaan yang ditemukan consistency checker
+type ConsistencyDrift struct {
+	AchievementID   string `json:"achievement_id"`
+	Category        string `json:"category"`
+	MongoStatus     string `json:"mongo_status,omitempty"`
+	PostgresStatus  string `json:"postgres_status,omitempty"`
+	MongoDeleted    bool   `json:"mongo_deleted"`
+	PostgresDeleted bool   `json:"postgres_deleted"`
+	Repaired        bool   `json:"repaired"`
+	Skipped         bool   `json:"skipped,omitempty"` // berubah selama pemeriksaan, tidak diperbaiki
+	RepairError     string `json:"repair_error,omitempty"`
+}
+
+// ConsistencyReport hasil satu kali pemeriksaan konsistensi
+type ConsistencyReport struct {
+	DryRun          bool               `json:"dry_run"`
+	SourceOfTruth   string             `json:"source_of_truth"`
+	ScannedMongo    int                `json:"scanned_mongo"`
+	ScannedPostgres int                `json:"scanned_postgres"`
+	PendingOutbox   int                `json:"pending_outbox"` // dilewati karena perubahan masih menunggu relay
+	Counts          map[string]int     `json:"counts"`
+	Repaired        int                `json:"repaired"`
+	Skipped         int                `json:"skipped"` // drift yang berubah selama pemeriksaan
+	Unrepairable    int                `json:"unrepairable"`
+	Drifts          []ConsistencyDrift `json:"drifts"`
+	CheckedAt       time.Time          `json:"checked_at"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreditRuleVersion satu versi aturan poin prestasi; hanya satu versi yang aktif
+type CreditRuleVersion struct {
+	Version     int               `json:"version"`
+	Note        *string           `json:"note"`
+	IsActive    bool              `json:"is_active"`
+	CreatedBy   *uuid.UUID        `json:"created_by"`
+	CreatedAt   time.Time         `json:"created_at"`
+	ActivatedAt *time.Time        `json:"activated_at"`
+	Rules       []CreditPointRule `json:"rules,omitempty"`
+}
+
+// CreditPointRule aturan poin berdasarkan kategori, level dan peringkat ('*' = semua)
+type CreditPointRule struct {
+	ID       uuid.UUID `json:"id"`
+	Version  int       `json:"version"`
+	Category string    `json:"category"`
+	Level    string    `json:"level"`
+	Rank     string    `json:"rank"`
+	Points   float64   `json:"points"`
+}
+
+// AchievementCreditPoint poin yang diberikan ke seorang mahasiswa untuk sebuah achievement
+type AchievementCreditPoint struct {
+	MongoAchievementID string     `json:"achievement_id"`
+	StudentID          string     `json:"student_id"`
+	Points             float64    `json:"points"`
+	RuleVersion        int        `json:"rule_version"`
+	RuleID             *uuid.UUID `json:"rule_id"`
+	ComputedAt         time.Time  `json:"computed_at"`
+}
+
+// StudentPointTotal total poin seorang mahasiswa
+type StudentPointTotal struct {
+	StudentID        string  `json:"student_id"`
+	StudentNumber    string  `json:"student_number"`
+	StudentName      string  `json:"student_name"`
+	ProgramStudy     string  `json:"program_study"`
+	AcademicYear     string  `json:"academic_year"`
+	TotalPoints      float64 `json:"total_points"`
+	AchievementCount int     `json:"achievement_count"`
+}
+
+// CreditRuleVersionRequest untuk membuat versi aturan poin baru
+type CreditRuleVersionRequest struct {
+	Note  string                   `json:"note"`
+	Rules []CreditPointRuleRequest `json:"rules"`
+}
+
+// CreditPointRuleRequest satu aturan dalam CreditRuleVersionRequest
+type CreditPointRuleRequest struct {
+	Category string  `json:"category"`
+	Level    string  `json:"level"`
+	Rank     string  `json:"rank"`
+	Points   float64 `json:"points"`
+}
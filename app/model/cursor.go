@@ -0,0 +1,17 @@
+package models
+
+// PageCursor posisi pada listing dengan pagination keyset. Dikirim ke client sebagai
+// string opaque (lihat utils.EncodeCursor); isinya tidak boleh diandalkan client.
+type PageCursor struct {
+	Sort     string `json:"s"`           // identitas urutan listing; cursor ditolak jika urutan berbeda
+	Key      string `json:"k"`           // nilai kolom sort (representasi teks PostgreSQL)
+	ID       string `json:"i"`           // ID baris sebagai pemecah seri
+	Backward bool   `json:"b,omitempty"` // true untuk halaman sebelumnya
+}
+
+// CursorPaginationMeta metadata pagination mode cursor; cursor null jika tidak ada halaman ke arah tersebut
+type CursorPaginationMeta struct {
+	Limit      int     `json:"limit"`
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Format file export
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// Status job export background
+const (
+	ExportJobPending   = "pending"
+	ExportJobRunning   = "running"
+	ExportJobCompleted = "completed"
+	ExportJobFailed    = "failed"
+)
+
+// ExportJob export besar yang dibuat di background; file bisa diunduh pemiliknya sampai ExpiresAt
+type ExportJob struct {
+	ID          uuid.UUID  `json:"id"`
+	UserID      uuid.UUID  `json:"user_id"`
+	Kind        string     `json:"kind"` // achievements, students, advisees, statistics
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Filename    string     `json:"filename"`
+	FilePath    string     `json:"-"`
+	RowCount    int        `json:"row_count"`
+	FileSize    int64      `json:"file_size"`
+	Error       *string    `json:"error,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"` // diisi jika status completed
+	CreatedAt   time.Time  `json:"created_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+}
+
+// AchievementExportRow satu baris export achievement dari read model
+type AchievementExportRow struct {
+	AchievementID   string
+	StudentNumber   string
+	StudentName     string
+	ProgramStudy    string
+	AcademicYear    string
+	Title           string
+	Category        string
+	Level           string
+	AchievementDate *time.Time
+	Status          string
+	SubmittedAt     *time.Time
+	VerifiedAt      *time.Time
+	Tags            []string
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// AchievementCategory master data kategori achievement (bisa bertingkat lewat ParentCode)
+type AchievementCategory struct {
+	Code       string    `json:"code"`
+	ParentCode *string   `json:"parent_code"`
+	LabelID    string    `json:"label_id"`
+	LabelEN    string    `json:"label_en"`
+	Aliases    []string  `json:"aliases"`
+	SortOrder  int       `json:"sort_order"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AchievementCategoryTree kategori beserta sub-kategorinya
+type AchievementCategoryTree struct {
+	AchievementCategory
+	Children []AchievementCategoryTree `json:"children"`
+}
+
+// AchievementLevel master data level achievement, diurutkan berdasarkan Rank (1 = terendah)
+type AchievementLevel struct {
+	Code      string    `json:"code"`
+	Rank      int       `json:"rank"`
+	LabelID   string    `json:"label_id"`
+	LabelEN   string    `json:"label_en"`
+	Aliases   []string  `json:"aliases"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AchievementCategoryRequest untuk request body create/update kategori
+type AchievementCategoryRequest struct {
+	Code       string   `json:"code"`
+	ParentCode *string  `json:"parent_code"`
+	LabelID    string   `json:"label_id"`
+	LabelEN    string   `json:"label_en"`
+	Aliases    []string `json:"aliases"`
+	SortOrder  int      `json:"sort_order"`
+	IsActive   *bool    `json:"is_active"`
+}
+
+// AchievementLevelRequest untuk request body create/update level
+type AchievementLevelRequest struct {
+	Code     string   `json:"code"`
+	Rank     int      `json:"rank"`
+	LabelID  string   `json:"label_id"`
+	LabelEN  string   `json:"label_en"`
+	Aliases  []string `json:"aliases"`
+	IsActive *bool    `json:"is_active"`
+}
+
+// MasterDataNormalizationReport hasil normalisasi kategori/level achievement lama ke code kanonik
+type MasterDataNormalizationReport struct {
+	DryRun              bool           `json:"dry_run"`
+	Scanned             int            `json:"scanned"`
+	Updated             int            `json:"updated"`
+	AlreadyCanonical    int            `json:"already_canonical"`
+	UnmatchedCategories map[string]int `json:"unmatched_categories"`
+	UnmatchedLevels     map[string]int `json:"unmatched_levels"`
+}
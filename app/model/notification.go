@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification pemberitahuan in-app untuk user
+type Notification struct {
+	ID                 uuid.UUID `json:"id"`
+	UserID             uuid.UUID `json:"user_id"`
+	Type               string    `json:"type"`
+	Title              string    `json:"title"`
+	Message            string    `json:"message"`
+	MongoAchievementID *string   `json:"mongo_achievement_id"`
+	IsRead             bool      `json:"is_read"`
+	CreatedAt          time.Time `json:"created_at"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Jenis event outbox achievement yang diterapkan relay ke MongoDB
+const (
+	OutboxAchievementCreated  = "achievement.created"
+	OutboxAchievementStatus   = "achievement.status_changed"
+	OutboxAchievementDeleted  = "achievement.deleted"
+	OutboxAchievementRestored = "achievement.restored"
+	OutboxAchievementStage    = "achievement.stage_advanced"
+)
+
+// OutboxEvent perubahan achievement yang dicatat di PostgreSQL dalam transaksi yang sama
+// dengan perubahan reference, lalu diterapkan ke MongoDB oleh relay. FailedAt terisi jika event
+// di-park setelah gagal sebanyak batas percobaan.
+type OutboxEvent struct {
+	ID                 int64           `json:"id"`
+	MongoAchievementID string          `json:"achievement_id"`
+	EventType          string          `json:"event_type"`
+	Payload            json.RawMessage `json:"payload"`
+	Attempts           int             `json:"attempts"`
+	LastError          *string         `json:"last_error,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
+	ProcessedAt        *time.Time      `json:"processed_at,omitempty"`
+	FailedAt           *time.Time      `json:"failed_at,omitempty"`
+}
+
+// OutboxStatusPayload payload event achievement.status_changed. From adalah status sebelum transisi;
+// relay hanya menerapkan event ke dokumen yang masih berstatus From (atau sudah berstatus Status).
+type OutboxStatusPayload struct {
+	Status string `json:"status"`
+	From   string `json:"from,omitempty"`
+}
+
+// OutboxStagePayload payload event achievement.stage_advanced
+type OutboxStagePayload struct {
+	Stage int `json:"stage"`
+}
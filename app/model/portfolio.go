@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StudentPortfolio pengaturan portofolio publik mahasiswa. Portofolio hanya tampil jika IsPublic
+// (opt-in) dan hanya berisi achievement terverifikasi yang tidak disembunyikan.
+type StudentPortfolio struct {
+	StudentID            uuid.UUID `json:"student_id"` // user ID mahasiswa
+	Slug                 string    `json:"slug"`
+	IsPublic             bool      `json:"is_public"`
+	Headline             string    `json:"headline"`
+	Bio                  string    `json:"bio"`
+	HiddenAchievementIDs []string  `json:"hidden_achievement_ids"`
+	CreatedAt            time.Time `json:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at"`
+}
+
+// UpdatePortfolioRequest untuk request body pengaturan portofolio; field kosong (null) tidak diubah
+type UpdatePortfolioRequest struct {
+	Slug     *string `json:"slug"`
+	IsPublic *bool   `json:"is_public"`
+	Headline *string `json:"headline"`
+	Bio      *string `json:"bio"`
+}
+
+// PortfolioVisibilityRequest untuk request body tampil/sembunyikan achievement di portofolio
+type PortfolioVisibilityRequest struct {
+	Visible *bool `json:"visible"`
+}
+
+// PortfolioAchievement achievement terverifikasi di halaman pengaturan portofolio beserta status tampilnya
+type PortfolioAchievement struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Category   string     `json:"category"`
+	Level      string     `json:"level"`
+	Date       time.Time  `json:"date"`
+	VerifiedAt *time.Time `json:"verified_at"`
+	Visible    bool       `json:"visible"`
+}
+
+// PublicPortfolio data portofolio untuk publik. Sengaja tidak memuat kontak (email, username),
+// NIM, dokumen maupun details achievement.
+type PublicPortfolio struct {
+	Slug             string                `json:"slug"`
+	Name             string                `json:"name"`
+	ProgramStudy     string                `json:"program_study"`
+	Headline         string                `json:"headline,omitempty"`
+	Bio              string                `json:"bio,omitempty"`
+	Institution      string                `json:"institution"`
+	AchievementCount int                   `json:"achievement_count"`
+	Achievements     []PublicPortfolioItem `json:"achievements"`
+	UpdatedAt        time.Time             `json:"updated_at"`
+}
+
+// PublicPortfolioItem satu achievement terverifikasi di portofolio publik
+type PublicPortfolioItem struct {
+	Title            string     `json:"title"`
+	Category         string     `json:"category"`
+	Level            string     `json:"level"`
+	Date             time.Time  `json:"date"`
+	Description      string     `json:"description,omitempty"`
+	Tags             []string   `json:"tags,omitempty"`
+	VerifiedAt       *time.Time `json:"verified_at"`
+	VerificationCode string     `json:"verification_code,omitempty"`
+	VerificationURL  string     `json:"verification_url,omitempty"`
+}
@@ -25,8 +25,9 @@ type StudentDetail struct {
 
 // TopStudent untuk statistics top students
 type TopStudent struct {
-	StudentID            string `json:"student_id"`
-	StudentName          string `json:"student_name"`
-	TotalAchievements    int    `json:"total_achievements"`
-	VerifiedAchievements int    `json:"verified_achievements"`
+	StudentID            string  `json:"student_id"`
+	StudentName          string  `json:"student_name"`
+	TotalAchievements    int     `json:"total_achievements"`
+	VerifiedAchievements int     `json:"verified_achievements"`
+	TotalPoints          float64 `json:"total_points"`
 }
\ No newline at end of file
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope template transkrip default (dipakai jika program studi tidak punya template sendiri)
+const TranscriptScopeDefault = "default"
+
+// Bagian transkrip yang bisa diatur urutannya di template
+const (
+	TranscriptSectionIdentity     = "identity"
+	TranscriptSectionSummary      = "summary"
+	TranscriptSectionAchievements = "achievements"
+	TranscriptSectionSignature    = "signature"
+)
+
+// Kolom tabel achievement pada transkrip
+const (
+	TranscriptColumnTitle  = "title"
+	TranscriptColumnDate   = "date"
+	TranscriptColumnStatus = "status"
+	TranscriptColumnPoints = "points"
+	TranscriptColumnCode   = "code"
+)
+
+// TranscriptLayout pengaturan tampilan transkrip prestasi (SKPI). Field kosong diisi nilai default.
+type TranscriptLayout struct {
+	PageSize       string   `json:"page_size"`       // A4 atau Letter
+	Orientation    string   `json:"orientation"`     // portrait atau landscape
+	HeaderLines    []string `json:"header_lines"`    // kop institusi; baris pertama dicetak tebal
+	Title          string   `json:"title"`           // judul dokumen
+	AccentColor    string   `json:"accent_color"`    // warna garis dan header tabel, #RRGGBB
+	Sections       []string `json:"sections"`        // urutan bagian: identity, summary, achievements, signature
+	Columns        []string `json:"columns"`         // kolom tabel: title, date, status, points, code
+	IncludePending bool     `json:"include_pending"` // ikut cetak achievement yang masih menunggu verifikasi
+	ShowQRCode     bool     `json:"show_qr_code"`
+	SignatoryName  string   `json:"signatory_name"`
+	SignatoryTitle string   `json:"signatory_title"`
+	FooterText     string   `json:"footer_text"`
+}
+
+// TranscriptTemplate template transkrip per program studi (scope = nama program studi lowercase atau "default")
+type TranscriptTemplate struct {
+	Scope     string           `json:"scope"`
+	Layout    TranscriptLayout `json:"layout"`
+	UpdatedBy *uuid.UUID       `json:"updated_by"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// TranscriptEntry achievement yang dicetak di transkrip beserta waktu verifikasinya
+type TranscriptEntry struct {
+	Achievement Achievement
+	VerifiedAt  *time.Time
+}
+
+// TranscriptItem satu baris tabel transkrip
+type TranscriptItem struct {
+	AchievementID    string
+	Title            string
+	Date             time.Time
+	Status           string
+	VerifiedAt       *time.Time
+	Points           float64
+	VerificationCode string
+}
+
+// TranscriptGroup kelompok baris transkrip dengan kategori dan level yang sama
+type TranscriptGroup struct {
+	Category string
+	Level    string
+	Items    []TranscriptItem
+	Points   float64
+}
+
+// TranscriptData seluruh isi transkrip yang dirender ke PDF
+type TranscriptData struct {
+	StudentName      string
+	StudentNumber    string
+	ProgramStudy     string
+	AcademicYear     string
+	Groups           []TranscriptGroup
+	AchievementCount int
+	TotalPoints      float64
+	VerificationCode string
+	VerificationURL  string
+	IssuedAt         time.Time
+}
+
+// TranscriptRecord bukti publik penerbitan transkrip (dicek lewat kode/QR di dokumen)
+type TranscriptRecord struct {
+	ID                  uuid.UUID `json:"id"`
+	Code                string    `json:"code"`
+	StudentID           string    `json:"student_id"`
+	HolderName          string    `json:"holder_name"`
+	AchievementCount    int       `json:"achievement_count"`
+	TotalPoints         float64   `json:"total_points"`
+	VerifierInstitution string    `json:"verifier_institution"`
+	Signature           string    `json:"-"`
+	IssuedAt            time.Time `json:"issued_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// TrashedAchievement achievement di trash beserta waktu purge permanennya
+type TrashedAchievement struct {
+	Achievement
+	PurgeAt *time.Time `json:"purge_at"`
+}
+
+// TrashedUser user di trash beserta waktu purge permanennya
+type TrashedUser struct {
+	User
+	PurgeAt *time.Time `json:"purge_at"`
+}
@@ -0,0 +1,7 @@
+package models
+
+// FieldError kesalahan validasi untuk satu field request
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status klaim di inbox verifikasi
+const (
+	InboxStateUnclaimed       = "unclaimed"
+	InboxStateClaimedByMe     = "claimed_by_me"
+	InboxStateClaimedByOthers = "claimed_by_others"
+)
+
+// ReviewClaim penanda bahwa achievement sedang direview seorang verifikator; berlaku sampai ExpiresAt
+type ReviewClaim struct {
+	AchievementID string    `json:"achievement_id"`
+	ClaimedBy     uuid.UUID `json:"claimed_by"`
+	ClaimerName   string    `json:"claimer_name"`
+	ClaimedAt     time.Time `json:"claimed_at"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// InboxFilter filter inbox verifikasi. AdvisorIDs nil berarti semua mahasiswa (admin / verifikator fakultas).
+type InboxFilter struct {
+	UserID        string   // verifikator yang membuka inbox, untuk status klaim
+	AdvisorIDs    []string // dosen wali yang mahasiswanya masuk inbox (diri sendiri dan yang mendelegasikan)
+	State         string   // unclaimed, claimed_by_me, claimed_by_others
+	Category      string
+	Level         string
+	StudentID     string
+	ProgramStudy  string
+	Search        string // judul atau nama/NIM mahasiswa
+	SubmittedFrom *time.Time
+	SubmittedTo   *time.Time
+	SortBy        string // age, level, student
+	SortOrder     string // asc, desc
+
+	// StagePermissions permission user; hanya achievement yang tahap pending-nya memakai salah satu
+	// permission ini yang masuk inbox. nil berarti tanpa filter tahap.
+	StagePermissions       []string
+	DefaultStagePermission string // permission tahap achievement tanpa rantai verifikasi
+}
+
+// InboxItem satu baris inbox verifikasi
+type InboxItem struct {
+	AchievementID string       `json:"achievement_id"`
+	Title         string       `json:"title"`
+	Category      string       `json:"category"`
+	Level         string       `json:"level"`
+	LevelRank     int          `json:"level_rank"`
+	StudentID     string       `json:"student_id"`
+	StudentNumber string       `json:"student_number"`
+	StudentName   string       `json:"student_name"`
+	ProgramStudy  string       `json:"program_study"`
+	AdvisorID     string       `json:"advisor_id"`
+	Delegated     bool         `json:"delegated"` // masuk inbox karena delegasi dari dosen wali lain
+	SubmittedAt   *time.Time   `json:"submitted_at"`
+	WaitingHours  int          `json:"waiting_hours"`
+	CurrentStage  int          `json:"current_stage"`
+	TotalStages   int          `json:"total_stages"`
+	Claim         *ReviewClaim `json:"claim"`
+}
+
+// InboxCounts jumlah achievement di inbox per status klaim (filter lain tetap berlaku)
+type InboxCounts struct {
+	Total           int64 `json:"total"`
+	Unclaimed       int64 `json:"unclaimed"`
+	ClaimedByMe     int64 `json:"claimed_by_me"`
+	ClaimedByOthers int64 `json:"claimed_by_others"`
+}
+
+// VerificationDelegation pelimpahan verifikasi mahasiswa bimbingan seorang dosen wali ke dosen lain
+// selama periode tertentu (mis. cuti)
+type VerificationDelegation struct {
+	ID            uuid.UUID  `json:"id"`
+	DelegatorID   uuid.UUID  `json:"delegator_id"`
+	DelegatorName string     `json:"delegator_name"`
+	DelegateID    uuid.UUID  `json:"delegate_id"`
+	DelegateName  string     `json:"delegate_name"`
+	StartsAt      time.Time  `json:"starts_at"`
+	EndsAt        time.Time  `json:"ends_at"`
+	Reason        *string    `json:"reason"`
+	CreatedBy     *uuid.UUID `json:"created_by"`
+	CreatedAt     time.Time  `json:"created_at"`
+	RevokedAt     *time.Time `json:"revoked_at"`
+}
+
+// CreateDelegationRequest untuk request body delegasi verifikasi. Tanggal YYYY-MM-DD atau RFC3339;
+// starts_at kosong berarti mulai sekarang. delegator_id hanya dipakai admin.
+type CreateDelegationRequest struct {
+	DelegatorID string `json:"delegator_id"`
+	DelegateID  string `json:"delegate_id"`
+	StartsAt    string `json:"starts_at"`
+	EndsAt      string `json:"ends_at"`
+	Reason      string `json:"reason"`
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationRecord bukti publik bahwa achievement diverifikasi universitas. Datanya snapshot saat
+// diterbitkan dan ditandatangani, sehingga perubahan langsung di database terdeteksi saat pengecekan.
+type VerificationRecord struct {
+	ID                  uuid.UUID  `json:"id"`
+	Code                string     `json:"code"`
+	MongoAchievementID  string     `json:"mongo_achievement_id"`
+	Title               string     `json:"title"`
+	HolderName          string     `json:"holder_name"`
+	Level               string     `json:"level"`
+	VerifiedAt          time.Time  `json:"verified_at"`
+	VerifierInstitution string     `json:"verifier_institution"`
+	Signature           string     `json:"-"`
+	IssuedAt            time.Time  `json:"issued_at"`
+	IssuedBy            *uuid.UUID `json:"issued_by"` // admin yang menerbitkan ulang; nil jika diterbitkan otomatis
+	RevokedAt           *time.Time `json:"revoked_at"`
+	RevokedBy           *uuid.UUID `json:"revoked_by"`
+	RevokeReason        *string    `json:"revoke_reason"`
+	URL                 string     `json:"url"`
+}
+
+// Jenis dokumen yang diverifikasi lewat kode publik
+const (
+	VerificationTypeAchievement = "achievement"
+	VerificationTypeTranscript  = "transcript"
+)
+
+// PublicVerificationResult response endpoint verifikasi publik; hanya data yang memang untuk publik
+type PublicVerificationResult struct {
+	Code                string     `json:"code"`
+	Type                string     `json:"type"`
+	Valid               bool       `json:"valid"`
+	Title               string     `json:"title,omitempty"`
+	HolderName          string     `json:"holder_name,omitempty"`
+	Level               string     `json:"level,omitempty"`
+	VerifiedAt          *time.Time `json:"verified_at,omitempty"`
+	VerifierInstitution string     `json:"verifier_institution,omitempty"`
+	RevokedAt           *time.Time `json:"revoked_at,omitempty"`
+	AchievementCount    *int       `json:"achievement_count,omitempty"` // transkrip
+	TotalPoints         *float64   `json:"total_points,omitempty"`      // transkrip
+	IssuedAt            *time.Time `json:"issued_at,omitempty"`         // transkrip
+}
+
+// RevokeVerificationRecordRequest untuk request body pencabutan kode verifikasi
+type RevokeVerificationRecordRequest struct {
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationSLARule batas waktu verifikasi per level achievement ('*' = default)
+type VerificationSLARule struct {
+	Level              string    `json:"level"`
+	ReminderAfterHours int       `json:"reminder_after_hours"`
+	EscalateAfterHours int       `json:"escalate_after_hours"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// VerificationEscalation catatan reminder/eskalasi submission yang melewati SLA
+type VerificationEscalation struct {
+	ID                 uuid.UUID `json:"id"`
+	MongoAchievementID string    `json:"mongo_achievement_id"`
+	EscalationType     string    `json:"escalation_type"` // reminder, escalation
+	NotifiedUserID     uuid.UUID `json:"notified_user_id"`
+	PendingHours       int       `json:"pending_hours"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// DepartmentAdmin admin departemen yang menerima eskalasi SLA untuk dosen wali di departemennya
+type DepartmentAdmin struct {
+	Department string    `json:"department"`
+	UserID     uuid.UUID `json:"user_id"`
+	FullName   string    `json:"full_name"`
+	Email      string    `json:"email"`
+}
+
+// DepartmentAdminRequest untuk request body penambahan admin departemen
+type DepartmentAdminRequest struct {
+	Department string `json:"department"`
+	UserID     string `json:"user_id"`
+}
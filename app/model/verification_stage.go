@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationStage konfigurasi tahap verifikasi per level/category.
+// Level/Category kosong berarti berlaku untuk semua.
+type VerificationStage struct {
+	ID         uuid.UUID `json:"id"`
+	StageOrder int       `json:"stage_order"`
+	Name       string    `json:"name"`
+	Permission string    `json:"permission"`
+	Level      *string   `json:"level"`
+	Category   *string   `json:"category"`
+	IsActive   bool      `json:"is_active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// AchievementApproval status satu tahap verifikasi untuk sebuah achievement
+type AchievementApproval struct {
+	ID                 uuid.UUID  `json:"id"`
+	MongoAchievementID string     `json:"mongo_achievement_id"`
+	StageOrder         int        `json:"stage_order"`
+	StageName          string     `json:"stage_name"`
+	Permission         string     `json:"permission"`
+	Status             string     `json:"status"` // pending, approved, rejected
+	DecidedBy          *uuid.UUID `json:"decided_by"`
+	Note               *string    `json:"note"`
+	DecidedAt          *time.Time `json:"decided_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+type AchievementApprovalRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementApprovalRepository(db *sql.DB) *AchievementApprovalRepository {
+	return &AchievementApprovalRepository{db: db}
+}
+
+// ReplaceForAchievement membuat ulang daftar tahap verifikasi (status pending) untuk sebuah achievement
+func (r *AchievementApprovalRepository) ReplaceForAchievement(mongoID string, approvals []models.AchievementApproval) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.ReplaceForAchievementTx(tx, mongoID, approvals); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceForAchievementTx sama dengan ReplaceForAchievement di dalam transaksi yang sudah ada (outbox)
+func (r *AchievementApprovalRepository) ReplaceForAchievementTx(tx *sql.Tx, mongoID string, approvals []models.AchievementApproval) error {
+	if _, err := tx.Exec(`DELETE FROM achievement_approvals WHERE mongo_achievement_id = $1`, mongoID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_approvals
+		(id, mongo_achievement_id, stage_order, stage_name, permission, status, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	for _, approval := range approvals {
+		_, err := tx.Exec(
+			query,
+			approval.ID,
+			mongoID,
+			approval.StageOrder,
+			approval.StageName,
+			approval.Permission,
+			approval.Status,
+			approval.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FindByMongoID mencari semua tahap verifikasi sebuah achievement
+func (r *AchievementApprovalRepository) FindByMongoID(mongoID string) ([]models.AchievementApproval, error) {
+	query := `
+		SELECT id, mongo_achievement_id, stage_order, stage_name, permission, status,
+		       decided_by, note, decided_at, created_at
+		FROM achievement_approvals
+		WHERE mongo_achievement_id = $1
+		ORDER BY stage_order ASC
+	`
+
+	rows, err := r.db.Query(query, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var approvals []models.AchievementApproval
+	for rows.Next() {
+		var approval models.AchievementApproval
+		err := rows.Scan(
+			&approval.ID,
+			&approval.MongoAchievementID,
+			&approval.StageOrder,
+			&approval.StageName,
+			&approval.Permission,
+			&approval.Status,
+			&approval.DecidedBy,
+			&approval.Note,
+			&approval.DecidedAt,
+			&approval.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, approval)
+	}
+
+	return approvals, nil
+}
+
+// UpdateDecisionTx menyimpan keputusan (approved/rejected) pada satu tahap verifikasi di dalam transaksi
+// yang sama dengan perubahan status atau tahap achievement. Mengembalikan ErrVersionConflict jika tahap
+// sudah diputuskan (mis. oleh verifikator lain secara bersamaan).
+func (r *AchievementApprovalRepository) UpdateDecisionTx(tx *sql.Tx, mongoID string, stageOrder int, status string, decidedBy string, note *string) error {
+	query := `
+		UPDATE achievement_approvals
+		SET status = $1, decided_by = $2, note = $3, decided_at = $4
+		WHERE mongo_achievement_id = $5 AND stage_order = $6 AND status = 'pending'
+	`
+
+	result, err := tx.Exec(query, status, decidedBy, note, time.Now(), mongoID, stageOrder)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+type AchievementDuplicateRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementDuplicateRepository(db *sql.DB) *AchievementDuplicateRepository {
+	return &AchievementDuplicateRepository{db: db}
+}
+
+// ReplaceForAchievement mengganti flag duplikat sebuah achievement dengan hasil deteksi terbaru
+func (r *AchievementDuplicateRepository) ReplaceForAchievement(mongoID string, flags []models.AchievementDuplicateFlag) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM achievement_duplicate_flags WHERE mongo_achievement_id = $1`, mongoID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_duplicate_flags
+		(mongo_achievement_id, duplicate_of_id, score, reasons, detected_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, flag := range flags {
+		_, err := tx.Exec(query, mongoID, flag.DuplicateOfID, flag.Score, pq.Array(flag.Reasons), flag.DetectedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindByAchievement mencari flag duplikat yang melibatkan achievement (di kedua sisi pasangan)
+func (r *AchievementDuplicateRepository) FindByAchievement(mongoID string) ([]models.AchievementDuplicateFlag, error) {
+	return r.find(`WHERE f.mongo_achievement_id = $1 OR f.duplicate_of_id = $1`, mongoID)
+}
+
+// FindActive mencari semua flag duplikat di mana kedua achievement belum dihapus
+func (r *AchievementDuplicateRepository) FindActive() ([]models.AchievementDuplicateFlag, error) {
+	return r.find(`
+		INNER JOIN achievement_references a ON a.mongo_achievement_id = f.mongo_achievement_id
+		INNER JOIN achievement_references b ON b.mongo_achievement_id = f.duplicate_of_id
+		WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+	`)
+}
+
+func (r *AchievementDuplicateRepository) find(where string, args ...interface{}) ([]models.AchievementDuplicateFlag, error) {
+	query := `
+		SELECT f.mongo_achievement_id, f.duplicate_of_id, f.score, f.reasons, f.detected_at
+		FROM achievement_duplicate_flags f
+	` + where + `
+		ORDER BY f.detected_at DESC
+	`
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []models.AchievementDuplicateFlag{}
+	for rows.Next() {
+		var flag models.AchievementDuplicateFlag
+		err := rows.Scan(
+			&flag.MongoAchievementID,
+			&flag.DuplicateOfID,
+			&flag.Score,
+			pq.Array(&flag.Reasons),
+			&flag.DetectedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+
+	return flags, nil
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+type AchievementImportRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementImportRepository(db *sql.DB) *AchievementImportRepository {
+	return &AchievementImportRepository{db: db}
+}
+
+// Create menyimpan job import baru
+func (r *AchievementImportRepository) Create(job *models.AchievementImportJob) error {
+	errors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_import_jobs
+		(id, filename, status, pre_verified, total_rows, processed_rows, created_count, failed_count, errors, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err = r.db.Exec(query,
+		job.ID,
+		job.Filename,
+		job.Status,
+		job.PreVerified,
+		job.TotalRows,
+		job.ProcessedRows,
+		job.CreatedCount,
+		job.FailedCount,
+		errors,
+		job.CreatedBy,
+		job.CreatedAt,
+	)
+	return err
+}
+
+// FindByID mencari job import; nil jika tidak ada
+func (r *AchievementImportRepository) FindByID(id string) (*models.AchievementImportJob, error) {
+	query := `
+		SELECT id, filename, status, pre_verified, total_rows, processed_rows, created_count, failed_count,
+		       errors, created_by, created_at, started_at, finished_at
+		FROM achievement_import_jobs
+		WHERE id::text = $1
+	`
+
+	job, err := scanImportJob(r.db.QueryRow(query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// FindRecent mencari job import terbaru
+func (r *AchievementImportRepository) FindRecent(limit int) ([]models.AchievementImportJob, error) {
+	query := `
+		SELECT id, filename, status, pre_verified, total_rows, processed_rows, created_count, failed_count,
+		       errors, created_by, created_at, started_at, finished_at
+		FROM achievement_import_jobs
+		ORDER BY created_at DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []models.AchievementImportJob{}
+	for rows.Next() {
+		job, err := scanImportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func scanImportJob(row interface{ Scan(...interface{}) error }) (*models.AchievementImportJob, error) {
+	var job models.AchievementImportJob
+	var errors []byte
+	err := row.Scan(
+		&job.ID,
+		&job.Filename,
+		&job.Status,
+		&job.PreVerified,
+		&job.TotalRows,
+		&job.ProcessedRows,
+		&job.CreatedCount,
+		&job.FailedCount,
+		&errors,
+		&job.CreatedBy,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(errors, &job.Errors); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkStarted mengubah status job menjadi running
+func (r *AchievementImportRepository) MarkStarted(id string) error {
+	_, err := r.db.Exec(`
+		UPDATE achievement_import_jobs
+		SET status = $1, started_at = $2
+		WHERE id::text = $3
+	`, models.ImportJobRunning, time.Now(), id)
+	return err
+}
+
+// UpdateProgress menyimpan progress job (jumlah baris diproses, dibuat, gagal dan daftar error)
+func (r *AchievementImportRepository) UpdateProgress(job *models.AchievementImportJob) error {
+	errors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE achievement_import_jobs
+		SET processed_rows = $1, created_count = $2, failed_count = $3, errors = $4
+		WHERE id::text = $5
+	`, job.ProcessedRows, job.CreatedCount, job.FailedCount, errors, job.ID.String())
+	return err
+}
+
+// MarkFinished menyimpan progress akhir dan status selesai (completed atau failed)
+func (r *AchievementImportRepository) MarkFinished(job *models.AchievementImportJob) error {
+	errors, err := json.Marshal(job.Errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(`
+		UPDATE achievement_import_jobs
+		SET status = $1, processed_rows = $2, created_count = $3, failed_count = $4, errors = $5, finished_at = $6
+		WHERE id::text = $7
+	`, job.Status, job.ProcessedRows, job.CreatedCount, job.FailedCount, errors, job.FinishedAt, job.ID.String())
+	return err
+}
+
+// FailInterrupted menandai job yang masih pending/running sebagai gagal. Dipanggil saat server start,
+// karena job berjalan di dalam proses dan tidak dilanjutkan setelah restart.
+func (r *AchievementImportRepository) FailInterrupted() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE achievement_import_jobs
+		SET status = $1, finished_at = $2
+		WHERE status IN ($3, $4)
+	`, models.ImportJobFailed, time.Now(), models.ImportJobPending, models.ImportJobRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+)
+
+type AchievementMemberRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementMemberRepository(db *sql.DB) *AchievementMemberRepository {
+	return &AchievementMemberRepository{db: db}
+}
+
+// ReplaceForAchievement menyamakan daftar anggota tim di PostgreSQL dengan data di MongoDB
+func (r *AchievementMemberRepository) ReplaceForAchievement(mongoID string, members []models.AchievementMember) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM achievement_members WHERE mongo_achievement_id = $1`, mongoID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_members
+		(mongo_achievement_id, student_id, role, status, invited_at, responded_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, member := range members {
+		_, err := tx.Exec(
+			query,
+			mongoID,
+			member.StudentID,
+			member.Role,
+			member.Status,
+			member.InvitedAt,
+			member.RespondedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,749 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AchievementReadModelRepository mengelola tabel achievement_read_model: proyeksi gabungan
+// achievement_references, dokumen MongoDB dan data mahasiswa untuk listing dan laporan
+type AchievementReadModelRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementReadModelRepository(db *sql.DB) *AchievementReadModelRepository {
+	return &AchievementReadModelRepository{db: db}
+}
+
+// Upsert memproyeksikan satu dokumen achievement. Data reference dan mahasiswa dibaca langsung dari
+// PostgreSQL dalam query yang sama; status di dokumen disamakan dengan status reference (sumber kebenaran).
+// Mengembalikan false jika achievement tidak punya reference aktif (tidak ada yang diproyeksikan).
+func (r *AchievementReadModelRepository) Upsert(achievement *models.Achievement) (bool, error) {
+	document, err := json.Marshal(achievement)
+	if err != nil {
+		return false, err
+	}
+
+	tags := achievement.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+	memberIDs := []string{}
+	for _, member := range achievement.Members {
+		if member.Status == "confirmed" {
+			memberIDs = append(memberIDs, member.StudentID)
+		}
+	}
+
+	query := `
+		INSERT INTO achievement_read_model (
+			mongo_achievement_id, reference_id, student_id, status, current_stage, total_stages,
+			submitted_at, verified_at, verified_by, rejection_note, created_at, updated_at,
+			student_number, student_name, program_study, academic_year, advisor_id,
+			title, category, level, achievement_date, tags, member_ids, document, projected_at
+		)
+		SELECT ar.mongo_achievement_id, ar.id, ar.student_id, ar.status, ar.current_stage, ar.total_stages,
+		       ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at,
+		       s.student_id, u.full_name, s.program_study, s.academic_year, s.advisor_id,
+		       $2, $3, $4, $5, $6, $7, jsonb_set($8::jsonb, '{status}', to_jsonb(ar.status::text)), NOW()
+		FROM achievement_references ar
+		LEFT JOIN students s ON s.user_id = ar.student_id
+		LEFT JOIN users u ON u.id = ar.student_id
+		WHERE ar.mongo_achievement_id = $1 AND ar.deleted_at IS NULL
+		ON CONFLICT (mongo_achievement_id) DO UPDATE SET
+			reference_id = EXCLUDED.reference_id,
+			student_id = EXCLUDED.student_id,
+			status = EXCLUDED.status,
+			current_stage = EXCLUDED.current_stage,
+			total_stages = EXCLUDED.total_stages,
+			submitted_at = EXCLUDED.submitted_at,
+			verified_at = EXCLUDED.verified_at,
+			verified_by = EXCLUDED.verified_by,
+			rejection_note = EXCLUDED.rejection_note,
+			created_at = EXCLUDED.created_at,
+			updated_at = EXCLUDED.updated_at,
+			student_number = EXCLUDED.student_number,
+			student_name = EXCLUDED.student_name,
+			program_study = EXCLUDED.program_study,
+			academic_year = EXCLUDED.academic_year,
+			advisor_id = EXCLUDED.advisor_id,
+			title = EXCLUDED.title,
+			category = EXCLUDED.category,
+			level = EXCLUDED.level,
+			achievement_date = EXCLUDED.achievement_date,
+			tags = EXCLUDED.tags,
+			member_ids = EXCLUDED.member_ids,
+			document = EXCLUDED.document,
+			projected_at = EXCLUDED.projected_at
+	`
+
+	result, err := r.db.Exec(query,
+		achievement.AchievementID,
+		achievement.Title,
+		achievement.Category,
+		achievement.Level,
+		achievement.Date,
+		pq.Array(tags),
+		pq.Array(memberIDs),
+		document,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Delete menghapus proyeksi achievement (achievement dihapus atau reference tidak aktif)
+func (r *AchievementReadModelRepository) Delete(mongoID string) error {
+	_, err := r.db.Exec(`DELETE FROM achievement_read_model WHERE mongo_achievement_id = $1`, mongoID)
+	return err
+}
+
+// Now waktu server database; dipakai sebagai batas rebuild agar tidak terpengaruh selisih jam aplikasi
+func (r *AchievementReadModelRepository) Now() (time.Time, error) {
+	var now time.Time
+	err := r.db.QueryRow(`SELECT NOW()`).Scan(&now)
+	return now, err
+}
+
+// DeleteProjectedBefore menghapus proyeksi yang tidak diperbarui sejak cutoff (sisa setelah rebuild)
+func (r *AchievementReadModelRepository) DeleteProjectedBefore(cutoff time.Time) (int64, error) {
+	result, err := r.db.Exec(`DELETE FROM achievement_read_model WHERE projected_at < $1`, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// RefreshStudent memperbarui data mahasiswa pada proyeksi berdasarkan user ID mahasiswa
+func (r *AchievementReadModelRepository) RefreshStudent(userID string) error {
+	return r.refreshStudent("s.user_id", userID)
+}
+
+// RefreshStudentProfile memperbarui data mahasiswa pada proyeksi berdasarkan ID profil students
+func (r *AchievementReadModelRepository) RefreshStudentProfile(studentID string) error {
+	return r.refreshStudent("s.id", studentID)
+}
+
+func (r *AchievementReadModelRepository) refreshStudent(column, id string) error {
+	query := fmt.Sprintf(`
+		UPDATE achievement_read_model rm
+		SET student_number = s.student_id,
+		    student_name = u.full_name,
+		    program_study = s.program_study,
+		    academic_year = s.academic_year,
+		    advisor_id = s.advisor_id,
+		    projected_at = NOW()
+		FROM students s
+		INNER JOIN users u ON u.id = s.user_id
+		WHERE rm.student_id = s.user_id AND %s::text = $1
+	`, column)
+
+	_, err := r.db.Exec(query, id)
+	return err
+}
+
+// FindAll mencari achievement dengan filter listing admin; semua filter dijalankan di satu tabel
+func (r *AchievementReadModelRepository) FindAll(filter models.AchievementListFilter, limit, offset int) ([]models.Achievement, int64, error) {
+	whereClause, args := listConditions(filter)
+	return r.findPage(whereClause, listOrder(filter).orderBy(false), args, limit, offset)
+}
+
+// FindAllByCursor sama seperti FindAll dengan pagination keyset. cursor nil berarti halaman pertama;
+// cursor harus dibuat dengan sort_by/sort_order yang sama.
+func (r *AchievementReadModelRepository) FindAllByCursor(filter models.AchievementListFilter, cursor *models.PageCursor, limit int) ([]models.Achievement, *models.PageCursor, *models.PageCursor, error) {
+	whereClause, args := listConditions(filter)
+	return r.findCursorPage(whereClause, args, listOrder(filter), cursor, limit)
+}
+
+func listConditions(filter models.AchievementListFilter) (string, []interface{}) {
+	whereClause := "WHERE TRUE"
+	args := []interface{}{}
+	argIndex := 1
+
+	addCondition := func(condition string, value interface{}) {
+		whereClause += fmt.Sprintf(" AND "+condition, argIndex)
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filter.Status != "" {
+		addCondition("status = $%d", filter.Status)
+	}
+	if filter.StudentID != "" {
+		addCondition("student_id::text = $%d", filter.StudentID)
+	}
+	if filter.Category != "" {
+		addCondition("category = $%d", filter.Category)
+	}
+	if filter.Level != "" {
+		addCondition("level = $%d", filter.Level)
+	}
+	if filter.DateFrom != nil {
+		addCondition("achievement_date >= $%d", *filter.DateFrom)
+	}
+	if filter.DateTo != nil {
+		addCondition("achievement_date < $%d", *filter.DateTo)
+	}
+	if filter.SubmittedFrom != nil {
+		addCondition("submitted_at >= $%d", *filter.SubmittedFrom)
+	}
+	if filter.SubmittedTo != nil {
+		addCondition("submitted_at < $%d", *filter.SubmittedTo)
+	}
+	if filter.VerifiedFrom != nil {
+		addCondition("verified_at >= $%d", *filter.VerifiedFrom)
+	}
+	if filter.VerifiedTo != nil {
+		addCondition("verified_at < $%d", *filter.VerifiedTo)
+	}
+	if filter.VerifiedBy != "" {
+		addCondition("verified_by::text = $%d", filter.VerifiedBy)
+	}
+	if filter.AdvisorID != "" {
+		addCondition("advisor_id::text = $%d", filter.AdvisorID)
+	}
+	if filter.ProgramStudy != "" {
+		addCondition("LOWER(program_study) = LOWER($%d)", filter.ProgramStudy)
+	}
+	if filter.AcademicYear != "" {
+		addCondition("academic_year = $%d", filter.AcademicYear)
+	}
+	if filter.AchievementIDs != nil {
+		addCondition("mongo_achievement_id = ANY($%d)", pq.Array(filter.AchievementIDs))
+	}
+
+	return whereClause, args
+}
+
+// listOrder urutan listing admin. Kolom waktu yang bisa kosong (submitted_at, verified_at) selalu
+// ditaruh di akhir, baik ascending maupun descending.
+func listOrder(filter models.AchievementListFilter) keysetOrder {
+	validSortFields := map[string]bool{
+		"created_at":   true,
+		"submitted_at": true,
+		"verified_at":  true,
+		"updated_at":   true,
+	}
+	sortBy := "created_at"
+	if validSortFields[filter.SortBy] {
+		sortBy = filter.SortBy
+	}
+	desc := filter.SortOrder != "asc" || !validSortFields[filter.SortBy]
+
+	sortExpr := sortBy
+	if sortBy == "submitted_at" || sortBy == "verified_at" {
+		last := "'infinity'::timestamp"
+		if desc {
+			last = "'-infinity'::timestamp"
+		}
+		sortExpr = fmt.Sprintf("COALESCE(%s, %s)", sortBy, last)
+	}
+
+	direction := "asc"
+	if desc {
+		direction = "desc"
+	}
+	return keysetOrder{
+		name:     "achievements:" + sortBy + ":" + direction,
+		sortExpr: sortExpr,
+		idExpr:   "mongo_achievement_id",
+		desc:     desc,
+	}
+}
+
+// inboxFrom sumber data inbox verifikasi: tahap yang sedang berjalan, klaim yang masih berlaku dan
+// rank level master data
+const inboxFrom = `
+	FROM achievement_read_model a
+	LEFT JOIN achievement_approvals ap ON ap.mongo_achievement_id = a.mongo_achievement_id AND ap.stage_order = a.current_stage
+	LEFT JOIN verification_claims vc ON vc.mongo_achievement_id = a.mongo_achievement_id AND vc.expires_at > NOW()
+	LEFT JOIN users cu ON cu.id = vc.claimed_by
+	LEFT JOIN achievement_levels al ON al.code = a.level
+`
+
+// inboxConditions kondisi inbox. includeState false mengabaikan filter status klaim (untuk hitungan
+// per status). Hanya placeholder yang dipakai query yang diikat ke args.
+func inboxConditions(filter models.InboxFilter, includeState bool) (string, []interface{}) {
+	whereClause := "WHERE a.status = 'submitted'"
+	args := []interface{}{}
+	argIndex := 1
+
+	addCondition := func(condition string, value interface{}) {
+		whereClause += " AND " + strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filter.AdvisorIDs != nil {
+		addCondition("a.advisor_id::text = ANY($?)", pq.Array(filter.AdvisorIDs))
+	}
+	if filter.StagePermissions != nil {
+		// Achievement tanpa rantai verifikasi menunggu tahap default
+		whereClause += fmt.Sprintf(" AND COALESCE(ap.permission, $%d) = ANY($%d)", argIndex, argIndex+1)
+		args = append(args, filter.DefaultStagePermission, pq.Array(filter.StagePermissions))
+		argIndex += 2
+	}
+	if filter.Category != "" {
+		addCondition("a.category = $?", filter.Category)
+	}
+	if filter.Level != "" {
+		addCondition("a.level = $?", filter.Level)
+	}
+	if filter.StudentID != "" {
+		addCondition("a.student_id::text = $?", filter.StudentID)
+	}
+	if filter.ProgramStudy != "" {
+		addCondition("LOWER(a.program_study) = LOWER($?)", filter.ProgramStudy)
+	}
+	if filter.Search != "" {
+		addCondition("(a.title ILIKE $? OR a.student_name ILIKE $? OR a.student_number ILIKE $?)", "%"+escapeLike(filter.Search)+"%")
+	}
+	if filter.SubmittedFrom != nil {
+		addCondition("a.submitted_at >= $?", *filter.SubmittedFrom)
+	}
+	if filter.SubmittedTo != nil {
+		addCondition("a.submitted_at < $?", *filter.SubmittedTo)
+	}
+
+	if includeState {
+		switch filter.State {
+		case models.InboxStateUnclaimed:
+			whereClause += " AND vc.claimed_by IS NULL"
+		case models.InboxStateClaimedByMe:
+			addCondition("vc.claimed_by::text = $?", filter.UserID)
+		case models.InboxStateClaimedByOthers:
+			addCondition("vc.claimed_by::text <> $?", filter.UserID)
+		}
+	}
+
+	return whereClause, args
+}
+
+// escapeLike meng-escape karakter wildcard LIKE agar kata kunci dicocokkan apa adanya
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// inboxOrderBy urutan inbox: age (paling lama menunggu lebih dulu), level (tingkat tertinggi lebih dulu)
+// atau student (nama mahasiswa A-Z). sort_order membalik arah default.
+func inboxOrderBy(filter models.InboxFilter) string {
+	reverse := func(defaultDirection string) string {
+		if filter.SortOrder == "" {
+			return defaultDirection
+		}
+		if filter.SortOrder == "desc" {
+			return "DESC"
+		}
+		return "ASC"
+	}
+
+	tiebreak := "COALESCE(a.submitted_at, 'infinity'::timestamp) ASC, a.mongo_achievement_id ASC"
+	switch filter.SortBy {
+	case "level":
+		return fmt.Sprintf("ORDER BY COALESCE(al.rank, 0) %s, %s", reverse("DESC"), tiebreak)
+	case "student":
+		return fmt.Sprintf("ORDER BY LOWER(COALESCE(a.student_name, '')) %s, %s", reverse("ASC"), tiebreak)
+	default:
+		direction := reverse("ASC")
+		last := "'infinity'::timestamp"
+		if direction == "DESC" {
+			last = "'-infinity'::timestamp"
+		}
+		return fmt.Sprintf("ORDER BY COALESCE(a.submitted_at, %s) %s, a.mongo_achievement_id %s", last, direction, direction)
+	}
+}
+
+// FindInbox mencari achievement berstatus submitted untuk inbox verifikasi beserta klaim review yang aktif
+func (r *AchievementReadModelRepository) FindInbox(filter models.InboxFilter, limit, offset int) ([]models.InboxItem, int64, error) {
+	whereClause, args := inboxConditions(filter, true)
+
+	var total int64
+	if err := r.db.QueryRow(fmt.Sprintf(`SELECT COUNT(*) %s %s`, inboxFrom, whereClause), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT a.mongo_achievement_id, a.title, a.category, a.level, COALESCE(al.rank, 0),
+		       a.student_id::text, COALESCE(a.student_number, ''), COALESCE(a.student_name, ''),
+		       COALESCE(a.program_study, ''), COALESCE(a.advisor_id::text, ''),
+		       a.submitted_at, a.current_stage, a.total_stages,
+		       vc.claimed_by, COALESCE(cu.full_name, ''), vc.claimed_at, vc.expires_at
+		%s
+		%s
+		%s
+		LIMIT $%d OFFSET $%d
+	`, inboxFrom, whereClause, inboxOrderBy(filter), len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := []models.InboxItem{}
+	for rows.Next() {
+		var item models.InboxItem
+		var claimedBy uuid.NullUUID
+		var claimerName string
+		var claimedAt, expiresAt sql.NullTime
+		if err := rows.Scan(
+			&item.AchievementID,
+			&item.Title,
+			&item.Category,
+			&item.Level,
+			&item.LevelRank,
+			&item.StudentID,
+			&item.StudentNumber,
+			&item.StudentName,
+			&item.ProgramStudy,
+			&item.AdvisorID,
+			&item.SubmittedAt,
+			&item.CurrentStage,
+			&item.TotalStages,
+			&claimedBy,
+			&claimerName,
+			&claimedAt,
+			&expiresAt,
+		); err != nil {
+			return nil, 0, err
+		}
+
+		if claimedBy.Valid {
+			item.Claim = &models.ReviewClaim{
+				AchievementID: item.AchievementID,
+				ClaimedBy:     claimedBy.UUID,
+				ClaimerName:   claimerName,
+				ClaimedAt:     claimedAt.Time,
+				ExpiresAt:     expiresAt.Time,
+			}
+		}
+		items = append(items, item)
+	}
+
+	return items, total, rows.Err()
+}
+
+// CountInbox menghitung isi inbox per status klaim dengan filter yang sama (kecuali filter status klaim)
+func (r *AchievementReadModelRepository) CountInbox(filter models.InboxFilter) (models.InboxCounts, error) {
+	whereClause, args := inboxConditions(filter, false)
+	args = append(args, filter.UserID)
+
+	var counts models.InboxCounts
+	err := r.db.QueryRow(fmt.Sprintf(`
+		SELECT COUNT(*),
+		       COUNT(*) FILTER (WHERE vc.claimed_by IS NULL),
+		       COUNT(*) FILTER (WHERE vc.claimed_by::text = $%[3]d),
+		       COUNT(*) FILTER (WHERE vc.claimed_by::text <> $%[3]d)
+		%[1]s
+		%[2]s
+	`, inboxFrom, whereClause, len(args)), args...).Scan(&counts.Total, &counts.Unclaimed, &counts.ClaimedByMe, &counts.ClaimedByOthers)
+	return counts, err
+}
+
+// FindByStudentIDs mencari achievement milik mahasiswa, termasuk prestasi tim yang sudah dikonfirmasi
+func (r *AchievementReadModelRepository) FindByStudentIDs(studentIDs []string, limit, offset int) ([]models.Achievement, int64, error) {
+	if len(studentIDs) == 0 {
+		return []models.Achievement{}, 0, nil
+	}
+
+	return r.findPage(
+		"WHERE (student_id::text = ANY($1) OR member_ids && $1::text[])",
+		"ORDER BY created_at DESC, mongo_achievement_id DESC",
+		[]interface{}{pq.Array(studentIDs)},
+		limit, offset,
+	)
+}
+
+// FindTranscriptEntries mencari achievement mahasiswa (termasuk prestasi tim yang dikonfirmasi) dengan status
+// tertentu untuk transkrip, urut tanggal prestasi
+func (r *AchievementReadModelRepository) FindTranscriptEntries(studentID string, statuses []string) ([]models.TranscriptEntry, error) {
+	query := `
+		SELECT document, verified_at
+		FROM achievement_read_model
+		WHERE (student_id::text = $1 OR $1 = ANY(member_ids)) AND status = ANY($2)
+		ORDER BY achievement_date ASC NULLS LAST, mongo_achievement_id ASC
+	`
+
+	rows, err := r.db.Query(query, studentID, pq.Array(statuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.TranscriptEntry{}
+	for rows.Next() {
+		var document []byte
+		var entry models.TranscriptEntry
+		if err := rows.Scan(&document, &entry.VerifiedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(document, &entry.Achievement); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// FindTitleKeys mencari judul dan tanggal achievement yang dimiliki mahasiswa,
+// dipakai untuk mendeteksi baris import yang sudah pernah dibuat
+func (r *AchievementReadModelRepository) FindTitleKeys(studentIDs []string) ([]models.AchievementTitleKey, error) {
+	if len(studentIDs) == 0 {
+		return []models.AchievementTitleKey{}, nil
+	}
+
+	query := `
+		SELECT student_id::text, title, achievement_date
+		FROM achievement_read_model
+		WHERE student_id::text = ANY($1)
+	`
+
+	rows, err := r.db.Query(query, pq.Array(studentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []models.AchievementTitleKey{}
+	for rows.Next() {
+		var key models.AchievementTitleKey
+		if err := rows.Scan(&key.StudentID, &key.Title, &key.Date); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// FindExportPage mengambil satu halaman baris export dengan filter dan urutan yang sama seperti listing.
+// Halaman berikutnya diambil dengan cursor next; nil berarti halaman terakhir.
+func (r *AchievementReadModelRepository) FindExportPage(filter models.AchievementListFilter, cursor *models.PageCursor, limit int) ([]models.AchievementExportRow, *models.PageCursor, error) {
+	whereClause, args := listConditions(filter)
+	order := listOrder(filter)
+	if cursor != nil {
+		condition, cursorArgs, err := order.condition(cursor, len(args)+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		whereClause += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT mongo_achievement_id, COALESCE(student_number, ''), COALESCE(student_name, ''),
+		       COALESCE(program_study, ''), COALESCE(academic_year, ''), title, category, level,
+		       achievement_date, status, submitted_at, verified_at, tags, %s
+		FROM achievement_read_model
+		%s
+		%s
+		LIMIT $%d
+	`, order.keyColumns(), whereClause, order.orderBy(false), len(args)+1)
+
+	rows, err := r.db.Query(query, append(args, limit+1)...)
+	if err != nil {
+		return nil, nil, cursorQueryError(err, cursor)
+	}
+	defer rows.Close()
+
+	items := []models.AchievementExportRow{}
+	keys := []models.PageCursor{}
+	for rows.Next() {
+		var item models.AchievementExportRow
+		var key models.PageCursor
+		err := rows.Scan(
+			&item.AchievementID,
+			&item.StudentNumber,
+			&item.StudentName,
+			&item.ProgramStudy,
+			&item.AcademicYear,
+			&item.Title,
+			&item.Category,
+			&item.Level,
+			&item.AchievementDate,
+			&item.Status,
+			&item.SubmittedAt,
+			&item.VerifiedAt,
+			pq.Array(&item.Tags),
+			&key.Key,
+			&key.ID,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, item)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	items, next, _ := keysetPage(order, items, keys, limit, nil)
+	return items, next, nil
+}
+
+func (r *AchievementReadModelRepository) findPage(whereClause, orderByClause string, args []interface{}, limit, offset int) ([]models.Achievement, int64, error) {
+	var total int64
+	countQuery := fmt.Sprintf(`SELECT COUNT(*) FROM achievement_read_model %s`, whereClause)
+	if err := r.db.QueryRow(countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT document
+		FROM achievement_read_model
+		%s
+		%s
+		LIMIT $%d OFFSET $%d
+	`, whereClause, orderByClause, len(args)+1, len(args)+2)
+
+	rows, err := r.db.Query(query, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	achievements := []models.Achievement{}
+	for rows.Next() {
+		var document []byte
+		if err := rows.Scan(&document); err != nil {
+			return nil, 0, err
+		}
+
+		var achievement models.Achievement
+		if err := json.Unmarshal(document, &achievement); err != nil {
+			return nil, 0, err
+		}
+		achievements = append(achievements, achievement)
+	}
+
+	return achievements, total, rows.Err()
+}
+
+// findCursorPage seperti findPage tanpa COUNT dan OFFSET: posisi halaman ditentukan cursor
+func (r *AchievementReadModelRepository) findCursorPage(whereClause string, args []interface{}, order keysetOrder, cursor *models.PageCursor, limit int) ([]models.Achievement, *models.PageCursor, *models.PageCursor, error) {
+	if cursor != nil {
+		condition, cursorArgs, err := order.condition(cursor, len(args)+1)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		whereClause += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT document, %s
+		FROM achievement_read_model
+		%s
+		%s
+		LIMIT $%d
+	`, order.keyColumns(), whereClause, order.orderBy(cursor != nil && cursor.Backward), len(args)+1)
+
+	rows, err := r.db.Query(query, append(args, limit+1)...)
+	if err != nil {
+		return nil, nil, nil, cursorQueryError(err, cursor)
+	}
+	defer rows.Close()
+
+	achievements := []models.Achievement{}
+	keys := []models.PageCursor{}
+	for rows.Next() {
+		var document []byte
+		var key models.PageCursor
+		if err := rows.Scan(&document, &key.Key, &key.ID); err != nil {
+			return nil, nil, nil, err
+		}
+
+		var achievement models.Achievement
+		if err := json.Unmarshal(document, &achievement); err != nil {
+			return nil, nil, nil, err
+		}
+		achievements = append(achievements, achievement)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	achievements, next, prev := keysetPage(order, achievements, keys, limit, cursor)
+	return achievements, next, prev, nil
+}
+
+// GetStatistics menghitung statistik achievement (exclude revoked) untuk mahasiswa tertentu,
+// termasuk prestasi tim yang dikonfirmasi. studentIDs nil berarti semua mahasiswa.
+// Bentuk hasil sama dengan AchievementRepository.GetStatisticsByStudentIDs.
+func (r *AchievementReadModelRepository) GetStatistics(studentIDs []string) (map[string]interface{}, error) {
+	whereClause := "WHERE status <> 'revoked'"
+	args := []interface{}{}
+	if studentIDs != nil {
+		whereClause += " AND (student_id::text = ANY($1) OR member_ids && $1::text[])"
+		args = append(args, pq.Array(studentIDs))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT status, category, level,
+		       COALESCE(TO_CHAR(achievement_date, 'YYYY-MM'), '0001-01') AS period,
+		       COUNT(*)
+		FROM achievement_read_model
+		%s
+		GROUP BY status, category, level, period
+	`, whereClause)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[string]int{}
+	categoryCount := make(map[string]int)
+	levelCount := make(map[string]int)
+	periodCount := make(map[string]int)
+	totalAchievements := 0
+
+	for rows.Next() {
+		var status, category, level, period string
+		var count int
+		if err := rows.Scan(&status, &category, &level, &period, &count); err != nil {
+			return nil, err
+		}
+
+		totalAchievements += count
+		totals[status] += count
+		if category != "" {
+			categoryCount[category] += count
+		}
+		if level != "" {
+			levelCount[level] += count
+		}
+		periodCount[period] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"total_achievements": totalAchievements,
+		"total_verified":     totals["verified"],
+		"total_pending":      totals["submitted"],
+		"total_rejected":     totals["rejected"],
+		"total_draft":        totals["draft"],
+		"category_count":     categoryCount,
+		"level_count":        levelCount,
+		"period_count":       periodCount,
+	}, nil
+}
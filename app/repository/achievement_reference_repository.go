@@ -5,6 +5,9 @@ models "crud-app/app/model"
 "database/sql"
 "fmt"
 "time"
+
+"github.com/google/uuid"
+"github.com/lib/pq"
 )
 
 type AchievementReferenceRepository struct {
@@ -17,18 +20,30 @@ return &AchievementReferenceRepository{db: db}
 
 // Create menyimpan reference achievement ke PostgreSQL
 func (r *AchievementReferenceRepository) Create(ref *models.AchievementReferences) error {
+return r.create(r.db, ref)
+}
+
+// CreateTx menyimpan reference achievement di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) CreateTx(tx *sql.Tx, ref *models.AchievementReferences) error {
+return r.create(tx, ref)
+}
+
+func (r *AchievementReferenceRepository) create(db sqlExecutor, ref *models.AchievementReferences) error {
 query := `
 		INSERT INTO achievement_references 
-		(id, student_id, mongo_achievement_id, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		(id, student_id, mongo_achievement_id, status, submitted_at, verified_at, verified_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-_, err := r.db.Exec(
+_, err := db.Exec(
 query,
 ref.ID,
 ref.StudentID,
 ref.MongoAchievementID,
 ref.Status,
+ref.SubmittedAt,
+ref.VerifiedAt,
+ref.VerifiedBy,
 ref.CreatedAt,
 ref.UpdatedAt,
 )
@@ -41,6 +56,7 @@ func (r *AchievementReferenceRepository) FindByID(id string) (*models.Achievemen
 query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
 		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages,
 		       deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE id = $1 AND deleted_at IS NULL
@@ -56,6 +72,8 @@ err := r.db.QueryRow(query, id).Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -76,6 +94,7 @@ func (r *AchievementReferenceRepository) FindByMongoID(mongoID string) (*models.
 query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
 		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages,
 		       deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = $1 AND deleted_at IS NULL
@@ -91,6 +110,8 @@ err := r.db.QueryRow(query, mongoID).Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -111,6 +132,7 @@ func (r *AchievementReferenceRepository) FindByStudentID(studentID string) ([]mo
 query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
 		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages,
 		       deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE student_id = $1 AND deleted_at IS NULL
@@ -135,6 +157,8 @@ err := rows.Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -164,7 +188,7 @@ return err
 func (r *AchievementReferenceRepository) UpdateSubmittedStatus(mongoID string) error {
 query := `
 		UPDATE achievement_references
-		SET status = 'submitted', submitted_at = $1, updated_at = $1
+		SET status = 'submitted', submitted_at = $1, stage_entered_at = $1, updated_at = $1
 		WHERE mongo_achievement_id = $2
 	`
 
@@ -172,6 +196,89 @@ _, err := r.db.Exec(query, time.Now(), mongoID)
 return err
 }
 
+// UpdateSubmittedStages mengupdate status draft menjadi submitted dan menyimpan jumlah tahap verifikasi.
+// Mengembalikan ErrVersionConflict jika reference tidak lagi berstatus draft.
+func (r *AchievementReferenceRepository) UpdateSubmittedStages(mongoID string, totalStages int) error {
+	return r.updateSubmittedStages(r.db, mongoID, totalStages)
+}
+
+// UpdateSubmittedStagesTx sama dengan UpdateSubmittedStages di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) UpdateSubmittedStagesTx(tx *sql.Tx, mongoID string, totalStages int) error {
+	return r.updateSubmittedStages(tx, mongoID, totalStages)
+}
+
+func (r *AchievementReferenceRepository) updateSubmittedStages(db sqlExecutor, mongoID string, totalStages int) error {
+	query := `
+		UPDATE achievement_references
+		SET status = 'submitted', submitted_at = $1, stage_entered_at = $1, updated_at = $1,
+		    current_stage = 1, total_stages = $2
+		WHERE mongo_achievement_id = $3 AND status = 'draft' AND deleted_at IS NULL
+	`
+
+	result, err := db.Exec(query, time.Now(), totalStages, mongoID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// AdvanceStageTx memindahkan achievement ke tahap verifikasi berikutnya di dalam transaksi yang sama
+// dengan keputusan tahapnya. Mengembalikan ErrVersionConflict jika reference tidak lagi berstatus submitted.
+func (r *AchievementReferenceRepository) AdvanceStageTx(tx *sql.Tx, mongoID string, nextStage int) error {
+	query := `
+		UPDATE achievement_references
+		SET current_stage = $1, stage_entered_at = $2, updated_at = $2
+		WHERE mongo_achievement_id = $3 AND status = 'submitted'
+	`
+
+	result, err := tx.Exec(query, nextStage, time.Now(), mongoID)
+	if err != nil {
+		return err
+	}
+	return requireAffected(result)
+}
+
+// CountByVerificationStage menghitung achievement submitted per tahap verifikasi.
+// studentIDs kosong berarti semua mahasiswa.
+func (r *AchievementReferenceRepository) CountByVerificationStage(studentIDs []string) (map[int]int, error) {
+	query := `
+		SELECT current_stage, COUNT(*)
+		FROM achievement_references
+		WHERE status = 'submitted' AND deleted_at IS NULL
+	`
+	args := []interface{}{}
+
+	if len(studentIDs) > 0 {
+		placeholders := ""
+		for i, id := range studentIDs {
+			if i > 0 {
+				placeholders += ", "
+			}
+			placeholders += fmt.Sprintf("$%d", i+1)
+			args = append(args, id)
+		}
+		query += fmt.Sprintf(" AND student_id::text IN (%s)", placeholders)
+	}
+	query += " GROUP BY current_stage"
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var stage, count int
+		if err := rows.Scan(&stage, &count); err != nil {
+			return nil, err
+		}
+		counts[stage] = count
+	}
+
+	return counts, nil
+}
+
 // Delete menghapus reference (hard delete - untuk rollback)
 func (r *AchievementReferenceRepository) Delete(mongoID string) error {
 query := `DELETE FROM achievement_references WHERE mongo_achievement_id = $1`
@@ -181,15 +288,150 @@ return err
 
 // SoftDelete melakukan soft delete reference (FR-005)
 func (r *AchievementReferenceRepository) SoftDelete(mongoID string) error {
+return r.softDelete(r.db, mongoID)
+}
+
+// SoftDeleteTx melakukan soft delete reference di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) SoftDeleteTx(tx *sql.Tx, mongoID string) error {
+return r.softDelete(tx, mongoID)
+}
+
+func (r *AchievementReferenceRepository) softDelete(db sqlExecutor, mongoID string) error {
 query := `
 		UPDATE achievement_references
 		SET deleted_at = $1, updated_at = $1
 		WHERE mongo_achievement_id = $2 AND deleted_at IS NULL
 	`
 
-_, err := r.db.Exec(query, time.Now(), mongoID)
+_, err := db.Exec(query, time.Now(), mongoID)
+return err
+}
+
+// Restore mengembalikan reference yang di-soft delete. Mengembalikan sql.ErrNoRows
+// jika tidak ada reference di trash untuk achievement tersebut.
+func (r *AchievementReferenceRepository) Restore(mongoID string) error {
+return r.restore(r.db, mongoID)
+}
+
+// RestoreTx mengembalikan reference yang di-soft delete di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) RestoreTx(tx *sql.Tx, mongoID string) error {
+return r.restore(tx, mongoID)
+}
+
+func (r *AchievementReferenceRepository) restore(db sqlExecutor, mongoID string) error {
+query := `
+		UPDATE achievement_references
+		SET deleted_at = NULL, updated_at = $1
+		WHERE mongo_achievement_id = $2 AND deleted_at IS NOT NULL
+	`
+
+result, err := db.Exec(query, time.Now(), mongoID)
+if err != nil {
+return err
+}
+
+affected, err := result.RowsAffected()
+if err != nil {
+return err
+}
+if affected == 0 {
+return sql.ErrNoRows
+}
+return nil
+}
+
+// Purge menghapus permanen reference beserta semua data PostgreSQL yang terkait
+// dengan achievement (approval, anggota tim, poin, flag duplikat, eskalasi, pencabutan, outbox)
+func (r *AchievementReferenceRepository) Purge(mongoID string) error {
+tx, err := r.db.Begin()
+if err != nil {
+return err
+}
+defer tx.Rollback()
+
+statements := []string{
+`DELETE FROM achievement_approvals WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_members WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_credit_points WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_duplicate_flags WHERE mongo_achievement_id = $1 OR duplicate_of_id = $1`,
+`DELETE FROM verification_escalations WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_revocations WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_verification_records WHERE mongo_achievement_id = $1`,
+`UPDATE notifications SET mongo_achievement_id = NULL WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_outbox WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_read_model WHERE mongo_achievement_id = $1`,
+`DELETE FROM achievement_references WHERE mongo_achievement_id = $1`,
+}
+for _, statement := range statements {
+if _, err := tx.Exec(statement, mongoID); err != nil {
+return err
+}
+}
+
+return tx.Commit()
+}
+
+// FindAllStates mengambil ringkasan status semua reference, termasuk yang terhapus (consistency checker)
+func (r *AchievementReferenceRepository) FindAllStates() ([]models.AchievementState, error) {
+query := `
+		SELECT mongo_achievement_id, student_id, status, deleted_at IS NOT NULL
+		FROM achievement_references
+	`
+
+rows, err := r.db.Query(query)
+if err != nil {
+return nil, err
+}
+defer rows.Close()
+
+var states []models.AchievementState
+for rows.Next() {
+var state models.AchievementState
+if err := rows.Scan(&state.AchievementID, &state.StudentID, &state.Status, &state.IsDeleted); err != nil {
+return nil, err
+}
+states = append(states, state)
+}
+
+return states, nil
+}
+
+// FindState mengambil status dan tanda terhapus satu reference (nil jika tidak ada)
+func (r *AchievementReferenceRepository) FindState(mongoID string) (*models.AchievementState, error) {
+query := `
+		SELECT mongo_achievement_id, student_id, status, deleted_at IS NOT NULL
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1
+	`
+
+var state models.AchievementState
+err := r.db.QueryRow(query, mongoID).Scan(&state.AchievementID, &state.StudentID, &state.Status, &state.IsDeleted)
+if err == sql.ErrNoRows {
+return nil, nil
+}
+if err != nil {
+return nil, err
+}
+return &state, nil
+}
+
+// RepairState menyamakan status dan tanda terhapus reference dengan store lain (consistency repair).
+// Hanya berlaku jika reference masih dalam keadaan expected; ErrVersionConflict jika sudah berubah.
+func (r *AchievementReferenceRepository) RepairState(mongoID string, expected models.AchievementState, status string, deleted bool) error {
+query := `
+		UPDATE achievement_references
+		SET status = $1,
+		    deleted_at = CASE WHEN $2 THEN COALESCE(deleted_at, $3) ELSE NULL END,
+		    updated_at = $3
+		WHERE mongo_achievement_id = $4 AND status = $5 AND (deleted_at IS NOT NULL) = $6
+	`
+
+result, err := r.db.Exec(query, status, deleted, time.Now(), mongoID, expected.Status, expected.IsDeleted)
+if err != nil {
 return err
 }
+return requireAffected(result)
+}
 
 // FindByStudentIDs mencari achievement references berdasarkan multiple student_ids (FR-006)
 func (r *AchievementReferenceRepository) FindByStudentIDs(studentIDs []string, limit, offset int) ([]models.AchievementReferences, int64, error) {
@@ -208,11 +450,15 @@ placeholders += "$" + fmt.Sprintf("%d", i+1)
 args = append(args, id)
 }
 
+// Prestasi tim ikut ditampilkan untuk anggota yang sudah konfirmasi
 // Count total
 countQuery := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM achievement_references
-		WHERE student_id::text IN (%s) AND deleted_at IS NULL
+		WHERE (student_id::text IN (%[1]s) OR mongo_achievement_id IN (
+			SELECT mongo_achievement_id FROM achievement_members
+			WHERE status = 'confirmed' AND student_id::text IN (%[1]s)
+		)) AND deleted_at IS NULL
 	`, placeholders)
 
 var total int64
@@ -225,11 +471,15 @@ return nil, 0, err
 query := fmt.Sprintf(`
 		SELECT id, student_id, mongo_achievement_id, status, 
 		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages,
 		       deleted_at, created_at, updated_at
 		FROM achievement_references
-		WHERE student_id::text IN (%s) AND deleted_at IS NULL
+		WHERE (student_id::text IN (%[1]s) OR mongo_achievement_id IN (
+			SELECT mongo_achievement_id FROM achievement_members
+			WHERE status = 'confirmed' AND student_id::text IN (%[1]s)
+		)) AND deleted_at IS NULL
 		ORDER BY created_at DESC
-		LIMIT $%d OFFSET $%d
+		LIMIT $%[2]d OFFSET $%[3]d
 	`, placeholders, len(studentIDs)+1, len(studentIDs)+2)
 
 args = append(args, limit, offset)
@@ -251,6 +501,8 @@ err := rows.Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -264,12 +516,22 @@ references = append(references, ref)
 return references, total, nil
 }
 
-// UpdateVerification mengupdate status menjadi verified dan set verified_by, verified_at (FR-007)
+// UpdateVerification mengupdate status submitted menjadi verified dan set verified_by, verified_at (FR-007).
+// Mengembalikan ErrVersionConflict jika reference tidak lagi berstatus submitted.
 func (r *AchievementReferenceRepository) UpdateVerification(mongoID string, verifiedBy string, status string) error {
+return r.updateVerification(r.db, mongoID, verifiedBy, status)
+}
+
+// UpdateVerificationTx sama dengan UpdateVerification di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) UpdateVerificationTx(tx *sql.Tx, mongoID string, verifiedBy string, status string) error {
+return r.updateVerification(tx, mongoID, verifiedBy, status)
+}
+
+func (r *AchievementReferenceRepository) updateVerification(db sqlExecutor, mongoID string, verifiedBy string, status string) error {
 query := `
 		UPDATE achievement_references
 		SET status = $1, verified_by = $2, verified_at = $3, updated_at = $3
-		WHERE mongo_achievement_id = $4
+		WHERE mongo_achievement_id = $4 AND status = 'submitted'
 	`
 
 verifiedByUUID, err := parseUUID(verifiedBy)
@@ -277,16 +539,29 @@ if err != nil {
 return err
 }
 
-_, err = r.db.Exec(query, status, verifiedByUUID, time.Now(), mongoID)
+result, err := db.Exec(query, status, verifiedByUUID, time.Now(), mongoID)
+if err != nil {
 return err
 }
+return requireAffected(result)
+}
 
-// UpdateRejection mengupdate status menjadi rejected dan set rejection_note (FR-007)
+// UpdateRejection mengupdate status submitted menjadi rejected dan set rejection_note (FR-007).
+// Mengembalikan ErrVersionConflict jika reference tidak lagi berstatus submitted.
 func (r *AchievementReferenceRepository) UpdateRejection(mongoID string, verifiedBy string, rejectionNote string) error {
+return r.updateRejection(r.db, mongoID, verifiedBy, rejectionNote)
+}
+
+// UpdateRejectionTx sama dengan UpdateRejection di dalam transaksi (outbox)
+func (r *AchievementReferenceRepository) UpdateRejectionTx(tx *sql.Tx, mongoID string, verifiedBy string, rejectionNote string) error {
+return r.updateRejection(tx, mongoID, verifiedBy, rejectionNote)
+}
+
+func (r *AchievementReferenceRepository) updateRejection(db sqlExecutor, mongoID string, verifiedBy string, rejectionNote string) error {
 query := `
 		UPDATE achievement_references
 		SET status = 'rejected', verified_by = $1, verified_at = $2, rejection_note = $3, updated_at = $2
-		WHERE mongo_achievement_id = $4
+		WHERE mongo_achievement_id = $4 AND status = 'submitted'
 	`
 
 verifiedByUUID, err := parseUUID(verifiedBy)
@@ -294,9 +569,85 @@ if err != nil {
 return err
 }
 
-_, err = r.db.Exec(query, verifiedByUUID, time.Now(), rejectionNote, mongoID)
+result, err := db.Exec(query, verifiedByUUID, time.Now(), rejectionNote, mongoID)
+if err != nil {
 return err
 }
+return requireAffected(result)
+}
+
+// UpdateRevocation mencabut achievement terverifikasi dan mencatat alasannya dalam satu transaksi
+func (r *AchievementReferenceRepository) UpdateRevocation(mongoID string, revokedBy string, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := r.UpdateRevocationTx(tx, mongoID, revokedBy, reason); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateRevocationTx mencabut achievement terverifikasi di dalam transaksi yang sudah ada (outbox).
+// Mengembalikan sql.ErrNoRows jika achievement tidak berstatus verified.
+func (r *AchievementReferenceRepository) UpdateRevocationTx(tx *sql.Tx, mongoID string, revokedBy string, reason string) error {
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE achievement_references
+		SET status = 'revoked', updated_at = $1
+		WHERE mongo_achievement_id = $2 AND status = 'verified' AND deleted_at IS NULL
+	`, now, mongoID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO achievement_revocations
+		(id, mongo_achievement_id, previous_status, reason, revoked_by, revoked_at)
+		VALUES ($1, $2, 'verified', $3, $4, $5)
+	`, uuid.New(), mongoID, reason, revokedBy, now)
+	return err
+}
+
+// FindRevocation mencari catatan pencabutan terakhir sebuah achievement
+func (r *AchievementReferenceRepository) FindRevocation(mongoID string) (*models.AchievementRevocation, error) {
+	query := `
+		SELECT id, mongo_achievement_id, previous_status, reason, revoked_by, revoked_at
+		FROM achievement_revocations
+		WHERE mongo_achievement_id = $1
+		ORDER BY revoked_at DESC
+		LIMIT 1
+	`
+
+	var revocation models.AchievementRevocation
+	err := r.db.QueryRow(query, mongoID).Scan(
+		&revocation.ID,
+		&revocation.MongoAchievementID,
+		&revocation.PreviousStatus,
+		&revocation.Reason,
+		&revocation.RevokedBy,
+		&revocation.RevokedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &revocation, nil
+}
 
 // FindPendingVerification mencari achievement yang perlu diverifikasi (status: submitted) (FR-007)
 func (r *AchievementReferenceRepository) FindPendingVerification(limit, offset int) ([]models.AchievementReferences, int64, error) {
@@ -317,6 +668,7 @@ return nil, 0, err
 query := `
 		SELECT id, student_id, mongo_achievement_id, status, 
 		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages,
 		       deleted_at, created_at, updated_at
 		FROM achievement_references
 		WHERE status = 'submitted' AND deleted_at IS NULL
@@ -342,6 +694,8 @@ err := rows.Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -363,35 +717,64 @@ return nil, nil
 return &uuidStr, nil
 }
 
-// FindAllWithFilters mencari semua achievement references dengan filter dan sorting (FR-010)
-func (r *AchievementReferenceRepository) FindAllWithFilters(
-limit, offset int,
-statusFilter, studentIDFilter string,
-sortBy, sortOrder string,
-) ([]models.AchievementReferences, int64, error) {
+// FindAllWithFilters mencari semua achievement references dengan filter dan sorting (FR-010).
+// Filter mahasiswa (advisor, program studi, angkatan) dicocokkan lewat tabel students.
+func (r *AchievementReferenceRepository) FindAllWithFilters(filter models.AchievementListFilter, limit, offset int) ([]models.AchievementReferences, int64, error) {
 // Build WHERE clause
-whereClause := "WHERE deleted_at IS NULL"
+whereClause := "WHERE ar.deleted_at IS NULL"
 args := []interface{}{}
 argIndex := 1
 
-if statusFilter != "" {
-whereClause += fmt.Sprintf(" AND status = $%d", argIndex)
-args = append(args, statusFilter)
+addCondition := func(condition string, value interface{}) {
+whereClause += fmt.Sprintf(" AND "+condition, argIndex)
+args = append(args, value)
 argIndex++
 }
 
-if studentIDFilter != "" {
-whereClause += fmt.Sprintf(" AND student_id::text = $%d", argIndex)
-args = append(args, studentIDFilter)
-argIndex++
+if filter.Status != "" {
+addCondition("ar.status = $%d", filter.Status)
+}
+if filter.StudentID != "" {
+addCondition("ar.student_id::text = $%d", filter.StudentID)
+}
+if filter.AchievementIDs != nil {
+addCondition("ar.mongo_achievement_id = ANY($%d)", pq.Array(filter.AchievementIDs))
+}
+if filter.SubmittedFrom != nil {
+addCondition("ar.submitted_at >= $%d", *filter.SubmittedFrom)
+}
+if filter.SubmittedTo != nil {
+addCondition("ar.submitted_at < $%d", *filter.SubmittedTo)
+}
+if filter.VerifiedFrom != nil {
+addCondition("ar.verified_at >= $%d", *filter.VerifiedFrom)
+}
+if filter.VerifiedTo != nil {
+addCondition("ar.verified_at < $%d", *filter.VerifiedTo)
+}
+if filter.VerifiedBy != "" {
+addCondition("ar.verified_by::text = $%d", filter.VerifiedBy)
+}
+if filter.AdvisorID != "" {
+addCondition("s.advisor_id::text = $%d", filter.AdvisorID)
+}
+if filter.ProgramStudy != "" {
+addCondition("LOWER(s.program_study) = LOWER($%d)", filter.ProgramStudy)
 }
+if filter.AcademicYear != "" {
+addCondition("s.academic_year = $%d", filter.AcademicYear)
+}
+
+fromClause := `
+		FROM achievement_references ar
+		LEFT JOIN students s ON s.user_id = ar.student_id`
 
 // Count total
 countQuery := fmt.Sprintf(`
 		SELECT COUNT(*)
-		FROM achievement_references
 		%s
-	`, whereClause)
+		%s
+	`, fromClause, whereClause)
 
 var total int64
 err := r.db.QueryRow(countQuery, args...).Scan(&total)
@@ -399,34 +782,35 @@ if err != nil {
 return nil, 0, err
 }
 
-// Build ORDER BY clause
-orderByClause := "ORDER BY created_at DESC" // default
-if sortBy != "" {
+// Build ORDER BY clause; id sebagai pemecah seri agar halaman tidak tumpang tindih
+orderByClause := "ORDER BY ar.created_at DESC, ar.id DESC" // default
+if filter.SortBy != "" {
 validSortFields := map[string]bool{
 "created_at":   true,
 "submitted_at": true,
 "verified_at":  true,
 "updated_at":   true,
 }
-if validSortFields[sortBy] {
-order := "DESC"
-if sortOrder == "asc" {
-order = "ASC"
+if validSortFields[filter.SortBy] {
+order := "DESC NULLS LAST"
+if filter.SortOrder == "asc" {
+order = "ASC NULLS LAST"
 }
-orderByClause = fmt.Sprintf("ORDER BY %s %s", sortBy, order)
+orderByClause = fmt.Sprintf("ORDER BY ar.%s %s, ar.id DESC", filter.SortBy, order)
 }
 }
 
 // Get data with pagination
 query := fmt.Sprintf(`
-		SELECT id, student_id, mongo_achievement_id, status, 
-		       submitted_at, verified_at, verified_by, rejection_note,
-		       deleted_at, created_at, updated_at
-		FROM achievement_references
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, 
+		       ar.submitted_at, ar.verified_at, ar.verified_by, ar.rejection_note,
+		       ar.current_stage, ar.total_stages,
+		       ar.deleted_at, ar.created_at, ar.updated_at
+		%s
 		%s
 		%s
 		LIMIT $%d OFFSET $%d
-	`, whereClause, orderByClause, argIndex, argIndex+1)
+	`, fromClause, whereClause, orderByClause, argIndex, argIndex+1)
 
 args = append(args, limit, offset)
 rows, err := r.db.Query(query, args...)
@@ -447,6 +831,8 @@ err := rows.Scan(
 &ref.VerifiedAt,
 &ref.VerifiedBy,
 &ref.RejectionNote,
+&ref.CurrentStage,
+&ref.TotalStages,
 &ref.DeletedAt,
 &ref.CreatedAt,
 &ref.UpdatedAt,
@@ -460,8 +846,36 @@ references = append(references, ref)
 return references, total, nil
 }
 
-// GetTopStudents mencari top students berdasarkan jumlah achievement (FR-011)
-func (r *AchievementReferenceRepository) GetTopStudents(studentIDs []string, limit int) ([]models.TopStudent, error) {
+// creditedAchievementsCTE: satu baris per (mahasiswa, achievement) yang dikreditkan,
+// yaitu pemilik achievement dan anggota tim yang sudah konfirmasi (exclude revoked)
+const creditedAchievementsCTE = `credited AS (
+			SELECT ar.student_id::text AS student_id, ar.mongo_achievement_id, ar.status
+			FROM achievement_references ar
+			WHERE ar.deleted_at IS NULL AND ar.status <> 'revoked'
+			UNION
+			SELECT am.student_id::text AS student_id, ar.mongo_achievement_id, ar.status
+			FROM achievement_members am
+			INNER JOIN achievement_references ar ON ar.mongo_achievement_id = am.mongo_achievement_id
+			WHERE am.status = 'confirmed' AND ar.deleted_at IS NULL AND ar.status <> 'revoked'
+		)`
+
+// pointTotalsCTE: total poin per mahasiswa dari achievement_credit_points
+const pointTotalsCTE = `point_totals AS (
+			SELECT p.student_id::text AS student_id, SUM(p.points) AS total_points
+			FROM achievement_credit_points p
+			GROUP BY p.student_id
+		)`
+
+// topStudentsOrder menentukan urutan ranking top students ("points" atau default jumlah achievement)
+func topStudentsOrder(rankBy string) string {
+	if rankBy == "points" {
+		return "total_points DESC, total_achievements DESC"
+	}
+	return "total_achievements DESC, verified_achievements DESC"
+}
+
+// GetTopStudents mencari top students berdasarkan jumlah achievement atau total poin (FR-011)
+func (r *AchievementReferenceRepository) GetTopStudents(studentIDs []string, limit int, rankBy string) ([]models.TopStudent, error) {
 	if len(studentIDs) == 0 {
 		return []models.TopStudent{}, nil
 	}
@@ -477,19 +891,23 @@ func (r *AchievementReferenceRepository) GetTopStudents(studentIDs []string, lim
 		args = append(args, id)
 	}
 
+	// Prestasi tim dihitung untuk setiap anggota yang sudah konfirmasi
 	query := fmt.Sprintf(`
+		WITH %s, %s
 		SELECT 
-			ar.student_id,
+			c.student_id,
 			u.full_name as student_name,
 			COUNT(*) as total_achievements,
-			COUNT(CASE WHEN ar.status = 'verified' THEN 1 END) as verified_achievements
-		FROM achievement_references ar
-		INNER JOIN users u ON ar.student_id::text = u.id
-		WHERE ar.student_id::text IN (%s) AND ar.deleted_at IS NULL
-		GROUP BY ar.student_id, u.full_name
-		ORDER BY total_achievements DESC, verified_achievements DESC
+			COUNT(CASE WHEN c.status = 'verified' THEN 1 END) as verified_achievements,
+			COALESCE(MAX(pt.total_points), 0) as total_points
+		FROM credited c
+		INNER JOIN users u ON u.id::text = c.student_id
+		LEFT JOIN point_totals pt ON pt.student_id = c.student_id
+		WHERE c.student_id IN (%s)
+		GROUP BY c.student_id, u.full_name
+		ORDER BY %s
 		LIMIT $%d
-	`, placeholders, len(studentIDs)+1)
+	`, creditedAchievementsCTE, pointTotalsCTE, placeholders, topStudentsOrder(rankBy), len(studentIDs)+1)
 
 	args = append(args, limit)
 	rows, err := r.db.Query(query, args...)
@@ -506,6 +924,7 @@ func (r *AchievementReferenceRepository) GetTopStudents(studentIDs []string, lim
 			&student.StudentName,
 			&student.TotalAchievements,
 			&student.VerifiedAchievements,
+			&student.TotalPoints,
 		)
 		if err != nil {
 			return nil, err
@@ -516,19 +935,21 @@ func (r *AchievementReferenceRepository) GetTopStudents(studentIDs []string, lim
 	return topStudents, nil
 }
 
-// GetAllTopStudents mencari top students dari semua data (FR-011)
-func (r *AchievementReferenceRepository) GetAllTopStudents(limit int) ([]models.TopStudent, error) {
+// GetAllTopStudents mencari top students dari semua data berdasarkan jumlah achievement atau total poin (FR-011)
+func (r *AchievementReferenceRepository) GetAllTopStudents(limit int, rankBy string) ([]models.TopStudent, error) {
 	query := `
+		WITH ` + creditedAchievementsCTE + `, ` + pointTotalsCTE + `
 		SELECT 
-			ar.student_id,
+			c.student_id,
 			u.full_name as student_name,
 			COUNT(*) as total_achievements,
-			COUNT(CASE WHEN ar.status = 'verified' THEN 1 END) as verified_achievements
-		FROM achievement_references ar
-		INNER JOIN users u ON ar.student_id::text = u.id
-		WHERE ar.deleted_at IS NULL
-		GROUP BY ar.student_id, u.full_name
-		ORDER BY total_achievements DESC, verified_achievements DESC
+			COUNT(CASE WHEN c.status = 'verified' THEN 1 END) as verified_achievements,
+			COALESCE(MAX(pt.total_points), 0) as total_points
+		FROM credited c
+		INNER JOIN users u ON u.id::text = c.student_id
+		LEFT JOIN point_totals pt ON pt.student_id = c.student_id
+		GROUP BY c.student_id, u.full_name
+		ORDER BY ` + topStudentsOrder(rankBy) + `
 		LIMIT $1
 	`
 
@@ -546,6 +967,7 @@ func (r *AchievementReferenceRepository) GetAllTopStudents(limit int) ([]models.
 			&student.StudentName,
 			&student.TotalAchievements,
 			&student.VerifiedAchievements,
+			&student.TotalPoints,
 		)
 		if err != nil {
 			return nil, err
@@ -554,4 +976,51 @@ func (r *AchievementReferenceRepository) GetAllTopStudents(limit int) ([]models.
 	}
 
 	return topStudents, nil
-}
\ No newline at end of file
+}
+// FindStagePendingBefore mencari achievement submitted yang sudah menunggu di tahap verifikasi saat ini
+// sejak sebelum cutoff (SLA verifikasi)
+func (r *AchievementReferenceRepository) FindStagePendingBefore(cutoff time.Time) ([]models.AchievementReferences, error) {
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status,
+		       submitted_at, verified_at, verified_by, rejection_note,
+		       current_stage, total_stages, COALESCE(stage_entered_at, submitted_at),
+		       deleted_at, created_at, updated_at
+		FROM achievement_references
+		WHERE status = 'submitted' AND deleted_at IS NULL
+		  AND COALESCE(stage_entered_at, submitted_at) < $1
+		ORDER BY COALESCE(stage_entered_at, submitted_at) ASC
+	`
+
+	rows, err := r.db.Query(query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var references []models.AchievementReferences
+	for rows.Next() {
+		var ref models.AchievementReferences
+		err := rows.Scan(
+			&ref.ID,
+			&ref.StudentID,
+			&ref.MongoAchievementID,
+			&ref.Status,
+			&ref.SubmittedAt,
+			&ref.VerifiedAt,
+			&ref.VerifiedBy,
+			&ref.RejectionNote,
+			&ref.CurrentStage,
+			&ref.TotalStages,
+			&ref.StageEnteredAt,
+			&ref.DeletedAt,
+			&ref.CreatedAt,
+			&ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	return references, nil
+}
@@ -8,6 +8,7 @@ models "crud-app/app/model"
 "go.mongodb.org/mongo-driver/bson"
 "go.mongodb.org/mongo-driver/bson/primitive"
 "go.mongodb.org/mongo-driver/mongo"
+"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type AchievementRepository struct {
@@ -23,6 +24,7 @@ collection: db.Collection("achievements"),
 // Create menyimpan achievement baru ke MongoDB
 func (r *AchievementRepository) Create(ctx context.Context, achievement *models.Achievement) error {
 achievement.ID = primitive.NewObjectID()
+achievement.Version = 1
 achievement.CreatedAt = time.Now()
 achievement.UpdatedAt = time.Now()
 
@@ -30,6 +32,80 @@ _, err := r.collection.InsertOne(ctx, achievement)
 return err
 }
 
+// outboxSeqFilter hanya cocok dengan dokumen yang belum menerapkan event outbox seq (atau yang lebih baru),
+// sehingga event yang diterapkan ulang atau terlambat tidak mengubah apa pun
+func outboxSeqFilter(achievementID string, seq int64) bson.M {
+return bson.M{
+"achievement_id": achievementID,
+"$or": []bson.M{
+{"outbox_seq": bson.M{"$exists": false}},
+{"outbox_seq": bson.M{"$lt": seq}},
+},
+}
+}
+
+// CreateFromOutbox menyimpan achievement dari event outbox secara idempotent:
+// dokumen hanya dibuat jika achievement_id belum ada
+func (r *AchievementRepository) CreateFromOutbox(ctx context.Context, achievement *models.Achievement, seq int64) error {
+achievement.OutboxSeq = seq
+update := bson.M{"$setOnInsert": achievement}
+
+_, err := r.collection.UpdateOne(ctx, bson.M{"achievement_id": achievement.AchievementID}, update, options.Update().SetUpsert(true))
+return err
+}
+
+// ApplyOutboxChange menerapkan $set/$unset dari event outbox seq dan menaikkan versi.
+// Event yang sudah diterapkan (atau lebih lama dari event terakhir) diabaikan; mengembalikan
+// mongo.ErrNoDocuments jika achievement belum ada di MongoDB. expected (opsional) adalah syarat
+// tambahan atas dokumen, misalnya status sebelum transisi; ErrVersionConflict jika dokumen belum
+// menerapkan event ini tetapi tidak memenuhi syarat tersebut.
+func (r *AchievementRepository) ApplyOutboxChange(ctx context.Context, achievementID string, seq int64, expected bson.M, set bson.M, unset bson.M) error {
+fields := bson.M{"outbox_seq": seq, "updated_at": time.Now()}
+for key, value := range set {
+fields[key] = value
+}
+update := bson.M{
+"$set": fields,
+"$inc": bson.M{"version": 1},
+}
+if len(unset) > 0 {
+update["$unset"] = unset
+}
+
+filter := outboxSeqFilter(achievementID, seq)
+for key, value := range expected {
+filter[key] = value
+}
+
+result, err := r.collection.UpdateOne(ctx, filter, update)
+if err != nil {
+return err
+}
+if result.MatchedCount > 0 {
+return nil
+}
+
+// Tidak cocok: event sudah diterapkan, dokumennya belum ada (harus dicoba lagi),
+// atau dokumen tidak dalam keadaan yang diharapkan
+count, err := r.collection.CountDocuments(ctx, bson.M{"achievement_id": achievementID})
+if err != nil {
+return err
+}
+if count == 0 {
+return mongo.ErrNoDocuments
+}
+if len(expected) > 0 {
+pending, err := r.collection.CountDocuments(ctx, outboxSeqFilter(achievementID, seq))
+if err != nil {
+return err
+}
+if pending > 0 {
+return ErrVersionConflict
+}
+}
+return nil
+}
+
 // FindByID mencari achievement berdasarkan achievement_id (exclude deleted)
 func (r *AchievementRepository) FindByID(ctx context.Context, achievementID string) (*models.Achievement, error) {
 var achievement models.Achievement
@@ -46,11 +122,12 @@ return nil, err
 return &achievement, nil
 }
 
-// FindByStudentID mencari semua achievement berdasarkan student_id (exclude deleted)
+// FindByStudentID mencari semua achievement milik student_id, termasuk prestasi tim
+// di mana student tersebut anggota yang sudah konfirmasi (exclude deleted)
 func (r *AchievementRepository) FindByStudentID(ctx context.Context, studentID string) ([]models.Achievement, error) {
 var achievements []models.Achievement
 filter := bson.M{
-"student_id": studentID,
+"$or":        creditedStudentFilter(studentID),
 "is_deleted": false,
 }
 
@@ -67,24 +144,83 @@ return nil, err
 return achievements, nil
 }
 
-// Update mengupdate achievement
+// Update mengupdate achievement jika versinya masih sama dengan achievement.Version
+// (optimistic concurrency); versi dinaikkan, ErrVersionConflict jika sudah diubah pihak lain
 func (r *AchievementRepository) Update(ctx context.Context, achievementID string, achievement *models.Achievement) error {
-achievement.UpdatedAt = time.Now()
+filter := versionFilter(achievementID, achievement.Version)
+
+updated := *achievement
+updated.Version = achievement.Version + 1
+updated.UpdatedAt = time.Now()
+update := bson.M{"$set": &updated}
+// details bertag omitempty: details yang dikosongkan harus di-unset secara eksplisit
+unset := bson.M{}
+if len(achievement.Details) == 0 {
+unset["details"] = ""
+}
+if len(achievement.Tags) == 0 {
+unset["tags"] = ""
+}
+if len(unset) > 0 {
+update["$unset"] = unset
+}
+
+result, err := r.collection.UpdateOne(ctx, filter, update)
+if err != nil {
+return err
+}
+if result.MatchedCount == 0 {
+return ErrVersionConflict
+}
+
+*achievement = updated
+return nil
+}
+
+// versionFilter: achievement lama tanpa field version dianggap versi 0
+func versionFilter(achievementID string, version int) bson.M {
+if version == 0 {
+return bson.M{
+"achievement_id": achievementID,
+"is_deleted":     false,
+"$or": bson.A{
+bson.M{"version": 0},
+bson.M{"version": bson.M{"$exists": false}},
+},
+}
+}
+return bson.M{
+"achievement_id": achievementID,
+"is_deleted":     false,
+"version":        version,
+}
+}
+
+// UpdateMembers mengupdate daftar anggota tim achievement
+func (r *AchievementRepository) UpdateMembers(ctx context.Context, achievementID string, members []models.AchievementMember) error {
 filter := bson.M{"achievement_id": achievementID}
-update := bson.M{"$set": achievement}
+update := bson.M{
+"$set": bson.M{
+"members":    members,
+"updated_at": time.Now(),
+},
+"$inc": bson.M{"version": 1},
+}
 
 _, err := r.collection.UpdateOne(ctx, filter, update)
 return err
 }
 
-// UpdateStatus mengupdate status achievement
-func (r *AchievementRepository) UpdateStatus(ctx context.Context, achievementID string, status string) error {
+// UpdateMasterData mengupdate category dan level achievement ke code kanonik master data
+func (r *AchievementRepository) UpdateMasterData(ctx context.Context, achievementID string, category string, level string) error {
 filter := bson.M{"achievement_id": achievementID}
 update := bson.M{
 "$set": bson.M{
-"status":     status,
+"category":   category,
+"level":      level,
 "updated_at": time.Now(),
 },
+"$inc": bson.M{"version": 1},
 }
 
 _, err := r.collection.UpdateOne(ctx, filter, update)
@@ -111,12 +247,276 @@ update := bson.M{
 "deleted_at": now,
 "updated_at": now,
 },
+"$inc": bson.M{"version": 1},
 }
 
 _, err := r.collection.UpdateOne(ctx, filter, update)
 return err
 }
 
+// FindDeleted mencari achievement yang sudah di-soft delete (trash), terbaru lebih dulu
+func (r *AchievementRepository) FindDeleted(ctx context.Context, limit, offset int) ([]models.Achievement, int64, error) {
+filter := bson.M{"is_deleted": true}
+
+total, err := r.collection.CountDocuments(ctx, filter)
+if err != nil {
+return nil, 0, err
+}
+
+opts := options.Find().
+SetSort(bson.D{{Key: "deleted_at", Value: -1}}).
+SetSkip(int64(offset)).
+SetLimit(int64(limit))
+
+cursor, err := r.collection.Find(ctx, filter, opts)
+if err != nil {
+return nil, 0, err
+}
+defer cursor.Close(ctx)
+
+achievements := []models.Achievement{}
+if err := cursor.All(ctx, &achievements); err != nil {
+return nil, 0, err
+}
+
+return achievements, total, nil
+}
+
+// FindDeletedByID mencari achievement di trash berdasarkan achievement_id
+func (r *AchievementRepository) FindDeletedByID(ctx context.Context, achievementID string) (*models.Achievement, error) {
+var achievement models.Achievement
+filter := bson.M{
+"achievement_id": achievementID,
+"is_deleted":     true,
+}
+
+err := r.collection.FindOne(ctx, filter).Decode(&achievement)
+if err != nil {
+return nil, err
+}
+
+return &achievement, nil
+}
+
+// FindDeletedBefore mencari achievement di trash yang dihapus sebelum cutoff (untuk purge)
+func (r *AchievementRepository) FindDeletedBefore(ctx context.Context, cutoff time.Time) ([]models.Achievement, error) {
+filter := bson.M{
+"is_deleted": true,
+"deleted_at": bson.M{"$lt": cutoff},
+}
+
+cursor, err := r.collection.Find(ctx, filter)
+if err != nil {
+return nil, err
+}
+defer cursor.Close(ctx)
+
+var achievements []models.Achievement
+if err := cursor.All(ctx, &achievements); err != nil {
+return nil, err
+}
+
+return achievements, nil
+}
+
+// Restore mengembalikan achievement dari trash. Mengembalikan mongo.ErrNoDocuments
+// jika achievement tidak ada di trash.
+func (r *AchievementRepository) Restore(ctx context.Context, achievementID string) error {
+filter := bson.M{
+"achievement_id": achievementID,
+"is_deleted":     true,
+}
+update := bson.M{
+"$set":   bson.M{"is_deleted": false, "updated_at": time.Now()},
+"$unset": bson.M{"deleted_at": ""},
+"$inc":   bson.M{"version": 1},
+}
+
+result, err := r.collection.UpdateOne(ctx, filter, update)
+if err != nil {
+return err
+}
+if result.MatchedCount == 0 {
+return mongo.ErrNoDocuments
+}
+return nil
+}
+
+// FindAllStates mengambil ringkasan status semua achievement, termasuk yang terhapus (consistency checker)
+func (r *AchievementRepository) FindAllStates(ctx context.Context) ([]models.AchievementState, error) {
+opts := options.Find().SetProjection(bson.M{"achievement_id": 1, "student_id": 1, "status": 1, "is_deleted": 1})
+
+cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+if err != nil {
+return nil, err
+}
+defer cursor.Close(ctx)
+
+var docs []models.Achievement
+if err := cursor.All(ctx, &docs); err != nil {
+return nil, err
+}
+
+states := make([]models.AchievementState, len(docs))
+for i, doc := range docs {
+states[i] = models.AchievementState{
+AchievementID: doc.AchievementID,
+StudentID:     doc.StudentID,
+Status:        doc.Status,
+IsDeleted:     doc.IsDeleted,
+}
+}
+return states, nil
+}
+
+// FindState mengambil status dan tanda terhapus satu achievement, termasuk yang terhapus (nil jika tidak ada)
+func (r *AchievementRepository) FindState(ctx context.Context, achievementID string) (*models.AchievementState, error) {
+opts := options.FindOne().SetProjection(bson.M{"achievement_id": 1, "student_id": 1, "status": 1, "is_deleted": 1})
+
+var doc models.Achievement
+err := r.collection.FindOne(ctx, bson.M{"achievement_id": achievementID}, opts).Decode(&doc)
+if err == mongo.ErrNoDocuments {
+return nil, nil
+}
+if err != nil {
+return nil, err
+}
+
+return &models.AchievementState{
+AchievementID: doc.AchievementID,
+StudentID:     doc.StudentID,
+Status:        doc.Status,
+IsDeleted:     doc.IsDeleted,
+}, nil
+}
+
+// RepairState menyamakan status dan tanda terhapus achievement dengan store lain (consistency repair).
+// Hanya berlaku jika dokumen masih dalam keadaan expected; ErrVersionConflict jika sudah berubah.
+func (r *AchievementRepository) RepairState(ctx context.Context, achievementID string, expected models.AchievementState, status string, deleted bool) error {
+now := time.Now()
+update := bson.M{
+"$set": bson.M{"status": status, "is_deleted": deleted, "updated_at": now},
+"$inc": bson.M{"version": 1},
+}
+if deleted {
+update["$set"].(bson.M)["deleted_at"] = now
+} else {
+update["$unset"] = bson.M{"deleted_at": ""}
+}
+
+filter := bson.M{
+"achievement_id": achievementID,
+"status":         expected.Status,
+"is_deleted":     expected.IsDeleted,
+}
+result, err := r.collection.UpdateOne(ctx, filter, update)
+if err != nil {
+return err
+}
+if result.MatchedCount == 0 {
+return ErrVersionConflict
+}
+return nil
+}
+
+// EnsureSearchIndex membuat text index pencarian achievement (title, description, tags).
+// Bahasa "none": tanpa stemming/stopword karena konten mayoritas berbahasa Indonesia.
+func (r *AchievementRepository) EnsureSearchIndex(ctx context.Context) error {
+index := mongo.IndexModel{
+Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}},
+Options: options.Index().
+SetName("achievement_search").
+SetWeights(bson.M{"title": 10, "tags": 5, "description": 1}).
+SetDefaultLanguage("none"),
+}
+
+_, err := r.collection.Indexes().CreateOne(ctx, index)
+return err
+}
+
+// searchFacetBucket satu bucket hasil $group pada facet pencarian
+type searchFacetBucket struct {
+Value string `bson:"_id"`
+Count int    `bson:"count"`
+}
+
+// Search mencari achievement dengan filter yang berisi $text, diurutkan berdasarkan relevansi.
+// Facet dihitung dari seluruh hasil yang cocok, bukan hanya halaman ini.
+func (r *AchievementRepository) Search(ctx context.Context, filter bson.M, limit, offset int) (*models.AchievementSearchResult, error) {
+facetGroup := func(field interface{}) bson.A {
+return bson.A{
+bson.M{"$group": bson.M{"_id": field, "count": bson.M{"$sum": 1}}},
+bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+}
+}
+
+pipeline := bson.A{
+bson.M{"$match": filter},
+bson.M{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+bson.M{"$facet": bson.M{
+"hits": bson.A{
+bson.M{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "date", Value: -1}, {Key: "achievement_id", Value: 1}}},
+bson.M{"$skip": offset},
+bson.M{"$limit": limit},
+},
+"total":    bson.A{bson.M{"$count": "count"}},
+"category": facetGroup("$category"),
+"level":    facetGroup("$level"),
+"status":   facetGroup("$status"),
+"year":     facetGroup(bson.M{"$toString": bson.M{"$year": "$date"}}),
+}},
+}
+
+cursor, err := r.collection.Aggregate(ctx, pipeline)
+if err != nil {
+return nil, err
+}
+defer cursor.Close(ctx)
+
+var results []struct {
+Hits []struct {
+models.Achievement `bson:",inline"`
+Score float64 `bson:"score"`
+} `bson:"hits"`
+Total    []struct{ Count int64 `bson:"count"` } `bson:"total"`
+Category []searchFacetBucket                     `bson:"category"`
+Level    []searchFacetBucket                     `bson:"level"`
+Status   []searchFacetBucket                     `bson:"status"`
+Year     []searchFacetBucket                     `bson:"year"`
+}
+if err := cursor.All(ctx, &results); err != nil {
+return nil, err
+}
+
+result := &models.AchievementSearchResult{Hits: []models.AchievementSearchHit{}}
+if len(results) == 0 {
+return result, nil
+}
+
+facet := results[0]
+for _, hit := range facet.Hits {
+result.Hits = append(result.Hits, models.AchievementSearchHit{Achievement: hit.Achievement, Score: hit.Score})
+}
+if len(facet.Total) > 0 {
+result.Total = facet.Total[0].Count
+}
+result.Facets = models.SearchFacets{
+Category: facetCounts(facet.Category),
+Level:    facetCounts(facet.Level),
+Status:   facetCounts(facet.Status),
+Year:     facetCounts(facet.Year),
+}
+return result, nil
+}
+
+func facetCounts(buckets []searchFacetBucket) []models.FacetCount {
+counts := make([]models.FacetCount, len(buckets))
+for i, bucket := range buckets {
+counts[i] = models.FacetCount{Value: bucket.Value, Count: bucket.Count}
+}
+return counts
+}
+
 // FindAll mencari semua achievement dengan filter
 func (r *AchievementRepository) FindAll(ctx context.Context, filter bson.M) ([]models.Achievement, error) {
 var achievements []models.Achievement
@@ -158,7 +558,7 @@ return achievements, nil
 // GetStatisticsByStudentIDs - Get statistics untuk multiple students (FR-011)
 func (r *AchievementRepository) GetStatisticsByStudentIDs(ctx context.Context, studentIDs []string) (map[string]interface{}, error) {
 	filter := bson.M{
-		"student_id": bson.M{"$in": studentIDs},
+		"$or":        creditedStudentFilter(bson.M{"$in": studentIDs}),
 		"is_deleted": false,
 	}
 
@@ -186,6 +586,12 @@ func (r *AchievementRepository) GetStatisticsByStudentIDs(ctx context.Context, s
 	periodCount := make(map[string]int)
 
 	for _, achievement := range achievements {
+		// Achievement yang dicabut tidak dihitung di statistik
+		if achievement.Status == "revoked" {
+			totalAchievements--
+			continue
+		}
+
 		// Count by status
 		switch achievement.Status {
 		case "verified":
@@ -223,4 +629,15 @@ func (r *AchievementRepository) GetStatisticsByStudentIDs(ctx context.Context, s
 	stats["period_count"] = periodCount
 
 	return stats, nil
+}
+
+// creditedStudentFilter mencocokkan pemilik achievement atau anggota tim yang sudah konfirmasi
+func creditedStudentFilter(studentMatch interface{}) bson.A {
+	return bson.A{
+		bson.M{"student_id": studentMatch},
+		bson.M{"members": bson.M{"$elemMatch": bson.M{
+			"student_id": studentMatch,
+			"status":     "confirmed",
+		}}},
+	}
 }
\ No newline at end of file
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"strings"
+)
+
+type AchievementSchemaRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementSchemaRepository(db *sql.DB) *AchievementSchemaRepository {
+	return &AchievementSchemaRepository{db: db}
+}
+
+// FindAll mencari semua schema detail achievement
+func (r *AchievementSchemaRepository) FindAll() ([]models.AchievementCategorySchema, error) {
+	query := `
+		SELECT category, schema, updated_by, updated_at
+		FROM achievement_category_schemas
+		ORDER BY category ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var schemas []models.AchievementCategorySchema
+	for rows.Next() {
+		var schema models.AchievementCategorySchema
+		if err := rows.Scan(&schema.Category, &schema.Schema, &schema.UpdatedBy, &schema.UpdatedAt); err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, schema)
+	}
+
+	return schemas, nil
+}
+
+// FindByCategory mencari schema sebuah kategori (case-insensitive), nil jika tidak ada
+func (r *AchievementSchemaRepository) FindByCategory(category string) (*models.AchievementCategorySchema, error) {
+	query := `
+		SELECT category, schema, updated_by, updated_at
+		FROM achievement_category_schemas
+		WHERE category = $1
+	`
+
+	var schema models.AchievementCategorySchema
+	err := r.db.QueryRow(query, strings.ToLower(category)).Scan(&schema.Category, &schema.Schema, &schema.UpdatedBy, &schema.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
+
+// Upsert membuat atau mengganti schema sebuah kategori
+func (r *AchievementSchemaRepository) Upsert(schema *models.AchievementCategorySchema) error {
+	query := `
+		INSERT INTO achievement_category_schemas (category, schema, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (category) DO UPDATE
+		SET schema = EXCLUDED.schema,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query, strings.ToLower(schema.Category), []byte(schema.Schema), schema.UpdatedBy, schema.UpdatedAt)
+	return err
+}
+
+// Delete menghapus schema sebuah kategori
+func (r *AchievementSchemaRepository) Delete(category string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM achievement_category_schemas WHERE category = $1`, strings.ToLower(category))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
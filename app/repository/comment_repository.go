@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentRepository struct {
+	collection *mongo.Collection
+}
+
+func NewCommentRepository(db *mongo.Database) *CommentRepository {
+	return &CommentRepository{
+		collection: db.Collection("achievement_comments"),
+	}
+}
+
+// Create menyimpan komentar baru
+func (r *CommentRepository) Create(ctx context.Context, comment *models.AchievementComment) error {
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+
+	_, err := r.collection.InsertOne(ctx, comment)
+	return err
+}
+
+// FindByID mencari komentar berdasarkan comment_id (termasuk yang sudah dihapus)
+func (r *CommentRepository) FindByID(ctx context.Context, commentID string) (*models.AchievementComment, error) {
+	var comment models.AchievementComment
+	err := r.collection.FindOne(ctx, bson.M{"comment_id": commentID}).Decode(&comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return &comment, nil
+}
+
+// FindByAchievementID mencari semua komentar sebuah achievement, urut dari yang terlama
+func (r *CommentRepository) FindByAchievementID(ctx context.Context, achievementID string) ([]models.AchievementComment, error) {
+	var comments []models.AchievementComment
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, bson.M{"achievement_id": achievementID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// UpdateContent mengupdate isi dan mentions komentar
+func (r *CommentRepository) UpdateContent(ctx context.Context, commentID string, content string, mentions []string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"content":    content,
+			"mentions":   mentions,
+			"edited_at":  now,
+			"updated_at": now,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"comment_id": commentID, "is_deleted": false}, update)
+	return err
+}
+
+// DeleteByAchievement menghapus permanen semua komentar sebuah achievement (purge trash)
+func (r *CommentRepository) DeleteByAchievement(ctx context.Context, achievementID string) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"achievement_id": achievementID})
+	return err
+}
+
+// SoftDelete menandai komentar sebagai dihapus; balasan tetap tersimpan
+func (r *CommentRepository) SoftDelete(ctx context.Context, commentID string) error {
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"is_deleted": true,
+			"deleted_at": now,
+			"updated_at": now,
+		},
+	}
+
+	_, err := r.collection.UpdateOne(ctx, bson.M{"comment_id": commentID, "is_deleted": false}, update)
+	return err
+}
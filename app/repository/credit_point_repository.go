@@ -0,0 +1,288 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+type CreditPointRepository struct {
+	db *sql.DB
+}
+
+func NewCreditPointRepository(db *sql.DB) *CreditPointRepository {
+	return &CreditPointRepository{db: db}
+}
+
+// FindVersions mencari semua versi aturan poin (terbaru lebih dulu)
+func (r *CreditPointRepository) FindVersions() ([]models.CreditRuleVersion, error) {
+	query := `
+		SELECT version, note, is_active, created_by, created_at, activated_at
+		FROM credit_rule_versions
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.CreditRuleVersion
+	for rows.Next() {
+		var version models.CreditRuleVersion
+		err := rows.Scan(
+			&version.Version,
+			&version.Note,
+			&version.IsActive,
+			&version.CreatedBy,
+			&version.CreatedAt,
+			&version.ActivatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
+
+// FindVersion mencari versi aturan poin beserta aturannya, nil jika tidak ada
+func (r *CreditPointRepository) FindVersion(versionNumber int) (*models.CreditRuleVersion, error) {
+	return r.findVersion(`WHERE version = $1`, versionNumber)
+}
+
+// FindActiveVersion mencari versi aturan poin yang aktif beserta aturannya, nil jika tidak ada
+func (r *CreditPointRepository) FindActiveVersion() (*models.CreditRuleVersion, error) {
+	return r.findVersion(`WHERE is_active = TRUE`)
+}
+
+func (r *CreditPointRepository) findVersion(where string, args ...interface{}) (*models.CreditRuleVersion, error) {
+	query := `
+		SELECT version, note, is_active, created_by, created_at, activated_at
+		FROM credit_rule_versions
+	` + where
+
+	var version models.CreditRuleVersion
+	err := r.db.QueryRow(query, args...).Scan(
+		&version.Version,
+		&version.Note,
+		&version.IsActive,
+		&version.CreatedBy,
+		&version.CreatedAt,
+		&version.ActivatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := r.findRules(version.Version)
+	if err != nil {
+		return nil, err
+	}
+	version.Rules = rules
+
+	return &version, nil
+}
+
+func (r *CreditPointRepository) findRules(versionNumber int) ([]models.CreditPointRule, error) {
+	query := `
+		SELECT id, version, category, level, rank, points
+		FROM credit_point_rules
+		WHERE version = $1
+		ORDER BY category ASC, level ASC, rank ASC
+	`
+
+	rows, err := r.db.Query(query, versionNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.CreditPointRule{}
+	for rows.Next() {
+		var rule models.CreditPointRule
+		if err := rows.Scan(&rule.ID, &rule.Version, &rule.Category, &rule.Level, &rule.Rank, &rule.Points); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// CreateVersion menyimpan versi aturan baru (belum aktif) beserta aturannya dalam satu transaksi
+func (r *CreditPointRepository) CreateVersion(version *models.CreditRuleVersion) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRow(`
+		INSERT INTO credit_rule_versions (note, is_active, created_by, created_at)
+		VALUES ($1, FALSE, $2, $3)
+		RETURNING version
+	`, version.Note, version.CreatedBy, version.CreatedAt).Scan(&version.Version)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO credit_point_rules (id, version, category, level, rank, points)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for i := range version.Rules {
+		rule := &version.Rules[i]
+		rule.Version = version.Version
+		if _, err := tx.Exec(query, rule.ID, rule.Version, rule.Category, rule.Level, rule.Rank, rule.Points); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ActivateVersion menjadikan sebuah versi sebagai satu-satunya versi aktif
+func (r *CreditPointRepository) ActivateVersion(versionNumber int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE credit_rule_versions SET is_active = FALSE WHERE is_active = TRUE`); err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE credit_rule_versions SET is_active = TRUE, activated_at = $1
+		WHERE version = $2
+	`, time.Now(), versionNumber)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// ReplaceAchievementPoints mengganti poin sebuah achievement untuk semua mahasiswa yang dikreditkan
+func (r *CreditPointRepository) ReplaceAchievementPoints(mongoID string, points []models.AchievementCreditPoint) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM achievement_credit_points WHERE mongo_achievement_id = $1`, mongoID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_credit_points
+		(mongo_achievement_id, student_id, points, rule_version, rule_id, computed_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, point := range points {
+		_, err := tx.Exec(query, mongoID, point.StudentID, point.Points, point.RuleVersion, point.RuleID, point.ComputedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// DeleteAchievementPoints menghapus poin sebuah achievement (misalnya saat dicabut)
+func (r *CreditPointRepository) DeleteAchievementPoints(mongoID string) error {
+	_, err := r.db.Exec(`DELETE FROM achievement_credit_points WHERE mongo_achievement_id = $1`, mongoID)
+	return err
+}
+
+// FindPointsByStudent mencari semua poin seorang mahasiswa
+func (r *CreditPointRepository) FindPointsByStudent(studentID string) ([]models.AchievementCreditPoint, error) {
+	query := `
+		SELECT mongo_achievement_id, student_id, points, rule_version, rule_id, computed_at
+		FROM achievement_credit_points
+		WHERE student_id::text = $1
+		ORDER BY computed_at DESC
+	`
+
+	rows, err := r.db.Query(query, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []models.AchievementCreditPoint{}
+	for rows.Next() {
+		var point models.AchievementCreditPoint
+		err := rows.Scan(
+			&point.MongoAchievementID,
+			&point.StudentID,
+			&point.Points,
+			&point.RuleVersion,
+			&point.RuleID,
+			&point.ComputedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// SumPointsByCohort menghitung total poin per mahasiswa dalam satu angkatan (opsional per program studi)
+func (r *CreditPointRepository) SumPointsByCohort(academicYear, programStudy string) ([]models.StudentPointTotal, error) {
+	query := `
+		SELECT s.user_id, s.student_id, u.full_name, s.program_study, s.academic_year,
+		       COALESCE(SUM(p.points), 0) AS total_points,
+		       COUNT(p.mongo_achievement_id) AS achievement_count
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		LEFT JOIN achievement_credit_points p ON p.student_id::text = s.user_id::text
+		WHERE s.academic_year = $1 AND ($2 = '' OR s.program_study = $2)
+		GROUP BY s.user_id, s.student_id, u.full_name, s.program_study, s.academic_year
+		ORDER BY total_points DESC, u.full_name ASC
+	`
+
+	rows, err := r.db.Query(query, academicYear, programStudy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []models.StudentPointTotal{}
+	for rows.Next() {
+		var total models.StudentPointTotal
+		err := rows.Scan(
+			&total.StudentID,
+			&total.StudentNumber,
+			&total.StudentName,
+			&total.ProgramStudy,
+			&total.AcademicYear,
+			&total.TotalPoints,
+			&total.AchievementCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+		totals = append(totals, total)
+	}
+
+	return totals, nil
+}
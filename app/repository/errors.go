@@ -0,0 +1,16 @@
+package repository
+
+import "errors"
+
+// ErrVersionConflict dikembalikan oleh update bersyarat ketika resource sudah diubah
+// pihak lain (versi atau status tidak lagi sesuai dengan yang diharapkan)
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrStillReferenced dikembalikan oleh hard delete ketika data masih direferensikan
+// oleh tabel lain (foreign key), sehingga tidak bisa dihapus permanen
+var ErrStillReferenced = errors.New("record still referenced")
+
+// ErrInvalidCursor dikembalikan listing keyset ketika cursor dibuat untuk urutan lain
+// (misalnya sort_by berubah di antara halaman) atau nilainya tidak cocok dengan tipe kolom
+// (cursor diubah client)
+var ErrInvalidCursor = errors.New("invalid cursor")
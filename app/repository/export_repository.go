@@ -0,0 +1,129 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+type ExportRepository struct {
+	db *sql.DB
+}
+
+func NewExportRepository(db *sql.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// Create menyimpan job export baru
+func (r *ExportRepository) Create(job *models.ExportJob) error {
+	query := `
+		INSERT INTO export_jobs (id, user_id, kind, format, status, filename, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(query, job.ID, job.UserID, job.Kind, job.Format, job.Status, job.Filename, job.CreatedAt, job.ExpiresAt)
+	return err
+}
+
+// FindByID mencari job export; nil jika tidak ada
+func (r *ExportRepository) FindByID(id string) (*models.ExportJob, error) {
+	query := `
+		SELECT id, user_id, kind, format, status, filename, file_path, row_count, file_size,
+		       error, created_at, finished_at, expires_at
+		FROM export_jobs
+		WHERE id::text = $1
+	`
+
+	var job models.ExportJob
+	err := r.db.QueryRow(query, id).Scan(
+		&job.ID,
+		&job.UserID,
+		&job.Kind,
+		&job.Format,
+		&job.Status,
+		&job.Filename,
+		&job.FilePath,
+		&job.RowCount,
+		&job.FileSize,
+		&job.Error,
+		&job.CreatedAt,
+		&job.FinishedAt,
+		&job.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// MarkRunning mengubah status job menjadi running dan mencatat lokasi file
+func (r *ExportRepository) MarkRunning(id, filePath string) error {
+	_, err := r.db.Exec(`
+		UPDATE export_jobs SET status = $1, file_path = $2 WHERE id::text = $3
+	`, models.ExportJobRunning, filePath, id)
+	return err
+}
+
+// MarkCompleted menyimpan hasil export yang berhasil
+func (r *ExportRepository) MarkCompleted(id string, rowCount int, fileSize int64) error {
+	_, err := r.db.Exec(`
+		UPDATE export_jobs
+		SET status = $1, row_count = $2, file_size = $3, finished_at = $4
+		WHERE id::text = $5
+	`, models.ExportJobCompleted, rowCount, fileSize, time.Now(), id)
+	return err
+}
+
+// MarkFailed menyimpan pesan kegagalan export
+func (r *ExportRepository) MarkFailed(id, message string) error {
+	_, err := r.db.Exec(`
+		UPDATE export_jobs
+		SET status = $1, error = $2, finished_at = $3
+		WHERE id::text = $4
+	`, models.ExportJobFailed, message, time.Now(), id)
+	return err
+}
+
+// FailInterrupted menandai job yang masih pending/running sebagai gagal (dipanggil saat server start)
+func (r *ExportRepository) FailInterrupted() (int64, error) {
+	result, err := r.db.Exec(`
+		UPDATE export_jobs
+		SET status = $1, error = $2, finished_at = $3
+		WHERE status IN ($4, $5)
+	`, models.ExportJobFailed, "Export terhenti karena server dimulai ulang", time.Now(), models.ExportJobPending, models.ExportJobRunning)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// FindExpired mencari job export yang sudah melewati masa berlaku
+func (r *ExportRepository) FindExpired(now time.Time) ([]models.ExportJob, error) {
+	rows, err := r.db.Query(`
+		SELECT id, file_path FROM export_jobs WHERE expires_at < $1
+	`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.ExportJob
+	for rows.Next() {
+		var job models.ExportJob
+		if err := rows.Scan(&job.ID, &job.FilePath); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+// Delete menghapus job export
+func (r *ExportRepository) Delete(id string) error {
+	_, err := r.db.Exec(`DELETE FROM export_jobs WHERE id::text = $1`, id)
+	return err
+}
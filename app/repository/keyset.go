@@ -0,0 +1,90 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// keysetOrder urutan stabil untuk pagination cursor: satu ekspresi sort ditambah ID sebagai pemecah seri.
+// Ekspresi sort tidak boleh NULL (bungkus kolom nullable dengan COALESCE) agar perbandingan baris berlaku.
+// Nilai cursor dikirim sebagai teks; PostgreSQL menyimpulkan tipenya dari kolom pembanding.
+type keysetOrder struct {
+	name     string // identitas urutan yang disimpan di cursor
+	sortExpr string
+	idExpr   string
+	desc     bool
+}
+
+// orderBy klausa ORDER BY; untuk halaman sebelumnya arahnya dibalik (hasil dibalik lagi oleh keysetPage)
+func (k keysetOrder) orderBy(backward bool) string {
+	direction := "ASC"
+	if k.desc != backward {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("ORDER BY %s %s, %s %s", k.sortExpr, direction, k.idExpr, direction)
+}
+
+// keyColumns kolom tambahan di SELECT untuk membentuk cursor dari setiap baris
+func (k keysetOrder) keyColumns() string {
+	return fmt.Sprintf("(%s)::text, (%s)::text", k.sortExpr, k.idExpr)
+}
+
+// condition kondisi WHERE untuk baris setelah (atau sebelum) cursor; argIndex nomor placeholder pertama
+func (k keysetOrder) condition(cursor *models.PageCursor, argIndex int) (string, []interface{}, error) {
+	if cursor.Sort != k.name {
+		return "", nil, ErrInvalidCursor
+	}
+
+	operator := ">"
+	if k.desc != cursor.Backward {
+		operator = "<"
+	}
+	condition := fmt.Sprintf("(%s, %s) %s ($%d, $%d)", k.sortExpr, k.idExpr, operator, argIndex, argIndex+1)
+	return condition, []interface{}{cursor.Key, cursor.ID}, nil
+}
+
+// cursorQueryError mengubah error data PostgreSQL (kelas 22, mis. 22007/22P02) pada query ber-cursor menjadi
+// ErrInvalidCursor: nilai cursor yang diubah client tidak bisa dikonversi ke tipe kolom pembanding
+func cursorQueryError(err error, cursor *models.PageCursor) error {
+	var pqErr *pq.Error
+	if cursor != nil && errors.As(err, &pqErr) && pqErr.Code.Class() == "22" {
+		return ErrInvalidCursor
+	}
+	return err
+}
+
+// keysetPage memotong hasil query keyset (diambil limit+1 baris untuk mendeteksi halaman berikutnya),
+// mengembalikan urutan halaman sebelumnya ke urutan normal, lalu menyusun cursor next dan prev
+func keysetPage[T any](order keysetOrder, items []T, keys []models.PageCursor, limit int, cursor *models.PageCursor) ([]T, *models.PageCursor, *models.PageCursor) {
+	hasMore := len(items) > limit
+	if hasMore {
+		items, keys = items[:limit], keys[:limit]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+			keys[i], keys[j] = keys[j], keys[i]
+		}
+	}
+	if len(items) == 0 {
+		return items, nil, nil
+	}
+
+	first, last := keys[0], keys[len(keys)-1]
+	first.Sort, last.Sort = order.name, order.name
+	first.Backward = true
+
+	var next, prev *models.PageCursor
+	if hasMore || backward {
+		next = &last
+	}
+	if (hasMore && backward) || (cursor != nil && !backward) {
+		prev = &first
+	}
+	return items, next, prev
+}
@@ -0,0 +1,290 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type MasterDataRepository struct {
+	db *sql.DB
+}
+
+func NewMasterDataRepository(db *sql.DB) *MasterDataRepository {
+	return &MasterDataRepository{db: db}
+}
+
+// FindAllCategories mencari semua kategori achievement (opsional hanya yang aktif)
+func (r *MasterDataRepository) FindAllCategories(activeOnly bool) ([]models.AchievementCategory, error) {
+	query := `
+		SELECT code, parent_code, label_id, label_en, aliases, sort_order, is_active, created_at, updated_at
+		FROM achievement_categories
+	`
+	if activeOnly {
+		query += " WHERE is_active = TRUE"
+	}
+	query += " ORDER BY sort_order ASC, code ASC"
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.AchievementCategory
+	for rows.Next() {
+		var category models.AchievementCategory
+		err := rows.Scan(
+			&category.Code,
+			&category.ParentCode,
+			&category.LabelID,
+			&category.LabelEN,
+			pq.Array(&category.Aliases),
+			&category.SortOrder,
+			&category.IsActive,
+			&category.CreatedAt,
+			&category.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+// FindCategoryByCode mencari kategori berdasarkan code, nil jika tidak ada
+func (r *MasterDataRepository) FindCategoryByCode(code string) (*models.AchievementCategory, error) {
+	query := `
+		SELECT code, parent_code, label_id, label_en, aliases, sort_order, is_active, created_at, updated_at
+		FROM achievement_categories
+		WHERE code = $1
+	`
+
+	var category models.AchievementCategory
+	err := r.db.QueryRow(query, code).Scan(
+		&category.Code,
+		&category.ParentCode,
+		&category.LabelID,
+		&category.LabelEN,
+		pq.Array(&category.Aliases),
+		&category.SortOrder,
+		&category.IsActive,
+		&category.CreatedAt,
+		&category.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// CreateCategory menyimpan kategori baru
+func (r *MasterDataRepository) CreateCategory(category *models.AchievementCategory) error {
+	query := `
+		INSERT INTO achievement_categories
+		(code, parent_code, label_id, label_en, aliases, sort_order, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		category.Code,
+		category.ParentCode,
+		category.LabelID,
+		category.LabelEN,
+		pq.Array(category.Aliases),
+		category.SortOrder,
+		category.IsActive,
+		category.CreatedAt,
+		category.UpdatedAt,
+	)
+	return err
+}
+
+// UpdateCategory mengupdate kategori berdasarkan code
+func (r *MasterDataRepository) UpdateCategory(category *models.AchievementCategory) error {
+	query := `
+		UPDATE achievement_categories
+		SET parent_code = $1, label_id = $2, label_en = $3, aliases = $4,
+		    sort_order = $5, is_active = $6, updated_at = $7
+		WHERE code = $8
+	`
+
+	_, err := r.db.Exec(
+		query,
+		category.ParentCode,
+		category.LabelID,
+		category.LabelEN,
+		pq.Array(category.Aliases),
+		category.SortOrder,
+		category.IsActive,
+		category.UpdatedAt,
+		category.Code,
+	)
+	return err
+}
+
+// DeactivateCategory menonaktifkan kategori (achievement lama tetap merujuk code-nya)
+func (r *MasterDataRepository) DeactivateCategory(code string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE achievement_categories SET is_active = FALSE, updated_at = $1
+		WHERE code = $2 AND is_active = TRUE
+	`, time.Now(), code)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// CountActiveChildren menghitung sub-kategori aktif dari sebuah kategori
+func (r *MasterDataRepository) CountActiveChildren(code string) (int, error) {
+	var count int
+	err := r.db.QueryRow(`
+		SELECT COUNT(*) FROM achievement_categories
+		WHERE parent_code = $1 AND is_active = TRUE
+	`, code).Scan(&count)
+	return count, err
+}
+
+// FindAllLevels mencari semua level achievement terurut berdasarkan rank (opsional hanya yang aktif)
+func (r *MasterDataRepository) FindAllLevels(activeOnly bool) ([]models.AchievementLevel, error) {
+	query := `
+		SELECT code, rank, label_id, label_en, aliases, is_active, created_at, updated_at
+		FROM achievement_levels
+	`
+	if activeOnly {
+		query += " WHERE is_active = TRUE"
+	}
+	query += " ORDER BY rank ASC"
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var levels []models.AchievementLevel
+	for rows.Next() {
+		var level models.AchievementLevel
+		err := rows.Scan(
+			&level.Code,
+			&level.Rank,
+			&level.LabelID,
+			&level.LabelEN,
+			pq.Array(&level.Aliases),
+			&level.IsActive,
+			&level.CreatedAt,
+			&level.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+// FindLevelByCode mencari level berdasarkan code, nil jika tidak ada
+func (r *MasterDataRepository) FindLevelByCode(code string) (*models.AchievementLevel, error) {
+	query := `
+		SELECT code, rank, label_id, label_en, aliases, is_active, created_at, updated_at
+		FROM achievement_levels
+		WHERE code = $1
+	`
+
+	var level models.AchievementLevel
+	err := r.db.QueryRow(query, code).Scan(
+		&level.Code,
+		&level.Rank,
+		&level.LabelID,
+		&level.LabelEN,
+		pq.Array(&level.Aliases),
+		&level.IsActive,
+		&level.CreatedAt,
+		&level.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
+// CreateLevel menyimpan level baru
+func (r *MasterDataRepository) CreateLevel(level *models.AchievementLevel) error {
+	query := `
+		INSERT INTO achievement_levels
+		(code, rank, label_id, label_en, aliases, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		level.Code,
+		level.Rank,
+		level.LabelID,
+		level.LabelEN,
+		pq.Array(level.Aliases),
+		level.IsActive,
+		level.CreatedAt,
+		level.UpdatedAt,
+	)
+	return err
+}
+
+// UpdateLevel mengupdate level berdasarkan code
+func (r *MasterDataRepository) UpdateLevel(level *models.AchievementLevel) error {
+	query := `
+		UPDATE achievement_levels
+		SET rank = $1, label_id = $2, label_en = $3, aliases = $4, is_active = $5, updated_at = $6
+		WHERE code = $7
+	`
+
+	_, err := r.db.Exec(
+		query,
+		level.Rank,
+		level.LabelID,
+		level.LabelEN,
+		pq.Array(level.Aliases),
+		level.IsActive,
+		level.UpdatedAt,
+		level.Code,
+	)
+	return err
+}
+
+// DeactivateLevel menonaktifkan level (achievement lama tetap merujuk code-nya)
+func (r *MasterDataRepository) DeactivateLevel(code string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE achievement_levels SET is_active = FALSE, updated_at = $1
+		WHERE code = $2 AND is_active = TRUE
+	`, time.Now(), code)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Create menyimpan notifikasi baru
+func (r *NotificationRepository) Create(notification *models.Notification) error {
+	query := `
+		INSERT INTO notifications (id, user_id, type, title, message, mongo_achievement_id, is_read, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		notification.ID,
+		notification.UserID,
+		notification.Type,
+		notification.Title,
+		notification.Message,
+		notification.MongoAchievementID,
+		notification.IsRead,
+		notification.CreatedAt,
+	)
+
+	return err
+}
+
+// FindByUserID mencari notifikasi milik user dengan pagination
+func (r *NotificationRepository) FindByUserID(userID string, unreadOnly bool, limit, offset int) ([]models.Notification, int64, error) {
+	whereClause := "WHERE user_id = $1"
+	if unreadOnly {
+		whereClause += " AND is_read = FALSE"
+	}
+
+	var total int64
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM notifications `+whereClause, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, user_id, type, title, message, mongo_achievement_id, is_read, created_at
+		FROM notifications
+		` + whereClause + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		var notification models.Notification
+		err := rows.Scan(
+			&notification.ID,
+			&notification.UserID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Message,
+			&notification.MongoAchievementID,
+			&notification.IsRead,
+			&notification.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, total, nil
+}
+
+// MarkRead menandai notifikasi milik user sebagai sudah dibaca
+func (r *NotificationRepository) MarkRead(id string, userID string) (bool, error) {
+	result, err := r.db.Exec(`UPDATE notifications SET is_read = TRUE WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
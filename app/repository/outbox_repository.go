@@ -0,0 +1,201 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// sqlExecutor dipenuhi *sql.DB dan *sql.Tx, sehingga query repository yang sama
+// bisa dijalankan langsung atau di dalam transaksi outbox
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// requireAffected mengembalikan ErrVersionConflict jika update bersyarat tidak mengenai baris apa pun
+func requireAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+// WithinTransaction menjalankan fn dalam satu transaksi PostgreSQL lalu mencatat events
+// di outbox pada transaksi yang sama. Jika fn gagal, tidak ada event yang tercatat.
+// ID event diisi setelah insert.
+func (r *OutboxRepository) WithinTransaction(fn func(tx *sql.Tx) error, events ...*models.OutboxEvent) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO achievement_outbox (mongo_achievement_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+	for _, event := range events {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+		err := tx.QueryRow(query, event.MongoAchievementID, event.EventType, []byte(event.Payload), event.CreatedAt).Scan(&event.ID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// FindPending mencari event yang belum diterapkan dan belum di-park sebagai failed,
+// urut sesuai urutan pencatatan
+func (r *OutboxRepository) FindPending(limit int) ([]models.OutboxEvent, error) {
+	query := `
+		SELECT id, mongo_achievement_id, event_type, payload, attempts, last_error, created_at, processed_at, failed_at
+		FROM achievement_outbox
+		WHERE processed_at IS NULL AND failed_at IS NULL
+		ORDER BY id ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var event models.OutboxEvent
+		var payload []byte
+		err := rows.Scan(
+			&event.ID,
+			&event.MongoAchievementID,
+			&event.EventType,
+			&payload,
+			&event.Attempts,
+			&event.LastError,
+			&event.CreatedAt,
+			&event.ProcessedAt,
+			&event.FailedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// FindPendingAchievementIDs mencari achievement yang masih punya event outbox belum diterapkan
+func (r *OutboxRepository) FindPendingAchievementIDs() ([]string, error) {
+	rows, err := r.db.Query(`SELECT DISTINCT mongo_achievement_id FROM achievement_outbox WHERE processed_at IS NULL AND failed_at IS NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// HasPending true jika achievement masih punya event outbox yang belum diterapkan
+func (r *OutboxRepository) HasPending(mongoID string) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM achievement_outbox
+			WHERE mongo_achievement_id = $1 AND processed_at IS NULL AND failed_at IS NULL
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRow(query, mongoID).Scan(&exists)
+	return exists, err
+}
+
+// HasPendingBefore true jika masih ada event lebih lama untuk achievement yang sama
+// yang belum diterapkan (event harus diterapkan berurutan)
+func (r *OutboxRepository) HasPendingBefore(mongoID string, eventID int64) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM achievement_outbox
+			WHERE mongo_achievement_id = $1 AND id < $2 AND processed_at IS NULL AND failed_at IS NULL
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRow(query, mongoID, eventID).Scan(&exists)
+	return exists, err
+}
+
+// MarkProcessed menandai event sudah diterapkan ke MongoDB
+func (r *OutboxRepository) MarkProcessed(eventID int64) error {
+	query := `
+		UPDATE achievement_outbox
+		SET processed_at = $1, attempts = attempts + 1, last_error = NULL
+		WHERE id = $2 AND processed_at IS NULL
+	`
+
+	_, err := r.db.Exec(query, time.Now(), eventID)
+	return err
+}
+
+// MarkFailed mencatat percobaan yang gagal; event tetap pending untuk dicoba lagi sampai
+// maxAttempts percobaan, lalu di-park (failed_at diisi). parked true jika event di-park.
+func (r *OutboxRepository) MarkFailed(eventID int64, errMessage string, maxAttempts int) (bool, error) {
+	query := `
+		UPDATE achievement_outbox
+		SET attempts = attempts + 1, last_error = $1,
+		    failed_at = CASE WHEN attempts + 1 >= $2 THEN $3::timestamp ELSE NULL END
+		WHERE id = $4 AND processed_at IS NULL AND failed_at IS NULL
+		RETURNING failed_at IS NOT NULL
+	`
+
+	var parked bool
+	err := r.db.QueryRow(query, errMessage, maxAttempts, time.Now(), eventID).Scan(&parked)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return parked, err
+}
+
+// CountStates menghitung event yang masih pending dan yang sudah di-park sebagai failed
+func (r *OutboxRepository) CountStates() (pending int, failed int, err error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE failed_at IS NULL),
+			COUNT(*) FILTER (WHERE failed_at IS NOT NULL)
+		FROM achievement_outbox
+		WHERE processed_at IS NULL
+	`
+
+	err = r.db.QueryRow(query).Scan(&pending, &failed)
+	return pending, failed, err
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrSlugTaken dikembalikan Upsert ketika slug portofolio sudah dipakai mahasiswa lain
+var ErrSlugTaken = errors.New("portfolio slug already taken")
+
+type PortfolioRepository struct {
+	db *sql.DB
+}
+
+func NewPortfolioRepository(db *sql.DB) *PortfolioRepository {
+	return &PortfolioRepository{db: db}
+}
+
+// FindByStudentID mencari portofolio milik mahasiswa (user ID), nil jika belum dibuat
+func (r *PortfolioRepository) FindByStudentID(studentID string) (*models.StudentPortfolio, error) {
+	return r.findOne(`
+		SELECT student_id, slug, is_public, headline, bio, created_at, updated_at
+		FROM student_portfolios
+		WHERE student_id::text = $1
+	`, studentID)
+}
+
+// FindBySlug mencari portofolio berdasarkan slug (huruf kecil), nil jika tidak ada
+func (r *PortfolioRepository) FindBySlug(slug string) (*models.StudentPortfolio, error) {
+	return r.findOne(`
+		SELECT student_id, slug, is_public, headline, bio, created_at, updated_at
+		FROM student_portfolios
+		WHERE slug = $1
+	`, slug)
+}
+
+func (r *PortfolioRepository) findOne(query, arg string) (*models.StudentPortfolio, error) {
+	var portfolio models.StudentPortfolio
+	err := r.db.QueryRow(query, arg).Scan(
+		&portfolio.StudentID,
+		&portfolio.Slug,
+		&portfolio.IsPublic,
+		&portfolio.Headline,
+		&portfolio.Bio,
+		&portfolio.CreatedAt,
+		&portfolio.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	portfolio.HiddenAchievementIDs, err = r.findHiddenIDs(portfolio.StudentID.String())
+	if err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+func (r *PortfolioRepository) findHiddenIDs(studentID string) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT mongo_achievement_id
+		FROM portfolio_hidden_achievements
+		WHERE student_id::text = $1
+		ORDER BY mongo_achievement_id ASC
+	`, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Upsert membuat atau mengubah pengaturan portofolio. Mengembalikan ErrSlugTaken jika slug sudah dipakai.
+func (r *PortfolioRepository) Upsert(portfolio *models.StudentPortfolio) error {
+	query := `
+		INSERT INTO student_portfolios (student_id, slug, is_public, headline, bio, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		ON CONFLICT (student_id) DO UPDATE SET
+			slug = EXCLUDED.slug,
+			is_public = EXCLUDED.is_public,
+			headline = EXCLUDED.headline,
+			bio = EXCLUDED.bio,
+			updated_at = NOW()
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(query,
+		portfolio.StudentID,
+		portfolio.Slug,
+		portfolio.IsPublic,
+		portfolio.Headline,
+		portfolio.Bio,
+	).Scan(&portfolio.CreatedAt, &portfolio.UpdatedAt)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrSlugTaken
+	}
+	return err
+}
+
+// SetHidden menyembunyikan (hidden=true) atau menampilkan kembali achievement di portofolio mahasiswa
+func (r *PortfolioRepository) SetHidden(studentID, mongoID string, hidden bool) error {
+	if !hidden {
+		_, err := r.db.Exec(`
+			DELETE FROM portfolio_hidden_achievements
+			WHERE student_id::text = $1 AND mongo_achievement_id = $2
+		`, studentID, mongoID)
+		return err
+	}
+
+	_, err := r.db.Exec(`
+		INSERT INTO portfolio_hidden_achievements (student_id, mongo_achievement_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, studentID, mongoID)
+	return err
+}
@@ -3,6 +3,9 @@ package repository
 import (
 models "crud-app/app/model"
 "database/sql"
+"fmt"
+
+"github.com/lib/pq"
 )
 
 type StudentRepository struct {
@@ -187,7 +190,7 @@ func (r *StudentRepository) FindAll(limit, offset int) ([]models.StudentDetail,
 		FROM students s
 		INNER JOIN users u ON s.user_id = u.id
 		LEFT JOIN users u2 ON s.advisor_id = u2.id
-		ORDER BY s.created_at DESC
+		ORDER BY s.created_at DESC, s.id DESC
 		LIMIT $1 OFFSET $2
 	`
 
@@ -219,6 +222,77 @@ func (r *StudentRepository) FindAll(limit, offset int) ([]models.StudentDetail,
 	return students, total, nil
 }
 
+// studentsOrder urutan listing students untuk pagination cursor (terbaru lebih dulu)
+var studentsOrder = keysetOrder{
+name:     "students:created_at:desc",
+sortExpr: "s.created_at",
+idExpr:   "s.id",
+desc:     true,
+}
+
+// FindAllByCursor mencari students dengan pagination keyset. cursor nil berarti halaman pertama;
+// mengembalikan cursor halaman berikutnya dan sebelumnya (nil jika tidak ada).
+func (r *StudentRepository) FindAllByCursor(cursor *models.PageCursor, limit int) ([]models.StudentDetail, *models.PageCursor, *models.PageCursor, error) {
+whereClause := ""
+args := []interface{}{}
+if cursor != nil {
+condition, cursorArgs, err := studentsOrder.condition(cursor, 1)
+if err != nil {
+return nil, nil, nil, err
+}
+whereClause = "WHERE " + condition
+args = append(args, cursorArgs...)
+}
+
+query := fmt.Sprintf(`
+		SELECT s.id, s.user_id, s.student_id, u.full_name, s.program_study, 
+		       s.academic_year, s.advisor_id, 
+		       COALESCE(u2.full_name, '') as advisor_name, %s
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		LEFT JOIN users u2 ON s.advisor_id = u2.id
+		%s
+		%s
+		LIMIT $%d
+	`, studentsOrder.keyColumns(), whereClause, studentsOrder.orderBy(cursor != nil && cursor.Backward), len(args)+1)
+
+rows, err := r.db.Query(query, append(args, limit+1)...)
+if err != nil {
+return nil, nil, nil, cursorQueryError(err, cursor)
+}
+defer rows.Close()
+
+students := []models.StudentDetail{}
+keys := []models.PageCursor{}
+for rows.Next() {
+var student models.StudentDetail
+var key models.PageCursor
+err := rows.Scan(
+&student.ID,
+&student.UserID,
+&student.StudentID,
+&student.FullName,
+&student.ProgramStudy,
+&student.AcademicYear,
+&student.AdvisorID,
+&student.AdvisorName,
+&key.Key,
+&key.ID,
+)
+if err != nil {
+return nil, nil, nil, err
+}
+students = append(students, student)
+keys = append(keys, key)
+}
+if err := rows.Err(); err != nil {
+return nil, nil, nil, err
+}
+
+students, next, prev := keysetPage(studentsOrder, students, keys, limit, cursor)
+return students, next, prev, nil
+}
+
 // FindByAdvisorID mencari students berdasarkan advisor_id
 func (r *StudentRepository) FindByAdvisorID(advisorID string) ([]models.StudentDetail, error) {
 	query := `
@@ -258,4 +332,45 @@ func (r *StudentRepository) FindByAdvisorID(advisorID string) ([]models.StudentD
 	}
 
 	return students, nil
-}
\ No newline at end of file
+}
+
+// FindByStudentNumbers mencari mahasiswa aktif berdasarkan NIM (students.student_id), dikembalikan per NIM
+func (r *StudentRepository) FindByStudentNumbers(studentNumbers []string) (map[string]models.StudentDetail, error) {
+	students := make(map[string]models.StudentDetail)
+	if len(studentNumbers) == 0 {
+		return students, nil
+	}
+
+	query := `
+		SELECT s.id, s.user_id, s.student_id, u.full_name, s.program_study,
+		       s.academic_year, COALESCE(s.advisor_id::text, '')
+		FROM students s
+		INNER JOIN users u ON s.user_id = u.id
+		WHERE s.student_id = ANY($1) AND u.is_active = TRUE
+	`
+
+	rows, err := r.db.Query(query, pq.Array(studentNumbers))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var student models.StudentDetail
+		err := rows.Scan(
+			&student.ID,
+			&student.UserID,
+			&student.StudentID,
+			&student.FullName,
+			&student.ProgramStudy,
+			&student.AcademicYear,
+			&student.AdvisorID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		students[student.StudentID] = student
+	}
+
+	return students, rows.Err()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+type TranscriptRepository struct {
+	db *sql.DB
+}
+
+func NewTranscriptRepository(db *sql.DB) *TranscriptRepository {
+	return &TranscriptRepository{db: db}
+}
+
+// FindAllTemplates mencari semua template transkrip
+func (r *TranscriptRepository) FindAllTemplates() ([]models.TranscriptTemplate, error) {
+	query := `
+		SELECT scope, layout, updated_by, updated_at
+		FROM transcript_templates
+		ORDER BY scope ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []models.TranscriptTemplate{}
+	for rows.Next() {
+		template, err := scanTranscriptTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+// FindTemplate mencari template sebuah scope (case-insensitive), nil jika tidak ada
+func (r *TranscriptRepository) FindTemplate(scope string) (*models.TranscriptTemplate, error) {
+	query := `
+		SELECT scope, layout, updated_by, updated_at
+		FROM transcript_templates
+		WHERE scope = $1
+	`
+
+	template, err := scanTranscriptTemplate(r.db.QueryRow(query, strings.ToLower(scope)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return template, err
+}
+
+func scanTranscriptTemplate(row interface{ Scan(...interface{}) error }) (*models.TranscriptTemplate, error) {
+	var template models.TranscriptTemplate
+	var layout []byte
+	if err := row.Scan(&template.Scope, &layout, &template.UpdatedBy, &template.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(layout, &template.Layout); err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// UpsertTemplate membuat atau mengganti template sebuah scope
+func (r *TranscriptRepository) UpsertTemplate(template *models.TranscriptTemplate) error {
+	layout, err := json.Marshal(template.Layout)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO transcript_templates (scope, layout, updated_by, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (scope) DO UPDATE
+		SET layout = EXCLUDED.layout,
+		    updated_by = EXCLUDED.updated_by,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err = r.db.Exec(query, strings.ToLower(template.Scope), layout, template.UpdatedBy, template.UpdatedAt)
+	return err
+}
+
+// DeleteTemplate menghapus template sebuah scope
+func (r *TranscriptRepository) DeleteTemplate(scope string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM transcript_templates WHERE scope = $1`, strings.ToLower(scope))
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// CreateRecord menyimpan record verifikasi transkrip. Mengembalikan ErrDuplicateCode jika kodenya bentrok.
+func (r *TranscriptRepository) CreateRecord(record *models.TranscriptRecord) error {
+	query := `
+		INSERT INTO transcript_verification_records
+		(id, code, student_id, holder_name, achievement_count, total_points, verifier_institution, signature, issued_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.Exec(query,
+		record.ID,
+		record.Code,
+		record.StudentID,
+		record.HolderName,
+		record.AchievementCount,
+		record.TotalPoints,
+		record.VerifierInstitution,
+		record.Signature,
+		record.IssuedAt,
+	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateCode
+	}
+	return err
+}
+
+// FindRecordByCode mencari record verifikasi transkrip berdasarkan kode publik, nil jika tidak ada
+func (r *TranscriptRepository) FindRecordByCode(code string) (*models.TranscriptRecord, error) {
+	query := `
+		SELECT id, code, student_id, holder_name, achievement_count, total_points,
+		       verifier_institution, signature, issued_at
+		FROM transcript_verification_records
+		WHERE code = $1
+	`
+
+	var record models.TranscriptRecord
+	err := r.db.QueryRow(query, code).Scan(
+		&record.ID,
+		&record.Code,
+		&record.StudentID,
+		&record.HolderName,
+		&record.AchievementCount,
+		&record.TotalPoints,
+		&record.VerifierInstitution,
+		&record.Signature,
+		&record.IssuedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
@@ -4,7 +4,10 @@ import (
 models "crud-app/app/model"
 "database/sql"
 "errors"
+"fmt"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type UserRepository struct {
@@ -101,6 +104,7 @@ func (r *UserRepository) Create(user *models.User) error {
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
+	user.Version = 1
 	_, err := r.db.Exec(
 		query,
 		user.ID,
@@ -136,17 +140,17 @@ func (r *UserRepository) FindAll(limit, offset int, roleFilter string) ([]models
 
 	// Get data with pagination
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, version, created_at, updated_at
 		FROM users
 		WHERE deleted_at IS NULL
 	`
 
 	if roleFilter != "" {
 		query += ` AND role_id = $1`
-		query += ` ORDER BY created_at DESC LIMIT $2 OFFSET $3`
+		query += ` ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`
 		args = append(args, limit, offset)
 	} else {
-		query += ` ORDER BY created_at DESC LIMIT $1 OFFSET $2`
+		query += ` ORDER BY created_at DESC, id DESC LIMIT $1 OFFSET $2`
 		args = []interface{}{limit, offset}
 	}
 
@@ -167,6 +171,7 @@ func (r *UserRepository) FindAll(limit, offset int, roleFilter string) ([]models
 			&user.FullName,
 			&user.RoleID,
 			&user.IsActive,
+			&user.Version,
 			&user.CreatedAt,
 			&user.UpdatedAt,
 		)
@@ -179,10 +184,84 @@ func (r *UserRepository) FindAll(limit, offset int, roleFilter string) ([]models
 	return users, total, nil
 }
 
+// usersOrder urutan listing users untuk pagination cursor (terbaru lebih dulu)
+var usersOrder = keysetOrder{
+	name:     "users:created_at:desc",
+	sortExpr: "created_at",
+	idExpr:   "id",
+	desc:     true,
+}
+
+// FindAllByCursor mencari users dengan pagination keyset. cursor nil berarti halaman pertama;
+// mengembalikan cursor halaman berikutnya dan sebelumnya (nil jika tidak ada).
+func (r *UserRepository) FindAllByCursor(cursor *models.PageCursor, limit int, roleFilter string) ([]models.User, *models.PageCursor, *models.PageCursor, error) {
+	whereClause := "WHERE deleted_at IS NULL"
+	args := []interface{}{}
+
+	if roleFilter != "" {
+		args = append(args, roleFilter)
+		whereClause += fmt.Sprintf(" AND role_id = $%d", len(args))
+	}
+	if cursor != nil {
+		condition, cursorArgs, err := usersOrder.condition(cursor, len(args)+1)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		whereClause += " AND " + condition
+		args = append(args, cursorArgs...)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, version, created_at, updated_at, %s
+		FROM users
+		%s
+		%s
+		LIMIT $%d
+	`, usersOrder.keyColumns(), whereClause, usersOrder.orderBy(cursor != nil && cursor.Backward), len(args)+1)
+
+	rows, err := r.db.Query(query, append(args, limit+1)...)
+	if err != nil {
+		return nil, nil, nil, cursorQueryError(err, cursor)
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	keys := []models.PageCursor{}
+	for rows.Next() {
+		var user models.User
+		var key models.PageCursor
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.PasswordHash,
+			&user.FullName,
+			&user.RoleID,
+			&user.IsActive,
+			&user.Version,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&key.Key,
+			&key.ID,
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		users = append(users, user)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	users, next, prev := keysetPage(usersOrder, users, keys, limit, cursor)
+	return users, next, prev, nil
+}
+
 // FindByID mencari user berdasarkan ID (FR-009)
 func (r *UserRepository) FindByID(userID string) (*models.User, error) {
 	query := `
-		SELECT id, username, email, password_hash, full_name, role_id, is_active, created_at, updated_at
+		SELECT id, username, email, password_hash, full_name, role_id, is_active, version, created_at, updated_at
 		FROM users
 		WHERE id = $1 AND deleted_at IS NULL
 	`
@@ -196,6 +275,7 @@ func (r *UserRepository) FindByID(userID string) (*models.User, error) {
 		&user.FullName,
 		&user.RoleID,
 		&user.IsActive,
+		&user.Version,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -210,15 +290,17 @@ func (r *UserRepository) FindByID(userID string) (*models.User, error) {
 	return &user, nil
 }
 
-// Update mengupdate user (FR-009)
+// Update mengupdate user (FR-009). Update hanya berhasil jika versi di database
+// masih sama dengan user.Version; jika tidak, ErrVersionConflict dikembalikan.
 func (r *UserRepository) Update(userID string, user *models.User) error {
 	query := `
 		UPDATE users
-		SET username = $1, email = $2, full_name = $3, role_id = $4, is_active = $5, updated_at = $6
-		WHERE id = $7 AND deleted_at IS NULL
+		SET username = $1, email = $2, full_name = $3, role_id = $4, is_active = $5, updated_at = $6,
+		    version = version + 1
+		WHERE id = $7 AND version = $8 AND deleted_at IS NULL
 	`
 
-	_, err := r.db.Exec(
+	result, err := r.db.Exec(
 		query,
 		user.Username,
 		user.Email,
@@ -227,16 +309,29 @@ func (r *UserRepository) Update(userID string, user *models.User) error {
 		user.IsActive,
 		user.UpdatedAt,
 		userID,
+		user.Version,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVersionConflict
+	}
+
+	user.Version++
+	return nil
 }
 
 // UpdatePassword mengupdate password user
 func (r *UserRepository) UpdatePassword(userID string, passwordHash string) error {
 	query := `
 		UPDATE users
-		SET password_hash = $1, updated_at = $2
+		SET password_hash = $1, updated_at = $2, version = version + 1
 		WHERE id = $3 AND deleted_at IS NULL
 	`
 
@@ -248,7 +343,7 @@ func (r *UserRepository) UpdatePassword(userID string, passwordHash string) erro
 func (r *UserRepository) AssignRole(userID string, roleID string) error {
 	query := `
 		UPDATE users
-		SET role_id = $1, updated_at = $2
+		SET role_id = $1, updated_at = $2, version = version + 1
 		WHERE id = $3 AND deleted_at IS NULL
 	`
 
@@ -260,7 +355,7 @@ func (r *UserRepository) AssignRole(userID string, roleID string) error {
 func (r *UserRepository) SoftDelete(userID string) error {
 	query := `
 		UPDATE users
-		SET deleted_at = $1, updated_at = $1, is_active = false
+		SET deleted_at = $1, updated_at = $1, is_active = false, version = version + 1
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
@@ -268,6 +363,149 @@ func (r *UserRepository) SoftDelete(userID string) error {
 	return err
 }
 
+// FindDeleted mencari user yang sudah di-soft delete (trash), terbaru lebih dulu
+func (r *UserRepository) FindDeleted(limit, offset int) ([]models.User, int64, error) {
+	var total int64
+	err := r.db.QueryRow(`SELECT COUNT(*) FROM users WHERE deleted_at IS NOT NULL`).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := `
+		SELECT id, username, email, full_name, role_id, is_active, version, deleted_at, created_at, updated_at
+		FROM users
+		WHERE deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.Query(query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []models.User{}
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Username,
+			&user.Email,
+			&user.FullName,
+			&user.RoleID,
+			&user.IsActive,
+			&user.Version,
+			&user.DeletedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+
+	return users, total, nil
+}
+
+// FindDeletedByID mencari user di trash berdasarkan ID. Mengembalikan nil jika tidak ada.
+func (r *UserRepository) FindDeletedByID(userID string) (*models.User, error) {
+	query := `
+		SELECT id, username, email, full_name, role_id, is_active, version, deleted_at, created_at, updated_at
+		FROM users
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	var user models.User
+	err := r.db.QueryRow(query, userID).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Email,
+		&user.FullName,
+		&user.RoleID,
+		&user.IsActive,
+		&user.Version,
+		&user.DeletedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// FindDeletedIDsBefore mencari ID user di trash yang dihapus sebelum cutoff (untuk purge)
+func (r *UserRepository) FindDeletedIDsBefore(cutoff time.Time) ([]string, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE deleted_at IS NOT NULL AND deleted_at < $1
+	`
+
+	return r.queryIDs(query, cutoff)
+}
+
+// Restore mengembalikan user dari trash dan mengaktifkannya kembali.
+// Mengembalikan sql.ErrNoRows jika user tidak ada di trash.
+func (r *UserRepository) Restore(userID string) error {
+	query := `
+		UPDATE users
+		SET deleted_at = NULL, is_active = true, updated_at = $1, version = version + 1
+		WHERE id = $2 AND deleted_at IS NOT NULL
+	`
+
+	result, err := r.db.Exec(query, time.Now(), userID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// Purge menghapus permanen user di trash beserta data yang hanya miliknya (notifikasi,
+// penugasan admin departemen, profil mahasiswa/dosen). Jika user masih direferensikan
+// data lain (misalnya prestasi atau riwayat verifikasi), ErrStillReferenced dikembalikan
+// dan tidak ada yang dihapus.
+func (r *UserRepository) Purge(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	statements := []string{
+		`DELETE FROM notifications WHERE user_id = $1`,
+		`DELETE FROM department_admins WHERE user_id = $1`,
+		`DELETE FROM students WHERE user_id = $1`,
+		`DELETE FROM lecturers WHERE user_id = $1`,
+		`DELETE FROM users WHERE id = $1 AND deleted_at IS NOT NULL`,
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(statement, userID); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				return ErrStillReferenced
+			}
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 // CheckUsernameExists mengecek apakah username sudah ada
 func (r *UserRepository) CheckUsernameExists(username string) (bool, error) {
 	query := `SELECT EXISTS(SELECT 1 FROM users WHERE username = $1 AND deleted_at IS NULL)`
@@ -305,4 +543,58 @@ func (r *UserRepository) CheckRoleExists(roleID string) (bool, error) {
 	}
 
 	return exists, nil
-}
\ No newline at end of file
+}
+// FindDepartmentAdminIDs mencari user ID admin departemen
+func (r *UserRepository) FindDepartmentAdminIDs(department string) ([]string, error) {
+	query := `
+		SELECT da.user_id
+		FROM department_admins da
+		INNER JOIN users u ON u.id = da.user_id
+		WHERE LOWER(da.department) = LOWER($1) AND u.deleted_at IS NULL AND u.is_active = TRUE
+	`
+
+	return r.queryIDs(query, department)
+}
+
+// FindActiveIDsByRole mencari user ID aktif berdasarkan role
+func (r *UserRepository) FindActiveIDsByRole(roleID string) ([]string, error) {
+	query := `
+		SELECT id
+		FROM users
+		WHERE role_id = $1 AND deleted_at IS NULL AND is_active = TRUE
+	`
+
+	return r.queryIDs(query, roleID)
+}
+
+// FindActiveIDsByPermission mencari user ID aktif yang role-nya memiliki permission tertentu
+func (r *UserRepository) FindActiveIDsByPermission(permission string) ([]string, error) {
+	query := `
+		SELECT DISTINCT u.id
+		FROM users u
+		INNER JOIN role_permissions rp ON rp.role_id = u.role_id
+		INNER JOIN permissions p ON p.id = rp.permission_id
+		WHERE p.name = $1 AND u.deleted_at IS NULL AND u.is_active = TRUE
+	`
+
+	return r.queryIDs(query, permission)
+}
+
+func (r *UserRepository) queryIDs(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
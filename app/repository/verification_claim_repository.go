@@ -0,0 +1,82 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+// VerificationClaimRepository mengelola klaim review di inbox verifikasi
+type VerificationClaimRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationClaimRepository(db *sql.DB) *VerificationClaimRepository {
+	return &VerificationClaimRepository{db: db}
+}
+
+// Claim mengklaim achievement untuk direview userID selama ttl. Klaim milik user yang sama diperpanjang,
+// klaim user lain yang sudah kedaluwarsa diambil alih. Jika achievement masih diklaim user lain,
+// mengembalikan klaim tersebut dengan claimed=false.
+func (r *VerificationClaimRepository) Claim(mongoID, userID string, ttl time.Duration) (*models.ReviewClaim, bool, error) {
+	var claimedAt time.Time
+	err := r.db.QueryRow(`
+		INSERT INTO verification_claims (mongo_achievement_id, claimed_by, claimed_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (mongo_achievement_id) DO UPDATE
+		SET claimed_by = EXCLUDED.claimed_by,
+		    claimed_at = CASE WHEN verification_claims.claimed_by = EXCLUDED.claimed_by
+		                      THEN verification_claims.claimed_at ELSE EXCLUDED.claimed_at END,
+		    expires_at = EXCLUDED.expires_at
+		WHERE verification_claims.claimed_by = EXCLUDED.claimed_by
+		   OR verification_claims.expires_at <= NOW()
+		RETURNING claimed_at
+	`, mongoID, userID, ttl.Seconds()).Scan(&claimedAt)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, false, err
+	}
+
+	claim, findErr := r.FindActive(mongoID)
+	if findErr != nil {
+		return nil, false, findErr
+	}
+	return claim, err == nil, nil
+}
+
+// FindActive mencari klaim yang belum kedaluwarsa, nil jika tidak ada
+func (r *VerificationClaimRepository) FindActive(mongoID string) (*models.ReviewClaim, error) {
+	var claim models.ReviewClaim
+	err := r.db.QueryRow(`
+		SELECT c.mongo_achievement_id, c.claimed_by, COALESCE(u.full_name, ''), c.claimed_at, c.expires_at
+		FROM verification_claims c
+		LEFT JOIN users u ON u.id = c.claimed_by
+		WHERE c.mongo_achievement_id = $1 AND c.expires_at > NOW()
+	`, mongoID).Scan(&claim.AchievementID, &claim.ClaimedBy, &claim.ClaimerName, &claim.ClaimedAt, &claim.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// Release melepas klaim milik userID; force melepas klaim siapa pun (admin).
+// Mengembalikan false jika tidak ada klaim yang dilepas.
+func (r *VerificationClaimRepository) Release(mongoID, userID string, force bool) (bool, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM verification_claims
+		WHERE mongo_achievement_id = $1 AND ($2 OR claimed_by::text = $3)
+	`, mongoID, force, userID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// DeleteByAchievement menghapus klaim setelah achievement diputuskan
+func (r *VerificationClaimRepository) DeleteByAchievement(mongoID string) error {
+	_, err := r.db.Exec(`DELETE FROM verification_claims WHERE mongo_achievement_id = $1`, mongoID)
+	return err
+}
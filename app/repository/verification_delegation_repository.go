@@ -0,0 +1,187 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"fmt"
+)
+
+// VerificationDelegationRepository mengelola delegasi verifikasi antar dosen wali
+type VerificationDelegationRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationDelegationRepository(db *sql.DB) *VerificationDelegationRepository {
+	return &VerificationDelegationRepository{db: db}
+}
+
+const delegationColumns = `
+	d.id, d.delegator_id, COALESCE(u1.full_name, ''), d.delegate_id, COALESCE(u2.full_name, ''),
+	d.starts_at, d.ends_at, d.reason, d.created_by, d.created_at, d.revoked_at
+`
+
+const delegationJoins = `
+	FROM verification_delegations d
+	LEFT JOIN users u1 ON u1.id = d.delegator_id
+	LEFT JOIN users u2 ON u2.id = d.delegate_id
+`
+
+// Create menyimpan delegasi baru
+func (r *VerificationDelegationRepository) Create(delegation *models.VerificationDelegation) error {
+	_, err := r.db.Exec(`
+		INSERT INTO verification_delegations (id, delegator_id, delegate_id, starts_at, ends_at, reason, created_by, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`,
+		delegation.ID,
+		delegation.DelegatorID,
+		delegation.DelegateID,
+		delegation.StartsAt,
+		delegation.EndsAt,
+		delegation.Reason,
+		delegation.CreatedBy,
+		delegation.CreatedAt,
+	)
+	return err
+}
+
+// FindByID mencari delegasi, nil jika tidak ada
+func (r *VerificationDelegationRepository) FindByID(id string) (*models.VerificationDelegation, error) {
+	delegations, err := r.find("WHERE d.id::text = $1", id)
+	if err != nil || len(delegations) == 0 {
+		return nil, err
+	}
+	return &delegations[0], nil
+}
+
+// FindForUser mencari delegasi yang dibuat atau diterima user; includeEnded menyertakan delegasi
+// yang sudah berakhir atau dicabut
+func (r *VerificationDelegationRepository) FindForUser(userID string, includeEnded bool) ([]models.VerificationDelegation, error) {
+	whereClause := "WHERE (d.delegator_id::text = $1 OR d.delegate_id::text = $1)"
+	if !includeEnded {
+		whereClause += " AND d.revoked_at IS NULL AND d.ends_at > NOW()"
+	}
+	return r.find(whereClause, userID)
+}
+
+// FindAll mencari semua delegasi (admin)
+func (r *VerificationDelegationRepository) FindAll(includeEnded bool) ([]models.VerificationDelegation, error) {
+	whereClause := "WHERE TRUE"
+	if !includeEnded {
+		whereClause += " AND d.revoked_at IS NULL AND d.ends_at > NOW()"
+	}
+	return r.find(whereClause)
+}
+
+// FindActiveDelegatorIDs mencari dosen wali yang saat ini mendelegasikan verifikasi ke delegateID
+func (r *VerificationDelegationRepository) FindActiveDelegatorIDs(delegateID string) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT delegator_id::text
+		FROM verification_delegations
+		WHERE delegate_id::text = $1
+		  AND revoked_at IS NULL
+		  AND starts_at <= NOW() AND ends_at > NOW()
+	`, delegateID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// FindActiveDelegateIDs mencari dosen yang saat ini menerima delegasi verifikasi dari delegatorID
+func (r *VerificationDelegationRepository) FindActiveDelegateIDs(delegatorID string) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT DISTINCT delegate_id::text
+		FROM verification_delegations
+		WHERE delegator_id::text = $1
+		  AND revoked_at IS NULL
+		  AND starts_at <= NOW() AND ends_at > NOW()
+	`, delegatorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// HasOverlap memeriksa apakah sudah ada delegasi aktif dengan pasangan dosen yang sama pada periode yang beririsan
+func (r *VerificationDelegationRepository) HasOverlap(delegation *models.VerificationDelegation) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM verification_delegations
+			WHERE delegator_id = $1 AND delegate_id = $2
+			  AND revoked_at IS NULL
+			  AND starts_at < $4 AND ends_at > $3
+		)
+	`, delegation.DelegatorID, delegation.DelegateID, delegation.StartsAt, delegation.EndsAt).Scan(&exists)
+	return exists, err
+}
+
+// Revoke mencabut delegasi yang belum dicabut
+func (r *VerificationDelegationRepository) Revoke(id string) error {
+	result, err := r.db.Exec(`
+		UPDATE verification_delegations SET revoked_at = NOW()
+		WHERE id::text = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrVersionConflict
+	}
+	return nil
+}
+
+func (r *VerificationDelegationRepository) find(whereClause string, args ...interface{}) ([]models.VerificationDelegation, error) {
+	query := fmt.Sprintf(`SELECT %s %s %s ORDER BY d.starts_at DESC, d.id`, delegationColumns, delegationJoins, whereClause)
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	delegations := []models.VerificationDelegation{}
+	for rows.Next() {
+		var d models.VerificationDelegation
+		if err := rows.Scan(
+			&d.ID,
+			&d.DelegatorID,
+			&d.DelegatorName,
+			&d.DelegateID,
+			&d.DelegateName,
+			&d.StartsAt,
+			&d.EndsAt,
+			&d.Reason,
+			&d.CreatedBy,
+			&d.CreatedAt,
+			&d.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		delegations = append(delegations, d)
+	}
+	return delegations, rows.Err()
+}
@@ -0,0 +1,173 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateCode dikembalikan Create ketika kode verifikasi sudah dipakai record lain
+var ErrDuplicateCode = errors.New("duplicate verification code")
+
+type VerificationRecordRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationRecordRepository(db *sql.DB) *VerificationRecordRepository {
+	return &VerificationRecordRepository{db: db}
+}
+
+const verificationRecordColumns = `
+	id, code, mongo_achievement_id, title, holder_name, level, verified_at, verifier_institution,
+	signature, issued_at, issued_by, revoked_at, revoked_by, revoke_reason
+`
+
+// Create menyimpan record verifikasi baru. Mengembalikan ErrDuplicateCode jika kodenya bentrok dan
+// ErrVersionConflict jika achievement sudah punya record aktif (diterbitkan bersamaan).
+func (r *VerificationRecordRepository) Create(record *models.VerificationRecord) error {
+	query := `
+		INSERT INTO achievement_verification_records
+		(id, code, mongo_achievement_id, title, holder_name, level, verified_at, verifier_institution, signature, issued_at, issued_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.Exec(query,
+		record.ID,
+		record.Code,
+		record.MongoAchievementID,
+		record.Title,
+		record.HolderName,
+		record.Level,
+		record.VerifiedAt,
+		record.VerifierInstitution,
+		record.Signature,
+		record.IssuedAt,
+		record.IssuedBy,
+	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		if pqErr.Constraint == "idx_verification_records_active" {
+			return ErrVersionConflict
+		}
+		return ErrDuplicateCode
+	}
+	return err
+}
+
+// FindByCode mencari record berdasarkan kode publik (termasuk yang sudah dicabut)
+func (r *VerificationRecordRepository) FindByCode(code string) (*models.VerificationRecord, error) {
+	return r.findOne(`SELECT `+verificationRecordColumns+` FROM achievement_verification_records WHERE code = $1`, code)
+}
+
+// FindActiveByAchievement mencari record aktif (belum dicabut) sebuah achievement
+func (r *VerificationRecordRepository) FindActiveByAchievement(mongoID string) (*models.VerificationRecord, error) {
+	return r.findOne(`
+		SELECT `+verificationRecordColumns+`
+		FROM achievement_verification_records
+		WHERE mongo_achievement_id = $1 AND revoked_at IS NULL
+	`, mongoID)
+}
+
+// HasRevoked true jika achievement pernah punya record yang sudah dicabut
+func (r *VerificationRecordRepository) HasRevoked(mongoID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(`
+		SELECT EXISTS(
+			SELECT 1 FROM achievement_verification_records
+			WHERE mongo_achievement_id = $1 AND revoked_at IS NOT NULL
+		)
+	`, mongoID).Scan(&exists)
+	return exists, err
+}
+
+// FindActiveCodes mencari kode record aktif beberapa achievement sekaligus (per mongo achievement ID)
+func (r *VerificationRecordRepository) FindActiveCodes(mongoIDs []string) (map[string]string, error) {
+	codes := make(map[string]string)
+	if len(mongoIDs) == 0 {
+		return codes, nil
+	}
+
+	rows, err := r.db.Query(`
+		SELECT mongo_achievement_id, code
+		FROM achievement_verification_records
+		WHERE mongo_achievement_id = ANY($1) AND revoked_at IS NULL
+	`, pq.Array(mongoIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mongoID, code string
+		if err := rows.Scan(&mongoID, &code); err != nil {
+			return nil, err
+		}
+		codes[mongoID] = code
+	}
+	return codes, rows.Err()
+}
+
+func (r *VerificationRecordRepository) findOne(query string, arg string) (*models.VerificationRecord, error) {
+	var record models.VerificationRecord
+	err := r.db.QueryRow(query, arg).Scan(
+		&record.ID,
+		&record.Code,
+		&record.MongoAchievementID,
+		&record.Title,
+		&record.HolderName,
+		&record.Level,
+		&record.VerifiedAt,
+		&record.VerifierInstitution,
+		&record.Signature,
+		&record.IssuedAt,
+		&record.IssuedBy,
+		&record.RevokedAt,
+		&record.RevokedBy,
+		&record.RevokeReason,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Revoke mencabut record aktif sebuah achievement. Mengembalikan sql.ErrNoRows jika tidak ada record aktif.
+func (r *VerificationRecordRepository) Revoke(mongoID, revokedBy, reason string) error {
+	return r.revoke(r.db, mongoID, revokedBy, reason, true)
+}
+
+// RevokeTx mencabut record aktif di dalam transaksi pencabutan achievement; tidak error jika
+// achievement belum punya record
+func (r *VerificationRecordRepository) RevokeTx(tx *sql.Tx, mongoID, revokedBy, reason string) error {
+	return r.revoke(tx, mongoID, revokedBy, reason, false)
+}
+
+func (r *VerificationRecordRepository) revoke(db sqlExecutor, mongoID, revokedBy, reason string, requireRecord bool) error {
+	result, err := db.Exec(`
+		UPDATE achievement_verification_records
+		SET revoked_at = $1, revoked_by = $2, revoke_reason = $3
+		WHERE mongo_achievement_id = $4 AND revoked_at IS NULL
+	`, time.Now(), revokedBy, reason, mongoID)
+	if err != nil {
+		return err
+	}
+	if !requireRecord {
+		return nil
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
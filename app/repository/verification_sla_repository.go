@@ -0,0 +1,196 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"time"
+)
+
+type VerificationSLARepository struct {
+	db *sql.DB
+}
+
+func NewVerificationSLARepository(db *sql.DB) *VerificationSLARepository {
+	return &VerificationSLARepository{db: db}
+}
+
+// FindAllRules mencari semua aturan SLA verifikasi
+func (r *VerificationSLARepository) FindAllRules() ([]models.VerificationSLARule, error) {
+	query := `
+		SELECT level, reminder_after_hours, escalate_after_hours, updated_at
+		FROM verification_sla_rules
+		ORDER BY level ASC
+	`
+
+	rows, err := r.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []models.VerificationSLARule
+	for rows.Next() {
+		var rule models.VerificationSLARule
+		if err := rows.Scan(&rule.Level, &rule.ReminderAfterHours, &rule.EscalateAfterHours, &rule.UpdatedAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// UpsertRule membuat atau mengupdate aturan SLA untuk sebuah level
+func (r *VerificationSLARepository) UpsertRule(rule *models.VerificationSLARule) error {
+	query := `
+		INSERT INTO verification_sla_rules (level, reminder_after_hours, escalate_after_hours, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (level) DO UPDATE
+		SET reminder_after_hours = EXCLUDED.reminder_after_hours,
+		    escalate_after_hours = EXCLUDED.escalate_after_hours,
+		    updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.Exec(query, rule.Level, rule.ReminderAfterHours, rule.EscalateAfterHours, rule.UpdatedAt)
+	return err
+}
+
+// DeleteRule menghapus aturan SLA sebuah level
+func (r *VerificationSLARepository) DeleteRule(level string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM verification_sla_rules WHERE level = $1`, level)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// CreateEscalation mencatat reminder/eskalasi yang dikirim
+func (r *VerificationSLARepository) CreateEscalation(escalation *models.VerificationEscalation) error {
+	query := `
+		INSERT INTO verification_escalations
+		(id, mongo_achievement_id, escalation_type, notified_user_id, pending_hours, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		escalation.ID,
+		escalation.MongoAchievementID,
+		escalation.EscalationType,
+		escalation.NotifiedUserID,
+		escalation.PendingHours,
+		escalation.CreatedAt,
+	)
+
+	return err
+}
+
+// HasEscalation mengecek apakah reminder/eskalasi sudah pernah dikirim sejak waktu tertentu
+func (r *VerificationSLARepository) HasEscalation(mongoID string, escalationType string, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM verification_escalations
+			WHERE mongo_achievement_id = $1 AND escalation_type = $2 AND created_at >= $3
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRow(query, mongoID, escalationType, since).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// FindEscalationsByMongoID mencari riwayat reminder/eskalasi sebuah achievement
+func (r *VerificationSLARepository) FindEscalationsByMongoID(mongoID string) ([]models.VerificationEscalation, error) {
+	query := `
+		SELECT id, mongo_achievement_id, escalation_type, notified_user_id, pending_hours, created_at
+		FROM verification_escalations
+		WHERE mongo_achievement_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(query, mongoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var escalations []models.VerificationEscalation
+	for rows.Next() {
+		var escalation models.VerificationEscalation
+		err := rows.Scan(
+			&escalation.ID,
+			&escalation.MongoAchievementID,
+			&escalation.EscalationType,
+			&escalation.NotifiedUserID,
+			&escalation.PendingHours,
+			&escalation.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		escalations = append(escalations, escalation)
+	}
+
+	return escalations, nil
+}
+
+// FindDepartmentAdmins mencari semua admin departemen beserta nama user-nya
+func (r *VerificationSLARepository) FindDepartmentAdmins() ([]models.DepartmentAdmin, error) {
+	rows, err := r.db.Query(`
+		SELECT da.department, da.user_id, u.full_name, u.email
+		FROM department_admins da
+		INNER JOIN users u ON u.id = da.user_id
+		ORDER BY LOWER(da.department) ASC, u.full_name ASC
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	admins := []models.DepartmentAdmin{}
+	for rows.Next() {
+		var admin models.DepartmentAdmin
+		if err := rows.Scan(&admin.Department, &admin.UserID, &admin.FullName, &admin.Email); err != nil {
+			return nil, err
+		}
+		admins = append(admins, admin)
+	}
+	return admins, rows.Err()
+}
+
+// AddDepartmentAdmin menambahkan admin departemen; tidak berubah jika sudah terdaftar
+func (r *VerificationSLARepository) AddDepartmentAdmin(department, userID string) error {
+	_, err := r.db.Exec(`
+		INSERT INTO department_admins (department, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (department, user_id) DO NOTHING
+	`, department, userID)
+	return err
+}
+
+// RemoveDepartmentAdmin menghapus admin departemen (nama departemen case-insensitive)
+func (r *VerificationSLARepository) RemoveDepartmentAdmin(department, userID string) (bool, error) {
+	result, err := r.db.Exec(`
+		DELETE FROM department_admins
+		WHERE LOWER(department) = LOWER($1) AND user_id::text = $2
+	`, department, userID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
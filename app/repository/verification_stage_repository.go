@@ -0,0 +1,115 @@
+package repository
+
+import (
+	models "crud-app/app/model"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// ErrDuplicateStage dikembalikan Create ketika tahap dengan urutan, nama, level dan category yang sama sudah ada
+var ErrDuplicateStage = errors.New("verification stage already exists")
+
+type VerificationStageRepository struct {
+	db *sql.DB
+}
+
+func NewVerificationStageRepository(db *sql.DB) *VerificationStageRepository {
+	return &VerificationStageRepository{db: db}
+}
+
+// FindApplicable mencari tahap verifikasi aktif yang berlaku untuk level dan category tertentu
+func (r *VerificationStageRepository) FindApplicable(level, category string) ([]models.VerificationStage, error) {
+	query := `
+		SELECT id, stage_order, name, permission, level, category, is_active, created_at
+		FROM verification_stages
+		WHERE is_active = TRUE
+		  AND (level IS NULL OR LOWER(level) = LOWER($1))
+		  AND (category IS NULL OR LOWER(category) = LOWER($2))
+		ORDER BY stage_order ASC, created_at ASC
+	`
+
+	return r.query(query, level, category)
+}
+
+// FindAll mencari semua konfigurasi tahap verifikasi
+func (r *VerificationStageRepository) FindAll() ([]models.VerificationStage, error) {
+	query := `
+		SELECT id, stage_order, name, permission, level, category, is_active, created_at
+		FROM verification_stages
+		ORDER BY stage_order ASC, created_at ASC
+	`
+
+	return r.query(query)
+}
+
+// Create menyimpan konfigurasi tahap verifikasi baru. Mengembalikan ErrDuplicateStage jika sudah ada.
+func (r *VerificationStageRepository) Create(stage *models.VerificationStage) error {
+	query := `
+		INSERT INTO verification_stages (id, stage_order, name, permission, level, category, is_active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.Exec(
+		query,
+		stage.ID,
+		stage.StageOrder,
+		stage.Name,
+		stage.Permission,
+		stage.Level,
+		stage.Category,
+		stage.IsActive,
+		stage.CreatedAt,
+	)
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return ErrDuplicateStage
+	}
+	return err
+}
+
+// Delete menghapus konfigurasi tahap verifikasi
+func (r *VerificationStageRepository) Delete(id string) (bool, error) {
+	result, err := r.db.Exec(`DELETE FROM verification_stages WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *VerificationStageRepository) query(query string, args ...interface{}) ([]models.VerificationStage, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stages []models.VerificationStage
+	for rows.Next() {
+		var stage models.VerificationStage
+		err := rows.Scan(
+			&stage.ID,
+			&stage.StageOrder,
+			&stage.Name,
+			&stage.Permission,
+			&stage.Level,
+			&stage.Category,
+			&stage.IsActive,
+			&stage.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, stage)
+	}
+
+	return stages, nil
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DuplicateConfig ambang batas deteksi kemungkinan duplikat
+type DuplicateConfig struct {
+	TitleSimilarity float64       // 0..1, kemiripan judul minimal
+	DateWindow      time.Duration // selisih tanggal prestasi maksimal
+}
+
+// NormalizeTitle mengubah judul menjadi huruf kecil tanpa tanda baca dengan spasi tunggal
+func NormalizeTitle(title string) string {
+	mapped := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, title)
+	return strings.Join(strings.Fields(mapped), " ")
+}
+
+// TitleSimilarity menghitung kemiripan dua judul (koefisien Dice atas bigram karakter judul ternormalisasi), 0..1
+func TitleSimilarity(a, b string) float64 {
+	a, b = NormalizeTitle(a), NormalizeTitle(b)
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	bigrams := func(s string) map[string]int {
+		runes := []rune(s)
+		counts := make(map[string]int)
+		for i := 0; i+1 < len(runes); i++ {
+			counts[string(runes[i:i+2])]++
+		}
+		return counts
+	}
+
+	left, right := bigrams(a), bigrams(b)
+	total, shared := 0, 0
+	for gram, count := range left {
+		total += count
+		if other, ok := right[gram]; ok {
+			if other < count {
+				shared += other
+			} else {
+				shared += count
+			}
+		}
+	}
+	for _, count := range right {
+		total += count
+	}
+	if total == 0 {
+		return 0
+	}
+
+	return 2 * float64(shared) / float64(total)
+}
+
+// DetectDuplicates mencari kemungkinan duplikat candidate di antara others.
+// Dokumen dengan hash yang sama selalu dianggap duplikat; selain itu judul harus mirip,
+// kategori sama dan tanggal berdekatan. Hasil diurutkan dari skor tertinggi.
+func DetectDuplicates(candidate *models.Achievement, others []models.Achievement, cfg DuplicateConfig) []models.DuplicateMatch {
+	hashes := make(map[string]bool)
+	for _, doc := range candidate.Documents {
+		if doc.Hash != "" {
+			hashes[doc.Hash] = true
+		}
+	}
+
+	matches := []models.DuplicateMatch{}
+	for _, other := range others {
+		if other.AchievementID == candidate.AchievementID || other.IsDeleted || other.Status == "rejected" {
+			continue
+		}
+
+		sharedDocument := false
+		for _, doc := range other.Documents {
+			if doc.Hash != "" && hashes[doc.Hash] {
+				sharedDocument = true
+				break
+			}
+		}
+
+		gap := candidate.Date.Sub(other.Date)
+		if gap < 0 {
+			gap = -gap
+		}
+		closeDate := gap <= cfg.DateWindow
+		sameCategory := candidate.Category == other.Category
+		similarity := TitleSimilarity(candidate.Title, other.Title)
+		similarTitle := similarity >= cfg.TitleSimilarity
+
+		if !sharedDocument && !(similarTitle && closeDate && sameCategory) {
+			continue
+		}
+
+		match := models.DuplicateMatch{
+			AchievementID: other.AchievementID,
+			StudentID:     other.StudentID,
+			Title:         other.Title,
+			Status:        other.Status,
+			Score:         similarity,
+			Reasons:       []string{},
+			OtherStudent:  other.StudentID != candidate.StudentID,
+		}
+		if sharedDocument {
+			match.Score = 1
+			match.Reasons = append(match.Reasons, "document_hash")
+		}
+		if similarTitle {
+			match.Reasons = append(match.Reasons, "title")
+		}
+		if closeDate {
+			match.Reasons = append(match.Reasons, "date")
+		}
+		if sameCategory {
+			match.Reasons = append(match.Reasons, "category")
+		}
+		matches = append(matches, match)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return matches
+}
+
+// BuildDuplicateClusters menggabungkan pasangan flag duplikat menjadi kelompok achievement yang saling terhubung.
+// Urutan kelompok dan anggota mengikuti kemunculan pertama di flags.
+func BuildDuplicateClusters(flags []models.AchievementDuplicateFlag) [][]string {
+	parent := make(map[string]string)
+	order := []string{}
+
+	var find func(id string) string
+	find = func(id string) string {
+		if _, ok := parent[id]; !ok {
+			parent[id] = id
+			order = append(order, id)
+		}
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	for _, flag := range flags {
+		left, right := find(flag.MongoAchievementID), find(flag.DuplicateOfID)
+		if left != right {
+			parent[right] = left
+		}
+	}
+
+	index := make(map[string]int)
+	clusters := [][]string{}
+	for _, id := range order {
+		root := find(id)
+		i, ok := index[root]
+		if !ok {
+			i = len(clusters)
+			index[root] = i
+			clusters = append(clusters, []string{})
+		}
+		clusters[i] = append(clusters[i], id)
+	}
+
+	return clusters
+}
+
+// findDuplicates mencari kemungkinan duplikat achievement dari semua mahasiswa
+func (s *AchievementService) findDuplicates(ctx context.Context, achievement *models.Achievement) ([]models.DuplicateMatch, error) {
+	candidates := bson.A{
+		bson.M{"date": bson.M{
+			"$gte": achievement.Date.Add(-s.duplicateConfig.DateWindow),
+			"$lte": achievement.Date.Add(s.duplicateConfig.DateWindow),
+		}},
+	}
+
+	hashes := []string{}
+	for _, doc := range achievement.Documents {
+		if doc.Hash != "" {
+			hashes = append(hashes, doc.Hash)
+		}
+	}
+	if len(hashes) > 0 {
+		candidates = append(candidates, bson.M{"documents.hash": bson.M{"$in": hashes}})
+	}
+
+	others, err := s.achievementRepo.FindAll(ctx, bson.M{
+		"achievement_id": bson.M{"$ne": achievement.AchievementID},
+		"is_deleted":     false,
+		"$or":            candidates,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return DetectDuplicates(achievement, others, s.duplicateConfig), nil
+}
+
+// duplicateWarnings menyembunyikan identitas achievement milik mahasiswa lain sebelum dikirim ke mahasiswa
+func duplicateWarnings(matches []models.DuplicateMatch) []models.DuplicateMatch {
+	warnings := make([]models.DuplicateMatch, 0, len(matches))
+	for _, match := range matches {
+		if match.OtherStudent {
+			match.AchievementID = ""
+			match.StudentID = ""
+			match.Title = ""
+			match.Status = ""
+		}
+		warnings = append(warnings, match)
+	}
+	return warnings
+}
+
+// recordDuplicateFlags menyimpan hasil deteksi untuk verifikator dan laporan admin
+func (s *AchievementService) recordDuplicateFlags(achievementID string, matches []models.DuplicateMatch) error {
+	now := time.Now()
+	flags := make([]models.AchievementDuplicateFlag, 0, len(matches))
+	for _, match := range matches {
+		flags = append(flags, models.AchievementDuplicateFlag{
+			MongoAchievementID: achievementID,
+			DuplicateOfID:      match.AchievementID,
+			Score:              match.Score,
+			Reasons:            match.Reasons,
+			DetectedAt:         now,
+		})
+	}
+	return s.duplicateRepo.ReplaceForAchievement(achievementID, flags)
+}
+
+// flaggedDuplicates mengambil flag duplikat sebuah achievement beserta data achievement pasangannya (untuk verifikator)
+func (s *AchievementService) flaggedDuplicates(ctx context.Context, achievement *models.Achievement) ([]models.DuplicateMatch, error) {
+	flags, err := s.duplicateRepo.FindByAchievement(achievement.AchievementID)
+	if err != nil {
+		return nil, err
+	}
+	if len(flags) == 0 {
+		return []models.DuplicateMatch{}, nil
+	}
+
+	otherIDs := []string{}
+	for _, flag := range flags {
+		if flag.MongoAchievementID == achievement.AchievementID {
+			otherIDs = append(otherIDs, flag.DuplicateOfID)
+		} else {
+			otherIDs = append(otherIDs, flag.MongoAchievementID)
+		}
+	}
+
+	others, err := s.achievementRepo.FindByAchievementIDs(ctx, otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]models.Achievement)
+	for _, other := range others {
+		byID[other.AchievementID] = other
+	}
+
+	matches := []models.DuplicateMatch{}
+	for i, flag := range flags {
+		other, ok := byID[otherIDs[i]]
+		if !ok {
+			continue
+		}
+		matches = append(matches, models.DuplicateMatch{
+			AchievementID: other.AchievementID,
+			StudentID:     other.StudentID,
+			Title:         other.Title,
+			Status:        other.Status,
+			Score:         flag.Score,
+			Reasons:       flag.Reasons,
+			OtherStudent:  other.StudentID != achievement.StudentID,
+			DetectedAt:    flag.DetectedAt,
+		})
+	}
+
+	return matches, nil
+}
+
+// GetDuplicateReport godoc
+// @Summary Duplicate achievement clusters
+// @Description Admin report of achievements flagged as likely duplicates when submitted for verification, grouped into clusters of connected achievements. Deleted achievements are excluded.
+// @Tags Statistics & Reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=object{clusters=[]models.DuplicateCluster,total=int}} "Duplicate clusters retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.duplicates)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve duplicate flags"
+// @Router /reports/duplicates [get]
+func (s *AchievementService) GetDuplicateReport(c *fiber.Ctx) error {
+	flags, err := s.duplicateRepo.FindActive()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data duplikat",
+		})
+	}
+
+	groups := BuildDuplicateClusters(flags)
+
+	ids := []string{}
+	for _, group := range groups {
+		ids = append(ids, group...)
+	}
+
+	ctx := context.Background()
+	achievements, err := s.achievementRepo.FindByAchievementIDs(ctx, ids)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data achievements",
+		})
+	}
+	byID := make(map[string]models.Achievement)
+	for _, achievement := range achievements {
+		byID[achievement.AchievementID] = achievement
+	}
+
+	clusterOf := make(map[string]int)
+	clusters := make([]models.DuplicateCluster, len(groups))
+	for i, group := range groups {
+		clusters[i] = models.DuplicateCluster{
+			AchievementIDs: group,
+			Achievements:   []models.Achievement{},
+			Flags:          []models.AchievementDuplicateFlag{},
+		}
+		for _, id := range group {
+			clusterOf[id] = i
+			if achievement, ok := byID[id]; ok {
+				clusters[i].Achievements = append(clusters[i].Achievements, achievement)
+			}
+		}
+	}
+	for _, flag := range flags {
+		i := clusterOf[flag.MongoAchievementID]
+		clusters[i].Flags = append(clusters[i].Flags, flag)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Laporan duplikat prestasi berhasil diambil",
+		"data": fiber.Map{
+			"clusters": clusters,
+			"total":    len(clusters),
+		},
+	})
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"encoding/csv"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Nama kolom file import (header tidak peka huruf besar/kecil, spasi boleh dipakai sebagai pemisah)
+var importColumnAliases = map[string]string{
+	"nim":           "nim",
+	"npm":           "nim",
+	"student_id":    "nim",
+	"title":         "title",
+	"judul":         "title",
+	"nama_prestasi": "title",
+	"category":      "category",
+	"kategori":      "category",
+	"level":         "level",
+	"tingkat":       "level",
+	"date":          "date",
+	"tanggal":       "date",
+	"description":   "description",
+	"deskripsi":     "description",
+	"keterangan":    "description",
+	"tags":          "tags",
+	"tag":           "tags",
+}
+
+var requiredImportColumns = []string{"nim", "title", "category", "level", "date"}
+
+// Format tanggal yang diterima di file import (selain serial date Excel)
+var importDateLayouts = []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "2006/01/02"}
+
+// ImportReferenceData data pembanding untuk validasi baris import
+type ImportReferenceData struct {
+	Students   map[string]models.StudentDetail // per NIM
+	Categories []models.AchievementCategory    // hanya yang aktif
+	Levels     []models.AchievementLevel       // hanya yang aktif
+	Existing   []models.AchievementTitleKey    // achievement yang sudah ada milik mahasiswa di file
+	Now        time.Time
+}
+
+// ParseImportFile membaca file CSV atau XLSX (sheet pertama). Baris pertama adalah header;
+// kolom "details.<field>" diisikan ke details achievement, kolom lain yang tidak dikenal diabaikan.
+func ParseImportFile(filename string, content []byte) (*models.ImportSheet, error) {
+	var records [][]string
+	var err error
+
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		records, err = readImportCSV(content)
+	case ".xlsx":
+		records, err = readImportXLSX(content)
+	default:
+		return nil, fmt.Errorf("Format file harus .csv atau .xlsx")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return importSheetFromRecords(records)
+}
+
+func readImportCSV(content []byte) ([][]string, error) {
+	content = bytes.TrimPrefix(content, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Excel dengan locale Indonesia menyimpan CSV dengan pemisah titik koma
+	firstLine := content
+	if i := bytes.IndexByte(content, '\n'); i >= 0 {
+		firstLine = content[:i]
+	}
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("File CSV tidak valid: %v", err)
+	}
+	return records, nil
+}
+
+func readImportXLSX(content []byte) ([][]string, error) {
+	file, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("File XLSX tidak valid: %v", err)
+	}
+	defer file.Close()
+
+	sheets := file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, fmt.Errorf("File XLSX tidak memiliki sheet")
+	}
+
+	// Nilai mentah: tanggal dibaca sebagai serial date, bukan hasil format tampilan
+	rows, err := file.GetRows(sheets[0], excelize.Options{RawCellValue: true})
+	if err != nil {
+		return nil, fmt.Errorf("Gagal membaca sheet '%s': %v", sheets[0], err)
+	}
+	return rows, nil
+}
+
+// importSheetFromRecords memetakan kolom berdasarkan header dan mengubah setiap baris tidak kosong menjadi ImportRow
+func importSheetFromRecords(records [][]string) (*models.ImportSheet, error) {
+	if len(records) == 0 {
+		return nil, fmt.Errorf("File kosong")
+	}
+
+	columns := make(map[string]int)
+	detailColumns := make(map[string]int)
+	sheet := &models.ImportSheet{IgnoredColumns: []string{}}
+
+	for i, header := range records[0] {
+		name := strings.ToLower(strings.Join(strings.Fields(strings.ReplaceAll(header, "-", " ")), "_"))
+		if name == "" {
+			continue
+		}
+		if field, ok := strings.CutPrefix(name, "details."); ok && field != "" {
+			detailColumns[field] = i
+			continue
+		}
+		column, ok := importColumnAliases[name]
+		if !ok {
+			sheet.IgnoredColumns = append(sheet.IgnoredColumns, strings.TrimSpace(header))
+			continue
+		}
+		if _, duplicate := columns[column]; duplicate {
+			return nil, fmt.Errorf("Kolom '%s' muncul lebih dari sekali", column)
+		}
+		columns[column] = i
+	}
+
+	var missing []string
+	for _, column := range requiredImportColumns {
+		if _, ok := columns[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Kolom wajib tidak ditemukan: %s", strings.Join(missing, ", "))
+	}
+
+	cell := func(record []string, index int) string {
+		if index < len(record) {
+			return strings.TrimSpace(record[index])
+		}
+		return ""
+	}
+	optional := func(record []string, column string) string {
+		if index, ok := columns[column]; ok {
+			return cell(record, index)
+		}
+		return ""
+	}
+
+	sheet.Rows = []models.ImportRow{}
+	for i, record := range records[1:] {
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		row := models.ImportRow{
+			Line:        i + 2,
+			NIM:         cell(record, columns["nim"]),
+			Title:       cell(record, columns["title"]),
+			Category:    cell(record, columns["category"]),
+			Level:       cell(record, columns["level"]),
+			Date:        cell(record, columns["date"]),
+			Description: optional(record, "description"),
+			Tags:        optional(record, "tags"),
+		}
+		for field, index := range detailColumns {
+			if value := cell(record, index); value != "" {
+				if row.Details == nil {
+					row.Details = make(map[string]string)
+				}
+				row.Details[field] = value
+			}
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+
+	return sheet, nil
+}
+
+// ParseImportDate membaca tanggal dari file import: YYYY-MM-DD, DD/MM/YYYY, DD-MM-YYYY, YYYY/MM/DD
+// atau serial date Excel
+func ParseImportDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range importDateLayouts {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		date, err := excelize.ExcelDateToTime(serial, false)
+		if err == nil {
+			return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("Format tanggal '%s' tidak valid (gunakan YYYY-MM-DD atau DD/MM/YYYY)", value)
+}
+
+// parseImportDetailValue mengubah isi sel details menjadi angka atau boolean jika memungkinkan,
+// agar bisa divalidasi terhadap schema kategori
+func parseImportDetailValue(value string) interface{} {
+	if number, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return number
+	}
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number
+	}
+	switch strings.ToLower(value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return value
+}
+
+func importTitleKey(studentID, title string, date time.Time) string {
+	return studentID + "|" + strings.ToLower(strings.Join(strings.Fields(title), " ")) + "|" + date.Format("2006-01-02")
+}
+
+// ValidateImportRows memvalidasi setiap baris: NIM terdaftar, field wajib, kategori/level sesuai
+// master data, tanggal, tag, serta baris ganda di file maupun achievement yang sudah ada.
+// Details belum divalidasi terhadap schema kategori.
+func ValidateImportRows(rows []models.ImportRow, ref ImportReferenceData) []models.ImportRowResult {
+	existing := make(map[string]bool, len(ref.Existing))
+	for _, key := range ref.Existing {
+		if key.Date != nil {
+			existing[importTitleKey(key.StudentID, key.Title, *key.Date)] = true
+		}
+	}
+	seen := make(map[string]int)
+
+	results := make([]models.ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		result := models.ImportRowResult{
+			Line:        row.Line,
+			NIM:         row.NIM,
+			Title:       row.Title,
+			Description: row.Description,
+		}
+		fail := func(format string, args ...interface{}) {
+			result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		if row.NIM == "" {
+			fail("NIM wajib diisi")
+		} else if student, ok := ref.Students[row.NIM]; ok {
+			result.StudentID = student.UserID
+			result.StudentName = student.FullName
+		} else {
+			fail("NIM '%s' tidak terdaftar atau akun mahasiswa tidak aktif", row.NIM)
+		}
+
+		if row.Title == "" {
+			fail("Judul wajib diisi")
+		}
+
+		if row.Category == "" {
+			fail("Kategori wajib diisi")
+		} else if code, ok := ResolveCategoryCode(ref.Categories, row.Category); ok {
+			result.Category = code
+		} else {
+			fail("Kategori '%s' tidak terdaftar di master data", row.Category)
+		}
+
+		if row.Level == "" {
+			fail("Level wajib diisi")
+		} else if code, ok := ResolveLevelCode(ref.Levels, row.Level); ok {
+			result.Level = code
+		} else {
+			fail("Level '%s' tidak terdaftar di master data", row.Level)
+		}
+
+		if row.Date == "" {
+			fail("Tanggal wajib diisi")
+		} else if date, err := ParseImportDate(row.Date); err != nil {
+			fail("%s", err.Error())
+		} else if date.After(ref.Now) {
+			fail("Tanggal prestasi tidak boleh di masa depan")
+		} else {
+			result.Date = &date
+		}
+
+		if row.Tags != "" {
+			tags, err := NormalizeTags(strings.FieldsFunc(row.Tags, func(r rune) bool { return r == ',' || r == ';' }))
+			if err != nil {
+				fail("%s", err.Error())
+			}
+			result.Tags = tags
+		}
+
+		if len(row.Details) > 0 {
+			result.Details = make(map[string]interface{}, len(row.Details))
+			for field, value := range row.Details {
+				result.Details[field] = parseImportDetailValue(value)
+			}
+		}
+
+		if result.StudentID != "" && result.Title != "" && result.Date != nil {
+			key := importTitleKey(result.StudentID, result.Title, *result.Date)
+			if line, ok := seen[key]; ok {
+				fail("Duplikat dengan baris %d pada file", line)
+			} else {
+				seen[key] = row.Line
+			}
+			if existing[key] {
+				fail("Achievement dengan judul dan tanggal yang sama sudah ada untuk mahasiswa ini")
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,450 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Progress job import disimpan setiap sekian baris
+const importProgressInterval = 10
+
+// AchievementImportService import achievement massal dari CSV/XLSX (dry-run atau job background)
+type AchievementImportService struct {
+	importRepo    *repository.AchievementImportRepository
+	studentRepo   *repository.StudentRepository
+	readModelRepo *repository.AchievementReadModelRepository
+	masterRepo    *repository.MasterDataRepository
+	schemaRepo    *repository.AchievementSchemaRepository
+	referenceRepo *repository.AchievementReferenceRepository
+	outboxRepo    *repository.OutboxRepository
+	creditRepo    *repository.CreditPointRepository
+	relay         *OutboxRelay
+	notifier      *NotificationService
+	verifications *VerificationRecordService
+	maxRows       int
+}
+
+func NewAchievementImportService(mongoDB *mongo.Database, postgresDB *sql.DB) *AchievementImportService {
+	return &AchievementImportService{
+		importRepo:    repository.NewAchievementImportRepository(postgresDB),
+		studentRepo:   repository.NewStudentRepository(postgresDB),
+		readModelRepo: repository.NewAchievementReadModelRepository(postgresDB),
+		masterRepo:    repository.NewMasterDataRepository(postgresDB),
+		schemaRepo:    repository.NewAchievementSchemaRepository(postgresDB),
+		referenceRepo: repository.NewAchievementReferenceRepository(postgresDB),
+		outboxRepo:    repository.NewOutboxRepository(postgresDB),
+		creditRepo:    repository.NewCreditPointRepository(postgresDB),
+		relay:         NewOutboxRelay(mongoDB, postgresDB),
+		notifier:      NewNotificationService(postgresDB),
+		verifications: NewVerificationRecordService(mongoDB, postgresDB),
+		maxRows:       utils.GetEnvInt("IMPORT_MAX_ROWS", 1000),
+	}
+}
+
+// validateRows memvalidasi baris import terhadap data mahasiswa, master data, achievement yang
+// sudah ada dan schema details kategori
+func (s *AchievementImportService) validateRows(rows []models.ImportRow) ([]models.ImportRowResult, error) {
+	var nims []string
+	for _, row := range rows {
+		if row.NIM != "" {
+			nims = append(nims, row.NIM)
+		}
+	}
+
+	students, err := s.studentRepo.FindByStudentNumbers(nims)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := s.masterRepo.FindAllCategories(true)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.masterRepo.FindAllLevels(true)
+	if err != nil {
+		return nil, err
+	}
+
+	studentIDs := make([]string, 0, len(students))
+	for _, student := range students {
+		studentIDs = append(studentIDs, student.UserID)
+	}
+	existing, err := s.readModelRepo.FindTitleKeys(studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := ValidateImportRows(rows, ImportReferenceData{
+		Students:   students,
+		Categories: categories,
+		Levels:     levels,
+		Existing:   existing,
+		Now:        time.Now(),
+	})
+
+	for i := range results {
+		if results[i].Category == "" {
+			continue
+		}
+		violations, err := validateDetailsForCategory(s.schemaRepo, CategoryLineage(categories, results[i].Category), results[i].Details)
+		if err != nil {
+			return nil, err
+		}
+		for _, violation := range violations {
+			results[i].Errors = append(results[i].Errors, "details: "+violation)
+		}
+	}
+
+	return results, nil
+}
+
+// ImportAchievements godoc
+// @Summary Import achievements from CSV/XLSX
+// @Description Bulk import achievements from a spreadsheet (first sheet for XLSX; comma or semicolon separated CSV). The header row must contain nim, title, category, level and date (Indonesian names such as judul, kategori, tingkat, tanggal are accepted); description and tags are optional, and columns named details.<field> fill the category-specific details. Rows are mapped to students by NIM and validated against master data, category schemas and existing achievements. With dry_run=true (the default) only the per-row preview is returned. Otherwise the valid rows are created in a background job (as drafts, or verified when pre_verified=true) whose progress can be polled at /achievement-imports/{id}.
+// @Tags Achievement Imports
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV or XLSX file"
+// @Param dry_run formData boolean false "Only validate and preview (default true)"
+// @Param pre_verified formData boolean false "Create the achievements as verified (default false)"
+// @Success 200 {object} object{status=string,message=string,data=models.ImportPreview} "Dry-run preview"
+// @Success 202 {object} object{status=string,message=string,data=object{job=models.AchievementImportJob,preview=models.ImportPreview}} "Import job started"
+// @Failure 400 {object} map[string]interface{} "Invalid file, missing columns, too many rows, or no valid rows"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.import)"
+// @Failure 500 {object} map[string]interface{} "Failed to validate or start the import"
+// @Router /achievement-imports [post]
+func (s *AchievementImportService) ImportAchievements(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "File import (field 'file') harus diisi",
+		})
+	}
+
+	dryRun := true
+	if value := c.FormValue("dry_run"); value != "" {
+		dryRun = value == "true" || value == "1"
+	}
+	preVerified := c.FormValue("pre_verified") == "true" || c.FormValue("pre_verified") == "1"
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membaca file import",
+		})
+	}
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membaca file import",
+		})
+	}
+
+	sheet, err := ParseImportFile(fileHeader.Filename, content)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+	if len(sheet.Rows) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "File tidak berisi baris data",
+		})
+	}
+	if len(sheet.Rows) > s.maxRows {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("Maksimal %d baris per import, file berisi %d baris", s.maxRows, len(sheet.Rows)),
+		})
+	}
+
+	results, err := s.validateRows(sheet.Rows)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memvalidasi data import",
+		})
+	}
+
+	preview := models.ImportPreview{
+		Filename:       fileHeader.Filename,
+		IgnoredColumns: sheet.IgnoredColumns,
+		TotalRows:      len(results),
+		Rows:           results,
+	}
+	var validRows []models.ImportRowResult
+	var rowErrors []models.ImportRowError
+	for _, result := range results {
+		if len(result.Errors) == 0 {
+			validRows = append(validRows, result)
+			continue
+		}
+		rowErrors = append(rowErrors, models.ImportRowError{Line: result.Line, NIM: result.NIM, Errors: result.Errors})
+	}
+	preview.ValidRows = len(validRows)
+	preview.InvalidRows = len(rowErrors)
+
+	if dryRun {
+		return c.Status(200).JSON(fiber.Map{
+			"status":  "success",
+			"message": fmt.Sprintf("Dry-run selesai: %d baris valid, %d baris bermasalah", preview.ValidRows, preview.InvalidRows),
+			"data":    preview,
+		})
+	}
+
+	if len(validRows) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tidak ada baris valid untuk diimport",
+			"data":    preview,
+		})
+	}
+
+	// Baris yang tidak valid langsung dicatat sebagai gagal; hanya baris valid yang diproses job
+	if rowErrors == nil {
+		rowErrors = []models.ImportRowError{}
+	}
+	job := &models.AchievementImportJob{
+		ID:            uuid.New(),
+		Filename:      fileHeader.Filename,
+		Status:        models.ImportJobPending,
+		PreVerified:   preVerified,
+		TotalRows:     len(results),
+		ProcessedRows: len(rowErrors),
+		FailedCount:   len(rowErrors),
+		Errors:        rowErrors,
+		CreatedAt:     time.Now(),
+	}
+	if createdBy, err := uuid.Parse(userID); err == nil {
+		job.CreatedBy = &createdBy
+	}
+
+	if err := s.importRepo.Create(job); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membuat job import",
+		})
+	}
+
+	go s.run(*job, validRows, userID)
+
+	return c.Status(202).JSON(fiber.Map{
+		"status":  "success",
+		"message": fmt.Sprintf("Import %d achievement sedang diproses", len(validRows)),
+		"data": fiber.Map{
+			"job":     job,
+			"preview": preview,
+		},
+	})
+}
+
+// run membuat achievement untuk setiap baris valid dan menyimpan progress job secara berkala
+func (s *AchievementImportService) run(job models.AchievementImportJob, rows []models.ImportRowResult, importedBy string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Job import %s berhenti: %v", job.ID, r)
+			job.Status = models.ImportJobFailed
+			finishedAt := time.Now()
+			job.FinishedAt = &finishedAt
+			if err := s.importRepo.MarkFinished(&job); err != nil {
+				log.Printf("Gagal menyimpan status job import %s: %v", job.ID, err)
+			}
+		}
+	}()
+
+	if err := s.importRepo.MarkStarted(job.ID.String()); err != nil {
+		log.Printf("Gagal mengubah status job import %s: %v", job.ID, err)
+	}
+
+	ctx := context.Background()
+	for i := range rows {
+		if err := s.createAchievement(ctx, &rows[i], job.PreVerified, importedBy); err != nil {
+			log.Printf("Job import %s: baris %d gagal dibuat: %v", job.ID, rows[i].Line, err)
+			job.FailedCount++
+			job.Errors = append(job.Errors, models.ImportRowError{
+				Line:   rows[i].Line,
+				NIM:    rows[i].NIM,
+				Errors: []string{"Gagal menyimpan achievement"},
+			})
+		} else {
+			job.CreatedCount++
+		}
+		job.ProcessedRows++
+
+		if (i+1)%importProgressInterval == 0 {
+			if err := s.importRepo.UpdateProgress(&job); err != nil {
+				log.Printf("Gagal menyimpan progress job import %s: %v", job.ID, err)
+			}
+		}
+	}
+
+	job.Status = models.ImportJobCompleted
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err := s.importRepo.MarkFinished(&job); err != nil {
+		log.Printf("Gagal menyimpan status job import %s: %v", job.ID, err)
+	}
+}
+
+// createAchievement membuat satu achievement hasil import (draft, atau langsung verified) lewat outbox
+func (s *AchievementImportService) createAchievement(ctx context.Context, row *models.ImportRowResult, preVerified bool, importedBy string) error {
+	achievementID := uuid.New().String()
+	now := time.Now()
+
+	status := "draft"
+	if preVerified {
+		status = "verified"
+	}
+
+	achievement := &models.Achievement{
+		ID:            primitive.NewObjectID(),
+		AchievementID: achievementID,
+		StudentID:     row.StudentID,
+		Title:         row.Title,
+		Category:      row.Category,
+		Level:         row.Level,
+		Date:          *row.Date,
+		Description:   row.Description,
+		Details:       row.Details,
+		Documents:     []models.Document{},
+		Tags:          row.Tags,
+		Status:        status,
+		Version:       1,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	studentID, err := uuid.Parse(row.StudentID)
+	if err != nil {
+		return err
+	}
+	reference := &models.AchievementReferences{
+		ID:                 uuid.New(),
+		StudentID:          studentID,
+		MongoAchievementID: achievementID,
+		Status:             status,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if preVerified {
+		reference.SubmittedAt = &now
+		reference.VerifiedAt = &now
+		if verifiedBy, err := uuid.Parse(importedBy); err == nil {
+			reference.VerifiedBy = &verifiedBy
+		}
+	}
+
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementCreated, achievement)
+	if err != nil {
+		return err
+	}
+	err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+		return s.referenceRepo.CreateTx(tx, reference)
+	}, event)
+	if err != nil {
+		return err
+	}
+
+	// Terapkan ke MongoDB sekarang; jika gagal, relay worker akan mencoba lagi
+	s.relay.Dispatch(ctx, event)
+
+	if preVerified {
+		if err := awardCreditPoints(s.creditRepo, s.masterRepo, achievement); err != nil {
+			log.Printf("Gagal menghitung poin achievement %s: %v", achievementID, err)
+		}
+		s.verifications.IssueAfterVerification(ctx, achievementID)
+	}
+
+	message := fmt.Sprintf("Prestasi '%s' ditambahkan oleh bagian kemahasiswaan sebagai draft. Lengkapi dokumen pendukung lalu ajukan verifikasi.", achievement.Title)
+	if preVerified {
+		message = fmt.Sprintf("Prestasi '%s' ditambahkan oleh bagian kemahasiswaan dan sudah terverifikasi.", achievement.Title)
+	}
+	s.notifier.Notify(row.StudentID, "achievement_imported", "Prestasi ditambahkan", message, achievementID)
+
+	return nil
+}
+
+// GetImportJobs godoc
+// @Summary Get achievement import jobs
+// @Description Get the 50 most recent achievement import jobs with their progress.
+// @Tags Achievement Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.AchievementImportJob} "Import jobs retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.import)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve import jobs"
+// @Router /achievement-imports [get]
+func (s *AchievementImportService) GetImportJobs(c *fiber.Ctx) error {
+	jobs, err := s.importRepo.FindRecent(50)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data job import",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data job import berhasil diambil",
+		"data":    jobs,
+	})
+}
+
+// GetImportJob godoc
+// @Summary Get achievement import job progress
+// @Description Poll the progress of an achievement import job: status (pending, running, completed, failed), processed/created/failed row counts and per-row errors.
+// @Tags Achievement Imports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Import job ID"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementImportJob} "Import job retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.import)"
+// @Failure 404 {object} map[string]interface{} "Import job not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve import job"
+// @Router /achievement-imports/{id} [get]
+func (s *AchievementImportService) GetImportJob(c *fiber.Ctx) error {
+	job, err := s.importRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data job import",
+		})
+	}
+	if job == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Job import tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data job import berhasil diambil",
+		"data":    job,
+	})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"log"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// rebuildBatchSize jumlah dokumen MongoDB yang dibaca per batch saat rebuild read model
+const rebuildBatchSize = 500
+
+// AchievementProjector menjaga tabel achievement_read_model tetap sinkron dengan
+// achievement_references (PostgreSQL), dokumen MongoDB dan data mahasiswa
+type AchievementProjector struct {
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	readModelRepo   *repository.AchievementReadModelRepository
+}
+
+func NewAchievementProjector(mongoDB *mongo.Database, postgresDB *sql.DB) *AchievementProjector {
+	return &AchievementProjector{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(postgresDB),
+		readModelRepo:   repository.NewAchievementReadModelRepository(postgresDB),
+	}
+}
+
+// Project memproyeksikan ulang satu achievement; proyeksi dihapus jika dokumen atau reference aktifnya tidak ada
+func (p *AchievementProjector) Project(ctx context.Context, achievementID string) error {
+	achievement, err := p.achievementRepo.FindByID(ctx, achievementID)
+	if err == mongo.ErrNoDocuments {
+		return p.readModelRepo.Delete(achievementID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return p.project(achievement)
+}
+
+func (p *AchievementProjector) project(achievement *models.Achievement) error {
+	projected, err := p.readModelRepo.Upsert(achievement)
+	if err != nil {
+		return err
+	}
+	if !projected {
+		return p.readModelRepo.Delete(achievement.AchievementID)
+	}
+	return nil
+}
+
+// Refresh memproyeksikan ulang achievement setelah perubahan. Kegagalan hanya dicatat karena
+// perubahan utamanya sudah tersimpan; proyeksi bisa diperbaiki dengan rebuild.
+func (p *AchievementProjector) Refresh(ctx context.Context, achievementIDs ...string) {
+	for _, id := range achievementIDs {
+		if err := p.Project(ctx, id); err != nil {
+			log.Printf("Read model: gagal memproyeksikan achievement %s: %v", id, err)
+		}
+	}
+}
+
+// RefreshStudent memperbarui data mahasiswa (nama, program studi, angkatan, dosen wali) pada proyeksi
+func (p *AchievementProjector) RefreshStudent(userID string) {
+	if err := p.readModelRepo.RefreshStudent(userID); err != nil {
+		log.Printf("Read model: gagal memperbarui data mahasiswa %s: %v", userID, err)
+	}
+}
+
+// RefreshStudentProfile sama seperti RefreshStudent dengan ID profil students
+func (p *AchievementProjector) RefreshStudentProfile(studentID string) {
+	if err := p.readModelRepo.RefreshStudentProfile(studentID); err != nil {
+		log.Printf("Read model: gagal memperbarui data profil mahasiswa %s: %v", studentID, err)
+	}
+}
+
+// Rebuild membangun ulang seluruh read model dari PostgreSQL dan MongoDB.
+// Proyeksi yang tidak tersentuh (achievement sudah tidak aktif) dihapus di akhir.
+func (p *AchievementProjector) Rebuild(ctx context.Context) (projected int, removed int64, err error) {
+	startedAt, err := p.readModelRepo.Now()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	states, err := p.referenceRepo.FindAllStates()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ids := []string{}
+	for _, state := range states {
+		if !state.IsDeleted {
+			ids = append(ids, state.AchievementID)
+		}
+	}
+
+	for start := 0; start < len(ids); start += rebuildBatchSize {
+		end := start + rebuildBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		achievements, err := p.achievementRepo.FindByAchievementIDs(ctx, ids[start:end])
+		if err != nil {
+			return projected, 0, err
+		}
+		for i := range achievements {
+			if err := p.project(&achievements[i]); err != nil {
+				return projected, 0, err
+			}
+			projected++
+		}
+	}
+
+	removed, err = p.readModelRepo.DeleteProjectedBefore(startedAt)
+	return projected, removed, err
+}
@@ -0,0 +1,327 @@
+package service
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+type AchievementSchemaService struct {
+	schemaRepo *repository.AchievementSchemaRepository
+}
+
+func NewAchievementSchemaService(db *sql.DB) *AchievementSchemaService {
+	return &AchievementSchemaService{
+		schemaRepo: repository.NewAchievementSchemaRepository(db),
+	}
+}
+
+// CompileAchievementSchema mengompilasi JSON Schema detail achievement (draft 2020-12 jika $schema kosong)
+func CompileAchievementSchema(schema []byte) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+
+	if err := compiler.AddResource("details.json", bytes.NewReader(schema)); err != nil {
+		return nil, err
+	}
+
+	return compiler.Compile("details.json")
+}
+
+// ValidateAchievementDetails memvalidasi details terhadap schema kategori.
+// Mengembalikan daftar pelanggaran (kosong jika valid) atau error jika schema tidak valid.
+func ValidateAchievementDetails(schema []byte, details map[string]interface{}) ([]string, error) {
+	compiled, err := CompileAchievementSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateCompiledDetails(compiled, details)
+}
+
+// compiledSchemas cache schema terkompilasi per kategori; entri diganti saat versi (updated_at) berubah
+var compiledSchemas sync.Map
+
+type compiledCategorySchema struct {
+	version time.Time
+	schema  *jsonschema.Schema
+}
+
+// CompiledCategorySchema mengompilasi schema kategori, memakai cache selama schema belum diubah
+func CompiledCategorySchema(schema *models.AchievementCategorySchema) (*jsonschema.Schema, error) {
+	if cached, ok := compiledSchemas.Load(schema.Category); ok {
+		if entry := cached.(compiledCategorySchema); entry.version.Equal(schema.UpdatedAt) {
+			return entry.schema, nil
+		}
+	}
+
+	compiled, err := CompileAchievementSchema(schema.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	compiledSchemas.Store(schema.Category, compiledCategorySchema{version: schema.UpdatedAt, schema: compiled})
+	return compiled, nil
+}
+
+// validateCompiledDetails memvalidasi details terhadap schema yang sudah dikompilasi
+func validateCompiledDetails(compiled *jsonschema.Schema, details map[string]interface{}) ([]string, error) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+
+	// Normalisasi lewat JSON: details dari MongoDB bisa berisi int32/int64/primitive.A
+	raw, err := json.Marshal(details)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var instance interface{}
+	if err := decoder.Decode(&instance); err != nil {
+		return nil, err
+	}
+
+	err = compiled.Validate(instance)
+	if err == nil {
+		return []string{}, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, err
+	}
+
+	violations := []string{}
+	for _, unit := range validationErr.BasicOutput().Errors {
+		// Hanya error paling dalam yang informatif untuk client
+		if strings.HasPrefix(unit.Error, "doesn't validate with") {
+			continue
+		}
+		violations = append(violations, fmt.Sprintf("details%s: %s", unit.InstanceLocation, unit.Error))
+	}
+	if len(violations) == 0 {
+		violations = append(violations, validationErr.Message)
+	}
+
+	return violations, nil
+}
+
+// validateDetailsForCategory memvalidasi details terhadap schema pertama yang ditemukan
+// pada lineage kategori (kategori itu sendiri lalu induknya).
+// Mengembalikan daftar pelanggaran, atau error jika schema gagal diambil/dikompilasi.
+func validateDetailsForCategory(schemaRepo *repository.AchievementSchemaRepository, lineage []string, details map[string]interface{}) ([]string, error) {
+	for _, category := range lineage {
+		schema, err := schemaRepo.FindByCategory(category)
+		if err != nil {
+			return nil, err
+		}
+		if schema != nil {
+			compiled, err := CompiledCategorySchema(schema)
+			if err != nil {
+				return nil, err
+			}
+			return validateCompiledDetails(compiled, details)
+		}
+	}
+
+	// Kategori tanpa schema: details bebas
+	return []string{}, nil
+}
+
+// parseDetailsForm membaca field details dari form-data (string JSON) jika tidak ada di body JSON
+func parseDetailsForm(c *fiber.Ctx, details map[string]interface{}) (map[string]interface{}, error) {
+	if details != nil {
+		return details, nil
+	}
+
+	raw := strings.TrimSpace(c.FormValue("details"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var parsed map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
+// GetSchemas godoc
+// @Summary Get achievement detail schemas
+// @Description Get the JSON Schema of the structured details object for every category, so clients can render category-specific forms.
+// @Tags Achievement Schemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.AchievementCategorySchema} "Schemas retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve schemas"
+// @Router /achievement-schemas [get]
+func (s *AchievementSchemaService) GetSchemas(c *fiber.Ctx) error {
+	schemas, err := s.schemaRepo.FindAll()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil schema achievement",
+		})
+	}
+
+	if schemas == nil {
+		schemas = []models.AchievementCategorySchema{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data schema achievement berhasil diambil",
+		"data":    schemas,
+	})
+}
+
+// GetSchema godoc
+// @Summary Get achievement detail schema of a category
+// @Description Get the JSON Schema of the structured details object for one category (case-insensitive).
+// @Tags Achievement Schemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category path string true "Achievement category"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementCategorySchema} "Schema retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "No schema for this category"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve schema"
+// @Router /achievement-schemas/{category} [get]
+func (s *AchievementSchemaService) GetSchema(c *fiber.Ctx) error {
+	schema, err := s.schemaRepo.FindByCategory(c.Params("category"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil schema achievement",
+		})
+	}
+	if schema == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Schema untuk kategori ini tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data schema achievement berhasil diambil",
+		"data":    schema,
+	})
+}
+
+// UpsertSchema godoc
+// @Summary Create or update achievement detail schema
+// @Description Set the JSON Schema (draft 2020-12 by default) used to validate the details object of a category. The schema must compile and describe an object.
+// @Tags Achievement Schemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category path string true "Achievement category"
+// @Param request body object true "JSON Schema document"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementCategorySchema} "Schema saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid JSON Schema"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievement_schemas.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to save schema"
+// @Router /achievement-schemas/{category} [put]
+func (s *AchievementSchemaService) UpsertSchema(c *fiber.Ctx) error {
+	category := strings.ToLower(strings.TrimSpace(c.Params("category")))
+	userID, _ := c.Locals("user_id").(string)
+
+	body := c.Body()
+	var document map[string]interface{}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Schema harus berupa JSON object",
+		})
+	}
+
+	if schemaType, ok := document["type"]; ok && schemaType != "object" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Schema details harus bertipe 'object'",
+		})
+	}
+
+	if _, err := CompileAchievementSchema(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("JSON Schema tidak valid: %v", err),
+		})
+	}
+
+	schema := &models.AchievementCategorySchema{
+		Category:  category,
+		Schema:    json.RawMessage(body),
+		UpdatedAt: time.Now(),
+	}
+	if updatedBy, err := uuid.Parse(userID); err == nil {
+		schema.UpdatedBy = &updatedBy
+	}
+
+	if err := s.schemaRepo.Upsert(schema); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan schema achievement",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Schema achievement berhasil disimpan",
+		"data":    schema,
+	})
+}
+
+// DeleteSchema godoc
+// @Summary Delete achievement detail schema
+// @Description Remove the JSON Schema of a category. Details of that category are then no longer validated.
+// @Tags Achievement Schemas
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param category path string true "Achievement category"
+// @Success 200 {object} object{status=string,message=string} "Schema deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievement_schemas.manage)"
+// @Failure 404 {object} map[string]interface{} "Schema not found"
+// @Failure 500 {object} map[string]interface{} "Failed to delete schema"
+// @Router /achievement-schemas/{category} [delete]
+func (s *AchievementSchemaService) DeleteSchema(c *fiber.Ctx) error {
+	deleted, err := s.schemaRepo.Delete(c.Params("category"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus schema achievement",
+		})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Schema untuk kategori ini tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Schema achievement berhasil dihapus",
+	})
+}
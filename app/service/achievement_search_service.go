@@ -0,0 +1,333 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"fmt"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Batas tag per achievement
+const (
+	MaxAchievementTags = 10
+	MaxTagLength       = 32
+)
+
+// descriptionSnippetLength panjang maksimal potongan description pada highlight (dalam karakter)
+const descriptionSnippetLength = 200
+
+// NormalizeTags merapikan tag: huruf kecil, spasi tunggal, tanpa duplikat (urutan pertama dipertahankan)
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), " ")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, fmt.Errorf("Tag '%s' terlalu panjang (maksimal %d karakter)", tag, MaxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxAchievementTags {
+		return nil, fmt.Errorf("Maksimal %d tag per achievement", MaxAchievementTags)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// SearchTerms mengambil kata yang dicari dari query $text (kata negasi "-kata" diabaikan)
+func SearchTerms(query string) []string {
+	terms := []string{}
+	seen := make(map[string]bool)
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, term := range strings.Fields(NormalizeTitle(field)) {
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	// Kata terpanjang didahulukan agar tidak terpotong oleh kata yang lebih pendek
+	sort.SliceStable(terms, func(i, j int) bool { return len(terms[i]) > len(terms[j]) })
+	return terms
+}
+
+// Highlight menandai kata yang cocok dengan <mark> (teks lain di-escape HTML). Jika maxLength > 0 dan
+// teks lebih panjang, yang dikembalikan potongan di sekitar kecocokan pertama. Kosong jika tidak ada yang cocok.
+func Highlight(text string, terms []string, maxLength int) string {
+	matches := findWholeWords(text, terms)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	prefix, suffix := "", ""
+	if maxLength > 0 && utf8.RuneCountInString(text) > maxLength {
+		runes := []rune(text)
+		start := utf8.RuneCountInString(text[:matches[0][0]]) - maxLength/4
+		if start < 0 {
+			start = 0
+		}
+		end := start + maxLength
+		if end > len(runes) {
+			end = len(runes)
+		}
+		if start > 0 {
+			prefix = "…"
+		}
+		if end < len(runes) {
+			suffix = "…"
+		}
+		text = string(runes[start:end])
+		matches = findWholeWords(text, terms)
+	}
+
+	var b strings.Builder
+	b.WriteString(prefix)
+	last := 0
+	for _, match := range matches {
+		b.WriteString(html.EscapeString(text[last:match[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		b.WriteString("</mark>")
+		last = match[1]
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	b.WriteString(suffix)
+	return b.String()
+}
+
+// findWholeWords mencari kemunculan kata utuh (tidak diapit huruf/angka), tanpa membedakan huruf besar/kecil
+func findWholeWords(text string, terms []string) [][]int {
+	if len(terms) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	matches := [][]int{}
+	for _, match := range pattern.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:match[0]])
+		after, _ := utf8.DecodeRuneInString(text[match[1]:])
+		if (match[0] > 0 && isWordRune(before)) || (match[1] < len(text) && isWordRune(after)) {
+			continue
+		}
+		matches = append(matches, match)
+	}
+	return matches
+}
+
+// searchHighlights menyusun highlight per field untuk satu hasil pencarian
+func searchHighlights(achievement *models.Achievement, terms []string) map[string]string {
+	highlights := make(map[string]string)
+	if title := Highlight(achievement.Title, terms, 0); title != "" {
+		highlights["title"] = title
+	}
+	if description := Highlight(achievement.Description, terms, descriptionSnippetLength); description != "" {
+		highlights["description"] = description
+	}
+	if tags := Highlight(strings.Join(achievement.Tags, ", "), terms, 0); tags != "" {
+		highlights["tags"] = tags
+	}
+	return highlights
+}
+
+// searchVisibility membatasi hasil pencarian: admin melihat semua, dosen melihat prestasi mahasiswa
+// bimbingannya (termasuk prestasi tim), mahasiswa melihat prestasi sendiri dan prestasi timnya
+func (s *AchievementService) searchVisibility(userID, roleID string) (bson.M, error) {
+	switch roleID {
+	case "1":
+		return bson.M{}, nil
+	case "2":
+		studentIDs, err := s.studentRepo.FindStudentIDsByAdvisorID(userID)
+		if err != nil {
+			return nil, err
+		}
+		if studentIDs == nil {
+			studentIDs = []string{}
+		}
+		return bson.M{"$or": bson.A{
+			bson.M{"student_id": bson.M{"$in": studentIDs}},
+			bson.M{"members": bson.M{"$elemMatch": bson.M{"student_id": bson.M{"$in": studentIDs}, "status": "confirmed"}}},
+		}}, nil
+	default:
+		return bson.M{"$or": bson.A{
+			bson.M{"student_id": userID},
+			bson.M{"members": bson.M{"$elemMatch": bson.M{"student_id": userID, "status": bson.M{"$ne": "declined"}}}},
+		}}, nil
+	}
+}
+
+// SearchAchievements godoc
+// @Summary Search achievements
+// @Description Full-text search over title, description and tags, ranked by relevance (title weighs most, then tags). Each hit carries highlighted matches; facets (category, level, status, year) count all matching achievements. Students see their own and team achievements, lecturers their advisees' achievements, admins everything.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search keywords (prefix a word with - to exclude it, quote a phrase to match it exactly)"
+// @Param category query string false "Filter by category code"
+// @Param level query string false "Filter by level code"
+// @Param status query string false "Filter by status (draft, submitted, verified, rejected, revoked)"
+// @Param year query int false "Filter by achievement year"
+// @Param tags query string false "Comma-separated tags; achievements must have all of them"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Success 200 {object} object{status=string,message=string,data=object{query=string,achievements=[]models.AchievementSearchHit,facets=models.SearchFacets,pagination=models.PaginationMeta}} "Search results"
+// @Failure 400 {object} map[string]interface{} "Missing query or invalid filter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.read)"
+// @Failure 500 {object} map[string]interface{} "Search failed"
+// @Router /achievements/search [get]
+func (s *AchievementService) SearchAchievements(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Parameter q (kata kunci) harus diisi",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	filter, err := s.searchVisibility(userID, roleID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data mahasiswa bimbingan",
+		})
+	}
+	filter["$text"] = bson.M{"$search": query}
+	filter["is_deleted"] = false
+
+	// Filter tambahan
+	category, level, err := s.resolveMasterData(c.Query("category"), c.Query("level"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+	if category != "" {
+		filter["category"] = category
+	}
+	if level != "" {
+		filter["level"] = level
+	}
+
+	if status := c.Query("status"); status != "" {
+		validStatuses := map[string]bool{
+			"draft":     true,
+			"submitted": true,
+			"verified":  true,
+			"rejected":  true,
+			"revoked":   true,
+		}
+		if !validStatuses[status] {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Invalid status filter. Valid values: draft, submitted, verified, rejected, revoked",
+			})
+		}
+		filter["status"] = status
+	}
+
+	if c.Query("year") != "" {
+		year := c.QueryInt("year", 0)
+		if year < 1900 || year > 9999 {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Parameter year tidak valid",
+			})
+		}
+		from := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+		filter["date"] = bson.M{"$gte": from, "$lt": from.AddDate(1, 0, 0)}
+	}
+
+	if raw := c.Query("tags"); raw != "" {
+		tags, err := NormalizeTags(strings.Split(raw, ","))
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		}
+		if len(tags) > 0 {
+			filter["tags"] = bson.M{"$all": tags}
+		}
+	}
+
+	result, err := s.achievementRepo.Search(context.Background(), filter, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal melakukan pencarian achievement",
+		})
+	}
+
+	terms := SearchTerms(query)
+	for i := range result.Hits {
+		result.Hits[i].Highlights = searchHighlights(&result.Hits[i].Achievement, terms)
+	}
+
+	totalPages := int(result.Total) / limit
+	if int(result.Total)%limit > 0 {
+		totalPages++
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Hasil pencarian achievement berhasil diambil",
+		"data": fiber.Map{
+			"query":        query,
+			"achievements": result.Hits,
+			"facets":       result.Facets,
+			"pagination": models.PaginationMeta{
+				Page:       page,
+				Limit:      limit,
+				TotalItems: result.Total,
+				TotalPages: totalPages,
+			},
+		},
+	})
+}
@@ -7,11 +7,14 @@ import (
 	"crud-app/app/utils"
 	"database/sql"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
-	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -19,7 +22,28 @@ type AchievementService struct {
 	achievementRepo *repository.AchievementRepository
 	referenceRepo   *repository.AchievementReferenceRepository
 	studentRepo     *repository.StudentRepository
+	permRepo        *repository.PermissionRepository
+	stageRepo       *repository.VerificationStageRepository
+	approvalRepo    *repository.AchievementApprovalRepository
+	slaRepo         *repository.VerificationSLARepository
+	commentRepo     *repository.CommentRepository
+	memberRepo      *repository.AchievementMemberRepository
+	schemaRepo      *repository.AchievementSchemaRepository
+	masterRepo      *repository.MasterDataRepository
+	creditRepo      *repository.CreditPointRepository
+	duplicateRepo   *repository.AchievementDuplicateRepository
+	outboxRepo      *repository.OutboxRepository
+	readModelRepo   *repository.AchievementReadModelRepository
+	relay           *OutboxRelay
+	projector       *AchievementProjector
+	notifier        *NotificationService
+	verifications   *VerificationRecordService
+	exports         *ExportService
+	claimRepo       *repository.VerificationClaimRepository
+	delegationRepo  *repository.VerificationDelegationRepository
 	uploadConfig    utils.FileUploadConfig
+	duplicateConfig DuplicateConfig
+	claimTTL        time.Duration
 }
 
 func NewAchievementService(mongoDB *mongo.Database, postgresDB *sql.DB) *AchievementService {
@@ -27,13 +51,37 @@ func NewAchievementService(mongoDB *mongo.Database, postgresDB *sql.DB) *Achieve
 		achievementRepo: repository.NewAchievementRepository(mongoDB),
 		referenceRepo:   repository.NewAchievementReferenceRepository(postgresDB),
 		studentRepo:     repository.NewStudentRepository(postgresDB),
+		permRepo:        repository.NewPermissionRepository(postgresDB),
+		stageRepo:       repository.NewVerificationStageRepository(postgresDB),
+		approvalRepo:    repository.NewAchievementApprovalRepository(postgresDB),
+		slaRepo:         repository.NewVerificationSLARepository(postgresDB),
+		commentRepo:     repository.NewCommentRepository(mongoDB),
+		memberRepo:      repository.NewAchievementMemberRepository(postgresDB),
+		schemaRepo:      repository.NewAchievementSchemaRepository(postgresDB),
+		masterRepo:      repository.NewMasterDataRepository(postgresDB),
+		creditRepo:      repository.NewCreditPointRepository(postgresDB),
+		duplicateRepo:   repository.NewAchievementDuplicateRepository(postgresDB),
+		outboxRepo:      repository.NewOutboxRepository(postgresDB),
+		readModelRepo:   repository.NewAchievementReadModelRepository(postgresDB),
+		relay:           NewOutboxRelay(mongoDB, postgresDB),
+		projector:       NewAchievementProjector(mongoDB, postgresDB),
+		notifier:        NewNotificationService(postgresDB),
+		verifications:   NewVerificationRecordService(mongoDB, postgresDB),
+		exports:         NewExportService(postgresDB),
+		claimRepo:       repository.NewVerificationClaimRepository(postgresDB),
+		delegationRepo:  repository.NewVerificationDelegationRepository(postgresDB),
+		claimTTL:        time.Duration(utils.GetEnvInt("REVIEW_CLAIM_TTL_MINUTES", 30)) * time.Minute,
 		uploadConfig:    utils.DefaultUploadConfig,
+		duplicateConfig: DuplicateConfig{
+			TitleSimilarity: float64(utils.GetEnvInt("DUPLICATE_TITLE_SIMILARITY_PERCENT", 80)) / 100,
+			DateWindow:      time.Duration(utils.GetEnvInt("DUPLICATE_DATE_WINDOW_DAYS", 7)) * 24 * time.Hour,
+		},
 	}
 }
 
 // SubmitAchievement godoc
 // @Summary Submit new achievement
-// @Description Student submits a new achievement with supporting documents. Uses hybrid database storage (MongoDB + PostgreSQL).
+// @Description Student submits a new achievement with supporting documents. Uses hybrid database storage (MongoDB + PostgreSQL). Likely duplicates (same document, or similar title with the same category and a close date) are reported in duplicate_warnings without blocking the submission.
 // @Tags Achievements
 // @Accept multipart/form-data
 // @Produce json
@@ -43,6 +91,8 @@ func NewAchievementService(mongoDB *mongo.Database, postgresDB *sql.DB) *Achieve
 // @Param level formData string true "Achievement level (Local/Regional/National/International)"
 // @Param date formData string true "Achievement date (YYYY-MM-DD format)"
 // @Param description formData string false "Detailed achievement description"
+// @Param details formData string false "Category-specific details as JSON object, validated against the category schema (see /achievement-schemas)"
+// @Param tags formData []string false "Free-form tags (repeat the field for each tag, max 10, lowercased)" collectionFormat(multi)
 // @Param documents formData file false "Supporting documents (certificates, photos, etc. - multiple files allowed)"
 // @Success 201 {object} object{status=string,message=string,data=models.Achievement} "Achievement created successfully with draft status"
 // @Failure 400 {object} map[string]interface{} "Invalid request, missing required fields, or file upload error"
@@ -86,6 +136,34 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	// Category dan level harus sesuai master data, disimpan sebagai code kanonik
+	if req.Category, req.Level, err = s.resolveMasterData(req.Category, req.Level); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	// Validasi details terhadap schema kategori
+	details, err := parseDetailsForm(c, req.Details)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format details tidak valid, harus berupa JSON object",
+		})
+	}
+	if errResp := s.checkDetails(c, req.Category, details); errResp != nil {
+		return errResp()
+	}
+
+	tags, err := NormalizeTags(req.Tags)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
 	// Step 3: Handle file upload (dokumen pendukung)
 	form, err := c.MultipartForm()
 	var documents []models.Document
@@ -102,12 +180,16 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 				})
 			}
 
+			// Hash isi file untuk deteksi dokumen duplikat
+			hash, _ := utils.HashFile(filepath)
+
 			// Add to documents
 			documents = append(documents, models.Document{
 				Filename:   file.Filename,
 				Filepath:   filepath,
 				Filesize:   file.Size,
 				Mimetype:   file.Header.Get("Content-Type"),
+				Hash:       hash,
 				UploadedAt: time.Now(),
 			})
 		}
@@ -116,8 +198,10 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 	// Generate achievement ID
 	achievementID := uuid.New().String()
 
-	// Step 4: Simpan ke MongoDB (full document)
+	// Step 4: Susun dokumen MongoDB (full document); ditulis ke MongoDB lewat outbox
+	now := time.Now()
 	achievement := &models.Achievement{
+		ID:            primitive.NewObjectID(),
 		AchievementID: achievementID,
 		StudentID:     userID,
 		Title:         req.Title,
@@ -125,49 +209,55 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 		Level:         req.Level,
 		Date:          achievementDate,
 		Description:   req.Description,
+		Details:       details,
 		Documents:     documents,
+		Tags:          tags,
 		Status:        "draft", // Status awal: draft
+		Version:       1,
 		IsDeleted:     false,
 		DeletedAt:     nil,
-		CreatedAt:     time.Now(),
-		UpdatedAt:     time.Now(),
-	}
-
-	ctx := context.Background()
-	if err := s.achievementRepo.Create(ctx, achievement); err != nil {
-		// Rollback: hapus uploaded files
-		for _, doc := range documents {
-			utils.DeleteFile(doc.Filepath)
-		}
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal menyimpan achievement ke MongoDB",
-		})
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
-	// Step 5: Simpan reference ke PostgreSQL
+	// Step 5: Simpan reference ke PostgreSQL bersama event outbox dalam satu transaksi
 	reference := &models.AchievementReferences{
 		ID:                 uuid.New(),
 		StudentID:          uuid.MustParse(userID),
 		MongoAchievementID: achievementID,
 		Status:             "draft",
-		CreatedAt:          time.Now(),
-		UpdatedAt:          time.Now(),
+		CreatedAt:          now,
+		UpdatedAt:          now,
 	}
 
-	if err := s.referenceRepo.Create(reference); err != nil {
-		// Rollback: hapus dari MongoDB dan files
-		s.achievementRepo.Delete(ctx, achievementID)
+	ctx := context.Background()
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementCreated, achievement)
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			return s.referenceRepo.CreateTx(tx, reference)
+		}, event)
+	}
+	if err != nil {
+		// Rollback: hapus uploaded files
 		for _, doc := range documents {
 			utils.DeleteFile(doc.Filepath)
 		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal menyimpan reference ke PostgreSQL",
+			"message": "Gagal menyimpan achievement",
 		})
 	}
 
-	// Step 6: Return achievement data
+	// Terapkan ke MongoDB sekarang; jika gagal, relay worker akan mencoba lagi
+	s.relay.Dispatch(ctx, event)
+
+	// Step 6: Peringatkan mahasiswa jika ada kemungkinan duplikat (tidak memblokir)
+	var warnings []models.DuplicateMatch
+	if matches, err := s.findDuplicates(ctx, achievement); err == nil && len(matches) > 0 {
+		warnings = duplicateWarnings(matches)
+	}
+
+	// Step 7: Return achievement data
 	response := models.AchievementResponse{
 		ID:            achievement.ID.Hex(),
 		AchievementID: achievement.AchievementID,
@@ -177,10 +267,14 @@ func (s *AchievementService) SubmitAchievement(c *fiber.Ctx) error {
 		Level:         achievement.Level,
 		Date:          achievement.Date,
 		Description:   achievement.Description,
+		Details:       achievement.Details,
 		Documents:     achievement.Documents,
+		Tags:          achievement.Tags,
 		Status:        achievement.Status,
 		CreatedAt:     achievement.CreatedAt,
 		UpdatedAt:     achievement.UpdatedAt,
+
+		DuplicateWarnings: warnings,
 	}
 
 	return c.Status(201).JSON(fiber.Map{
@@ -254,15 +348,16 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check ownership (hanya bisa lihat achievement sendiri, kecuali admin)
+	// Check ownership (hanya bisa lihat achievement sendiri atau prestasi tim, kecuali admin)
 	roleID, _ := c.Locals("role_id").(string)
-	if achievement.StudentID != userID && roleID != "1" {
+	if achievement.StudentID != userID && !isTeamParticipant(achievement, userID) && roleID != "1" {
 		return c.Status(403).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Anda tidak memiliki akses ke achievement ini",
 		})
 	}
 
+	c.Set(fiber.HeaderETag, ETag(achievement.Version))
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Data achievement berhasil diambil",
@@ -278,12 +373,15 @@ func (s *AchievementService) GetAchievementByID(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag of the achievement from the last read"
 // @Param request body models.SubmitAchievementRequest true "Achievement update request"
 // @Success 200 {object} object{status=string,message=string,data=models.Achievement} "Achievement updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request or achievement cannot be updated (not draft status)"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Access denied - not owner or insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
 // @Failure 500 {object} map[string]interface{} "Update operation failed"
 // @Router /achievements/{id} [put]
 func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
@@ -317,6 +415,11 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
+	}
+
 	// Parse request
 	var req models.SubmitAchievementRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -326,6 +429,18 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 		})
 	}
 
+	// Category dan level yang diubah harus sesuai master data
+	if req.Category != "" || req.Level != "" {
+		category, level, err := s.resolveMasterData(req.Category, req.Level)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		}
+		req.Category, req.Level = category, level
+	}
+
 	// Update fields
 	if req.Title != "" {
 		existing.Title = req.Title
@@ -341,19 +456,186 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 	}
 	if req.Date != "" {
 		date, err := time.Parse("2006-01-02", req.Date)
-		if err == nil {
-			existing.Date = date
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Format date tidak valid. Gunakan format YYYY-MM-DD",
+			})
 		}
+		existing.Date = date
+	}
+
+	// Details divalidasi ulang jika details atau kategori berubah
+	details, err := parseDetailsForm(c, req.Details)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format details tidak valid, harus berupa JSON object",
+		})
+	}
+	if details != nil {
+		existing.Details = details
+	}
+	if details != nil || req.Category != "" {
+		if errResp := s.checkDetails(c, existing.Category, existing.Details); errResp != nil {
+			return errResp()
+		}
+	}
+
+	// Tags diganti seluruhnya jika dikirim
+	if req.Tags != nil {
+		tags, err := NormalizeTags(req.Tags)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		}
+		existing.Tags = tags
 	}
 
 	// Update di MongoDB
 	if err := s.achievementRepo.Update(ctx, achievementID, existing); err != nil {
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Gagal mengupdate achievement",
 		})
 	}
 
+	s.projector.Refresh(ctx, achievementID)
+
+	c.Set(fiber.HeaderETag, ETag(existing.Version))
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement berhasil diupdate",
+		"data":    existing,
+	})
+}
+
+// PatchAchievement godoc
+// @Summary Patch achievement
+// @Description Partially update a draft achievement with a JSON Merge Patch document (RFC 7396). Omitted fields stay unchanged and null clears optional fields (description, details, tags); details is merged recursively and tags are replaced as a whole. Every field is validated strictly and all errors are returned per field.
+// @Tags Achievements
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag of the achievement from the last read"
+// @Param request body object{title=string,category=string,level=string,date=string,description=string,details=object,tags=[]string} true "Merge patch document"
+// @Success 200 {object} object{status=string,message=string,data=models.Achievement} "Achievement updated successfully"
+// @Failure 400 {object} object{status=string,message=string,errors=[]models.FieldError} "Invalid patch, field-level validation errors, or achievement not in draft status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Access denied - not owner or insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 415 {object} map[string]interface{} "Content-Type is not application/merge-patch+json"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
+// @Failure 500 {object} map[string]interface{} "Update operation failed"
+// @Router /achievements/{id} [patch]
+func (s *AchievementService) PatchAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	patch, errResp := readMergePatch(c)
+	if errResp != nil {
+		return errResp()
+	}
+
+	ctx := context.Background()
+
+	existing, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+
+	if existing.StudentID != userID {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anda tidak memiliki akses ke achievement ini",
+		})
+	}
+
+	if existing.Status != "draft" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement yang sudah disubmit tidak bisa diupdate",
+		})
+	}
+
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
+	}
+
+	patch.Allow("title", "category", "level", "date", "description", "details", "tags")
+	patch.String("title", &existing.Title, true)
+	patch.Date("date", &existing.Date)
+	patch.String("description", &existing.Description, false)
+
+	var tags []string
+	if patch.StringList("tags", &tags) {
+		if normalized, err := NormalizeTags(tags); err != nil {
+			patch.AddError("tags", err.Error())
+		} else {
+			existing.Tags = normalized
+		}
+	}
+
+	// Category dan level harus sesuai master data, disimpan sebagai code kanonik
+	var category, level string
+	if patch.String("category", &category, true) {
+		if code, _, err := s.resolveMasterData(category, ""); err != nil {
+			patch.AddError("category", err.Error())
+		} else {
+			existing.Category = code
+		}
+	}
+	if patch.String("level", &level, true) {
+		if _, code, err := s.resolveMasterData("", level); err != nil {
+			patch.AddError("level", err.Error())
+		} else {
+			existing.Level = code
+		}
+	}
+
+	// Details divalidasi ulang jika details atau kategori berubah
+	detailsChanged := patch.Object("details", &existing.Details)
+	if (detailsChanged || patch.Has("category")) && len(patch.Errors()) == 0 {
+		violations, err := s.detailsViolations(existing.Category, existing.Details)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal memvalidasi details achievement",
+			})
+		}
+		for _, violation := range violations {
+			patch.AddError("details", violation)
+		}
+	}
+
+	if errResp := mergePatchErrors(c, patch); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.achievementRepo.Update(ctx, achievementID, existing); err != nil {
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate achievement",
+		})
+	}
+
+	s.projector.Refresh(ctx, achievementID)
+
+	c.Set(fiber.HeaderETag, ETag(existing.Version))
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Achievement berhasil diupdate",
@@ -369,11 +651,14 @@ func (s *AchievementService) UpdateAchievement(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag of the achievement from the last read"
 // @Success 200 {object} object{status=string,message=string} "Achievement deleted successfully"
 // @Failure 400 {object} map[string]interface{} "Achievement cannot be deleted (not draft status)"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Access denied - not owner or insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
 // @Failure 500 {object} map[string]interface{} "Delete operation failed"
 // @Router /achievements/{id} [delete]
 func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
@@ -413,24 +698,28 @@ func (s *AchievementService) DeleteAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Step 2: Soft delete di MongoDB
-	if err := s.achievementRepo.SoftDelete(ctx, achievementID); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal menghapus achievement di MongoDB",
-		})
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
 	}
 
-	// Step 3: Soft delete reference di PostgreSQL
-	if err := s.referenceRepo.SoftDelete(achievementID); err != nil {
-		// Rollback MongoDB (restore from soft delete)
-		// Note: Untuk production, buat fungsi Restore() jika diperlukan
+	// Step 2: Soft delete reference di PostgreSQL bersama event outbox
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementDeleted, struct{}{})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			return s.referenceRepo.SoftDeleteTx(tx, achievementID)
+		}, event)
+	}
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal menghapus reference di PostgreSQL",
+			"message": "Gagal menghapus achievement",
 		})
 	}
 
+	// Step 3: Soft delete di MongoDB (lewat outbox)
+	s.relay.Dispatch(ctx, event)
+
 	// Step 4: Return success message
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
@@ -472,8 +761,6 @@ func (s *AchievementService) GetAdviseeAchievements(c *fiber.Ctx) error {
 	}
 	offset := (page - 1) * limit
 
-	ctx := context.Background()
-
 	// Step 1: Get list student IDs dari tabel students where advisor_id
 	studentIDs, err := s.studentRepo.FindStudentIDsByAdvisorID(userID)
 	if err != nil {
@@ -500,17 +787,17 @@ func (s *AchievementService) GetAdviseeAchievements(c *fiber.Ctx) error {
 		})
 	}
 
-	// Step 2: Get achievements references dengan filter student_ids
-	references, total, err := s.referenceRepo.FindByStudentIDs(studentIDs, limit, offset)
+	// Step 2: Get achievements mahasiswa bimbingan (termasuk prestasi tim) dari read model
+	achievements, total, err := s.readModelRepo.FindByStudentIDs(studentIDs, limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengambil data achievement references",
+			"message": "Gagal mengambil data prestasi mahasiswa bimbingan",
 		})
 	}
 
 	// Check if no achievements found
-	if len(references) == 0 {
+	if len(achievements) == 0 {
 		return c.Status(200).JSON(fiber.Map{
 			"status":  "success",
 			"message": "Tidak ada prestasi mahasiswa bimbingan",
@@ -526,28 +813,13 @@ func (s *AchievementService) GetAdviseeAchievements(c *fiber.Ctx) error {
 		})
 	}
 
-	// Step 3: Extract achievement IDs untuk fetch dari MongoDB
-	achievementIDs := make([]string, len(references))
-	for i, ref := range references {
-		achievementIDs[i] = ref.MongoAchievementID
-	}
-
-	// Step 4: Fetch detail dari MongoDB
-	achievements, err := s.achievementRepo.FindByAchievementIDs(ctx, achievementIDs)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal mengambil detail achievements dari MongoDB",
-		})
-	}
-
 	// Calculate total pages
 	totalPages := int(total) / limit
 	if int(total)%limit > 0 {
 		totalPages++
 	}
 
-	// Step 5: Return list dengan pagination
+	// Step 3: Return list dengan pagination
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Data prestasi mahasiswa bimbingan berhasil diambil",
@@ -565,17 +837,20 @@ func (s *AchievementService) GetAdviseeAchievements(c *fiber.Ctx) error {
 
 // SubmitForVerification godoc
 // @Summary Submit achievement for verification
-// @Description Submit a draft achievement for verification by lecturer. Changes status from 'draft' to 'submitted'.
+// @Description Submit a draft achievement for verification by lecturer. Changes status from 'draft' to 'submitted'. Likely duplicates are flagged for the verifier and returned as duplicate_warnings; achievements of other students are anonymized.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
-// @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,status=string,updated_at=string}} "Achievement submitted successfully"
+// @Param If-Match header string true "ETag of the achievement from the last read"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,status=string,updated_at=string,stages=[]models.AchievementApproval,duplicate_warnings=[]models.DuplicateMatch}} "Achievement submitted successfully"
 // @Failure 400 {object} map[string]interface{} "Achievement cannot be submitted (not draft status)"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Access denied - not owner or insufficient permissions"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
 // @Failure 500 {object} map[string]interface{} "Submission process failed - database error"
 // @Router /achievements/{id}/submit [post]
 func (s *AchievementService) SubmitForVerification(c *fiber.Ctx) error {
@@ -615,161 +890,253 @@ func (s *AchievementService) SubmitForVerification(c *fiber.Ctx) error {
 		})
 	}
 
-	// Step 2: Update status menjadi 'submitted' di MongoDB
-	if err := s.achievementRepo.UpdateStatus(ctx, achievementID, "submitted"); err != nil {
+	// Semua undangan anggota tim harus dijawab sebelum submit
+	if HasPendingTeamInvitations(achievement) {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Masih ada anggota tim yang belum mengonfirmasi undangan",
+		})
+	}
+
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, achievement.Version); errResp != nil {
+		return errResp()
+	}
+
+	// Step 2: Susun rantai tahap verifikasi berdasarkan level/category
+	stages, err := s.stageRepo.FindApplicable(achievement.Level, achievement.Category)
+	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengupdate status di MongoDB",
+			"message": "Gagal mengambil konfigurasi tahap verifikasi",
 		})
 	}
+	chain := ResolveVerificationStages(stages)
+
+	approvals := make([]models.AchievementApproval, len(chain))
+	for i, stage := range chain {
+		approvals[i] = models.AchievementApproval{
+			ID:                 uuid.New(),
+			MongoAchievementID: achievementID,
+			StageOrder:         stage.StageOrder,
+			StageName:          stage.Name,
+			Permission:         stage.Permission,
+			Status:             "pending",
+			CreatedAt:          time.Now(),
+		}
+	}
 
-	// Step 3: Update status dan submitted_at di PostgreSQL
-	if err := s.referenceRepo.UpdateSubmittedStatus(achievementID); err != nil {
-		// Rollback MongoDB
-		s.achievementRepo.UpdateStatus(ctx, achievementID, "draft")
+	// Step 3: Simpan tahap verifikasi, status, submitted_at, dan jumlah tahap di PostgreSQL
+	// bersama event outbox dalam satu transaksi
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementStatus, models.OutboxStatusPayload{Status: "submitted", From: "draft"})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			if err := s.approvalRepo.ReplaceForAchievementTx(tx, achievementID, approvals); err != nil {
+				return err
+			}
+			return s.referenceRepo.UpdateSubmittedStagesTx(tx, achievementID, len(chain))
+		}, event)
+	}
+	if err != nil {
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengupdate status di PostgreSQL",
+			"message": "Gagal menyimpan status submit",
 		})
 	}
 
-	// Step 4: Return updated status
+	// Step 4: Update status di MongoDB (lewat outbox)
+	s.relay.Dispatch(ctx, event)
+
+	// Step 5: Deteksi kemungkinan duplikat dan tandai untuk verifikator (tidak memblokir)
+	warnings := []models.DuplicateMatch{}
+	if matches, err := s.findDuplicates(ctx, achievement); err != nil {
+		log.Printf("Gagal mendeteksi duplikat achievement %s: %v", achievementID, err)
+	} else {
+		if err := s.recordDuplicateFlags(achievementID, matches); err != nil {
+			log.Printf("Gagal menyimpan flag duplikat achievement %s: %v", achievementID, err)
+		}
+		warnings = duplicateWarnings(matches)
+	}
+
+	// Step 6: Return updated status
 	achievement.Status = "submitted"
 	achievement.UpdatedAt = time.Now()
+	if updated, err := s.achievementRepo.FindByID(ctx, achievementID); err == nil {
+		c.Set(fiber.HeaderETag, ETag(updated.Version))
+	}
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Prestasi berhasil disubmit untuk verifikasi",
 		"data": fiber.Map{
-			"achievement_id": achievement.AchievementID,
-			"status":         achievement.Status,
-			"updated_at":     achievement.UpdatedAt,
+			"achievement_id":     achievement.AchievementID,
+			"status":             achievement.Status,
+			"updated_at":         achievement.UpdatedAt,
+			"stages":             approvals,
+			"duplicate_warnings": warnings,
 		},
 	})
 }
 
-// GetPendingVerification godoc
-// @Summary Get pending verification achievements
-// @Description Get paginated list of achievements that are pending verification (submitted status).
+// ApproveAchievement godoc
+// @Summary Approve achievement
+// @Description Verifier approves the current verification stage of a submitted achievement. The achievement becomes 'verified' only after every stage (e.g. advisor, then faculty) has approved; until then it stays 'submitted' with a partial-approval state.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number (default: 1)" default(1)
-// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
-// @Success 200 {object} object{status=string,message=string,data=object{achievements=[]models.Achievement,pagination=object}} "Pending achievements retrieved successfully"
+// @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag of the achievement from the last read"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object,approvals=[]models.AchievementApproval}} "Stage approved or achievement verified successfully"
+// @Failure 400 {object} map[string]interface{} "Achievement cannot be approved (not submitted status or verifier already approved an earlier stage)"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
-// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify)"
-// @Failure 500 {object} map[string]interface{} "Failed to retrieve pending achievements"
-// @Router /achievements/pending [get]
-func (s *AchievementService) GetPendingVerification(c *fiber.Ctx) error {
-	// Get pagination params
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 10)
-	offset := (page - 1) * limit
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions for the current verification stage, or achievement outside the caller's advisees and active delegations"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Achievement is claimed for review by another verifier"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
+// @Failure 500 {object} map[string]interface{} "Verification process failed - database error"
+// @Router /achievements/{id}/verify [post]
+func (s *AchievementService) ApproveAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
 
-	// Get pending achievements dari PostgreSQL
-	references, total, err := s.referenceRepo.FindPendingVerification(limit, offset)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
+	// Persetujuan harus berdasarkan versi terbaru (If-Match)
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		return requireIfMatch(c, 0)()
+	}
+
+	data, message, reviewErr := s.approveAchievement(context.Background(), achievementID, userID, roleID, "", ifMatch)
+	if reviewErr != nil {
+		return c.Status(reviewErr.status).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengambil data pending verification",
+			"message": reviewErr.message,
 		})
 	}
 
-	// Get full data dari MongoDB
-	ctx := context.Background()
-	var achievements []models.Achievement
-
-	for _, ref := range references {
-		achievement, err := s.achievementRepo.FindByID(ctx, ref.MongoAchievementID)
-		if err == nil && achievement != nil {
-			achievements = append(achievements, *achievement)
-		}
+	if achievement, ok := data["achievement"].(*models.Achievement); ok && achievement != nil {
+		c.Set(fiber.HeaderETag, ETag(achievement.Version))
 	}
-
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
-		"message": "Data pending verification berhasil diambil",
-		"data": fiber.Map{
-			"achievements": achievements,
-			"pagination": fiber.Map{
-				"page":       page,
-				"limit":      limit,
-				"total":      total,
-				"total_page": (total + int64(limit) - 1) / int64(limit),
-			},
-		},
+		"message": message,
+		"data":    data,
 	})
 }
 
-// ReviewAchievementDetail godoc
-// @Summary Review achievement detail
-// @Description Lecturer reviews detailed information of an achievement for verification process.
+// RejectAchievement godoc
+// @Summary Reject achievement
+// @Description Verifier of the current verification stage rejects a submitted achievement with reason. Changes status from 'submitted' to 'rejected'.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
-// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object}} "Achievement details retrieved successfully"
+// @Param If-Match header string true "ETag of the achievement from the last read"
+// @Param request body object{rejection_note=string} true "Rejection request with reason"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object,approvals=[]models.AchievementApproval}} "Achievement rejected successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request, missing rejection note, or achievement cannot be rejected"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
-// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify)"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions for the current verification stage, or achievement outside the caller's advisees and active delegations"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
-// @Failure 500 {object} map[string]interface{} "Failed to retrieve achievement details"
-// @Router /achievements/{id}/review [get]
-func (s *AchievementService) ReviewAchievementDetail(c *fiber.Ctx) error {
+// @Failure 409 {object} map[string]interface{} "Achievement is claimed for review by another verifier"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
+// @Failure 500 {object} map[string]interface{} "Rejection process failed - database error"
+// @Router /achievements/{id}/reject [post]
+func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
 
-	ctx := context.Background()
-	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
-	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
+	// Parse request body untuk rejection note
+	var req struct {
+		RejectionNote string `json:"rejection_note"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Achievement tidak ditemukan",
+			"message": "Invalid request body",
 		})
 	}
 
-	// Get reference data untuk info tambahan
-	reference, err := s.referenceRepo.FindByMongoID(achievementID)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
+	if req.RejectionNote == "" {
+		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengambil reference data",
+			"message": "Rejection note harus diisi",
+		})
+	}
+
+	// Penolakan harus berdasarkan versi terbaru (If-Match)
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch == "" {
+		return requireIfMatch(c, 0)()
+	}
+
+	data, reviewErr := s.rejectAchievement(context.Background(), achievementID, userID, roleID, req.RejectionNote, ifMatch)
+	if reviewErr != nil {
+		return c.Status(reviewErr.status).JSON(fiber.Map{
+			"status":  "error",
+			"message": reviewErr.message,
 		})
 	}
 
+	if achievement, ok := data["achievement"].(*models.Achievement); ok && achievement != nil {
+		c.Set(fiber.HeaderETag, ETag(achievement.Version))
+	}
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
-		"message": "Data achievement berhasil diambil",
-		"data": fiber.Map{
-			"achievement": achievement,
-			"reference":   reference,
-		},
+		"message": "Achievement berhasil direject",
+		"data":    data,
 	})
 }
 
-// ApproveAchievement godoc
-// @Summary Approve achievement
-// @Description Lecturer approves a submitted achievement. Changes status from 'submitted' to 'verified'.
+// RevokeAchievement godoc
+// @Summary Revoke verified achievement
+// @Description Admin revokes a previously verified achievement (e.g. forged certificate) with a mandatory reason. Changes status from 'verified' to 'revoked'; revoked achievements are excluded from statistics and rankings. The student is notified.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
-// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object}} "Achievement approved successfully"
-// @Failure 400 {object} map[string]interface{} "Achievement cannot be approved (not submitted status)"
+// @Param If-Match header string true "ETag of the achievement from the last read"
+// @Param request body models.RevokeRequest true "Revocation request with reason"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object,revocation=models.AchievementRevocation}} "Achievement revoked successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request, missing reason, or achievement is not verified"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
-// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify)"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires admin access)"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
-// @Failure 500 {object} map[string]interface{} "Verification process failed - database error"
-// @Router /achievements/{id}/verify [post]
-func (s *AchievementService) ApproveAchievement(c *fiber.Ctx) error {
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
+// @Failure 500 {object} map[string]interface{} "Revocation process failed - database error"
+// @Router /achievements/{id}/revoke [post]
+func (s *AchievementService) RevokeAchievement(c *fiber.Ctx) error {
 	achievementID := c.Params("id")
 	userID, _ := c.Locals("user_id").(string)
 
+	var req models.RevokeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Alasan pencabutan harus diisi",
+		})
+	}
+
 	ctx := context.Background()
 
-	// Get existing achievement
 	existing, err := s.achievementRepo.FindByID(ctx, achievementID)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{
@@ -778,69 +1145,92 @@ func (s *AchievementService) ApproveAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	// Check status (hanya bisa approve jika status submitted)
-	if existing.Status != "submitted" {
+	// Hanya achievement terverifikasi yang bisa dicabut
+	if existing.Status != "verified" {
 		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Hanya achievement dengan status 'submitted' yang bisa diapprove",
+			"message": "Hanya achievement dengan status 'verified' yang bisa dicabut",
 		})
 	}
 
-	// Update status di MongoDB
-	if err := s.achievementRepo.UpdateStatus(ctx, achievementID, "verified"); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal mengupdate status di MongoDB",
-		})
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
 	}
 
-	// Update verification di PostgreSQL
-	if err := s.referenceRepo.UpdateVerification(achievementID, userID, "verified"); err != nil {
-		// Rollback MongoDB
-		s.achievementRepo.UpdateStatus(ctx, achievementID, "submitted")
+	// Update status dan catat alasan di PostgreSQL bersama event outbox
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementStatus, models.OutboxStatusPayload{Status: "revoked", From: "verified"})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			if err := s.referenceRepo.UpdateRevocationTx(tx, achievementID, userID, req.Reason); err != nil {
+				return err
+			}
+			// Kode verifikasi publik ikut dicabut
+			return s.verifications.RevokeTx(tx, achievementID, userID, req.Reason)
+		}, event)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return versionConflict(c, 0)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengupdate verification di PostgreSQL",
+			"message": "Gagal menyimpan revocation",
 		})
 	}
 
+	// Update status di MongoDB (lewat outbox)
+	s.relay.Dispatch(ctx, event)
+
+	// Poin achievement yang dicabut tidak lagi dihitung
+	if err := s.creditRepo.DeleteAchievementPoints(achievementID); err != nil {
+		log.Printf("Gagal menghapus poin achievement %s: %v", achievementID, err)
+	}
+
+	s.notifier.Notify(existing.StudentID, "achievement_revoked", "Prestasi dicabut",
+		fmt.Sprintf("Prestasi '%s' dicabut oleh admin. Alasan: %s", existing.Title, req.Reason), achievementID)
+
 	// Get updated data
 	updated, _ := s.achievementRepo.FindByID(ctx, achievementID)
 	reference, _ := s.referenceRepo.FindByMongoID(achievementID)
+	revocation, _ := s.referenceRepo.FindRevocation(achievementID)
+	if updated != nil {
+		c.Set(fiber.HeaderETag, ETag(updated.Version))
+	}
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
-		"message": "Achievement berhasil diverifikasi",
+		"message": "Achievement berhasil dicabut",
 		"data": fiber.Map{
 			"achievement": updated,
 			"reference":   reference,
+			"revocation":  revocation,
 		},
 	})
 }
 
-// RejectAchievement godoc
-// @Summary Reject achievement
-// @Description Lecturer rejects a submitted achievement with reason. Changes status from 'submitted' to 'rejected'.
+// BulkReviewAchievements godoc
+// @Summary Bulk approve or reject achievements
+// @Description Verifier approves or rejects many submitted achievements at once. Each item goes through the same checks as the single verify/reject endpoints (an item outside the caller's advisees and active delegations, or whose pending stage the caller cannot decide, fails with 403), including optimistic concurrency: etags maps every achievement ID to the ETag from the last read, an item without an ETag fails with 428 and an item changed since then fails with 412. The response reports the result per item; one failing item does not fail the batch.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param id path string true "Achievement ID"
-// @Param request body object{rejection_note=string} true "Rejection request with reason"
-// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object}} "Achievement rejected successfully"
-// @Failure 400 {object} map[string]interface{} "Invalid request, missing rejection note, or achievement cannot be rejected"
+// @Param request body object{achievement_ids=[]string,action=string,note=string,etags=map[string]string} true "Bulk review request (action: approve or reject; note is required for reject; etags: ETag per achievement ID)"
+// @Success 200 {object} object{status=string,message=string,data=object{summary=object{total=int,succeeded=int,failed=int},results=[]object{achievement_id=string,success=bool,http_status=int,message=string,status=string}}} "Per-item review report"
+// @Failure 400 {object} map[string]interface{} "Invalid request, unknown action, missing note or too many IDs"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
-// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify)"
-// @Failure 404 {object} map[string]interface{} "Achievement not found"
-// @Failure 500 {object} map[string]interface{} "Rejection process failed - database error"
-// @Router /achievements/{id}/reject [post]
-func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
-	achievementID := c.Params("id")
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Router /achievements/bulk-review [post]
+func (s *AchievementService) BulkReviewAchievements(c *fiber.Ctx) error {
 	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
 
-	// Parse request body untuk rejection note
 	var req struct {
-		RejectionNote string `json:"rejection_note"`
+		AchievementIDs []string          `json:"achievement_ids"`
+		Action         string            `json:"action"`
+		Note           string            `json:"note"`
+		ETags          map[string]string `json:"etags"` // ETag per achievement dari GET terakhir
 	}
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(400).JSON(fiber.Map{
@@ -849,91 +1239,404 @@ func (s *AchievementService) RejectAchievement(c *fiber.Ctx) error {
 		})
 	}
 
-	if req.RejectionNote == "" {
+	if req.Action != "approve" && req.Action != "reject" {
 		return c.Status(400).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Rejection note harus diisi",
+			"message": "Action harus 'approve' atau 'reject'",
+		})
+	}
+
+	if req.Action == "reject" && req.Note == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Note harus diisi untuk action 'reject'",
+		})
+	}
+
+	if len(req.AchievementIDs) == 0 || len(req.AchievementIDs) > maxBulkReviewItems {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("achievement_ids harus berisi 1 sampai %d ID", maxBulkReviewItems),
 		})
 	}
 
 	ctx := context.Background()
+	results, summary := RunBulkReview(req.AchievementIDs, func(achievementID string) (string, string, error) {
+		var data fiber.Map
+		var message string
+		var reviewErr *reviewError
+
+		// Setiap item diputuskan berdasarkan versi yang dilihat verifikator, sama seperti If-Match
+		ifMatch := req.ETags[achievementID]
+		if ifMatch == "" {
+			return "", "", &reviewError{428, "ETag achievement wajib dikirim di etags (gunakan ETag dari response GET)"}
+		}
+
+		if req.Action == "approve" {
+			data, message, reviewErr = s.approveAchievement(ctx, achievementID, userID, roleID, req.Note, ifMatch)
+		} else {
+			data, reviewErr = s.rejectAchievement(ctx, achievementID, userID, roleID, req.Note, ifMatch)
+			message = "Achievement berhasil direject"
+		}
+		if reviewErr != nil {
+			return "", "", reviewErr
+		}
+
+		status := ""
+		if achievement, ok := data["achievement"].(*models.Achievement); ok && achievement != nil {
+			status = achievement.Status
+		}
+		return status, message, nil
+	})
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": fmt.Sprintf("%d dari %d achievement berhasil diproses", summary.Succeeded, summary.Total),
+		"data": fiber.Map{
+			"summary": summary,
+			"results": results,
+		},
+	})
+}
+
+// BulkReviewResult hasil review satu achievement dalam bulk review
+type BulkReviewResult struct {
+	AchievementID string `json:"achievement_id"`
+	Success       bool   `json:"success"`
+	HTTPStatus    int    `json:"http_status"`
+	Message       string `json:"message"`
+	Status        string `json:"status,omitempty"` // status achievement setelah diputuskan
+}
+
+// BulkReviewSummary jumlah item bulk review yang berhasil dan gagal
+type BulkReviewSummary struct {
+	Total     int `json:"total"`
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+}
+
+// RunBulkReview menjalankan review untuk setiap achievement sekali, sesuai urutan request (ID ganda diabaikan).
+// review mengembalikan status achievement dan pesan sukses, atau error; error yang memiliki method
+// HTTPStatus() dilaporkan dengan status tersebut, selain itu 500. Satu item gagal tidak menghentikan yang lain.
+func RunBulkReview(achievementIDs []string, review func(achievementID string) (string, string, error)) ([]BulkReviewResult, BulkReviewSummary) {
+	seen := make(map[string]bool)
+	results := []BulkReviewResult{}
+	summary := BulkReviewSummary{}
+
+	for _, achievementID := range achievementIDs {
+		if seen[achievementID] {
+			continue
+		}
+		seen[achievementID] = true
+
+		status, message, err := review(achievementID)
+		if err != nil {
+			httpStatus := 500
+			if withStatus, ok := err.(interface{ HTTPStatus() int }); ok {
+				httpStatus = withStatus.HTTPStatus()
+			}
+			results = append(results, BulkReviewResult{
+				AchievementID: achievementID,
+				HTTPStatus:    httpStatus,
+				Message:       err.Error(),
+			})
+			summary.Failed++
+			continue
+		}
+
+		results = append(results, BulkReviewResult{
+			AchievementID: achievementID,
+			Success:       true,
+			HTTPStatus:    200,
+			Message:       message,
+			Status:        status,
+		})
+		summary.Succeeded++
+	}
+
+	summary.Total = len(results)
+	return results, summary
+}
+
+// maxBulkReviewItems batas jumlah achievement dalam satu bulk review
+const maxBulkReviewItems = 500
+
+// reviewError kegagalan approve/reject beserta HTTP status yang sesuai
+type reviewError struct {
+	status  int
+	message string
+}
+
+func (e *reviewError) Error() string {
+	return e.message
+}
+
+// HTTPStatus status HTTP yang dilaporkan untuk kegagalan ini
+func (e *reviewError) HTTPStatus() int {
+	return e.status
+}
+
+// approveAchievement menyetujui tahap verifikasi saat ini (dipakai endpoint tunggal dan bulk).
+// note opsional disimpan pada tahap yang disetujui.
+func (s *AchievementService) approveAchievement(ctx context.Context, achievementID, userID, roleID, note, ifMatch string) (fiber.Map, string, *reviewError) {
+	// Get existing achievement
+	existing, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return nil, "", &reviewError{404, "Achievement tidak ditemukan"}
+	}
+
+	// Check status (hanya bisa approve jika status submitted)
+	if existing.Status != "submitted" {
+		return nil, "", &reviewError{400, "Hanya achievement dengan status 'submitted' yang bisa diapprove"}
+	}
+
+	// Keputusan harus berdasarkan versi terbaru (If-Match endpoint tunggal atau etags bulk review)
+	if !IfMatchSatisfied(ifMatch, existing.Version) {
+		return nil, "", &reviewError{412, "Achievement sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi"}
+	}
+
+	// Tentukan tahap verifikasi yang sedang berjalan
+	stage, approvals, err := s.currentApprovalStage(achievementID)
+	if err != nil {
+		return nil, "", &reviewError{500, "Gagal mengambil tahap verifikasi"}
+	}
+
+	// Scope inbox (mahasiswa bimbingan atau delegasi aktif) dan permission tahap yang menunggu
+	if reviewErr := s.decisionAccess(userID, roleID, existing, stage); reviewErr != nil {
+		return nil, "", reviewErr
+	}
+
+	// Achievement yang sedang diklaim verifikator lain tidak bisa diputuskan
+	if reviewErr := s.checkReviewClaim(achievementID, userID); reviewErr != nil {
+		return nil, "", reviewErr
+	}
+
+	// Satu verifikator tidak boleh menyetujui lebih dari satu tahap
+	for _, approval := range approvals {
+		if approval.Status == "approved" && approval.DecidedBy != nil && approval.DecidedBy.String() == userID {
+			return nil, "", &reviewError{400, "Anda sudah menyetujui tahap verifikasi sebelumnya untuk achievement ini"}
+		}
+	}
+
+	var approvalNote *string
+	if note != "" {
+		approvalNote = &note
+	}
+
+	// Masih ada tahap berikutnya: status tetap 'submitted'. Keputusan tahap dan perpindahan tahap disimpan
+	// dalam satu transaksi; versi dokumen MongoDB baru naik saat event outbox-nya diterapkan, sehingga
+	// transaksi yang gagal tidak membatalkan ETag klien. Keputusan bersamaan ditolak UPDATE kondisional
+	// pada tahap yang masih pending.
+	if stage.StageOrder < len(approvals) {
+		event, err := NewOutboxEvent(achievementID, models.OutboxAchievementStage, models.OutboxStagePayload{Stage: stage.StageOrder + 1})
+		if err == nil {
+			err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+				if err := s.approvalRepo.UpdateDecisionTx(tx, achievementID, stage.StageOrder, "approved", userID, approvalNote); err != nil {
+					return err
+				}
+				return s.referenceRepo.AdvanceStageTx(tx, achievementID, stage.StageOrder+1)
+			}, event)
+		}
+		if err != nil {
+			if err == repository.ErrVersionConflict {
+				return nil, "", &reviewError{412, "Achievement sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi"}
+			}
+			return nil, "", &reviewError{500, "Gagal menyimpan persetujuan tahap verifikasi"}
+		}
+
+		// Naikkan versi di MongoDB (lewat outbox)
+		s.relay.Dispatch(ctx, event)
+		s.projector.Refresh(ctx, achievementID)
+		s.releaseReviewClaim(achievementID)
+
+		updated, _ := s.achievementRepo.FindByID(ctx, achievementID)
+		reference, _ := s.referenceRepo.FindByMongoID(achievementID)
+		approvals, _ = s.approvalRepo.FindByMongoID(achievementID)
+
+		return fiber.Map{
+			"achievement": updated,
+			"reference":   reference,
+			"approvals":   approvals,
+		}, fmt.Sprintf("Tahap verifikasi '%s' disetujui, menunggu tahap berikutnya", stage.StageName), nil
+	}
+
+	// Tahap terakhir: keputusan tahap dan verifikasi di PostgreSQL bersama event outbox
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementStatus, models.OutboxStatusPayload{Status: "verified", From: "submitted"})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			if err := s.approvalRepo.UpdateDecisionTx(tx, achievementID, stage.StageOrder, "approved", userID, approvalNote); err != nil {
+				return err
+			}
+			return s.referenceRepo.UpdateVerificationTx(tx, achievementID, userID, "verified")
+		}, event)
+	}
+	if err != nil {
+		if err == repository.ErrVersionConflict {
+			return nil, "", &reviewError{412, "Achievement sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi"}
+		}
+		return nil, "", &reviewError{500, "Gagal menyimpan verifikasi"}
+	}
+
+	// Update status di MongoDB (lewat outbox)
+	s.relay.Dispatch(ctx, event)
+	s.releaseReviewClaim(achievementID)
+
+	// Hitung poin prestasi dengan versi aturan aktif; kegagalan tidak membatalkan verifikasi
+	if err := awardCreditPoints(s.creditRepo, s.masterRepo, existing); err != nil {
+		log.Printf("Gagal menghitung poin achievement %s: %v", achievementID, err)
+	}
+
+	// Terbitkan kode verifikasi publik (setelah status verified tersimpan)
+	s.verifications.IssueAfterVerification(ctx, achievementID)
+
+	// Get updated data
+	updated, _ := s.achievementRepo.FindByID(ctx, achievementID)
+	reference, _ := s.referenceRepo.FindByMongoID(achievementID)
+	approvals, _ = s.approvalRepo.FindByMongoID(achievementID)
+
+	return fiber.Map{
+		"achievement": updated,
+		"reference":   reference,
+		"approvals":   approvals,
+	}, "Achievement berhasil diverifikasi", nil
+}
 
+// rejectAchievement menolak achievement pada tahap verifikasi saat ini (dipakai endpoint tunggal dan bulk)
+func (s *AchievementService) rejectAchievement(ctx context.Context, achievementID, userID, roleID, rejectionNote, ifMatch string) (fiber.Map, *reviewError) {
 	// Get existing achievement
 	existing, err := s.achievementRepo.FindByID(ctx, achievementID)
 	if err != nil {
-		return c.Status(404).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Achievement tidak ditemukan",
-		})
+		return nil, &reviewError{404, "Achievement tidak ditemukan"}
 	}
 
 	// Check status (hanya bisa reject jika status submitted)
 	if existing.Status != "submitted" {
-		return c.Status(400).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Hanya achievement dengan status 'submitted' yang bisa direject",
-		})
+		return nil, &reviewError{400, "Hanya achievement dengan status 'submitted' yang bisa direject"}
 	}
 
-	// Update status di MongoDB
-	if err := s.achievementRepo.UpdateStatus(ctx, achievementID, "rejected"); err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal mengupdate status di MongoDB",
-		})
+	// Keputusan harus berdasarkan versi terbaru (If-Match endpoint tunggal atau etags bulk review)
+	if !IfMatchSatisfied(ifMatch, existing.Version) {
+		return nil, &reviewError{412, "Achievement sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi"}
 	}
 
-	// Update rejection di PostgreSQL
-	if err := s.referenceRepo.UpdateRejection(achievementID, userID, req.RejectionNote); err != nil {
-		// Rollback MongoDB
-		s.achievementRepo.UpdateStatus(ctx, achievementID, "submitted")
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal mengupdate rejection di PostgreSQL",
-		})
+	// Hanya verifikator tahap saat ini yang bisa reject
+	stage, _, err := s.currentApprovalStage(achievementID)
+	if err != nil {
+		return nil, &reviewError{500, "Gagal mengambil tahap verifikasi"}
+	}
+
+	// Scope inbox (mahasiswa bimbingan atau delegasi aktif) dan permission tahap yang menunggu
+	if reviewErr := s.decisionAccess(userID, roleID, existing, stage); reviewErr != nil {
+		return nil, reviewErr
 	}
 
+	// Achievement yang sedang diklaim verifikator lain tidak bisa diputuskan
+	if reviewErr := s.checkReviewClaim(achievementID, userID); reviewErr != nil {
+		return nil, reviewErr
+	}
+
+	// Keputusan tahap dan rejection di PostgreSQL bersama event outbox; versi MongoDB naik saat event
+	// diterapkan. Keputusan bersamaan ditolak UPDATE kondisional tahap pending dan status 'submitted'.
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementStatus, models.OutboxStatusPayload{Status: "rejected", From: "submitted"})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			if err := s.approvalRepo.UpdateDecisionTx(tx, achievementID, stage.StageOrder, "rejected", userID, &rejectionNote); err != nil {
+				return err
+			}
+			return s.referenceRepo.UpdateRejectionTx(tx, achievementID, userID, rejectionNote)
+		}, event)
+	}
+	if err != nil {
+		if err == repository.ErrVersionConflict {
+			return nil, &reviewError{412, "Achievement sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi"}
+		}
+		return nil, &reviewError{500, "Gagal menyimpan rejection"}
+	}
+
+	// Update status di MongoDB (lewat outbox)
+	s.relay.Dispatch(ctx, event)
+	s.releaseReviewClaim(achievementID)
+
 	// Get updated data
 	updated, _ := s.achievementRepo.FindByID(ctx, achievementID)
 	reference, _ := s.referenceRepo.FindByMongoID(achievementID)
+	approvals, _ := s.approvalRepo.FindByMongoID(achievementID)
 
-	return c.Status(200).JSON(fiber.Map{
-		"status":  "success",
-		"message": "Achievement berhasil direject",
-		"data": fiber.Map{
-			"achievement": updated,
-			"reference":   reference,
-		},
-	})
+	return fiber.Map{
+		"achievement": updated,
+		"reference":   reference,
+		"approvals":   approvals,
+	}, nil
+}
+
+// currentApprovalStage mengembalikan tahap verifikasi yang sedang menunggu keputusan.
+// Achievement yang disubmit sebelum ada rantai verifikasi diberi satu tahap default.
+func (s *AchievementService) currentApprovalStage(achievementID string) (*models.AchievementApproval, []models.AchievementApproval, error) {
+	approvals, err := s.approvalRepo.FindByMongoID(achievementID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(approvals) == 0 {
+		approvals = []models.AchievementApproval{{
+			ID:                 uuid.New(),
+			MongoAchievementID: achievementID,
+			StageOrder:         DefaultVerificationStage.StageOrder,
+			StageName:          DefaultVerificationStage.Name,
+			Permission:         DefaultVerificationStage.Permission,
+			Status:             "pending",
+			CreatedAt:          time.Now(),
+		}}
+		if err := s.approvalRepo.ReplaceForAchievement(achievementID, approvals); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for i := range approvals {
+		if approvals[i].Status == "pending" {
+			return &approvals[i], approvals, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("tidak ada tahap verifikasi yang menunggu untuk achievement %s", achievementID)
 }
 
 // GetAllAchievements godoc
 // @Summary Get all achievements (Admin)
-// @Description Admin gets paginated list of all achievements with filtering and sorting options.
+// @Description Admin gets paginated list of all achievements with filtering and sorting options. All filters run against the denormalized achievement read model, so totals and pages are consistent across both stores. Date ranges are inclusive and use YYYY-MM-DD. Pass cursor (empty for the first page) to page with stable keyset cursors instead of page numbers; cursor pages return next_cursor/prev_cursor and no totals.
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)" default(1)
 // @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
-// @Param status query string false "Filter by status (draft, submitted, verified, rejected)"
+// @Param cursor query string false "Opaque cursor from next_cursor/prev_cursor; send it empty to start cursor pagination. Keep the same filters and sorting across pages"
+// @Param status query string false "Filter by status (draft, submitted, verified, rejected, revoked)"
 // @Param student_id query string false "Filter by student ID"
-// @Param sort_by query string false "Sort by field (default: created_at)" default(created_at)
+// @Param category query string false "Filter by category code"
+// @Param level query string false "Filter by level code"
+// @Param date_from query string false "Achievement date from (YYYY-MM-DD)"
+// @Param date_to query string false "Achievement date to (YYYY-MM-DD)"
+// @Param submitted_from query string false "Submitted date from (YYYY-MM-DD)"
+// @Param submitted_to query string false "Submitted date to (YYYY-MM-DD)"
+// @Param verified_from query string false "Verified date from (YYYY-MM-DD)"
+// @Param verified_to query string false "Verified date to (YYYY-MM-DD)"
+// @Param verified_by query string false "Filter by verifier user ID"
+// @Param advisor_id query string false "Filter by the student's advisor user ID"
+// @Param program_study query string false "Filter by the student's study program"
+// @Param academic_year query string false "Filter by the student's academic year"
+// @Param sort_by query string false "Sort by field (created_at, submitted_at, verified_at, updated_at)" default(created_at)
 // @Param sort_order query string false "Sort order (asc, desc)" default(desc)
 // @Success 200 {object} object{status=string,message=string,data=object{achievements=[]models.Achievement,pagination=object,filters=object}} "All achievements retrieved successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid filter parameters"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
-// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires admin access)"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.read_all)"
 // @Failure 500 {object} map[string]interface{} "Failed to retrieve achievements"
 // @Router /achievements/all [get]
 func (s *AchievementService) GetAllAchievements(c *fiber.Ctx) error {
 	// Parse query parameters
 	page := c.QueryInt("page", 1)
 	limit := c.QueryInt("limit", 10)
-	statusFilter := c.Query("status", "")
-	studentIDFilter := c.Query("student_id", "")
-	sortBy := c.Query("sort_by", "created_at")
-	sortOrder := c.Query("sort_order", "desc")
 
 	// Validation
 	if page < 1 {
@@ -944,68 +1647,65 @@ func (s *AchievementService) GetAllAchievements(c *fiber.Ctx) error {
 	}
 	offset := (page - 1) * limit
 
-	// Validate status filter
-	if statusFilter != "" {
-		validStatuses := map[string]bool{
-			"draft":     true,
-			"submitted": true,
-			"verified":  true,
-			"rejected":  true,
+	filter, errResponse := s.parseListFilter(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	filters := fiber.Map{
+		"status":         filter.Status,
+		"student_id":     filter.StudentID,
+		"category":       filter.Category,
+		"level":          filter.Level,
+		"date_from":      c.Query("date_from"),
+		"date_to":        c.Query("date_to"),
+		"submitted_from": c.Query("submitted_from"),
+		"submitted_to":   c.Query("submitted_to"),
+		"verified_from":  c.Query("verified_from"),
+		"verified_to":    c.Query("verified_to"),
+		"verified_by":    filter.VerifiedBy,
+		"advisor_id":     filter.AdvisorID,
+		"program_study":  filter.ProgramStudy,
+		"academic_year":  filter.AcademicYear,
+		"sort_by":        filter.SortBy,
+		"sort_order":     filter.SortOrder,
+	}
+
+	// Mode cursor: posisi halaman ditentukan cursor (keyset), tanpa COUNT dan OFFSET
+	if cursorRequested(c) {
+		cursor, errResponse := parseCursor(c)
+		if errResponse != nil {
+			return errResponse()
 		}
-		if !validStatuses[statusFilter] {
-			return c.Status(400).JSON(fiber.Map{
+
+		achievements, next, prev, err := s.readModelRepo.FindAllByCursor(filter, cursor, limit)
+		if err == repository.ErrInvalidCursor {
+			return invalidCursor(c)
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
 				"status":  "error",
-				"message": "Invalid status filter. Valid values: draft, submitted, verified, rejected",
+				"message": "Gagal mengambil data achievements",
 			})
 		}
-	}
-
-	// Step 1: Get achievement references dari PostgreSQL dengan filter
-	references, total, err := s.referenceRepo.FindAllWithFilters(
-		limit,
-		offset,
-		statusFilter,
-		studentIDFilter,
-		sortBy,
-		sortOrder,
-	)
-	if err != nil {
-		return c.Status(500).JSON(fiber.Map{
-			"status":  "error",
-			"message": "Gagal mengambil data achievement references",
-		})
-	}
 
-	// Check if no data
-	if len(references) == 0 {
 		return c.Status(200).JSON(fiber.Map{
 			"status":  "success",
 			"message": "Data achievements berhasil diambil",
 			"data": fiber.Map{
-				"achievements": []models.Achievement{},
-				"pagination": fiber.Map{
-					"page":        page,
-					"limit":       limit,
-					"total_items": total,
-					"total_pages": 0,
-				},
+				"achievements": achievements,
+				"pagination":   NewCursorPaginationMeta(limit, next, prev),
+				"filters":      filters,
 			},
 		})
 	}
 
-	// Step 2: Extract achievement IDs untuk fetch dari MongoDB
-	achievementIDs := make([]string, len(references))
-	for i, ref := range references {
-		achievementIDs[i] = ref.MongoAchievementID
-	}
-
-	// Step 3: Fetch details dari MongoDB
-	ctx := context.Background()
-	achievements, err := s.achievementRepo.FindByAchievementIDs(ctx, achievementIDs)
+	// Semua filter (termasuk category, level dan tanggal prestasi dari MongoDB) dijalankan di read model
+	achievements, total, err := s.readModelRepo.FindAll(filter, limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengambil detail achievements dari MongoDB",
+			"message": "Gagal mengambil data achievements",
 		})
 	}
 
@@ -1015,7 +1715,6 @@ func (s *AchievementService) GetAllAchievements(c *fiber.Ctx) error {
 		totalPages++
 	}
 
-	// Step 4: Return dengan pagination
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Data achievements berhasil diambil",
@@ -1027,16 +1726,212 @@ func (s *AchievementService) GetAllAchievements(c *fiber.Ctx) error {
 				"total_items": total,
 				"total_pages": totalPages,
 			},
-			"filters": fiber.Map{
-				"status":     statusFilter,
-				"student_id": studentIDFilter,
-				"sort_by":    sortBy,
-				"sort_order": sortOrder,
-			},
+			"filters": filters,
 		},
 	})
 }
 
+// ExportAllAchievements godoc
+// @Summary Export all achievements
+// @Description Export achievements as CSV or XLSX using the same filters and sorting as GET /achievements/all. Small exports are streamed directly; exports larger than EXPORT_ASYNC_THRESHOLD rows (or async=true) run in the background and return 202 with an export job to poll at /exports/{id}.
+// @Tags Exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param async query bool false "Always run the export in the background"
+// @Param status query string false "Filter by status (draft, submitted, verified, rejected, revoked)"
+// @Param student_id query string false "Filter by student ID"
+// @Param category query string false "Filter by category code"
+// @Param level query string false "Filter by level code"
+// @Param date_from query string false "Achievement date from (YYYY-MM-DD)"
+// @Param date_to query string false "Achievement date to (YYYY-MM-DD)"
+// @Param submitted_from query string false "Submitted date from (YYYY-MM-DD)"
+// @Param submitted_to query string false "Submitted date to (YYYY-MM-DD)"
+// @Param verified_from query string false "Verified date from (YYYY-MM-DD)"
+// @Param verified_to query string false "Verified date to (YYYY-MM-DD)"
+// @Param verified_by query string false "Filter by verifier user ID"
+// @Param advisor_id query string false "Filter by the student's advisor user ID"
+// @Param program_study query string false "Filter by the student's study program"
+// @Param academic_year query string false "Filter by the student's academic year"
+// @Param sort_by query string false "Sort by field (created_at, submitted_at, verified_at, updated_at)" default(created_at)
+// @Param sort_order query string false "Sort order (asc, desc)" default(desc)
+// @Success 200 {file} file "CSV or XLSX file"
+// @Success 202 {object} object{status=string,message=string,data=models.ExportJob} "Export is running in the background"
+// @Failure 400 {object} map[string]interface{} "Invalid filter parameters or format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.read_all)"
+// @Failure 500 {object} map[string]interface{} "Failed to start export"
+// @Router /achievements/all/export [get]
+func (s *AchievementService) ExportAllAchievements(c *fiber.Ctx) error {
+	filter, errResponse := s.parseListFilter(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	_, total, err := s.readModelRepo.FindAll(filter, 1, 0)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data achievements",
+		})
+	}
+
+	categories, levels := masterDataLabels(s.masterRepo)
+	table := ExportTable{
+		Name:   "achievements",
+		Header: []string{"ID Achievement", "NIM", "Nama Mahasiswa", "Program Studi", "Angkatan", "Judul", "Kategori", "Level", "Tanggal Prestasi", "Status", "Diajukan", "Diverifikasi", "Tags"},
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			// Dibaca per halaman keyset agar koneksi database tidak tertahan selama client mengunduh
+			var cursor *models.PageCursor
+			for {
+				rows, next, err := s.readModelRepo.FindExportPage(filter, cursor, exportPageSize)
+				if err != nil {
+					return err
+				}
+				for _, row := range rows {
+					err := emit([]interface{}{
+						row.AchievementID, row.StudentNumber, row.StudentName, row.ProgramStudy, row.AcademicYear,
+						row.Title, labelOrCode(categories, row.Category), labelOrCode(levels, row.Level),
+						row.AchievementDate, row.Status, row.SubmittedAt, row.VerifiedAt, strings.Join(row.Tags, ", "),
+					})
+					if err != nil {
+						return err
+					}
+				}
+				if next == nil {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				cursor = next
+			}
+		},
+	}
+
+	return s.exports.Send(c, "achievements", table, total)
+}
+
+// masterDataLabels label kategori dan level (termasuk yang nonaktif) per kode; kosong jika gagal diambil
+func masterDataLabels(masterRepo *repository.MasterDataRepository) (map[string]string, map[string]string) {
+	categories := make(map[string]string)
+	if items, err := masterRepo.FindAllCategories(false); err == nil {
+		for _, item := range items {
+			categories[item.Code] = item.LabelID
+		}
+	}
+
+	levels := make(map[string]string)
+	if items, err := masterRepo.FindAllLevels(false); err == nil {
+		for _, item := range items {
+			levels[item.Code] = item.LabelID
+		}
+	}
+
+	return categories, levels
+}
+
+func labelOrCode(labels map[string]string, code string) string {
+	if label, ok := labels[code]; ok && label != "" {
+		return label
+	}
+	return code
+}
+
+// parseListFilter membaca filter listing semua achievement dari query (dipakai listing dan export);
+// mengembalikan fungsi response error jika ada parameter yang tidak valid
+func (s *AchievementService) parseListFilter(c *fiber.Ctx) (models.AchievementListFilter, func() error) {
+	filter := models.AchievementListFilter{
+		Status:       c.Query("status", ""),
+		StudentID:    c.Query("student_id", ""),
+		VerifiedBy:   c.Query("verified_by", ""),
+		AdvisorID:    c.Query("advisor_id", ""),
+		ProgramStudy: strings.TrimSpace(c.Query("program_study", "")),
+		AcademicYear: strings.TrimSpace(c.Query("academic_year", "")),
+		SortBy:       c.Query("sort_by", "created_at"),
+		SortOrder:    c.Query("sort_order", "desc"),
+	}
+
+	// Validate status filter
+	if filter.Status != "" {
+		validStatuses := map[string]bool{
+			"draft":     true,
+			"submitted": true,
+			"verified":  true,
+			"rejected":  true,
+			"revoked":   true,
+		}
+		if !validStatuses[filter.Status] {
+			return filter, func() error {
+				return c.Status(400).JSON(fiber.Map{
+					"status":  "error",
+					"message": "Invalid status filter. Valid values: draft, submitted, verified, rejected, revoked",
+				})
+			}
+		}
+	}
+
+	// Category dan level dicocokkan dengan code kanonik master data
+	var err error
+	if filter.Category, filter.Level, err = s.resolveMasterData(c.Query("category"), c.Query("level")); err != nil {
+		return filter, func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		}
+	}
+
+	// Rentang tanggal
+	ranges := []struct {
+		name     string
+		from, to **time.Time
+	}{
+		{"date", &filter.DateFrom, &filter.DateTo},
+		{"submitted", &filter.SubmittedFrom, &filter.SubmittedTo},
+		{"verified", &filter.VerifiedFrom, &filter.VerifiedTo},
+	}
+	for _, r := range ranges {
+		from, to, err := ParseDateRange(c.Query(r.name+"_from"), c.Query(r.name+"_to"))
+		if err != nil {
+			return filter, func() error {
+				return c.Status(400).JSON(fiber.Map{
+					"status":  "error",
+					"message": fmt.Sprintf("Parameter %s_from/%s_to tidak valid: %v", r.name, r.name, err),
+				})
+			}
+		}
+		*r.from, *r.to = from, to
+	}
+
+	return filter, nil
+}
+
+// ParseDateRange membaca rentang tanggal YYYY-MM-DD yang inklusif di kedua ujung.
+// Batas atas dikembalikan sebagai awal hari berikutnya (eksklusif); nilai kosong berarti tanpa batas.
+func ParseDateRange(fromValue, toValue string) (*time.Time, *time.Time, error) {
+	var from, to *time.Time
+	if fromValue != "" {
+		date, err := time.Parse("2006-01-02", fromValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format tanggal '%s' tidak valid, gunakan YYYY-MM-DD", fromValue)
+		}
+		from = &date
+	}
+	if toValue != "" {
+		date, err := time.Parse("2006-01-02", toValue)
+		if err != nil {
+			return nil, nil, fmt.Errorf("format tanggal '%s' tidak valid, gunakan YYYY-MM-DD", toValue)
+		}
+		next := date.AddDate(0, 0, 1)
+		to = &next
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, fmt.Errorf("tanggal awal harus sebelum atau sama dengan tanggal akhir")
+	}
+	return from, to, nil
+}
+
 // GetMyStatistics godoc
 // @Summary Get my achievement statistics
 // @Description Get comprehensive achievement statistics for the authenticated student including summary, category breakdown, level distribution, and period analysis.
@@ -1058,10 +1953,8 @@ func (s *AchievementService) GetMyStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	ctx := context.Background()
-
-	// Get statistics dari MongoDB
-	stats, err := s.achievementRepo.GetStatisticsByStudentIDs(ctx, []string{userID})
+	// Get statistics dari read model
+	stats, err := s.readModelRepo.GetStatistics([]string{userID})
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
@@ -1071,6 +1964,7 @@ func (s *AchievementService) GetMyStatistics(c *fiber.Ctx) error {
 
 	// Build response
 	response := buildStatisticsResponse(stats, false)
+	s.attachVerificationProgress(response, []string{userID})
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
@@ -1086,6 +1980,7 @@ func (s *AchievementService) GetMyStatistics(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param rank_by query string false "Rank top students by achievement count (default) or credit points (points)"
 // @Success 200 {object} object{status=string,message=string,data=object} "Advisee statistics retrieved successfully"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires lecturer access)"
@@ -1100,8 +1995,6 @@ func (s *AchievementService) GetAdviseeStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	ctx := context.Background()
-
 	// Get student IDs dari advisees
 	studentIDs, err := s.studentRepo.FindStudentIDsByAdvisorID(userID)
 	if err != nil {
@@ -1119,8 +2012,8 @@ func (s *AchievementService) GetAdviseeStatistics(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get statistics dari MongoDB
-	stats, err := s.achievementRepo.GetStatisticsByStudentIDs(ctx, studentIDs)
+	// Get statistics dari read model
+	stats, err := s.readModelRepo.GetStatistics(studentIDs)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
@@ -1129,13 +2022,14 @@ func (s *AchievementService) GetAdviseeStatistics(c *fiber.Ctx) error {
 	}
 
 	// Get top students
-	topStudents, err := s.referenceRepo.GetTopStudents(studentIDs, 10)
+	topStudents, err := s.referenceRepo.GetTopStudents(studentIDs, 10, c.Query("rank_by"))
 	if err != nil {
 		topStudents = []models.TopStudent{}
 	}
 
 	// Build response
 	response := buildStatisticsResponse(stats, true)
+	s.attachVerificationProgress(response, studentIDs)
 
 	// Convert topStudents to fiber.Map
 	topStudentsMap := []fiber.Map{}
@@ -1145,6 +2039,7 @@ func (s *AchievementService) GetAdviseeStatistics(c *fiber.Ctx) error {
 			"student_name":          student.StudentName,
 			"total_achievements":    student.TotalAchievements,
 			"verified_achievements": student.VerifiedAchievements,
+			"total_points":          student.TotalPoints,
 		})
 	}
 	response["top_students"] = topStudentsMap
@@ -1163,36 +2058,31 @@ func (s *AchievementService) GetAdviseeStatistics(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param rank_by query string false "Rank top students by achievement count (default) or credit points (points)"
 // @Success 200 {object} object{status=string,message=string,data=object} "All statistics retrieved successfully"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires admin access)"
 // @Failure 500 {object} map[string]interface{} "Failed to retrieve statistics from database"
 // @Router /reports/statistics [get]
 func (s *AchievementService) GetAllStatistics(c *fiber.Ctx) error {
-	ctx := context.Background()
-
-	// Get all student IDs (we'll use empty filter to get all)
-	// For simplicity, we'll aggregate all achievements
-	filter := bson.M{"is_deleted": false}
-	achievements, err := s.achievementRepo.FindAll(ctx, filter)
+	// Statistik semua mahasiswa dihitung langsung dari read model
+	stats, err := s.readModelRepo.GetStatistics(nil)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
-			"message": "Gagal mengambil data achievements",
+			"message": "Gagal mengambil statistik",
 		})
 	}
 
-	// Calculate statistics manually
-	stats := calculateStatisticsFromAchievements(achievements)
-
 	// Get top students (all students)
-	topStudents, err := s.referenceRepo.GetAllTopStudents(10)
+	topStudents, err := s.referenceRepo.GetAllTopStudents(10, c.Query("rank_by"))
 	if err != nil {
 		topStudents = []models.TopStudent{}
 	}
 
 	// Build response
 	response := buildStatisticsResponse(stats, true)
+	s.attachVerificationProgress(response, nil)
 
 	// Convert topStudents to fiber.Map
 	topStudentsMap := []fiber.Map{}
@@ -1202,6 +2092,7 @@ func (s *AchievementService) GetAllStatistics(c *fiber.Ctx) error {
 			"student_name":          student.StudentName,
 			"total_achievements":    student.TotalAchievements,
 			"verified_achievements": student.VerifiedAchievements,
+			"total_points":          student.TotalPoints,
 		})
 	}
 	response["top_students"] = topStudentsMap
@@ -1213,6 +2104,52 @@ func (s *AchievementService) GetAllStatistics(c *fiber.Ctx) error {
 	})
 }
 
+// ExportAllStatistics godoc
+// @Summary Export all achievement statistics
+// @Description Export the statistics of GET /reports/statistics (summary, per category, per level, per period and top students) as CSV or XLSX.
+// @Tags Exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param rank_by query string false "Rank top students by achievement count (default) or credit points (points)"
+// @Success 200 {file} file "CSV or XLSX file"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve statistics from database"
+// @Router /reports/statistics/export [get]
+func (s *AchievementService) ExportAllStatistics(c *fiber.Ctx) error {
+	stats, err := s.readModelRepo.GetStatistics(nil)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil statistik",
+		})
+	}
+
+	topStudents, err := s.referenceRepo.GetAllTopStudents(10, c.Query("rank_by"))
+	if err != nil {
+		topStudents = []models.TopStudent{}
+	}
+
+	categories, levels := masterDataLabels(s.masterRepo)
+	rows := StatisticsExportRows(stats, categories, levels, topStudents)
+	table := ExportTable{
+		Name:   "statistics",
+		Header: []string{"Bagian", "Item", "Jumlah", "Persentase", "Poin"},
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			for _, row := range rows {
+				if err := emit(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	return s.exports.Send(c, "statistics", table, int64(len(rows)))
+}
+
 // Helper function to build statistics response
 func buildStatisticsResponse(stats map[string]interface{}, includeTopStudents bool) fiber.Map {
 	totalAchievements := stats["total_achievements"].(int)
@@ -1306,54 +2243,35 @@ func buildEmptyStatistics(includeTopStudents bool) fiber.Map {
 	return response
 }
 
-// Helper function to calculate statistics from achievements
-func calculateStatisticsFromAchievements(achievements []models.Achievement) map[string]interface{} {
-	stats := make(map[string]interface{})
-
-	totalAchievements := len(achievements)
-	totalVerified := 0
-	totalPending := 0
-	totalRejected := 0
-	totalDraft := 0
-
-	categoryCount := make(map[string]int)
-	levelCount := make(map[string]int)
-	periodCount := make(map[string]int)
-
-	for _, achievement := range achievements {
-		switch achievement.Status {
-		case "verified":
-			totalVerified++
-		case "submitted":
-			totalPending++
-		case "rejected":
-			totalRejected++
-		case "draft":
-			totalDraft++
+// attachVerificationProgress menambahkan status persetujuan parsial (per tahap verifikasi)
+// ke response statistik. studentIDs nil berarti semua mahasiswa.
+func (s *AchievementService) attachVerificationProgress(response fiber.Map, studentIDs []string) {
+	byStage := []fiber.Map{}
+	partiallyApproved := 0
+
+	counts, err := s.referenceRepo.CountByVerificationStage(studentIDs)
+	if err == nil {
+		stages := make([]int, 0, len(counts))
+		for stage := range counts {
+			stages = append(stages, stage)
 		}
+		sort.Ints(stages)
 
-		if achievement.Category != "" {
-			categoryCount[achievement.Category]++
-		}
-
-		if achievement.Level != "" {
-			levelCount[achievement.Level]++
+		for _, stage := range stages {
+			byStage = append(byStage, fiber.Map{
+				"stage": stage,
+				"count": counts[stage],
+			})
+			if stage > 1 {
+				partiallyApproved += counts[stage]
+			}
 		}
-
-		yearMonth := achievement.Date.Format("2006-01")
-		periodCount[yearMonth]++
 	}
 
-	stats["total_achievements"] = totalAchievements
-	stats["total_verified"] = totalVerified
-	stats["total_pending"] = totalPending
-	stats["total_rejected"] = totalRejected
-	stats["total_draft"] = totalDraft
-	stats["category_count"] = categoryCount
-	stats["level_count"] = levelCount
-	stats["period_count"] = periodCount
-
-	return stats
+	if summary, ok := response["summary"].(fiber.Map); ok {
+		summary["total_partially_approved"] = partiallyApproved
+	}
+	response["by_verification_stage"] = byStage
 }
 
 // GetAchievementHistory godoc
@@ -1387,7 +2305,7 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 
 	// Check access (owner or admin/lecturer)
 	roleID, _ := c.Locals("role_id").(string)
-	if achievement.StudentID != userID && roleID != "1" && roleID != "2" {
+	if achievement.StudentID != userID && !isTeamParticipant(achievement, userID) && roleID != "1" && roleID != "2" {
 		return c.Status(403).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Anda tidak memiliki akses ke achievement ini",
@@ -1420,6 +2338,22 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 		})
 	}
 
+	// Persetujuan tahap antara (tahap terakhir tercatat sebagai verified/rejected)
+	approvals, _ := s.approvalRepo.FindByMongoID(achievementID)
+	for _, approval := range approvals {
+		if approval.Status != "approved" || approval.StageOrder >= len(approvals) || approval.DecidedAt == nil {
+			continue
+		}
+		history = append(history, fiber.Map{
+			"status":      "stage_approved",
+			"timestamp":   *approval.DecidedAt,
+			"stage":       approval.StageName,
+			"stage_order": approval.StageOrder,
+			"approved_by": approval.DecidedBy,
+			"note":        fmt.Sprintf("Stage %d of %d (%s) approved", approval.StageOrder, len(approvals), approval.StageName),
+		})
+	}
+
 	if reference.VerifiedAt != nil && reference.VerifiedBy != nil {
 		history = append(history, fiber.Map{
 			"status":      "verified",
@@ -1438,6 +2372,42 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 		})
 	}
 
+	if reference.Status == "revoked" {
+		if revocation, err := s.referenceRepo.FindRevocation(achievementID); err == nil && revocation != nil {
+			history = append(history, fiber.Map{
+				"status":            "revoked",
+				"timestamp":         revocation.RevokedAt,
+				"revoked_by":        revocation.RevokedBy,
+				"revocation_reason": revocation.Reason,
+				"note":              "Achievement revoked",
+			})
+		}
+	}
+
+	// Komentar hanya ditampilkan ke user yang boleh mengakses thread-nya
+	if canComment, _ := CanAccessAchievementThread(s.studentRepo, s.permRepo, s.approvalRepo, userID, roleID, achievement); canComment {
+		comments, _ := s.commentRepo.FindByAchievementID(ctx, achievementID)
+		for _, comment := range comments {
+			entry := fiber.Map{
+				"status":     "comment",
+				"timestamp":  comment.CreatedAt,
+				"comment_id": comment.CommentID,
+				"author_id":  comment.AuthorID,
+				"note":       "Comment added",
+			}
+			if comment.IsDeleted {
+				entry["note"] = "Comment deleted"
+			}
+			history = append(history, entry)
+		}
+	}
+
+	sort.SliceStable(history, func(i, j int) bool {
+		ti, _ := history[i]["timestamp"].(time.Time)
+		tj, _ := history[j]["timestamp"].(time.Time)
+		return ti.Before(tj)
+	})
+
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "History berhasil diambil",
@@ -1457,12 +2427,15 @@ func (s *AchievementService) GetAchievementHistory(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Achievement ID"
+// @Param If-Match header string true "ETag of the achievement from the last read"
 // @Param attachments formData file true "Additional attachment files (multiple files allowed)"
 // @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,new_documents=[]models.Document,total_documents=int}} "Attachments uploaded successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request, no files, or upload error"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Access denied or achievement not in draft status"
 // @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 412 {object} map[string]interface{} "Achievement was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
 // @Failure 500 {object} map[string]interface{} "Upload operation failed"
 // @Router /achievements/{id}/attachments [post]
 func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
@@ -1496,6 +2469,11 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		})
 	}
 
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, achievement.Version); errResp != nil {
+		return errResp()
+	}
+
 	// Handle file upload
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -1528,12 +2506,16 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 			})
 		}
 
+		// Hash isi file untuk deteksi dokumen duplikat
+		hash, _ := utils.HashFile(filepath)
+
 		// Add to documents
 		newDocuments = append(newDocuments, models.Document{
 			Filename:   file.Filename,
 			Filepath:   filepath,
 			Filesize:   file.Size,
 			Mimetype:   file.Header.Get("Content-Type"),
+			Hash:       hash,
 			UploadedAt: time.Now(),
 		})
 	}
@@ -1548,12 +2530,19 @@ func (s *AchievementService) UploadAttachment(c *fiber.Ctx) error {
 		for _, doc := range newDocuments {
 			utils.DeleteFile(doc.Filepath)
 		}
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Gagal mengupdate achievement",
 		})
 	}
 
+	s.projector.Refresh(ctx, achievementID)
+
+	c.Set(fiber.HeaderETag, ETag(achievement.Version))
+
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Attachment berhasil diupload",
@@ -1615,13 +2604,15 @@ func (s *AchievementService) GetStudentAchievements(c *fiber.Ctx) error {
 
 // GetStudentReport godoc
 // @Summary Get student report
-// @Description Get detailed achievement report for a specific student including statistics and achievement list.
+// @Description Get detailed achievement report for a specific student including statistics and a paginated achievement list (team achievements the student confirmed included). Revoked achievements are excluded from statistics.
 // @Tags Statistics & Reports
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Student ID"
-// @Success 200 {object} object{status=string,message=string,data=object{student=models.Student,statistics=object,achievements=[]models.Achievement}} "Student report retrieved successfully"
+// @Param page query int false "Page number for the achievement list" default(1)
+// @Param limit query int false "Achievements per page (max 100)" default(10)
+// @Success 200 {object} object{status=string,message=string,data=object{student=models.Student,statistics=object,achievements=[]models.Achievement,pagination=models.PaginationMeta}} "Student report retrieved successfully"
 // @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
 // @Failure 403 {object} map[string]interface{} "Access denied - not owner, admin, or lecturer"
 // @Failure 404 {object} map[string]interface{} "Student not found"
@@ -1642,7 +2633,16 @@ func (s *AchievementService) GetStudentReport(c *fiber.Ctx) error {
 		})
 	}
 
-	ctx := context.Background()
+	// Parse pagination parameters
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
 
 	// Get student info
 	student, err := s.studentRepo.FindByUserID(studentID)
@@ -1653,8 +2653,8 @@ func (s *AchievementService) GetStudentReport(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get achievements
-	achievements, err := s.achievementRepo.FindByStudentID(ctx, studentID)
+	// Get achievements (termasuk prestasi tim yang dikonfirmasi) dari read model
+	achievements, total, err := s.readModelRepo.FindByStudentIDs([]string{studentID}, limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
@@ -1662,16 +2662,101 @@ func (s *AchievementService) GetStudentReport(c *fiber.Ctx) error {
 		})
 	}
 
-	// Calculate statistics
-	stats := calculateStatisticsFromAchievements(achievements)
+	// Statistik dari read model, dihitung sama seperti statistik admin (exclude revoked)
+	stats, err := s.readModelRepo.GetStatistics([]string{studentID})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil statistik",
+		})
+	}
+	statistics := buildStatisticsResponse(stats, false)
+	s.attachVerificationProgress(statistics, []string{studentID})
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Report berhasil diambil",
 		"data": fiber.Map{
 			"student":      student,
-			"statistics":   buildStatisticsResponse(stats, false),
+			"statistics":   statistics,
 			"achievements": achievements,
+			"pagination": models.PaginationMeta{
+				Page:       page,
+				Limit:      limit,
+				TotalItems: total,
+				TotalPages: totalPages,
+			},
 		},
 	})
+}
+
+// checkDetails memvalidasi details terhadap schema kategori; mengembalikan fungsi response error jika gagal
+func (s *AchievementService) checkDetails(c *fiber.Ctx, category string, details map[string]interface{}) func() error {
+	violations, err := s.detailsViolations(category, details)
+	if err != nil {
+		return func() error {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal memvalidasi details achievement",
+			})
+		}
+	}
+
+	if len(violations) > 0 {
+		return func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": fmt.Sprintf("Details tidak sesuai schema kategori '%s'", category),
+				"errors":  violations,
+			})
+		}
+	}
+
+	return nil
+}
+
+// detailsViolations memvalidasi details terhadap schema kategori;
+// sub-kategori tanpa schema sendiri memakai schema kategori induknya
+func (s *AchievementService) detailsViolations(category string, details map[string]interface{}) ([]string, error) {
+	lineage := []string{category}
+	if categories, err := s.masterRepo.FindAllCategories(false); err == nil {
+		lineage = CategoryLineage(categories, category)
+	}
+
+	return validateDetailsForCategory(s.schemaRepo, lineage, details)
+}
+
+// resolveMasterData mengubah category/level menjadi code kanonik master data aktif.
+// Nilai kosong dibiarkan kosong; error berisi pesan untuk client.
+func (s *AchievementService) resolveMasterData(category, level string) (string, string, error) {
+	if category != "" {
+		categories, err := s.masterRepo.FindAllCategories(true)
+		if err != nil {
+			return "", "", fmt.Errorf("Gagal mengambil master data kategori")
+		}
+		code, ok := ResolveCategoryCode(categories, category)
+		if !ok {
+			return "", "", fmt.Errorf("Kategori '%s' tidak terdaftar di master data", category)
+		}
+		category = code
+	}
+
+	if level != "" {
+		levels, err := s.masterRepo.FindAllLevels(true)
+		if err != nil {
+			return "", "", fmt.Errorf("Gagal mengambil master data level")
+		}
+		code, ok := ResolveLevelCode(levels, level)
+		if !ok {
+			return "", "", fmt.Errorf("Level '%s' tidak terdaftar di master data", level)
+		}
+		level = code
+	}
+
+	return category, level, nil
 }
\ No newline at end of file
@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var validTeamRoles = map[string]bool{
+	"leader": true,
+	"member": true,
+}
+
+// CreditedStudentIDs mengembalikan mahasiswa yang dikreditkan atas achievement:
+// pemilik dan anggota tim yang sudah konfirmasi
+func CreditedStudentIDs(achievement *models.Achievement) []string {
+	credited := []string{achievement.StudentID}
+	for _, member := range achievement.Members {
+		if member.Status == "confirmed" && member.StudentID != achievement.StudentID {
+			credited = append(credited, member.StudentID)
+		}
+	}
+	return credited
+}
+
+// FindTeamMember mencari anggota tim berdasarkan user ID mahasiswa
+func FindTeamMember(achievement *models.Achievement, studentID string) *models.AchievementMember {
+	for i := range achievement.Members {
+		if achievement.Members[i].StudentID == studentID {
+			return &achievement.Members[i]
+		}
+	}
+	return nil
+}
+
+// HasPendingTeamInvitations true jika masih ada anggota yang belum menjawab undangan
+func HasPendingTeamInvitations(achievement *models.Achievement) bool {
+	for _, member := range achievement.Members {
+		if member.Status == "invited" {
+			return true
+		}
+	}
+	return false
+}
+
+// isTeamParticipant: anggota tim yang diundang atau sudah konfirmasi boleh melihat achievement
+func isTeamParticipant(achievement *models.Achievement, userID string) bool {
+	member := FindTeamMember(achievement, userID)
+	return member != nil && member.Status != "declined"
+}
+
+// InviteTeamMember godoc
+// @Summary Invite team member
+// @Description Owner of a draft achievement invites another student as co-member (role member; the owner is recorded as the only team leader). The invitee must confirm before the achievement can be submitted.
+// @Tags Achievement Team
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param request body models.TeamMemberRequest true "Student user ID and role (default member)"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,members=[]models.AchievementMember}} "Member invited successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request, leader role (the owner is the only leader), not a student, already a member, or achievement not in draft status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Not the achievement owner"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update team members"
+// @Router /achievements/{id}/members [post]
+func (s *AchievementService) InviteTeamMember(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	var req models.TeamMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	if req.StudentID == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Student ID harus diisi",
+		})
+	}
+	if req.Role == "" {
+		req.Role = "member"
+	}
+	if !validTeamRoles[req.Role] {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Role tidak valid. Valid values: leader, member",
+		})
+	}
+	// Pemilik achievement selalu menjadi satu-satunya leader tim
+	if req.Role == "leader" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tim hanya boleh memiliki satu leader, yaitu pemilik achievement",
+		})
+	}
+
+	ctx := context.Background()
+	achievement, errResp := s.loadOwnedDraft(c, ctx, achievementID, userID)
+	if errResp != nil {
+		return errResp()
+	}
+
+	if req.StudentID == achievement.StudentID {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Pemilik achievement sudah menjadi anggota tim",
+		})
+	}
+
+	student, err := s.studentRepo.FindByUserID(req.StudentID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data mahasiswa",
+		})
+	}
+	if student == nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Mahasiswa tidak ditemukan",
+		})
+	}
+
+	previous := append([]models.AchievementMember{}, achievement.Members...)
+	now := time.Now()
+
+	// Pemilik tercatat sebagai leader saat achievement pertama kali menjadi prestasi tim
+	if len(achievement.Members) == 0 {
+		achievement.Members = append(achievement.Members, models.AchievementMember{
+			StudentID:   achievement.StudentID,
+			Role:        "leader",
+			Status:      "confirmed",
+			InvitedAt:   now,
+			RespondedAt: &now,
+		})
+	}
+
+	invitation := models.AchievementMember{
+		StudentID: req.StudentID,
+		Role:      req.Role,
+		Status:    "invited",
+		InvitedAt: now,
+	}
+
+	// Anggota yang pernah menolak boleh diundang ulang
+	if existing := FindTeamMember(achievement, req.StudentID); existing != nil {
+		if existing.Status != "declined" {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Mahasiswa sudah diundang atau sudah menjadi anggota tim",
+			})
+		}
+		*existing = invitation
+	} else {
+		achievement.Members = append(achievement.Members, invitation)
+	}
+
+	if err := s.saveTeamMembers(ctx, achievementID, achievement.Members, previous); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan anggota tim",
+		})
+	}
+
+	s.notifier.Notify(req.StudentID, "team_invitation", "Undangan anggota tim",
+		fmt.Sprintf("Anda diundang sebagai %s pada prestasi tim '%s'", req.Role, achievement.Title), achievementID)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Anggota tim berhasil diundang",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"members":        achievement.Members,
+		},
+	})
+}
+
+// RemoveTeamMember godoc
+// @Summary Remove team member
+// @Description Owner of a draft achievement removes a co-member or cancels an invitation.
+// @Tags Achievement Team
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param studentId path string true "Student user ID"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,members=[]models.AchievementMember}} "Member removed successfully"
+// @Failure 400 {object} map[string]interface{} "Cannot remove owner or achievement not in draft status"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Not the achievement owner"
+// @Failure 404 {object} map[string]interface{} "Achievement or member not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update team members"
+// @Router /achievements/{id}/members/{studentId} [delete]
+func (s *AchievementService) RemoveTeamMember(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	memberID := c.Params("studentId")
+	userID, _ := c.Locals("user_id").(string)
+
+	ctx := context.Background()
+	achievement, errResp := s.loadOwnedDraft(c, ctx, achievementID, userID)
+	if errResp != nil {
+		return errResp()
+	}
+
+	if memberID == achievement.StudentID {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Pemilik achievement tidak bisa dihapus dari tim",
+		})
+	}
+
+	if FindTeamMember(achievement, memberID) == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anggota tim tidak ditemukan",
+		})
+	}
+
+	previous := achievement.Members
+	members := []models.AchievementMember{}
+	for _, member := range achievement.Members {
+		if member.StudentID != memberID {
+			members = append(members, member)
+		}
+	}
+	// Tinggal pemilik saja: bukan prestasi tim lagi
+	if len(members) == 1 {
+		members = []models.AchievementMember{}
+	}
+
+	if err := s.saveTeamMembers(ctx, achievementID, members, previous); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan anggota tim",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Anggota tim berhasil dihapus",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"members":        members,
+		},
+	})
+}
+
+// RespondTeamInvitation godoc
+// @Summary Respond to team invitation
+// @Description Invited student confirms or declines participation in a team achievement. Only confirmed members are credited.
+// @Tags Achievement Team
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param request body models.TeamInvitationResponse true "Accept (true) or decline (false)"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement_id=string,members=[]models.AchievementMember}} "Invitation answered successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request or no pending invitation"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update team members"
+// @Router /achievements/{id}/members/respond [post]
+func (s *AchievementService) RespondTeamInvitation(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	var req models.TeamInvitationResponse
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	ctx := context.Background()
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+
+	member := FindTeamMember(achievement, userID)
+	if member == nil || member.Status != "invited" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tidak ada undangan yang menunggu konfirmasi",
+		})
+	}
+
+	if achievement.Status != "draft" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Undangan hanya bisa dijawab selama achievement berstatus 'draft'",
+		})
+	}
+
+	previous := append([]models.AchievementMember{}, achievement.Members...)
+	now := time.Now()
+	member.RespondedAt = &now
+	member.Status = "declined"
+	if req.Accept {
+		member.Status = "confirmed"
+	}
+
+	if err := s.saveTeamMembers(ctx, achievementID, achievement.Members, previous); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan anggota tim",
+		})
+	}
+
+	answer := "menolak"
+	if req.Accept {
+		answer = "menerima"
+	}
+	s.notifier.Notify(achievement.StudentID, "team_invitation_answered", "Jawaban undangan anggota tim",
+		fmt.Sprintf("Undangan anggota tim pada prestasi '%s' telah dijawab: %s", achievement.Title, answer), achievementID)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Undangan berhasil dijawab",
+		"data": fiber.Map{
+			"achievement_id": achievementID,
+			"members":        achievement.Members,
+		},
+	})
+}
+
+// loadOwnedDraft memuat achievement milik user yang masih berstatus draft.
+// Jika gagal, fungsi response error dikembalikan.
+func (s *AchievementService) loadOwnedDraft(c *fiber.Ctx, ctx context.Context, achievementID, userID string) (*models.Achievement, func() error) {
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return nil, func() error {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Achievement tidak ditemukan",
+			})
+		}
+	}
+
+	if achievement.StudentID != userID {
+		return nil, func() error {
+			return c.Status(403).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Anda tidak memiliki akses ke achievement ini",
+			})
+		}
+	}
+
+	if achievement.Status != "draft" {
+		return nil, func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Anggota tim hanya bisa diubah jika status 'draft'",
+			})
+		}
+	}
+
+	return achievement, nil
+}
+
+// saveTeamMembers menyimpan anggota tim ke MongoDB lalu PostgreSQL, rollback MongoDB jika PostgreSQL gagal
+func (s *AchievementService) saveTeamMembers(ctx context.Context, achievementID string, members, previous []models.AchievementMember) error {
+	if err := s.achievementRepo.UpdateMembers(ctx, achievementID, members); err != nil {
+		return err
+	}
+
+	if err := s.memberRepo.ReplaceForAchievement(achievementID, members); err != nil {
+		s.achievementRepo.UpdateMembers(ctx, achievementID, previous)
+		return err
+	}
+
+	s.projector.Refresh(ctx, achievementID)
+	return nil
+}
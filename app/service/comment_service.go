@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var mentionPattern = regexp.MustCompile(`(?:^|\s)@([A-Za-z0-9_.\-]+)`)
+
+type CommentService struct {
+	achievementRepo *repository.AchievementRepository
+	commentRepo     *repository.CommentRepository
+	studentRepo     *repository.StudentRepository
+	userRepo        *repository.UserRepository
+	permRepo        *repository.PermissionRepository
+	approvalRepo    *repository.AchievementApprovalRepository
+	notifier        *NotificationService
+	editWindow      time.Duration
+	deleteWindow    time.Duration
+}
+
+func NewCommentService(mongoDB *mongo.Database, postgresDB *sql.DB) *CommentService {
+	return &CommentService{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		commentRepo:     repository.NewCommentRepository(mongoDB),
+		studentRepo:     repository.NewStudentRepository(postgresDB),
+		userRepo:        repository.NewUserRepository(postgresDB),
+		permRepo:        repository.NewPermissionRepository(postgresDB),
+		approvalRepo:    repository.NewAchievementApprovalRepository(postgresDB),
+		notifier:        NewNotificationService(postgresDB),
+		editWindow:      time.Duration(utils.GetEnvInt("COMMENT_EDIT_WINDOW_MINUTES", 15)) * time.Minute,
+		deleteWindow:    time.Duration(utils.GetEnvInt("COMMENT_DELETE_WINDOW_MINUTES", 60)) * time.Minute,
+	}
+}
+
+// ExtractMentions mengambil username unik yang di-mention dengan format @username
+func ExtractMentions(content string) []string {
+	seen := make(map[string]bool)
+	mentions := []string{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		username := strings.TrimRight(match[1], ".-")
+		if username == "" || seen[strings.ToLower(username)] {
+			continue
+		}
+		seen[strings.ToLower(username)] = true
+		mentions = append(mentions, username)
+	}
+
+	return mentions
+}
+
+// BuildCommentThreads menyusun komentar datar menjadi thread bersarang (urutan input dipertahankan).
+// Balasan yang parent-nya tidak ditemukan ditampilkan di level teratas.
+func BuildCommentThreads(comments []models.AchievementComment) []models.CommentThread {
+	children := make(map[string][]models.AchievementComment)
+	exists := make(map[string]bool)
+	for _, comment := range comments {
+		exists[comment.CommentID] = true
+	}
+
+	roots := []models.AchievementComment{}
+	for _, comment := range comments {
+		if comment.ParentID != nil && exists[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], comment)
+			continue
+		}
+		roots = append(roots, comment)
+	}
+
+	var build func(comment models.AchievementComment) models.CommentThread
+	build = func(comment models.AchievementComment) models.CommentThread {
+		thread := models.CommentThread{
+			AchievementComment: comment,
+			Replies:            []models.CommentThread{},
+		}
+		for _, child := range children[comment.CommentID] {
+			thread.Replies = append(thread.Replies, build(child))
+		}
+		return thread
+	}
+
+	threads := []models.CommentThread{}
+	for _, root := range roots {
+		threads = append(threads, build(root))
+	}
+
+	return threads
+}
+
+// CanAccessAchievementThread: pemilik, anggota tim, dosen wali pemilik, admin, dan verifikator fakultas
+// untuk achievement yang rantai verifikasinya sudah mencapai tahap fakultas
+func CanAccessAchievementThread(studentRepo *repository.StudentRepository, permRepo *repository.PermissionRepository, approvalRepo *repository.AchievementApprovalRepository, userID, roleID string, achievement *models.Achievement) (bool, error) {
+	if roleID == "1" || achievement.StudentID == userID {
+		return true, nil
+	}
+	if member := FindTeamMember(achievement, userID); member != nil && member.Status == "confirmed" {
+		return true, nil
+	}
+
+	student, err := studentRepo.FindByUserID(achievement.StudentID)
+	if err != nil {
+		return false, err
+	}
+	if student != nil && student.AdvisorID == userID {
+		return true, nil
+	}
+
+	faculty, err := userHasPermission(permRepo, userID, "achievements.verify_faculty")
+	if err != nil || !faculty {
+		return false, err
+	}
+
+	approvals, err := approvalRepo.FindByMongoID(achievement.AchievementID)
+	if err != nil {
+		return false, err
+	}
+	return ApprovalStageReached(approvals, "achievements.verify_faculty"), nil
+}
+
+// GetComments godoc
+// @Summary Get achievement comments
+// @Description Get threaded comments of an achievement. Students see comments on their own achievements, advisors on their advisees' achievements.
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param document query string false "Only comments anchored to this document filepath"
+// @Success 200 {object} object{status=string,message=string,data=object{comments=[]models.CommentThread,total=int}} "Comments retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve comments"
+// @Router /achievements/{id}/comments [get]
+func (s *CommentService) GetComments(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	documentFilter := c.Query("document", "")
+
+	ctx := context.Background()
+	if _, errResp := s.authorizeThread(c, ctx, achievementID); errResp != nil {
+		return errResp()
+	}
+
+	comments, err := s.commentRepo.FindByAchievementID(ctx, achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data komentar",
+		})
+	}
+
+	visible := []models.AchievementComment{}
+	for _, comment := range comments {
+		if documentFilter != "" && (comment.DocumentFilepath == nil || *comment.DocumentFilepath != documentFilter) {
+			continue
+		}
+		// Isi komentar yang dihapus disembunyikan, posisinya di thread tetap ada
+		if comment.IsDeleted {
+			comment.Content = ""
+			comment.Mentions = []string{}
+		}
+		visible = append(visible, comment)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data komentar berhasil diambil",
+		"data": fiber.Map{
+			"comments": BuildCommentThreads(visible),
+			"total":    len(visible),
+		},
+	})
+}
+
+// CreateComment godoc
+// @Summary Create achievement comment
+// @Description Add a comment or reply to an achievement, optionally anchored to one of its documents. Mentioned users (@username) with access to the achievement are notified.
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param request body models.CommentRequest true "Comment content, optional parent_id and document_filepath"
+// @Success 201 {object} object{status=string,message=string,data=models.AchievementComment} "Comment created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request, unknown parent or document"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to create comment"
+// @Router /achievements/{id}/comments [post]
+func (s *CommentService) CreateComment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	ctx := context.Background()
+	achievement, errResp := s.authorizeThread(c, ctx, achievementID)
+	if errResp != nil {
+		return errResp()
+	}
+
+	var req models.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Content harus diisi",
+		})
+	}
+
+	comment := &models.AchievementComment{
+		CommentID:     uuid.New().String(),
+		AchievementID: achievementID,
+		AuthorID:      userID,
+		Content:       req.Content,
+		IsDeleted:     false,
+	}
+
+	// Balasan harus pada thread achievement yang sama
+	if req.ParentID != "" {
+		parent, err := s.commentRepo.FindByID(ctx, req.ParentID)
+		if err != nil || parent.AchievementID != achievementID {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Komentar induk tidak ditemukan",
+			})
+		}
+		comment.ParentID = &req.ParentID
+	}
+
+	// Anchor ke dokumen harus dokumen milik achievement ini
+	if req.DocumentFilepath != "" {
+		found := false
+		for _, doc := range achievement.Documents {
+			if doc.Filepath == req.DocumentFilepath {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Dokumen tidak ditemukan pada achievement ini",
+			})
+		}
+		comment.DocumentFilepath = &req.DocumentFilepath
+	}
+
+	mentionedUserIDs := s.resolveMentions(achievement, req.Content, userID)
+	comment.Mentions = mentionedUserIDs
+
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan komentar",
+		})
+	}
+
+	// Notifikasi: pemilik achievement dan user yang di-mention
+	notified := map[string]bool{userID: true}
+	for _, mentionedID := range mentionedUserIDs {
+		notified[mentionedID] = true
+		s.notifier.Notify(mentionedID, "comment_mention", "Anda disebut dalam komentar",
+			fmt.Sprintf("Anda disebut dalam komentar pada prestasi '%s'", achievement.Title), achievementID)
+	}
+	if !notified[achievement.StudentID] {
+		s.notifier.Notify(achievement.StudentID, "comment_new", "Komentar baru pada prestasi",
+			fmt.Sprintf("Ada komentar baru pada prestasi '%s'", achievement.Title), achievementID)
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Komentar berhasil dibuat",
+		"data":    comment,
+	})
+}
+
+// UpdateComment godoc
+// @Summary Update achievement comment
+// @Description Edit own comment within the edit window (COMMENT_EDIT_WINDOW_MINUTES, default 15 minutes).
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param commentId path string true "Comment ID"
+// @Param request body object{content=string} true "New comment content"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementComment} "Comment updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request or edit window expired"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the comment author"
+// @Failure 404 {object} map[string]interface{} "Comment not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update comment"
+// @Router /achievements/{id}/comments/{commentId} [put]
+func (s *CommentService) UpdateComment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	commentID := c.Params("commentId")
+	userID, _ := c.Locals("user_id").(string)
+
+	ctx := context.Background()
+	achievement, errResp := s.authorizeThread(c, ctx, achievementID)
+	if errResp != nil {
+		return errResp()
+	}
+
+	var req models.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Content = strings.TrimSpace(req.Content)
+	if req.Content == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Content harus diisi",
+		})
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil || comment.AchievementID != achievementID || comment.IsDeleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Komentar tidak ditemukan",
+		})
+	}
+
+	if comment.AuthorID != userID {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Hanya penulis yang bisa mengedit komentar",
+		})
+	}
+
+	if time.Since(comment.CreatedAt) > s.editWindow {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("Komentar hanya bisa diedit dalam %d menit setelah dibuat", int(s.editWindow.Minutes())),
+		})
+	}
+
+	// Hanya mention baru yang mendapat notifikasi
+	previous := make(map[string]bool)
+	for _, mentionedID := range comment.Mentions {
+		previous[mentionedID] = true
+	}
+	mentionedUserIDs := s.resolveMentions(achievement, req.Content, userID)
+
+	if err := s.commentRepo.UpdateContent(ctx, commentID, req.Content, mentionedUserIDs); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate komentar",
+		})
+	}
+
+	for _, mentionedID := range mentionedUserIDs {
+		if !previous[mentionedID] {
+			s.notifier.Notify(mentionedID, "comment_mention", "Anda disebut dalam komentar",
+				fmt.Sprintf("Anda disebut dalam komentar pada prestasi '%s'", achievement.Title), achievementID)
+		}
+	}
+
+	updated, _ := s.commentRepo.FindByID(ctx, commentID)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Komentar berhasil diupdate",
+		"data":    updated,
+	})
+}
+
+// DeleteComment godoc
+// @Summary Delete achievement comment
+// @Description Delete own comment within the delete window (COMMENT_DELETE_WINDOW_MINUTES, default 60 minutes). Admins can delete any comment. Replies are kept.
+// @Tags Achievement Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param commentId path string true "Comment ID"
+// @Success 200 {object} object{status=string,message=string} "Comment deleted successfully"
+// @Failure 400 {object} map[string]interface{} "Delete window expired"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Not the comment author"
+// @Failure 404 {object} map[string]interface{} "Comment not found"
+// @Failure 500 {object} map[string]interface{} "Failed to delete comment"
+// @Router /achievements/{id}/comments/{commentId} [delete]
+func (s *CommentService) DeleteComment(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	commentID := c.Params("commentId")
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	ctx := context.Background()
+	if _, errResp := s.authorizeThread(c, ctx, achievementID); errResp != nil {
+		return errResp()
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil || comment.AchievementID != achievementID || comment.IsDeleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Komentar tidak ditemukan",
+		})
+	}
+
+	if roleID != "1" {
+		if comment.AuthorID != userID {
+			return c.Status(403).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Hanya penulis yang bisa menghapus komentar",
+			})
+		}
+		if time.Since(comment.CreatedAt) > s.deleteWindow {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": fmt.Sprintf("Komentar hanya bisa dihapus dalam %d menit setelah dibuat", int(s.deleteWindow.Minutes())),
+			})
+		}
+	}
+
+	if err := s.commentRepo.SoftDelete(ctx, commentID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus komentar",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Komentar berhasil dihapus",
+	})
+}
+
+// authorizeThread memuat achievement dan memastikan user boleh mengakses thread komentarnya.
+// Jika gagal, fungsi response error dikembalikan.
+func (s *CommentService) authorizeThread(c *fiber.Ctx, ctx context.Context, achievementID string) (*models.Achievement, func() error) {
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return nil, func() error {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Achievement tidak ditemukan",
+			})
+		}
+	}
+
+	allowed, err := CanAccessAchievementThread(s.studentRepo, s.permRepo, s.approvalRepo, userID, roleID, achievement)
+	if err != nil {
+		return nil, func() error {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengecek akses komentar",
+			})
+		}
+	}
+	if !allowed {
+		return nil, func() error {
+			return c.Status(403).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Anda tidak memiliki akses ke komentar achievement ini",
+			})
+		}
+	}
+
+	return achievement, nil
+}
+
+// resolveMentions mengubah @username menjadi user ID; hanya user yang boleh melihat thread yang diambil
+func (s *CommentService) resolveMentions(achievement *models.Achievement, content string, authorID string) []string {
+	userIDs := []string{}
+	seen := map[string]bool{authorID: true}
+
+	for _, username := range ExtractMentions(content) {
+		user, err := s.userRepo.FindByUsernameOrEmail(username)
+		if err != nil || seen[user.ID] || !user.IsActive {
+			continue
+		}
+
+		allowed, err := CanAccessAchievementThread(s.studentRepo, s.permRepo, s.approvalRepo, user.ID, user.RoleID, achievement)
+		if err != nil || !allowed {
+			continue
+		}
+
+		seen[user.ID] = true
+		userIDs = append(userIDs, user.ID)
+	}
+
+	return userIDs
+}
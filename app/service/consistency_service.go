@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"crypto/subtle"
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sumber kebenaran yang dipakai saat memperbaiki drift
+const (
+	SourceOfTruthPostgres = "postgres"
+	SourceOfTruthMongo    = "mongo"
+)
+
+// driftCategories urutan kategori drift pada laporan dan metrics
+var driftCategories = []string{
+	models.DriftMissingReference,
+	models.DriftOrphanReference,
+	models.DriftStatusMismatch,
+	models.DriftDeletedMismatch,
+}
+
+// ConsistencyService membandingkan koleksi achievements (MongoDB) dengan achievement_references
+// (PostgreSQL) dan, bila diminta, memperbaiki perbedaannya
+type ConsistencyService struct {
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	outboxRepo      *repository.OutboxRepository
+	projector       *AchievementProjector
+}
+
+func NewConsistencyService(mongoDB *mongo.Database, postgresDB *sql.DB) *ConsistencyService {
+	return &ConsistencyService{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(postgresDB),
+		outboxRepo:      repository.NewOutboxRepository(postgresDB),
+		projector:       NewAchievementProjector(mongoDB, postgresDB),
+	}
+}
+
+// ValidSourceOfTruth memeriksa nilai sumber kebenaran untuk repair
+func ValidSourceOfTruth(source string) bool {
+	return source == SourceOfTruthPostgres || source == SourceOfTruthMongo
+}
+
+// DetectDrift membandingkan status kedua store. Achievement yang masih punya event outbox pending
+// dilewati karena perbedaannya sedang menunggu relay, bukan drift.
+func DetectDrift(mongoStates, postgresStates []models.AchievementState, pending map[string]bool) []models.ConsistencyDrift {
+	mongoByID := make(map[string]models.AchievementState, len(mongoStates))
+	for _, state := range mongoStates {
+		mongoByID[state.AchievementID] = state
+	}
+	postgresByID := make(map[string]models.AchievementState, len(postgresStates))
+	for _, state := range postgresStates {
+		postgresByID[state.AchievementID] = state
+	}
+
+	drifts := []models.ConsistencyDrift{}
+	for id, doc := range mongoByID {
+		if pending[id] {
+			continue
+		}
+
+		ref, ok := postgresByID[id]
+		if !ok {
+			// Dokumen yang sudah terhapus tanpa reference tidak terlihat di mana pun
+			if !doc.IsDeleted {
+				drifts = append(drifts, models.ConsistencyDrift{
+					AchievementID: id,
+					Category:      models.DriftMissingReference,
+					MongoStatus:   doc.Status,
+					MongoDeleted:  doc.IsDeleted,
+				})
+			}
+			continue
+		}
+
+		drift := models.ConsistencyDrift{
+			AchievementID:   id,
+			MongoStatus:     doc.Status,
+			PostgresStatus:  ref.Status,
+			MongoDeleted:    doc.IsDeleted,
+			PostgresDeleted: ref.IsDeleted,
+		}
+		switch {
+		case doc.IsDeleted != ref.IsDeleted:
+			drift.Category = models.DriftDeletedMismatch
+			drifts = append(drifts, drift)
+		case doc.Status != ref.Status:
+			drift.Category = models.DriftStatusMismatch
+			drifts = append(drifts, drift)
+		}
+	}
+
+	for id, ref := range postgresByID {
+		if pending[id] || ref.IsDeleted {
+			continue
+		}
+		if _, ok := mongoByID[id]; !ok {
+			drifts = append(drifts, models.ConsistencyDrift{
+				AchievementID:  id,
+				Category:       models.DriftOrphanReference,
+				PostgresStatus: ref.Status,
+			})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].Category != drifts[j].Category {
+			return drifts[i].Category < drifts[j].Category
+		}
+		return drifts[i].AchievementID < drifts[j].AchievementID
+	})
+	return drifts
+}
+
+// RecheckDrift memeriksa ulang drift terhadap keadaan terbaru kedua store sesaat sebelum repair.
+// Event yang di-commit selama scan membuat store terlihat berbeda padahal sedang menunggu relay;
+// nil jika achievement kini punya event outbox pending atau keadaannya sudah berubah sejak scan.
+func RecheckDrift(drift models.ConsistencyDrift, pending bool, mongoState, postgresState *models.AchievementState) *models.ConsistencyDrift {
+	if pending {
+		return nil
+	}
+
+	var mongoStates, postgresStates []models.AchievementState
+	if mongoState != nil {
+		mongoStates = append(mongoStates, *mongoState)
+	}
+	if postgresState != nil {
+		postgresStates = append(postgresStates, *postgresState)
+	}
+
+	current := DetectDrift(mongoStates, postgresStates, nil)
+	if len(current) != 1 || current[0] != drift {
+		return nil
+	}
+	return &current[0]
+}
+
+// Check menjalankan pemeriksaan konsistensi. Jika dryRun false, drift diperbaiki mengikuti sourceOfTruth.
+func (s *ConsistencyService) Check(ctx context.Context, dryRun bool, sourceOfTruth string) (*models.ConsistencyReport, error) {
+	if !ValidSourceOfTruth(sourceOfTruth) {
+		return nil, fmt.Errorf("sumber kebenaran tidak valid: %q (gunakan %s atau %s)", sourceOfTruth, SourceOfTruthPostgres, SourceOfTruthMongo)
+	}
+
+	// Outbox dibaca lebih dulu agar perubahan yang di-commit selama scan tidak terbaca sebagai drift
+	pendingIDs, err := s.outboxRepo.FindPendingAchievementIDs()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[string]bool, len(pendingIDs))
+	for _, id := range pendingIDs {
+		pending[id] = true
+	}
+
+	postgresStates, err := s.referenceRepo.FindAllStates()
+	if err != nil {
+		return nil, err
+	}
+	mongoStates, err := s.achievementRepo.FindAllStates(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ConsistencyReport{
+		DryRun:          dryRun,
+		SourceOfTruth:   sourceOfTruth,
+		ScannedMongo:    len(mongoStates),
+		ScannedPostgres: len(postgresStates),
+		PendingOutbox:   len(pending),
+		Counts:          make(map[string]int, len(driftCategories)),
+		Drifts:          DetectDrift(mongoStates, postgresStates, pending),
+		CheckedAt:       time.Now(),
+	}
+	for _, category := range driftCategories {
+		report.Counts[category] = 0
+	}
+
+	for i := range report.Drifts {
+		drift := &report.Drifts[i]
+		report.Counts[drift.Category]++
+		if dryRun {
+			continue
+		}
+
+		mongoState, postgresState, err := s.recheck(ctx, drift)
+		if err != nil {
+			drift.RepairError = err.Error()
+			report.Unrepairable++
+			continue
+		}
+		if mongoState == nil && postgresState == nil {
+			// Berubah sejak scan: diperiksa lagi pada pemeriksaan berikutnya
+			drift.Skipped = true
+			report.Skipped++
+			continue
+		}
+
+		if err := s.repair(ctx, drift, mongoState, postgresState, sourceOfTruth); err != nil {
+			if err == repository.ErrVersionConflict {
+				drift.Skipped = true
+				report.Skipped++
+				continue
+			}
+			drift.RepairError = err.Error()
+			report.Unrepairable++
+			continue
+		}
+		drift.Repaired = true
+		report.Repaired++
+		s.projector.Refresh(ctx, drift.AchievementID)
+	}
+
+	return report, nil
+}
+
+// recheck membaca ulang outbox dan keadaan kedua store untuk satu drift. Kedua state nil jika drift
+// tidak lagi berlaku (event outbox pending atau keadaan berubah sejak scan).
+func (s *ConsistencyService) recheck(ctx context.Context, drift *models.ConsistencyDrift) (*models.AchievementState, *models.AchievementState, error) {
+	pending, err := s.outboxRepo.HasPending(drift.AchievementID)
+	if err != nil {
+		return nil, nil, err
+	}
+	mongoState, err := s.achievementRepo.FindState(ctx, drift.AchievementID)
+	if err != nil {
+		return nil, nil, err
+	}
+	postgresState, err := s.referenceRepo.FindState(drift.AchievementID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if RecheckDrift(*drift, pending, mongoState, postgresState) == nil {
+		return nil, nil, nil
+	}
+	return mongoState, postgresState, nil
+}
+
+// repair menyamakan store yang bukan sumber kebenaran dengan store sumber kebenaran. Store yang
+// diperbaiki hanya diubah jika masih sama dengan keadaan saat recheck (ErrVersionConflict jika berubah).
+func (s *ConsistencyService) repair(ctx context.Context, drift *models.ConsistencyDrift, doc, ref *models.AchievementState, sourceOfTruth string) error {
+	if sourceOfTruth == SourceOfTruthPostgres {
+		switch drift.Category {
+		case models.DriftMissingReference:
+			// Tanpa reference, achievement tidak pernah tercatat di PostgreSQL: sembunyikan dokumennya
+			return s.achievementRepo.RepairState(ctx, drift.AchievementID, *doc, drift.MongoStatus, true)
+		case models.DriftOrphanReference:
+			return fmt.Errorf("dokumen MongoDB tidak ada, tidak bisa dibuat ulang dari PostgreSQL")
+		default:
+			return s.achievementRepo.RepairState(ctx, drift.AchievementID, *doc, drift.PostgresStatus, drift.PostgresDeleted)
+		}
+	}
+
+	switch drift.Category {
+	case models.DriftMissingReference:
+		studentID, err := uuid.Parse(doc.StudentID)
+		if err != nil {
+			return fmt.Errorf("student_id tidak valid: %v", err)
+		}
+		now := time.Now()
+		return s.referenceRepo.Create(&models.AchievementReferences{
+			ID:                 uuid.New(),
+			StudentID:          studentID,
+			MongoAchievementID: drift.AchievementID,
+			Status:             drift.MongoStatus,
+			CreatedAt:          now,
+			UpdatedAt:          now,
+		})
+	case models.DriftOrphanReference:
+		return s.referenceRepo.SoftDelete(drift.AchievementID)
+	default:
+		return s.referenceRepo.RepairState(drift.AchievementID, *ref, drift.MongoStatus, drift.MongoDeleted)
+	}
+}
+
+// RecordConsistencyMetrics mengekspos hasil pemeriksaan terakhir di endpoint /metrics
+func RecordConsistencyMetrics(report *models.ConsistencyReport) {
+	for _, category := range driftCategories {
+		utils.SetGauge("achievement_consistency_drift", "Jumlah drift MongoDB/PostgreSQL per kategori pada pemeriksaan terakhir",
+			map[string]string{"category": category}, float64(report.Counts[category]))
+	}
+	utils.SetGauge("achievement_consistency_pending_outbox", "Achievement yang dilewati karena event outbox masih pending",
+		nil, float64(report.PendingOutbox))
+	utils.SetGauge("achievement_consistency_repaired", "Drift yang diperbaiki pada pemeriksaan terakhir",
+		nil, float64(report.Repaired))
+	utils.SetGauge("achievement_consistency_skipped", "Drift yang dilewati karena berubah selama pemeriksaan terakhir",
+		nil, float64(report.Skipped))
+	utils.SetGauge("achievement_consistency_unrepairable", "Drift yang gagal diperbaiki pada pemeriksaan terakhir",
+		nil, float64(report.Unrepairable))
+	utils.SetGauge("achievement_consistency_last_run_timestamp_seconds", "Waktu pemeriksaan konsistensi terakhir (unix)",
+		nil, float64(report.CheckedAt.Unix()))
+}
+
+// Metrics mengekspos metrics aplikasi dalam format teks Prometheus.
+// Jika METRICS_TOKEN diisi, request harus menyertakan header Authorization: Bearer <token>.
+func Metrics(c *fiber.Ctx) error {
+	if token := os.Getenv("METRICS_TOKEN"); token != "" {
+		provided := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			return c.Status(401).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Token metrics tidak valid",
+			})
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4")
+	return utils.WriteMetrics(c)
+}
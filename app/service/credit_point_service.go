@@ -0,0 +1,495 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type CreditPointService struct {
+	creditRepo *repository.CreditPointRepository
+	masterRepo *repository.MasterDataRepository
+}
+
+func NewCreditPointService(postgresDB *sql.DB) *CreditPointService {
+	return &CreditPointService{
+		creditRepo: repository.NewCreditPointRepository(postgresDB),
+		masterRepo: repository.NewMasterDataRepository(postgresDB),
+	}
+}
+
+// ResolveCreditRule memilih aturan paling spesifik yang cocok dengan kategori (beserta induknya), level dan peringkat.
+// Kategori persis lebih spesifik dari induknya, induk lebih spesifik dari '*'; lalu level, lalu peringkat.
+// Mengembalikan nil jika tidak ada aturan yang cocok.
+func ResolveCreditRule(rules []models.CreditPointRule, lineage []string, level, rank string) *models.CreditPointRule {
+	var best *models.CreditPointRule
+	bestScore := -1
+
+	for i := range rules {
+		rule := &rules[i]
+
+		categoryScore := -1
+		if rule.Category == "*" {
+			categoryScore = 0
+		} else {
+			for depth, code := range lineage {
+				if rule.Category == code {
+					categoryScore = len(lineage) - depth
+					break
+				}
+			}
+		}
+		if categoryScore < 0 {
+			continue
+		}
+
+		levelScore := 0
+		if rule.Level != "*" {
+			if rule.Level != level {
+				continue
+			}
+			levelScore = 1
+		}
+
+		rankScore := 0
+		if rule.Rank != "*" {
+			if rank == "" || !strings.EqualFold(strings.TrimSpace(rule.Rank), rank) {
+				continue
+			}
+			rankScore = 1
+		}
+
+		score := categoryScore*4 + levelScore*2 + rankScore
+		if score > bestScore {
+			best = rule
+			bestScore = score
+		}
+	}
+
+	return best
+}
+
+// ExtractRank mengambil peringkat dari details.rank (string atau angka), kosong jika tidak ada
+func ExtractRank(details map[string]interface{}) string {
+	value, ok := details["rank"]
+	if !ok || value == nil {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return strings.TrimSpace(fmt.Sprint(v))
+	}
+}
+
+// awardCreditPoints menghitung poin achievement terverifikasi dengan versi aturan aktif
+// dan menyimpannya untuk setiap mahasiswa yang dikreditkan (pemilik dan anggota tim)
+func awardCreditPoints(creditRepo *repository.CreditPointRepository, masterRepo *repository.MasterDataRepository, achievement *models.Achievement) error {
+	version, err := creditRepo.FindActiveVersion()
+	if err != nil {
+		return err
+	}
+	if version == nil {
+		return nil
+	}
+
+	categories, err := masterRepo.FindAllCategories(false)
+	if err != nil {
+		return err
+	}
+
+	lineage := CategoryLineage(categories, achievement.Category)
+	rule := ResolveCreditRule(version.Rules, lineage, achievement.Level, ExtractRank(achievement.Details))
+
+	points := 0.0
+	var ruleID *uuid.UUID
+	if rule != nil {
+		points = rule.Points
+		ruleID = &rule.ID
+	}
+
+	now := time.Now()
+	awards := []models.AchievementCreditPoint{}
+	for _, studentID := range CreditedStudentIDs(achievement) {
+		awards = append(awards, models.AchievementCreditPoint{
+			MongoAchievementID: achievement.AchievementID,
+			StudentID:          studentID,
+			Points:             points,
+			RuleVersion:        version.Version,
+			RuleID:             ruleID,
+			ComputedAt:         now,
+		})
+	}
+
+	return creditRepo.ReplaceAchievementPoints(achievement.AchievementID, awards)
+}
+
+// GetRuleVersions godoc
+// @Summary List credit rule versions
+// @Description List all versions of the credit point rules, newest first. Only one version is active at a time.
+// @Tags Credit Points
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.CreditRuleVersion} "Rule versions retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires credit_points.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve rule versions"
+// @Router /credit-rules [get]
+func (s *CreditPointService) GetRuleVersions(c *fiber.Ctx) error {
+	versions, err := s.creditRepo.FindVersions()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil versi aturan poin",
+		})
+	}
+
+	if versions == nil {
+		versions = []models.CreditRuleVersion{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Versi aturan poin berhasil diambil",
+		"data":    versions,
+	})
+}
+
+// GetRuleVersion godoc
+// @Summary Get credit rule version
+// @Description Get one version of the credit point rules including all its rules.
+// @Tags Credit Points
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Rule version"
+// @Success 200 {object} object{status=string,message=string,data=models.CreditRuleVersion} "Rule version retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid version"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires credit_points.manage)"
+// @Failure 404 {object} map[string]interface{} "Rule version not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve rule version"
+// @Router /credit-rules/{version} [get]
+func (s *CreditPointService) GetRuleVersion(c *fiber.Ctx) error {
+	versionNumber, err := c.ParamsInt("version")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Versi aturan tidak valid",
+		})
+	}
+
+	version, err := s.creditRepo.FindVersion(versionNumber)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil versi aturan poin",
+		})
+	}
+	if version == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Versi aturan poin tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Versi aturan poin berhasil diambil",
+		"data":    version,
+	})
+}
+
+// CreateRuleVersion godoc
+// @Summary Create credit rule version
+// @Description Create a new, inactive version of the credit point rules. Category and level must be master data codes or '*'; rank is matched case-insensitively against details.rank or '*'. Activate it separately so points already awarded keep their original version.
+// @Tags Credit Points
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreditRuleVersionRequest true "Rule version"
+// @Success 201 {object} object{status=string,message=string,data=models.CreditRuleVersion} "Rule version created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid rules"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires credit_points.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to create rule version"
+// @Router /credit-rules [post]
+func (s *CreditPointService) CreateRuleVersion(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+
+	var req models.CreditRuleVersionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	if len(req.Rules) == 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Minimal satu aturan poin harus diisi",
+		})
+	}
+
+	rules, err := s.buildRules(req.Rules)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	version := &models.CreditRuleVersion{
+		CreatedAt: time.Now(),
+		Rules:     rules,
+	}
+	if note := strings.TrimSpace(req.Note); note != "" {
+		version.Note = &note
+	}
+	if createdBy, err := uuid.Parse(userID); err == nil {
+		version.CreatedBy = &createdBy
+	}
+
+	if err := s.creditRepo.CreateVersion(version); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan versi aturan poin",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Versi aturan poin berhasil dibuat",
+		"data":    version,
+	})
+}
+
+// ActivateRuleVersion godoc
+// @Summary Activate credit rule version
+// @Description Make a rule version the active one. Only achievements verified afterwards use it; points already awarded keep the version they were computed with.
+// @Tags Credit Points
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param version path int true "Rule version"
+// @Success 200 {object} object{status=string,message=string,data=models.CreditRuleVersion} "Rule version activated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid version"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires credit_points.manage)"
+// @Failure 404 {object} map[string]interface{} "Rule version not found"
+// @Failure 500 {object} map[string]interface{} "Failed to activate rule version"
+// @Router /credit-rules/{version}/activate [post]
+func (s *CreditPointService) ActivateRuleVersion(c *fiber.Ctx) error {
+	versionNumber, err := c.ParamsInt("version")
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Versi aturan tidak valid",
+		})
+	}
+
+	if err := s.creditRepo.ActivateVersion(versionNumber); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Versi aturan poin tidak ditemukan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengaktifkan versi aturan poin",
+		})
+	}
+
+	version, _ := s.creditRepo.FindVersion(versionNumber)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Versi aturan poin berhasil diaktifkan",
+		"data":    version,
+	})
+}
+
+// GetStudentPoints godoc
+// @Summary Get student credit points
+// @Description Total credit points of a student with the points per verified achievement and the rule version used. Access control: admin, lecturer, or the student themselves.
+// @Tags Statistics & Reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Student ID"
+// @Success 200 {object} object{status=string,message=string,data=object{student_id=string,total_points=number,achievements=[]models.AchievementCreditPoint}} "Student points retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied - not owner, admin, or lecturer"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve points"
+// @Router /reports/points/student/{id} [get]
+func (s *CreditPointService) GetStudentPoints(c *fiber.Ctx) error {
+	studentID := c.Params("id")
+
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	// Only admin, lecturer, or the student themselves can view
+	if userID != studentID && roleID != "1" && roleID != "2" {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anda tidak memiliki akses ke data ini",
+		})
+	}
+
+	points, err := s.creditRepo.FindPointsByStudent(studentID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil poin mahasiswa",
+		})
+	}
+
+	total := 0.0
+	for _, point := range points {
+		total += point.Points
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Poin mahasiswa berhasil diambil",
+		"data": fiber.Map{
+			"student_id":   studentID,
+			"total_points": total,
+			"achievements": points,
+		},
+	})
+}
+
+// GetCohortPoints godoc
+// @Summary Get cohort credit points
+// @Description Total credit points per student for one academic year (cohort), optionally limited to a program study, ordered by points. Admin and lecturer only.
+// @Tags Statistics & Reports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param academic_year query string true "Academic year (cohort)"
+// @Param program_study query string false "Program study"
+// @Success 200 {object} object{status=string,message=string,data=object{academic_year=string,program_study=string,total_points=number,students=[]models.StudentPointTotal}} "Cohort points retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Missing academic_year"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Access denied - admin or lecturer only"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve points"
+// @Router /reports/points/cohort [get]
+func (s *CreditPointService) GetCohortPoints(c *fiber.Ctx) error {
+	roleID, _ := c.Locals("role_id").(string)
+	if roleID != "1" && roleID != "2" {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anda tidak memiliki akses ke data ini",
+		})
+	}
+
+	academicYear := strings.TrimSpace(c.Query("academic_year"))
+	programStudy := strings.TrimSpace(c.Query("program_study"))
+	if academicYear == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "academic_year harus diisi",
+		})
+	}
+
+	totals, err := s.creditRepo.SumPointsByCohort(academicYear, programStudy)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil poin angkatan",
+		})
+	}
+
+	cohortTotal := 0.0
+	for _, total := range totals {
+		cohortTotal += total.TotalPoints
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Poin angkatan berhasil diambil",
+		"data": fiber.Map{
+			"academic_year": academicYear,
+			"program_study": programStudy,
+			"total_points":  cohortTotal,
+			"students":      totals,
+		},
+	})
+}
+
+// buildRules memvalidasi aturan terhadap master data dan menormalkan '*' untuk nilai kosong
+func (s *CreditPointService) buildRules(requests []models.CreditPointRuleRequest) ([]models.CreditPointRule, error) {
+	categories, err := s.masterRepo.FindAllCategories(false)
+	if err != nil {
+		return nil, fmt.Errorf("Gagal mengambil master data kategori")
+	}
+	levels, err := s.masterRepo.FindAllLevels(false)
+	if err != nil {
+		return nil, fmt.Errorf("Gagal mengambil master data level")
+	}
+
+	knownCategories := make(map[string]bool)
+	for _, category := range categories {
+		knownCategories[category.Code] = true
+	}
+	knownLevels := make(map[string]bool)
+	for _, level := range levels {
+		knownLevels[level.Code] = true
+	}
+
+	seen := make(map[string]bool)
+	rules := []models.CreditPointRule{}
+	for i, req := range requests {
+		category := wildcardOr(strings.ToLower(strings.TrimSpace(req.Category)))
+		level := wildcardOr(strings.ToLower(strings.TrimSpace(req.Level)))
+		rank := wildcardOr(strings.TrimSpace(req.Rank))
+
+		if category != "*" && !knownCategories[category] {
+			return nil, fmt.Errorf("Aturan #%d: kategori '%s' tidak terdaftar di master data", i+1, category)
+		}
+		if level != "*" && !knownLevels[level] {
+			return nil, fmt.Errorf("Aturan #%d: level '%s' tidak terdaftar di master data", i+1, level)
+		}
+		if req.Points < 0 {
+			return nil, fmt.Errorf("Aturan #%d: poin tidak boleh negatif", i+1)
+		}
+
+		key := category + "|" + level + "|" + strings.ToLower(rank)
+		if seen[key] {
+			return nil, fmt.Errorf("Aturan #%d: kombinasi kategori, level dan peringkat duplikat", i+1)
+		}
+		seen[key] = true
+
+		rules = append(rules, models.CreditPointRule{
+			ID:       uuid.New(),
+			Category: category,
+			Level:    level,
+			Rank:     rank,
+			Points:   req.Points,
+		})
+	}
+
+	return rules, nil
+}
+
+func wildcardOr(value string) string {
+	if value == "" {
+		return "*"
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ETag membentuk entity tag (strong) dari versi resource
+func ETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatchSatisfied true jika header If-Match cocok dengan versi resource.
+// Mendukung daftar tag dipisah koma dan "*"; weak tag (W/) tidak pernah cocok (strong comparison, RFC 9110).
+func IfMatchSatisfied(header string, version int) bool {
+	current := ETag(version)
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+	return false
+}
+
+// requireIfMatch mewajibkan header If-Match yang cocok dengan versi saat ini:
+// 428 jika header tidak dikirim, 412 jika resource sudah berubah
+func requireIfMatch(c *fiber.Ctx, version int) func() error {
+	header := c.Get(fiber.HeaderIfMatch)
+	if header == "" {
+		return func() error {
+			return c.Status(428).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Header If-Match wajib dikirim (gunakan ETag dari response GET)",
+			})
+		}
+	}
+
+	if !IfMatchSatisfied(header, version) {
+		return func() error {
+			return versionConflict(c, version)
+		}
+	}
+
+	return nil
+}
+
+// versionConflict respons 412 ketika resource sudah diubah pihak lain; ETag versi terbaru ikut dikirim
+func versionConflict(c *fiber.Ctx, version int) error {
+	if version > 0 {
+		c.Set(fiber.HeaderETag, ETag(version))
+	}
+	return c.Status(412).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Data sudah diubah oleh pengguna lain. Muat ulang data lalu coba lagi",
+	})
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Baris CSV di-flush ke client setiap sekian baris
+const exportFlushInterval = 500
+
+// Jumlah baris yang diambil dari database per halaman saat export
+const exportPageSize = 500
+
+// Batas baris satu sheet XLSX (termasuk header)
+const maxXLSXRows = 1048576
+
+// ExportTable sumber data export. Rows memanggil emit untuk setiap baris secara berurutan dan harus
+// berhenti jika emit mengembalikan error; data dibaca bertahap sehingga tidak perlu dimuat sekaligus.
+// Nilai sel boleh berupa string, bilangan, bool, time.Time, *time.Time atau nil.
+type ExportTable struct {
+	Name   string // dipakai untuk nama file dan nama sheet
+	Header []string
+	Rows   func(ctx context.Context, emit func(values []interface{}) error) error
+}
+
+// ParseExportFormat memvalidasi parameter format (csv atau xlsx, default csv)
+func ParseExportFormat(value string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", models.ExportFormatCSV:
+		return models.ExportFormatCSV, true
+	case models.ExportFormatXLSX:
+		return models.ExportFormatXLSX, true
+	}
+	return "", false
+}
+
+// ExportContentType MIME type file export
+func ExportContentType(format string) string {
+	if format == models.ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportFilename nama file export, mis. achievements-20250817-1504.csv
+func ExportFilename(name, format string, now time.Time) string {
+	return fmt.Sprintf("%s-%s.%s", name, now.Format("20060102-1504"), format)
+}
+
+// WriteExport menulis tabel ke w dalam format csv atau xlsx dan mengembalikan jumlah baris data
+func WriteExport(ctx context.Context, w io.Writer, format string, table ExportTable) (int, error) {
+	if format == models.ExportFormatXLSX {
+		return writeExportXLSX(ctx, w, table)
+	}
+	return writeExportCSV(ctx, w, table)
+}
+
+func writeExportCSV(ctx context.Context, w io.Writer, table ExportTable) (int, error) {
+	// BOM agar Excel membaca file sebagai UTF-8
+	if _, err := w.Write([]byte("\xef\xbb\xbf")); err != nil {
+		return 0, err
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(table.Header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err := table.Rows(ctx, func(values []interface{}) error {
+		record := make([]string, len(values))
+		for i, value := range values {
+			record[i] = exportCSVValue(value)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushInterval == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// StatisticsExportRows mengubah hasil statistik menjadi baris export (Bagian, Item, Jumlah, Persentase, Poin).
+// Kategori dan level diurutkan dari jumlah terbanyak dengan label dari master data, periode dari yang terlama.
+func StatisticsExportRows(stats map[string]interface{}, categories, levels map[string]string, topStudents []models.TopStudent) [][]interface{} {
+	total, _ := stats["total_achievements"].(int)
+	count := func(key string) int {
+		value, _ := stats[key].(int)
+		return value
+	}
+
+	rows := [][]interface{}{
+		{"Ringkasan", "Total achievement", total, nil, nil},
+		{"Ringkasan", "Terverifikasi", count("total_verified"), nil, nil},
+		{"Ringkasan", "Menunggu verifikasi", count("total_pending"), nil, nil},
+		{"Ringkasan", "Ditolak", count("total_rejected"), nil, nil},
+		{"Ringkasan", "Draft", count("total_draft"), nil, nil},
+	}
+
+	grouped := func(section string, counts map[string]int, labels map[string]string) {
+		type entry struct {
+			label string
+			count int
+		}
+		entries := make([]entry, 0, len(counts))
+		for code, value := range counts {
+			label := code
+			if name, ok := labels[code]; ok && name != "" {
+				label = name
+			}
+			entries = append(entries, entry{label, value})
+		}
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].count != entries[j].count {
+				return entries[i].count > entries[j].count
+			}
+			return entries[i].label < entries[j].label
+		})
+
+		for _, e := range entries {
+			percentage := 0.0
+			if total > 0 {
+				percentage = math.Round(float64(e.count)/float64(total)*10000) / 100
+			}
+			rows = append(rows, []interface{}{section, e.label, e.count, percentage, nil})
+		}
+	}
+	categoryCount, _ := stats["category_count"].(map[string]int)
+	grouped("Kategori", categoryCount, categories)
+	levelCount, _ := stats["level_count"].(map[string]int)
+	grouped("Level", levelCount, levels)
+
+	periodCount, _ := stats["period_count"].(map[string]int)
+	periods := make([]string, 0, len(periodCount))
+	for period := range periodCount {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+	for _, period := range periods {
+		rows = append(rows, []interface{}{"Periode", period, periodCount[period], nil, nil})
+	}
+
+	for _, student := range topStudents {
+		rows = append(rows, []interface{}{"Top Mahasiswa", student.StudentName, student.TotalAchievements, nil, student.TotalPoints})
+	}
+
+	return rows
+}
+
+// exportCSVValue mengubah nilai sel menjadi teks. Teks yang diawali karakter formula diberi
+// tanda kutip tunggal agar tidak dieksekusi saat dibuka di spreadsheet.
+func exportCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			return "'" + v
+		}
+		return v
+	case time.Time:
+		return formatExportTime(v)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return formatExportTime(*v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatExportTime tanggal saja jika jamnya 00:00, selain itu tanggal dan jam
+func formatExportTime(t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func writeExportXLSX(ctx context.Context, w io.Writer, table ExportTable) (int, error) {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := exportSheetName(table.Name)
+	if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+		return 0, err
+	}
+
+	// StreamWriter menulis baris ke file sementara, bukan ke memori
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return 0, err
+	}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return 0, err
+	}
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: stringPtr("yyyy-mm-dd")})
+	if err != nil {
+		return 0, err
+	}
+	dateTimeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: stringPtr("yyyy-mm-dd hh:mm:ss")})
+	if err != nil {
+		return 0, err
+	}
+
+	// Header dibekukan; StreamWriter mengharuskan panes diatur sebelum baris pertama
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return 0, err
+	}
+
+	header := make([]interface{}, len(table.Header))
+	for i, title := range table.Header {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: title}
+	}
+	if err := stream.SetRow("A1", header); err != nil {
+		return 0, err
+	}
+
+	count := 0
+	err = table.Rows(ctx, func(values []interface{}) error {
+		if count+2 > maxXLSXRows {
+			return fmt.Errorf("data melebihi batas %d baris XLSX, gunakan format csv", maxXLSXRows-1)
+		}
+
+		cells := make([]interface{}, len(values))
+		for i, value := range values {
+			if ptr, ok := value.(*time.Time); ok {
+				if ptr == nil {
+					value = nil
+				} else {
+					value = *ptr
+				}
+			}
+			if t, ok := value.(time.Time); ok {
+				style := dateTimeStyle
+				if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+					style = dateStyle
+				}
+				cells[i] = excelize.Cell{StyleID: style, Value: t}
+				continue
+			}
+			cells[i] = value
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, count+2)
+		if err != nil {
+			return err
+		}
+		if err := stream.SetRow(cell, cells); err != nil {
+			return err
+		}
+
+		count++
+		if count%exportFlushInterval == 0 {
+			return ctx.Err()
+		}
+		return nil
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if err := stream.Flush(); err != nil {
+		return count, err
+	}
+	return count, file.Write(w)
+}
+
+// exportSheetName nama sheet XLSX (maksimal 31 karakter, tanpa karakter terlarang)
+func exportSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if len(name) > 31 {
+		name = name[:31]
+	}
+	if name == "" {
+		name = "Export"
+	}
+	return name
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"database/sql"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ExportService mengirim export CSV/XLSX: di-stream langsung ke client, atau untuk data besar
+// dibuat sebagai job background yang filenya diunduh setelah selesai
+type ExportService struct {
+	exportRepo     *repository.ExportRepository
+	dir            string
+	asyncThreshold int64
+	ttl            time.Duration
+}
+
+func NewExportService(db *sql.DB) *ExportService {
+	dir := os.Getenv("EXPORT_DIR")
+	if dir == "" {
+		dir = "./exports"
+	}
+
+	return &ExportService{
+		exportRepo:     repository.NewExportRepository(db),
+		dir:            dir,
+		asyncThreshold: int64(utils.GetEnvInt("EXPORT_ASYNC_THRESHOLD", 5000)),
+		ttl:            time.Duration(utils.GetEnvInt("EXPORT_TTL_HOURS", 24)) * time.Hour,
+	}
+}
+
+// Send mengirim tabel sesuai query format (csv/xlsx). Jika estimatedRows melebihi EXPORT_ASYNC_THRESHOLD
+// atau async=true, export dibuat di background dan response 202 berisi job-nya.
+// table.Rows dijalankan setelah handler selesai, sehingga tidak boleh memakai *fiber.Ctx.
+func (s *ExportService) Send(c *fiber.Ctx, kind string, table ExportTable, estimatedRows int64) error {
+	format, ok := ParseExportFormat(c.Query("format"))
+	if !ok {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Format export harus csv atau xlsx",
+		})
+	}
+
+	if c.QueryBool("async", false) || estimatedRows > s.asyncThreshold {
+		return s.startJob(c, kind, format, table)
+	}
+
+	filename := ExportFilename(table.Name, format, time.Now())
+	c.Set(fiber.HeaderContentType, ExportContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	// Status dan header sudah terkirim saat baris mulai ditulis; kegagalan di tengah hanya bisa dicatat
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if _, err := WriteExport(context.Background(), w, format, table); err != nil {
+			log.Printf("Export %s terhenti: %v", filename, err)
+		}
+	})
+	return nil
+}
+
+func (s *ExportService) startJob(c *fiber.Ctx, kind, format string, table ExportTable) error {
+	userID, _ := c.Locals("user_id").(string)
+	owner, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+
+	now := time.Now()
+	job := &models.ExportJob{
+		ID:        uuid.New(),
+		UserID:    owner,
+		Kind:      kind,
+		Format:    format,
+		Status:    models.ExportJobPending,
+		Filename:  ExportFilename(table.Name, format, now),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+
+	if err := s.exportRepo.Create(job); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membuat job export",
+		})
+	}
+
+	go s.run(*job, table)
+
+	return c.Status(202).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Export sedang diproses. Cek status export untuk link download",
+		"data":    job,
+	})
+}
+
+// run menulis export ke file di EXPORT_DIR lalu menyimpan hasilnya
+func (s *ExportService) run(job models.ExportJob, table ExportTable) {
+	id := job.ID.String()
+	fail := func(err error) {
+		log.Printf("Export %s gagal: %v", id, err)
+		if err := s.exportRepo.MarkFailed(id, "Gagal membuat file export"); err != nil {
+			log.Printf("Gagal menyimpan status export %s: %v", id, err)
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			fail(fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		fail(err)
+		return
+	}
+	path := filepath.Join(s.dir, id+"."+job.Format)
+	if err := s.exportRepo.MarkRunning(id, path); err != nil {
+		log.Printf("Gagal mengubah status export %s: %v", id, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	writer := bufio.NewWriter(file)
+	count, err := WriteExport(context.Background(), writer, job.Format, table)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		fail(err)
+		return
+	}
+
+	var size int64
+	if info, err := os.Stat(path); err == nil {
+		size = info.Size()
+	}
+	if err := s.exportRepo.MarkCompleted(id, count, size); err != nil {
+		log.Printf("Gagal menyimpan status export %s: %v", id, err)
+	}
+}
+
+// findOwnedJob mencari job export milik user yang login (admin boleh melihat semua);
+// mengembalikan fungsi response error jika tidak ditemukan atau bukan miliknya
+func (s *ExportService) findOwnedJob(c *fiber.Ctx) (*models.ExportJob, func() error) {
+	job, err := s.exportRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return nil, func() error {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengambil data export",
+			})
+		}
+	}
+
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+	if job == nil || (job.UserID.String() != userID && roleID != "1") {
+		return nil, func() error {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Export tidak ditemukan",
+			})
+		}
+	}
+
+	return job, nil
+}
+
+// GetExportJob godoc
+// @Summary Get export job status
+// @Description Poll a background export started by one of the export endpoints. When the status is completed, download_url points to the file until expires_at. Only the user who started the export (or an admin) can see it.
+// @Tags Exports
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 200 {object} object{status=string,message=string,data=models.ExportJob} "Export job retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Export not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve export"
+// @Router /exports/{id} [get]
+func (s *ExportService) GetExportJob(c *fiber.Ctx) error {
+	job, errResponse := s.findOwnedJob(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	if job.Status == models.ExportJobCompleted && time.Now().Before(job.ExpiresAt) {
+		job.DownloadURL = fmt.Sprintf("/api/v1/exports/%s/download", job.ID)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data export berhasil diambil",
+		"data":    job,
+	})
+}
+
+// DownloadExport godoc
+// @Summary Download export file
+// @Description Download the file of a completed background export. Only the user who started the export (or an admin) can download it.
+// @Tags Exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Export job ID"
+// @Success 200 {file} file "CSV or XLSX file"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Export not found"
+// @Failure 409 {object} map[string]interface{} "Export is not completed yet"
+// @Failure 410 {object} map[string]interface{} "Export file has expired"
+// @Router /exports/{id}/download [get]
+func (s *ExportService) DownloadExport(c *fiber.Ctx) error {
+	job, errResponse := s.findOwnedJob(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	if job.Status != models.ExportJobCompleted {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("Export belum selesai (status: %s)", job.Status),
+		})
+	}
+	if !time.Now().Before(job.ExpiresAt) {
+		return c.Status(410).JSON(fiber.Map{
+			"status":  "error",
+			"message": "File export sudah kedaluwarsa, silakan buat export baru",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, ExportContentType(job.Format))
+	return c.Download(job.FilePath, job.Filename)
+}
+
+// PurgeExpired menghapus file dan data job export yang sudah kedaluwarsa
+func (s *ExportService) PurgeExpired() (int, error) {
+	jobs, err := s.exportRepo.FindExpired(time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, job := range jobs {
+		if job.FilePath != "" {
+			if err := os.Remove(job.FilePath); err != nil && !os.IsNotExist(err) {
+				log.Printf("Export: gagal menghapus file %s: %v", job.FilePath, err)
+				continue
+			}
+		}
+		if err := s.exportRepo.Delete(job.ID.String()); err != nil {
+			log.Printf("Export: gagal menghapus job %s: %v", job.ID, err)
+			continue
+		}
+		purged++
+	}
+
+	return purged, nil
+}
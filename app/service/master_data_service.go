@@ -0,0 +1,707 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var masterCodePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+type MasterDataService struct {
+	masterRepo      *repository.MasterDataRepository
+	achievementRepo *repository.AchievementRepository
+	projector       *AchievementProjector
+}
+
+func NewMasterDataService(mongoDB *mongo.Database, postgresDB *sql.DB) *MasterDataService {
+	return &MasterDataService{
+		masterRepo:      repository.NewMasterDataRepository(postgresDB),
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		projector:       NewAchievementProjector(mongoDB, postgresDB),
+	}
+}
+
+// matchesMasterValue mencocokkan nilai bebas dengan code, label (ID/EN) atau alias (case-insensitive)
+func matchesMasterValue(value, code, labelID, labelEN string, aliases []string) bool {
+	value = strings.TrimSpace(value)
+	if strings.EqualFold(value, code) || strings.EqualFold(value, labelID) || strings.EqualFold(value, labelEN) {
+		return true
+	}
+	for _, alias := range aliases {
+		if strings.EqualFold(value, alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveCategoryCode mengubah nilai kategori bebas (code, label atau alias) menjadi code kanonik
+func ResolveCategoryCode(categories []models.AchievementCategory, value string) (string, bool) {
+	for _, category := range categories {
+		if matchesMasterValue(value, category.Code, category.LabelID, category.LabelEN, category.Aliases) {
+			return category.Code, true
+		}
+	}
+	return "", false
+}
+
+// ResolveLevelCode mengubah nilai level bebas (code, label atau alias) menjadi code kanonik
+func ResolveLevelCode(levels []models.AchievementLevel, value string) (string, bool) {
+	for _, level := range levels {
+		if matchesMasterValue(value, level.Code, level.LabelID, level.LabelEN, level.Aliases) {
+			return level.Code, true
+		}
+	}
+	return "", false
+}
+
+// CategoryLineage mengembalikan code kategori beserta semua induknya, mulai dari kategori itu sendiri
+func CategoryLineage(categories []models.AchievementCategory, code string) []string {
+	parents := make(map[string]*string)
+	for _, category := range categories {
+		parents[category.Code] = category.ParentCode
+	}
+
+	lineage := []string{code}
+	seen := map[string]bool{code: true}
+	for current := code; parents[current] != nil; {
+		current = *parents[current]
+		if seen[current] {
+			break
+		}
+		seen[current] = true
+		lineage = append(lineage, current)
+	}
+
+	return lineage
+}
+
+// BuildCategoryTree menyusun kategori datar menjadi pohon (urutan input dipertahankan)
+func BuildCategoryTree(categories []models.AchievementCategory) []models.AchievementCategoryTree {
+	exists := make(map[string]bool)
+	for _, category := range categories {
+		exists[category.Code] = true
+	}
+
+	children := make(map[string][]models.AchievementCategory)
+	roots := []models.AchievementCategory{}
+	for _, category := range categories {
+		if category.ParentCode != nil && exists[*category.ParentCode] {
+			children[*category.ParentCode] = append(children[*category.ParentCode], category)
+			continue
+		}
+		roots = append(roots, category)
+	}
+
+	var build func(category models.AchievementCategory) models.AchievementCategoryTree
+	build = func(category models.AchievementCategory) models.AchievementCategoryTree {
+		node := models.AchievementCategoryTree{
+			AchievementCategory: category,
+			Children:            []models.AchievementCategoryTree{},
+		}
+		for _, child := range children[category.Code] {
+			node.Children = append(node.Children, build(child))
+		}
+		return node
+	}
+
+	tree := []models.AchievementCategoryTree{}
+	for _, root := range roots {
+		tree = append(tree, build(root))
+	}
+
+	return tree
+}
+
+// isUniqueViolation true jika error PostgreSQL adalah pelanggaran unique/primary key
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
+
+// GetCategories godoc
+// @Summary Get achievement categories
+// @Description Get master data of achievement categories with bilingual labels. Use format=tree for the hierarchy and include_inactive=true to include deactivated categories.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param format query string false "Response format: flat (default) or tree"
+// @Param include_inactive query bool false "Include deactivated categories"
+// @Success 200 {object} object{status=string,message=string,data=[]models.AchievementCategory} "Categories retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve categories"
+// @Router /master-data/categories [get]
+func (s *MasterDataService) GetCategories(c *fiber.Ctx) error {
+	categories, err := s.masterRepo.FindAllCategories(!c.QueryBool("include_inactive", false))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data kategori",
+		})
+	}
+
+	if categories == nil {
+		categories = []models.AchievementCategory{}
+	}
+
+	var data interface{} = categories
+	if c.Query("format") == "tree" {
+		data = BuildCategoryTree(categories)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data kategori berhasil diambil",
+		"data":    data,
+	})
+}
+
+// CreateCategory godoc
+// @Summary Create achievement category
+// @Description Create a category (optionally as sub-category of parent_code). Code must be lowercase letters, digits, '-' or '_'.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AchievementCategoryRequest true "Category data"
+// @Success 201 {object} object{status=string,message=string,data=models.AchievementCategory} "Category created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request or unknown parent"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 409 {object} map[string]interface{} "Category code already exists"
+// @Failure 500 {object} map[string]interface{} "Failed to create category"
+// @Router /master-data/categories [post]
+func (s *MasterDataService) CreateCategory(c *fiber.Ctx) error {
+	var req models.AchievementCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	if !masterCodePattern.MatchString(req.Code) {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Code harus berisi huruf kecil, angka, '-' atau '_'",
+		})
+	}
+
+	category := &models.AchievementCategory{
+		Code:      req.Code,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if errResp := s.applyCategoryRequest(c, category, &req); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.masterRepo.CreateCategory(category); err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Code kategori sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan kategori",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kategori berhasil dibuat",
+		"data":    category,
+	})
+}
+
+// UpdateCategory godoc
+// @Summary Update achievement category
+// @Description Update labels, aliases, parent, sort order or active flag of a category. The code itself cannot change.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Category code"
+// @Param request body models.AchievementCategoryRequest true "Category data"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementCategory} "Category updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request, unknown parent or cyclic hierarchy"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 404 {object} map[string]interface{} "Category not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update category"
+// @Router /master-data/categories/{code} [put]
+func (s *MasterDataService) UpdateCategory(c *fiber.Ctx) error {
+	category, err := s.masterRepo.FindCategoryByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data kategori",
+		})
+	}
+	if category == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kategori tidak ditemukan",
+		})
+	}
+
+	var req models.AchievementCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	category.UpdatedAt = time.Now()
+	if errResp := s.applyCategoryRequest(c, category, &req); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.masterRepo.UpdateCategory(category); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate kategori",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kategori berhasil diupdate",
+		"data":    category,
+	})
+}
+
+// DeleteCategory godoc
+// @Summary Deactivate achievement category
+// @Description Deactivate a category so it can no longer be used for new or updated achievements. Existing achievements keep the code. Categories with active sub-categories cannot be deactivated.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Category code"
+// @Success 200 {object} object{status=string,message=string} "Category deactivated successfully"
+// @Failure 400 {object} map[string]interface{} "Category still has active sub-categories"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 404 {object} map[string]interface{} "Category not found or already inactive"
+// @Failure 500 {object} map[string]interface{} "Failed to deactivate category"
+// @Router /master-data/categories/{code} [delete]
+func (s *MasterDataService) DeleteCategory(c *fiber.Ctx) error {
+	code := c.Params("code")
+
+	children, err := s.masterRepo.CountActiveChildren(code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengecek sub-kategori",
+		})
+	}
+	if children > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kategori masih memiliki sub-kategori aktif",
+		})
+	}
+
+	deactivated, err := s.masterRepo.DeactivateCategory(code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menonaktifkan kategori",
+		})
+	}
+	if !deactivated {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kategori tidak ditemukan atau sudah nonaktif",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kategori berhasil dinonaktifkan",
+	})
+}
+
+// GetLevels godoc
+// @Summary Get achievement levels
+// @Description Get master data of achievement levels ordered by rank (1 = lowest) with bilingual labels.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_inactive query bool false "Include deactivated levels"
+// @Success 200 {object} object{status=string,message=string,data=[]models.AchievementLevel} "Levels retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve levels"
+// @Router /master-data/levels [get]
+func (s *MasterDataService) GetLevels(c *fiber.Ctx) error {
+	levels, err := s.masterRepo.FindAllLevels(!c.QueryBool("include_inactive", false))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data level",
+		})
+	}
+
+	if levels == nil {
+		levels = []models.AchievementLevel{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data level berhasil diambil",
+		"data":    levels,
+	})
+}
+
+// CreateLevel godoc
+// @Summary Create achievement level
+// @Description Create a level with a unique rank (1 = lowest). Code must be lowercase letters, digits, '-' or '_'.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.AchievementLevelRequest true "Level data"
+// @Success 201 {object} object{status=string,message=string,data=models.AchievementLevel} "Level created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 409 {object} map[string]interface{} "Level code or rank already exists"
+// @Failure 500 {object} map[string]interface{} "Failed to create level"
+// @Router /master-data/levels [post]
+func (s *MasterDataService) CreateLevel(c *fiber.Ctx) error {
+	var req models.AchievementLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Code = strings.ToLower(strings.TrimSpace(req.Code))
+	if !masterCodePattern.MatchString(req.Code) {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Code harus berisi huruf kecil, angka, '-' atau '_'",
+		})
+	}
+
+	level := &models.AchievementLevel{
+		Code:      req.Code,
+		IsActive:  true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if errResp := applyLevelRequest(c, level, &req); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.masterRepo.CreateLevel(level); err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Code atau rank level sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan level",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Level berhasil dibuat",
+		"data":    level,
+	})
+}
+
+// UpdateLevel godoc
+// @Summary Update achievement level
+// @Description Update rank, labels, aliases or active flag of a level. The code itself cannot change.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Level code"
+// @Param request body models.AchievementLevelRequest true "Level data"
+// @Success 200 {object} object{status=string,message=string,data=models.AchievementLevel} "Level updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 404 {object} map[string]interface{} "Level not found"
+// @Failure 409 {object} map[string]interface{} "Rank already used by another level"
+// @Failure 500 {object} map[string]interface{} "Failed to update level"
+// @Router /master-data/levels/{code} [put]
+func (s *MasterDataService) UpdateLevel(c *fiber.Ctx) error {
+	level, err := s.masterRepo.FindLevelByCode(c.Params("code"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data level",
+		})
+	}
+	if level == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Level tidak ditemukan",
+		})
+	}
+
+	var req models.AchievementLevelRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	level.UpdatedAt = time.Now()
+	if errResp := applyLevelRequest(c, level, &req); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.masterRepo.UpdateLevel(level); err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Rank level sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate level",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Level berhasil diupdate",
+		"data":    level,
+	})
+}
+
+// DeleteLevel godoc
+// @Summary Deactivate achievement level
+// @Description Deactivate a level so it can no longer be used for new or updated achievements. Existing achievements keep the code.
+// @Tags Master Data
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Level code"
+// @Success 200 {object} object{status=string,message=string} "Level deactivated successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires master_data.manage)"
+// @Failure 404 {object} map[string]interface{} "Level not found or already inactive"
+// @Failure 500 {object} map[string]interface{} "Failed to deactivate level"
+// @Router /master-data/levels/{code} [delete]
+func (s *MasterDataService) DeleteLevel(c *fiber.Ctx) error {
+	deactivated, err := s.masterRepo.DeactivateLevel(c.Params("code"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menonaktifkan level",
+		})
+	}
+	if !deactivated {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Level tidak ditemukan atau sudah nonaktif",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Level berhasil dinonaktifkan",
+	})
+}
+
+// NormalizeAchievements mengubah category/level achievement lama di MongoDB menjadi code kanonik.
+// Dipakai oleh command sekali jalan cmd/normalize-master-data; dryRun hanya menghitung tanpa menulis.
+func (s *MasterDataService) NormalizeAchievements(ctx context.Context, dryRun bool) (*models.MasterDataNormalizationReport, error) {
+	// Nilai lama boleh merujuk entri yang sudah nonaktif
+	categories, err := s.masterRepo.FindAllCategories(false)
+	if err != nil {
+		return nil, err
+	}
+	levels, err := s.masterRepo.FindAllLevels(false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Termasuk achievement yang sudah di-soft delete
+	achievements, err := s.achievementRepo.FindAll(ctx, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MasterDataNormalizationReport{
+		DryRun:              dryRun,
+		UnmatchedCategories: map[string]int{},
+		UnmatchedLevels:     map[string]int{},
+	}
+
+	for _, achievement := range achievements {
+		report.Scanned++
+
+		category := achievement.Category
+		if code, ok := ResolveCategoryCode(categories, achievement.Category); ok {
+			category = code
+		} else {
+			report.UnmatchedCategories[achievement.Category]++
+		}
+
+		level := achievement.Level
+		if code, ok := ResolveLevelCode(levels, achievement.Level); ok {
+			level = code
+		} else {
+			report.UnmatchedLevels[achievement.Level]++
+		}
+
+		if category == achievement.Category && level == achievement.Level {
+			report.AlreadyCanonical++
+			continue
+		}
+
+		if !dryRun {
+			if err := s.achievementRepo.UpdateMasterData(ctx, achievement.AchievementID, category, level); err != nil {
+				return report, err
+			}
+			s.projector.Refresh(ctx, achievement.AchievementID)
+		}
+		report.Updated++
+	}
+
+	return report, nil
+}
+
+// applyCategoryRequest memvalidasi request lalu mengisi field kategori (selain code)
+func (s *MasterDataService) applyCategoryRequest(c *fiber.Ctx, category *models.AchievementCategory, req *models.AchievementCategoryRequest) func() error {
+	if strings.TrimSpace(req.LabelID) == "" || strings.TrimSpace(req.LabelEN) == "" {
+		return func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "label_id dan label_en harus diisi",
+			})
+		}
+	}
+
+	if req.ParentCode != nil && *req.ParentCode == "" {
+		req.ParentCode = nil
+	}
+
+	if req.ParentCode != nil {
+		categories, err := s.masterRepo.FindAllCategories(false)
+		if err != nil {
+			return func() error {
+				return c.Status(500).JSON(fiber.Map{
+					"status":  "error",
+					"message": "Gagal mengambil data kategori",
+				})
+			}
+		}
+
+		parentExists := false
+		for _, existing := range categories {
+			if existing.Code == *req.ParentCode {
+				parentExists = true
+				break
+			}
+		}
+		if !parentExists {
+			return func() error {
+				return c.Status(400).JSON(fiber.Map{
+					"status":  "error",
+					"message": "Kategori induk tidak ditemukan",
+				})
+			}
+		}
+
+		// Induk tidak boleh kategori itu sendiri atau turunannya
+		for _, ancestor := range CategoryLineage(categories, *req.ParentCode) {
+			if ancestor == category.Code {
+				return func() error {
+					return c.Status(400).JSON(fiber.Map{
+						"status":  "error",
+						"message": "Hierarki kategori tidak boleh melingkar",
+					})
+				}
+			}
+		}
+	}
+
+	category.ParentCode = req.ParentCode
+	category.LabelID = strings.TrimSpace(req.LabelID)
+	category.LabelEN = strings.TrimSpace(req.LabelEN)
+	category.Aliases = normalizeAliases(req.Aliases)
+	category.SortOrder = req.SortOrder
+	if req.IsActive != nil {
+		category.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// applyLevelRequest memvalidasi request lalu mengisi field level (selain code)
+func applyLevelRequest(c *fiber.Ctx, level *models.AchievementLevel, req *models.AchievementLevelRequest) func() error {
+	if strings.TrimSpace(req.LabelID) == "" || strings.TrimSpace(req.LabelEN) == "" {
+		return func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "label_id dan label_en harus diisi",
+			})
+		}
+	}
+
+	if req.Rank < 1 {
+		return func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Rank harus >= 1",
+			})
+		}
+	}
+
+	level.Rank = req.Rank
+	level.LabelID = strings.TrimSpace(req.LabelID)
+	level.LabelEN = strings.TrimSpace(req.LabelEN)
+	level.Aliases = normalizeAliases(req.Aliases)
+	if req.IsActive != nil {
+		level.IsActive = *req.IsActive
+	}
+
+	return nil
+}
+
+// normalizeAliases membuang alias kosong/duplikat (case-insensitive)
+func normalizeAliases(aliases []string) []string {
+	seen := make(map[string]bool)
+	result := []string{}
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		result = append(result, alias)
+	}
+	return result
+}
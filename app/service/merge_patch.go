@@ -0,0 +1,260 @@
+package service
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MergePatch dokumen JSON Merge Patch (RFC 7396): field yang tidak ada berarti tidak berubah,
+// null berarti dikosongkan. Setiap field divalidasi ketat dan kesalahannya dikumpulkan per field.
+type MergePatch struct {
+	fields map[string]json.RawMessage
+	errors []models.FieldError
+}
+
+// ParseMergePatch membaca body merge patch; body harus berupa JSON object
+func ParseMergePatch(body []byte) (*MergePatch, error) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return nil, errors.New("merge patch harus berupa JSON object")
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(trimmed, &fields); err != nil {
+		return nil, err
+	}
+
+	return &MergePatch{fields: fields}, nil
+}
+
+// Has true jika field ada di patch (termasuk bernilai null)
+func (p *MergePatch) Has(field string) bool {
+	_, ok := p.fields[field]
+	return ok
+}
+
+func (p *MergePatch) isNull(field string) bool {
+	return bytes.Equal(bytes.TrimSpace(p.fields[field]), []byte("null"))
+}
+
+// AddError mencatat kesalahan validasi untuk field
+func (p *MergePatch) AddError(field, message string) {
+	p.errors = append(p.errors, models.FieldError{Field: field, Message: message})
+}
+
+// Errors mengembalikan semua kesalahan validasi yang terkumpul
+func (p *MergePatch) Errors() []models.FieldError {
+	return p.errors
+}
+
+// Allow menolak field di luar daftar yang boleh diubah (diurutkan agar pesan stabil)
+func (p *MergePatch) Allow(fields ...string) {
+	allowed := make(map[string]bool)
+	for _, field := range fields {
+		allowed[field] = true
+	}
+
+	unknown := []string{}
+	for field := range p.fields {
+		if !allowed[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, field := range unknown {
+		p.AddError(field, "Field tidak dikenal atau tidak dapat diubah")
+	}
+}
+
+// String menerapkan field string. Field wajib tidak boleh null atau kosong;
+// field opsional dikosongkan dengan null atau "". Mengembalikan true jika field ada dan valid.
+func (p *MergePatch) String(field string, target *string, required bool) bool {
+	if !p.Has(field) {
+		return false
+	}
+
+	if p.isNull(field) {
+		if required {
+			p.AddError(field, "Field wajib diisi dan tidak boleh null")
+			return false
+		}
+		*target = ""
+		return true
+	}
+
+	var value string
+	if err := json.Unmarshal(p.fields[field], &value); err != nil {
+		p.AddError(field, "Harus berupa string")
+		return false
+	}
+
+	value = strings.TrimSpace(value)
+	if required && value == "" {
+		p.AddError(field, "Field wajib diisi dan tidak boleh kosong")
+		return false
+	}
+
+	*target = value
+	return true
+}
+
+// Bool menerapkan field boolean (tidak boleh null)
+func (p *MergePatch) Bool(field string, target *bool) bool {
+	if !p.Has(field) {
+		return false
+	}
+
+	var value bool
+	if p.isNull(field) || json.Unmarshal(p.fields[field], &value) != nil {
+		p.AddError(field, "Harus berupa boolean")
+		return false
+	}
+
+	*target = value
+	return true
+}
+
+// StringList menerapkan field array string; null mengosongkan daftar
+func (p *MergePatch) StringList(field string, target *[]string) bool {
+	if !p.Has(field) {
+		return false
+	}
+
+	if p.isNull(field) {
+		*target = nil
+		return true
+	}
+
+	var values []string
+	if err := json.Unmarshal(p.fields[field], &values); err != nil {
+		p.AddError(field, "Harus berupa array string atau null")
+		return false
+	}
+
+	*target = values
+	return true
+}
+
+// Date menerapkan field tanggal berformat YYYY-MM-DD (tidak boleh null)
+func (p *MergePatch) Date(field string, target *time.Time) bool {
+	var raw string
+	if !p.String(field, &raw, true) {
+		return false
+	}
+
+	date, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		p.AddError(field, fmt.Sprintf("Tanggal '%s' tidak valid. Gunakan format YYYY-MM-DD", raw))
+		return false
+	}
+
+	*target = date
+	return true
+}
+
+// Object menggabungkan field object ke target dengan aturan RFC 7396 (rekursif);
+// null mengosongkan seluruh object.
+func (p *MergePatch) Object(field string, target *map[string]interface{}) bool {
+	if !p.Has(field) {
+		return false
+	}
+
+	if p.isNull(field) {
+		*target = nil
+		return true
+	}
+
+	var patch map[string]interface{}
+	if err := json.Unmarshal(p.fields[field], &patch); err != nil {
+		p.AddError(field, "Harus berupa JSON object atau null")
+		return false
+	}
+
+	var current interface{}
+	if *target != nil {
+		current = *target
+	}
+	merged, _ := ApplyMergePatch(current, patch).(map[string]interface{})
+	*target = merged
+	return true
+}
+
+// ApplyMergePatch menerapkan merge patch ke target sesuai algoritma RFC 7396 section 2
+func ApplyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	} else {
+		// Salin agar target asli tidak ikut berubah
+		copied := make(map[string]interface{}, len(targetObject))
+		for key, value := range targetObject {
+			copied[key] = value
+		}
+		targetObject = copied
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = ApplyMergePatch(targetObject[key], value)
+	}
+
+	return targetObject
+}
+
+// readMergePatch membaca body PATCH; mengembalikan closure respons error jika content type atau body tidak valid
+func readMergePatch(c *fiber.Ctx) (*MergePatch, func() error) {
+	mediaType, _, _ := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if mediaType != "application/merge-patch+json" && mediaType != fiber.MIMEApplicationJSON {
+		return nil, func() error {
+			return c.Status(415).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Content-Type harus application/merge-patch+json",
+			})
+		}
+	}
+
+	patch, err := ParseMergePatch(c.Body())
+	if err != nil {
+		return nil, func() error {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Body harus berupa JSON object merge patch",
+			})
+		}
+	}
+
+	return patch, nil
+}
+
+// mergePatchErrors mengembalikan closure respons 400 berisi kesalahan per field, nil jika tidak ada kesalahan
+func mergePatchErrors(c *fiber.Ctx, patch *MergePatch) func() error {
+	if len(patch.Errors()) == 0 {
+		return nil
+	}
+
+	return func() error {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Validasi gagal",
+			"errors":  patch.Errors(),
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+type NotificationService struct {
+	notificationRepo *repository.NotificationRepository
+}
+
+func NewNotificationService(db *sql.DB) *NotificationService {
+	return &NotificationService{
+		notificationRepo: repository.NewNotificationRepository(db),
+	}
+}
+
+// Notify membuat notifikasi in-app untuk user. achievementID boleh kosong.
+func (s *NotificationService) Notify(userID, notificationType, title, message, achievementID string) error {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	notification := &models.Notification{
+		ID:        uuid.New(),
+		UserID:    parsedUserID,
+		Type:      notificationType,
+		Title:     title,
+		Message:   message,
+		IsRead:    false,
+		CreatedAt: time.Now(),
+	}
+	if achievementID != "" {
+		notification.MongoAchievementID = &achievementID
+	}
+
+	return s.notificationRepo.Create(notification)
+}
+
+// GetMyNotifications godoc
+// @Summary Get my notifications
+// @Description Get paginated notifications of the authenticated user (newest first).
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {object} object{status=string,message=string,data=object{notifications=[]models.Notification,pagination=models.PaginationMeta}} "Notifications retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve notifications"
+// @Router /notifications [get]
+func (s *NotificationService) GetMyNotifications(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized",
+		})
+	}
+
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	unreadOnly := c.QueryBool("unread", false)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	notifications, total, err := s.notificationRepo.FindByUserID(userID, unreadOnly, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data notifikasi",
+		})
+	}
+
+	if notifications == nil {
+		notifications = []models.Notification{}
+	}
+
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data notifikasi berhasil diambil",
+		"data": fiber.Map{
+			"notifications": notifications,
+			"pagination": models.PaginationMeta{
+				Page:       page,
+				Limit:      limit,
+				TotalItems: total,
+				TotalPages: totalPages,
+			},
+		},
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark notification as read
+// @Description Mark one of the authenticated user's notifications as read.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Notification ID (UUID)"
+// @Success 200 {object} object{status=string,message=string} "Notification marked as read"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 404 {object} map[string]interface{} "Notification not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update notification"
+// @Router /notifications/{id}/read [put]
+func (s *NotificationService) MarkNotificationRead(c *fiber.Ctx) error {
+	notificationID := c.Params("id")
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized",
+		})
+	}
+
+	updated, err := s.notificationRepo.MarkRead(notificationID, userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate notifikasi",
+		})
+	}
+	if !updated {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Notifikasi tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Notifikasi ditandai sudah dibaca",
+	})
+}
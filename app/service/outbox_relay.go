@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OutboxMaxAttempts batas percobaan sebuah event outbox sebelum di-park sebagai failed
+const OutboxMaxAttempts = 10
+
+// OutboxRelay menerapkan event outbox achievement ke MongoDB. PostgreSQL adalah sumber
+// kebenaran untuk perubahan status; MongoDB menyusul secara eventual consistent.
+type OutboxRelay struct {
+	outboxRepo      *repository.OutboxRepository
+	achievementRepo *repository.AchievementRepository
+	projector       *AchievementProjector
+}
+
+func NewOutboxRelay(mongoDB *mongo.Database, postgresDB *sql.DB) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo:      repository.NewOutboxRepository(postgresDB),
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		projector:       NewAchievementProjector(mongoDB, postgresDB),
+	}
+}
+
+// NewOutboxEvent membuat event outbox dengan payload JSON
+func NewOutboxEvent(achievementID, eventType string, payload interface{}) (*models.OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxEvent{
+		MongoAchievementID: achievementID,
+		EventType:          eventType,
+		Payload:            data,
+	}, nil
+}
+
+// Dispatch langsung menerapkan event yang baru di-commit agar response mencerminkan perubahan.
+// Kegagalan hanya dicatat: event tetap pending dan diterapkan ulang oleh relay worker.
+func (r *OutboxRelay) Dispatch(ctx context.Context, events ...*models.OutboxEvent) {
+	for _, event := range events {
+		// Event lama yang belum diterapkan didahulukan oleh relay worker agar urutan terjaga
+		pending, err := r.outboxRepo.HasPendingBefore(event.MongoAchievementID, event.ID)
+		if err != nil || pending {
+			return
+		}
+
+		if err := r.process(ctx, event); err != nil {
+			log.Printf("Outbox: gagal menerapkan event %d (%s) untuk achievement %s: %v", event.ID, event.EventType, event.MongoAchievementID, err)
+			return
+		}
+	}
+}
+
+// RelayPending menerapkan event pending secara berurutan. Jika satu event gagal, event
+// berikutnya untuk achievement yang sama ditunda sampai eksekusi berikutnya. Event yang
+// sudah gagal OutboxMaxAttempts kali di-park dan tidak lagi menahan event lain.
+func (r *OutboxRelay) RelayPending(ctx context.Context, limit int) (int, error) {
+	events, err := r.outboxRepo.FindPending(limit)
+	if err != nil {
+		return 0, fmt.Errorf("gagal mengambil event outbox: %w", err)
+	}
+
+	applied := 0
+	blocked := make(map[string]bool)
+	for i := range events {
+		event := &events[i]
+		if blocked[event.MongoAchievementID] {
+			continue
+		}
+
+		if err := r.process(ctx, event); err != nil {
+			log.Printf("Outbox: gagal menerapkan event %d (%s) untuk achievement %s: %v", event.ID, event.EventType, event.MongoAchievementID, err)
+			blocked[event.MongoAchievementID] = true
+			continue
+		}
+		applied++
+	}
+
+	return applied, nil
+}
+
+// RecordMetrics memperbarui gauge jumlah event outbox pending dan yang di-park sebagai failed
+func (r *OutboxRelay) RecordMetrics() error {
+	pending, failed, err := r.outboxRepo.CountStates()
+	if err != nil {
+		return fmt.Errorf("gagal menghitung event outbox: %w", err)
+	}
+	RecordOutboxMetrics(pending, failed)
+	return nil
+}
+
+// RecordOutboxMetrics mengekspos jumlah event outbox pending dan failed
+func RecordOutboxMetrics(pending, failed int) {
+	utils.SetGauge("achievement_outbox_pending", "Event outbox yang belum diterapkan ke MongoDB",
+		nil, float64(pending))
+	utils.SetGauge("achievement_outbox_failed", "Event outbox yang di-park setelah gagal sebanyak batas percobaan",
+		nil, float64(failed))
+}
+
+func (r *OutboxRelay) process(ctx context.Context, event *models.OutboxEvent) error {
+	if err := r.apply(ctx, event); err != nil {
+		parked, markErr := r.outboxRepo.MarkFailed(event.ID, err.Error(), OutboxMaxAttempts)
+		if markErr != nil {
+			log.Printf("Outbox: gagal mencatat kegagalan event %d: %v", event.ID, markErr)
+		}
+		if parked {
+			log.Printf("Outbox: event %d (%s) untuk achievement %s di-park setelah %d percobaan", event.ID, event.EventType, event.MongoAchievementID, OutboxMaxAttempts)
+		}
+		return err
+	}
+
+	if err := r.outboxRepo.MarkProcessed(event.ID); err != nil {
+		return err
+	}
+
+	// Read model mengikuti setelah MongoDB dan PostgreSQL sama-sama mencerminkan perubahan
+	r.projector.Refresh(ctx, event.MongoAchievementID)
+	return nil
+}
+
+// apply menerapkan satu event ke MongoDB. Setiap operasi idempotent terhadap ID event,
+// sehingga aman diterapkan ulang setelah crash atau oleh beberapa relay sekaligus.
+func (r *OutboxRelay) apply(ctx context.Context, event *models.OutboxEvent) error {
+	switch event.EventType {
+	case models.OutboxAchievementCreated:
+		var achievement models.Achievement
+		if err := json.Unmarshal(event.Payload, &achievement); err != nil {
+			return err
+		}
+		return r.achievementRepo.CreateFromOutbox(ctx, &achievement, event.ID)
+
+	case models.OutboxAchievementStatus:
+		// Transisi status dijaga dua kali: UPDATE kondisional di PostgreSQL saat event ditulis, lalu di MongoDB
+		// dokumen harus masih berstatus asal (atau sudah berstatus tujuan, untuk event yang diterapkan ulang).
+		// Replay basi tidak bisa menimpa status yang lebih baru; konflik membuat event gagal dan dicoba lagi.
+		var payload models.OutboxStatusPayload
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return err
+		}
+		return r.achievementRepo.ApplyOutboxChange(ctx, event.MongoAchievementID, event.ID, OutboxStatusGuard(payload), bson.M{"status": payload.Status}, nil)
+
+	case models.OutboxAchievementStage:
+		// Status tetap 'submitted'; hanya versi dokumen yang naik agar ETag lama tidak berlaku lagi
+		return r.achievementRepo.ApplyOutboxChange(ctx, event.MongoAchievementID, event.ID, nil, nil, nil)
+
+	case models.OutboxAchievementDeleted:
+		return r.achievementRepo.ApplyOutboxChange(ctx, event.MongoAchievementID, event.ID, nil, bson.M{"is_deleted": true, "deleted_at": event.CreatedAt}, nil)
+
+	case models.OutboxAchievementRestored:
+		return r.achievementRepo.ApplyOutboxChange(ctx, event.MongoAchievementID, event.ID, nil, bson.M{"is_deleted": false}, bson.M{"deleted_at": ""})
+
+	default:
+		return fmt.Errorf("jenis event outbox tidak dikenal: %s", event.EventType)
+	}
+}
+
+// OutboxStatusGuard syarat dokumen MongoDB untuk menerapkan event status: masih berstatus asal, atau
+// sudah berstatus tujuan (event diterapkan ulang). nil untuk event lama tanpa status asal.
+func OutboxStatusGuard(payload models.OutboxStatusPayload) bson.M {
+	if payload.From == "" {
+		return nil
+	}
+	return bson.M{"status": bson.M{"$in": bson.A{payload.From, payload.Status}}}
+}
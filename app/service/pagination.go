@@ -0,0 +1,53 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// cursorRequested true jika client memakai pagination cursor. Parameter cursor kosong (?cursor=)
+// meminta halaman pertama; tanpa parameter cursor listing tetap memakai page/limit (offset).
+func cursorRequested(c *fiber.Ctx) bool {
+	return c.Context().QueryArgs().Has("cursor")
+}
+
+// parseCursor membaca parameter cursor; nil berarti halaman pertama.
+// Closure error (400) dikembalikan jika cursor tidak bisa dibaca.
+func parseCursor(c *fiber.Ctx) (*models.PageCursor, func() error) {
+	raw := c.Query("cursor")
+	if raw == "" {
+		return nil, nil
+	}
+
+	cursor, err := utils.DecodeCursor(raw)
+	if err != nil {
+		return nil, func() error {
+			return invalidCursor(c)
+		}
+	}
+	return cursor, nil
+}
+
+// invalidCursor respons 400 untuk cursor rusak atau dibuat untuk urutan/listing lain
+func invalidCursor(c *fiber.Ctx) error {
+	return c.Status(400).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Cursor tidak valid. Mulai lagi dari halaman pertama (cursor kosong)",
+	})
+}
+
+// NewCursorPaginationMeta menyusun metadata pagination cursor dari hasil repository
+func NewCursorPaginationMeta(limit int, next, prev *models.PageCursor) models.CursorPaginationMeta {
+	meta := models.CursorPaginationMeta{Limit: limit}
+	if next != nil {
+		encoded := utils.EncodeCursor(*next)
+		meta.NextCursor = &encoded
+	}
+	if prev != nil {
+		encoded := utils.EncodeCursor(*prev)
+		meta.PrevCursor = &encoded
+	}
+	return meta
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"fmt"
+	"time"
+)
+
+// userPermissions mengambil semua permission user dengan cache yang sama seperti RBAC middleware
+func userPermissions(permRepo *repository.PermissionRepository, userID string) ([]string, error) {
+	cacheKey := fmt.Sprintf("user_permissions:%s", userID)
+	var permissions []string
+
+	if utils.Cache != nil {
+		if cachedPerms, found := utils.Cache.Get(cacheKey); found {
+			permissions = cachedPerms.([]string)
+		}
+	}
+
+	if permissions == nil {
+		perms, err := permRepo.GetUserPermissions(userID)
+		if err != nil {
+			return nil, err
+		}
+		permissions = perms
+		if utils.Cache != nil {
+			utils.Cache.Set(cacheKey, permissions, 15*time.Minute)
+		}
+	}
+
+	return permissions, nil
+}
+
+// userHasPermission mengecek permission user dengan cache yang sama seperti RBAC middleware
+func userHasPermission(permRepo *repository.PermissionRepository, userID string, permissionName string) (bool, error) {
+	permissions, err := userPermissions(permRepo, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, perm := range permissions {
+		if perm == permissionName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
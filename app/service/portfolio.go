@@ -0,0 +1,224 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Batas panjang slug dan isi profil portofolio
+const (
+	portfolioSlugMinLength = 3
+	portfolioSlugMaxLength = 50
+	portfolioHeadlineMax   = 150
+	portfolioBioMax        = 2000
+)
+
+var portfolioSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Slug yang tidak boleh dipakai karena mirip halaman atau endpoint aplikasi
+var reservedPortfolioSlugs = map[string]bool{
+	"admin": true, "api": true, "public": true, "portfolio": true, "portfolios": true,
+	"verify": true, "me": true, "settings": true, "login": true, "swagger": true,
+}
+
+// NormalizePortfolioSlug memvalidasi slug portofolio: 3-50 karakter huruf kecil, angka dan tanda hubung
+// (tidak di awal/akhir dan tidak berurutan). Huruf besar diubah menjadi huruf kecil.
+func NormalizePortfolioSlug(raw string) (string, error) {
+	slug := strings.ToLower(strings.TrimSpace(raw))
+	if len(slug) < portfolioSlugMinLength || len(slug) > portfolioSlugMaxLength {
+		return "", fmt.Errorf("Slug harus %d-%d karakter", portfolioSlugMinLength, portfolioSlugMaxLength)
+	}
+	if !portfolioSlugPattern.MatchString(slug) {
+		return "", fmt.Errorf("Slug hanya boleh berisi huruf kecil, angka dan tanda hubung (tidak di awal/akhir)")
+	}
+	if reservedPortfolioSlugs[slug] {
+		return "", fmt.Errorf("Slug '%s' tidak boleh dipakai", slug)
+	}
+	return slug, nil
+}
+
+// SuggestPortfolioSlug usulan slug dari nama mahasiswa, mis. "Siti Rahayu" menjadi "siti-rahayu";
+// karakter selain huruf latin dan angka menjadi pemisah. Kosong jika nama tidak menghasilkan slug valid.
+func SuggestPortfolioSlug(name string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			builder.WriteRune(r)
+			dash = false
+		case builder.Len() > 0 && !dash:
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimRight(builder.String(), "-")
+	if len(slug) > portfolioSlugMaxLength {
+		slug = strings.TrimRight(slug[:portfolioSlugMaxLength], "-")
+	}
+	if _, err := NormalizePortfolioSlug(slug); err != nil {
+		return ""
+	}
+	return slug
+}
+
+// ValidatePortfolioProfile memeriksa panjang headline dan bio
+func ValidatePortfolioProfile(headline, bio string) error {
+	if utf8.RuneCountInString(headline) > portfolioHeadlineMax {
+		return fmt.Errorf("Headline maksimal %d karakter", portfolioHeadlineMax)
+	}
+	if utf8.RuneCountInString(bio) > portfolioBioMax {
+		return fmt.Errorf("Bio maksimal %d karakter", portfolioBioMax)
+	}
+	return nil
+}
+
+// PortfolioSource data yang dirangkai menjadi portofolio publik
+type PortfolioSource struct {
+	Portfolio    models.StudentPortfolio
+	Name         string
+	ProgramStudy string
+	Institution  string
+	Entries      []models.TranscriptEntry // achievement milik mahasiswa dari read model
+	Categories   map[string]string        // label per kode
+	Levels       map[string]string        // label per kode
+	Codes        map[string]string        // kode verifikasi aktif per achievement ID
+	VerifyURL    func(code string) string
+}
+
+// BuildPublicPortfolio menyusun portofolio publik: hanya achievement berstatus verified yang tidak
+// disembunyikan, terbaru lebih dulu. Dokumen dan details achievement tidak pernah disertakan.
+func BuildPublicPortfolio(source PortfolioSource) models.PublicPortfolio {
+	hidden := make(map[string]bool, len(source.Portfolio.HiddenAchievementIDs))
+	for _, id := range source.Portfolio.HiddenAchievementIDs {
+		hidden[id] = true
+	}
+
+	items := []models.PublicPortfolioItem{}
+	for _, entry := range source.Entries {
+		achievement := entry.Achievement
+		if achievement.Status != "verified" || hidden[achievement.AchievementID] {
+			continue
+		}
+
+		item := models.PublicPortfolioItem{
+			Title:       achievement.Title,
+			Category:    labelOrCode(source.Categories, achievement.Category),
+			Level:       labelOrCode(source.Levels, achievement.Level),
+			Date:        achievement.Date,
+			Description: achievement.Description,
+			Tags:        achievement.Tags,
+			VerifiedAt:  entry.VerifiedAt,
+		}
+		if code, ok := source.Codes[achievement.AchievementID]; ok {
+			item.VerificationCode = code
+			if source.VerifyURL != nil {
+				item.VerificationURL = source.VerifyURL(code)
+			}
+		}
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Date.After(items[j].Date)
+	})
+
+	return models.PublicPortfolio{
+		Slug:             source.Portfolio.Slug,
+		Name:             source.Name,
+		ProgramStudy:     source.ProgramStudy,
+		Headline:         source.Portfolio.Headline,
+		Bio:              source.Portfolio.Bio,
+		Institution:      source.Institution,
+		AchievementCount: len(items),
+		Achievements:     items,
+		UpdatedAt:        source.Portfolio.UpdatedAt,
+	}
+}
+
+var portfolioTemplate = template.Must(template.New("portfolio-page").Funcs(template.FuncMap{
+	"date": FormatIndonesianDate,
+	"verifiedDate": func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return FormatIndonesianDate(*t)
+	},
+}).Parse(`{{define "layout"}}<!DOCTYPE html>
+<html lang="id">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{margin:0;font-family:system-ui,-apple-system,"Segoe UI",Roboto,sans-serif;color:#1f2933;background:#f5f7fa;line-height:1.5}
+main{max-width:760px;margin:0 auto;padding:32px 20px}
+header{border-bottom:3px solid #1f4e79;padding-bottom:16px;margin-bottom:24px}
+h1{margin:0 0 4px;font-size:1.8rem}
+.meta{color:#52606d;margin:0}
+.headline{font-size:1.1rem;margin:12px 0 0}
+.bio{white-space:pre-line;margin:12px 0 0}
+.item{background:#fff;border:1px solid #e4e7eb;border-radius:8px;padding:16px;margin-bottom:12px}
+.item h3{margin:0 0 4px;font-size:1.1rem}
+.tags span{display:inline-block;background:#e6f0fa;color:#1f4e79;border-radius:4px;padding:0 6px;margin:4px 4px 0 0;font-size:.85rem}
+.verified{font-size:.9rem;color:#2f8132;margin:8px 0 0}
+a{color:#1f4e79}
+footer{color:#7b8794;font-size:.85rem;margin-top:32px}
+</style>
+</head>
+<body>
+<main>
+{{template "content" .}}
+</main>
+</body>
+</html>{{end}}
+
+{{define "portfolio"}}{{template "layout" .}}{{end}}
+{{define "content"}}{{with .Portfolio}}
+<header>
+<h1>{{.Name}}</h1>
+<p class="meta">{{if .ProgramStudy}}{{.ProgramStudy}} &middot; {{end}}{{.Institution}}</p>
+{{if .Headline}}<p class="headline">{{.Headline}}</p>{{end}}
+{{if .Bio}}<p class="bio">{{.Bio}}</p>{{end}}
+</header>
+<section>
+<h2>Prestasi Terverifikasi ({{.AchievementCount}})</h2>
+{{range .Achievements}}<article class="item">
+<h3>{{.Title}}</h3>
+<p class="meta">{{.Category}} &middot; {{.Level}} &middot; {{date .Date}}</p>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+{{if .Tags}}<div class="tags">{{range .Tags}}<span>{{.}}</span>{{end}}</div>{{end}}
+<p class="verified">&#10003; Diverifikasi{{with verifiedDate .VerifiedAt}} {{.}}{{end}}{{if .VerificationURL}} &middot; <a href="{{.VerificationURL}}" rel="nofollow">Cek kode {{.VerificationCode}}</a>{{end}}</p>
+</article>
+{{else}}<p>Belum ada prestasi yang ditampilkan.</p>
+{{end}}</section>
+<footer>Hanya prestasi yang telah diverifikasi oleh {{.Institution}} yang ditampilkan di halaman ini.</footer>
+{{end}}{{end}}`))
+
+var portfolioNotFoundTemplate = template.Must(template.Must(portfolioTemplate.Clone()).Parse(`{{define "content"}}
+<h1>Portofolio tidak ditemukan</h1>
+<p class="meta">Portofolio ini tidak ada atau tidak dipublikasikan oleh pemiliknya.</p>
+{{end}}`))
+
+// RenderPortfolioHTML menulis halaman HTML portofolio publik; semua isi di-escape oleh html/template
+func RenderPortfolioHTML(w io.Writer, portfolio models.PublicPortfolio) error {
+	return portfolioTemplate.ExecuteTemplate(w, "portfolio", map[string]interface{}{
+		"Title":     portfolio.Name + " - Portofolio Prestasi",
+		"Portfolio": portfolio,
+	})
+}
+
+// RenderPortfolioNotFoundHTML menulis halaman HTML untuk portofolio yang tidak ada atau tidak publik
+func RenderPortfolioNotFoundHTML(w io.Writer) error {
+	return portfolioNotFoundTemplate.ExecuteTemplate(w, "layout", map[string]interface{}{
+		"Title": "Portofolio tidak ditemukan",
+	})
+}
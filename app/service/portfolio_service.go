@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Header halaman portofolio publik: tanpa skrip, gambar eksternal maupun embed di situs lain
+const portfolioContentSecurityPolicy = "default-src 'none'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+// PortfolioService mengelola portofolio publik mahasiswa (opt-in) dan menyajikannya tanpa login
+type PortfolioService struct {
+	portfolioRepo *repository.PortfolioRepository
+	studentRepo   *repository.StudentRepository
+	userRepo      *repository.UserRepository
+	readModelRepo *repository.AchievementReadModelRepository
+	recordRepo    *repository.VerificationRecordRepository
+	masterRepo    *repository.MasterDataRepository
+	verifications *VerificationRecordService
+}
+
+func NewPortfolioService(mongoDB *mongo.Database, postgresDB *sql.DB) *PortfolioService {
+	return &PortfolioService{
+		portfolioRepo: repository.NewPortfolioRepository(postgresDB),
+		studentRepo:   repository.NewStudentRepository(postgresDB),
+		userRepo:      repository.NewUserRepository(postgresDB),
+		readModelRepo: repository.NewAchievementReadModelRepository(postgresDB),
+		recordRepo:    repository.NewVerificationRecordRepository(postgresDB),
+		masterRepo:    repository.NewMasterDataRepository(postgresDB),
+		verifications: NewVerificationRecordService(mongoDB, postgresDB),
+	}
+}
+
+// portfolioURL link publik sebuah portofolio
+func (s *PortfolioService) portfolioURL(slug string) string {
+	return s.verifications.config.PublicBaseURL + "/public/portfolios/" + slug
+}
+
+// currentStudent mengambil user ID mahasiswa yang login; mengembalikan fungsi response error
+// jika user tidak login atau tidak punya profil mahasiswa
+func (s *PortfolioService) currentStudent(c *fiber.Ctx) (string, func() error) {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return "", func() error {
+			return c.Status(401).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Unauthorized: User ID tidak ditemukan",
+			})
+		}
+	}
+
+	student, err := s.studentRepo.FindByUserID(userID)
+	if err != nil {
+		return "", func() error {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengambil data mahasiswa",
+			})
+		}
+	}
+	if student == nil {
+		return "", func() error {
+			return c.Status(403).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Portofolio hanya tersedia untuk mahasiswa",
+			})
+		}
+	}
+
+	return userID, nil
+}
+
+// GetMyPortfolio godoc
+// @Summary Get my portfolio settings
+// @Description Get the current student's public portfolio settings and their verified achievements with the visibility flag of each. When the portfolio has not been created yet, portfolio is null and suggested_slug is derived from the student's name.
+// @Tags Portfolio
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=object{portfolio=models.StudentPortfolio,achievements=[]models.PortfolioAchievement,public_url=string,suggested_slug=string}} "Portfolio settings retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Only students have a portfolio"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve portfolio"
+// @Router /portfolio [get]
+func (s *PortfolioService) GetMyPortfolio(c *fiber.Ctx) error {
+	userID, errResponse := s.currentStudent(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	portfolio, err := s.portfolioRepo.FindByStudentID(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data portofolio",
+		})
+	}
+
+	entries, err := s.readModelRepo.FindTranscriptEntries(userID, []string{"verified"})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data achievements",
+		})
+	}
+
+	hidden := make(map[string]bool)
+	if portfolio != nil {
+		for _, id := range portfolio.HiddenAchievementIDs {
+			hidden[id] = true
+		}
+	}
+	categories, levels := masterDataLabels(s.masterRepo)
+	achievements := make([]models.PortfolioAchievement, 0, len(entries))
+	for _, entry := range entries {
+		achievements = append(achievements, models.PortfolioAchievement{
+			ID:         entry.Achievement.AchievementID,
+			Title:      entry.Achievement.Title,
+			Category:   labelOrCode(categories, entry.Achievement.Category),
+			Level:      labelOrCode(levels, entry.Achievement.Level),
+			Date:       entry.Achievement.Date,
+			VerifiedAt: entry.VerifiedAt,
+			Visible:    !hidden[entry.Achievement.AchievementID],
+		})
+	}
+
+	data := fiber.Map{
+		"portfolio":    portfolio,
+		"achievements": achievements,
+	}
+	if portfolio == nil {
+		if user, err := s.userRepo.FindByID(userID); err == nil {
+			data["suggested_slug"] = SuggestPortfolioSlug(user.FullName)
+		}
+	} else if portfolio.IsPublic {
+		data["public_url"] = s.portfolioURL(portfolio.Slug)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data portofolio berhasil diambil",
+		"data":    data,
+	})
+}
+
+// UpdateMyPortfolio godoc
+// @Summary Create or update my portfolio
+// @Description Create or update the current student's public portfolio. The slug (3-50 lowercase letters, digits and hyphens) is required when the portfolio is created and becomes the public URL /public/portfolios/{slug}. The portfolio is only visible to the public when is_public is true. Omitted fields are left unchanged.
+// @Tags Portfolio
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdatePortfolioRequest true "Portfolio settings"
+// @Success 200 {object} object{status=string,message=string,data=object{portfolio=models.StudentPortfolio,public_url=string}} "Portfolio saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body, slug, headline or bio"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Only students have a portfolio"
+// @Failure 409 {object} map[string]interface{} "Slug already taken"
+// @Failure 500 {object} map[string]interface{} "Failed to save portfolio"
+// @Router /portfolio [put]
+func (s *PortfolioService) UpdateMyPortfolio(c *fiber.Ctx) error {
+	userID, errResponse := s.currentStudent(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	var req models.UpdatePortfolioRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	portfolio, err := s.portfolioRepo.FindByStudentID(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data portofolio",
+		})
+	}
+	if portfolio == nil {
+		if req.Slug == nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Slug wajib diisi saat membuat portofolio",
+			})
+		}
+		studentID, err := uuid.Parse(userID)
+		if err != nil {
+			return c.Status(401).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Unauthorized: User ID tidak valid",
+			})
+		}
+		portfolio = &models.StudentPortfolio{
+			StudentID:            studentID,
+			HiddenAchievementIDs: []string{},
+		}
+	}
+
+	if req.Slug != nil {
+		slug, err := NormalizePortfolioSlug(*req.Slug)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": err.Error(),
+			})
+		}
+		portfolio.Slug = slug
+	}
+	if req.IsPublic != nil {
+		portfolio.IsPublic = *req.IsPublic
+	}
+	if req.Headline != nil {
+		portfolio.Headline = strings.TrimSpace(*req.Headline)
+	}
+	if req.Bio != nil {
+		portfolio.Bio = strings.TrimSpace(*req.Bio)
+	}
+	if err := ValidatePortfolioProfile(portfolio.Headline, portfolio.Bio); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	if err := s.portfolioRepo.Upsert(portfolio); err != nil {
+		if err == repository.ErrSlugTaken {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Slug sudah dipakai, silakan pilih slug lain",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan portofolio",
+		})
+	}
+
+	data := fiber.Map{"portfolio": portfolio}
+	if portfolio.IsPublic {
+		data["public_url"] = s.portfolioURL(portfolio.Slug)
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Portofolio berhasil disimpan",
+		"data":    data,
+	})
+}
+
+// SetPortfolioAchievementVisibility godoc
+// @Summary Show or hide an achievement on my portfolio
+// @Description Toggle whether one of the current student's verified achievements appears on their public portfolio. Verified achievements are shown by default.
+// @Tags Portfolio
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param request body models.PortfolioVisibilityRequest true "Visibility"
+// @Success 200 {object} object{status=string,message=string,data=object{id=string,visible=bool}} "Visibility updated successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request body"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Only students have a portfolio"
+// @Failure 404 {object} map[string]interface{} "Verified achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to update visibility"
+// @Router /portfolio/achievements/{id}/visibility [put]
+func (s *PortfolioService) SetPortfolioAchievementVisibility(c *fiber.Ctx) error {
+	userID, errResponse := s.currentStudent(c)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	var req models.PortfolioVisibilityRequest
+	if err := c.BodyParser(&req); err != nil || req.Visible == nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Field visible (true/false) wajib diisi",
+		})
+	}
+
+	// Hanya achievement terverifikasi milik mahasiswa (termasuk prestasi tim) yang bisa diatur
+	achievementID := c.Params("id")
+	entries, err := s.readModelRepo.FindTranscriptEntries(userID, []string{"verified"})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data achievements",
+		})
+	}
+	found := false
+	for _, entry := range entries {
+		if entry.Achievement.AchievementID == achievementID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement terverifikasi tidak ditemukan",
+		})
+	}
+
+	if err := s.portfolioRepo.SetHidden(userID, achievementID, !*req.Visible); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengubah tampilan achievement",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tampilan achievement di portofolio berhasil diubah",
+		"data": fiber.Map{
+			"id":      achievementID,
+			"visible": *req.Visible,
+		},
+	})
+}
+
+// GetPublicPortfolio godoc
+// @Summary Public student portfolio
+// @Description Unauthenticated, read-only portfolio of a student who opted in. Lists only verified achievements the student has not hidden, each with its public verification link. Contact details, student number, documents and unverified achievements are never included. Browsers (Accept: text/html) get a server-rendered HTML page; other clients get JSON. Use format=html or format=json to choose explicitly. Rate-limited per client IP.
+// @Tags Portfolio
+// @Produce json,html
+// @Param slug path string true "Portfolio slug"
+// @Param format query string false "Response format (json, html)"
+// @Success 200 {object} object{status=string,message=string,data=models.PublicPortfolio} "Public portfolio"
+// @Failure 404 {object} map[string]interface{} "Portfolio not found or not public"
+// @Failure 429 {object} map[string]interface{} "Too many requests"
+// @Router /public/portfolios/{slug} [get]
+func (s *PortfolioService) GetPublicPortfolio(c *fiber.Ctx) error {
+	asHTML := false
+	switch strings.ToLower(c.Query("format")) {
+	case "html":
+		asHTML = true
+	case "json":
+	default:
+		asHTML = c.Accepts(fiber.MIMEApplicationJSON, fiber.MIMETextHTML) == fiber.MIMETextHTML
+	}
+	c.Vary(fiber.HeaderAccept)
+
+	// Portofolio yang tidak ada, tidak publik atau pemiliknya nonaktif dijawab sama agar tidak bisa ditebak
+	notFound := func() error {
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		if asHTML {
+			return s.sendPortfolioHTML(c, 404, RenderPortfolioNotFoundHTML)
+		}
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Portofolio tidak ditemukan",
+		})
+	}
+
+	slug, err := NormalizePortfolioSlug(c.Params("slug"))
+	if err != nil {
+		return notFound()
+	}
+
+	portfolio, err := s.portfolioRepo.FindBySlug(slug)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data portofolio",
+		})
+	}
+	if portfolio == nil || !portfolio.IsPublic {
+		return notFound()
+	}
+
+	studentID := portfolio.StudentID.String()
+	user, err := s.userRepo.FindByID(studentID)
+	if err != nil || !user.IsActive {
+		return notFound()
+	}
+	student, err := s.studentRepo.FindByUserID(studentID)
+	if err != nil || student == nil {
+		return notFound()
+	}
+
+	entries, err := s.readModelRepo.FindTranscriptEntries(studentID, []string{"verified"})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data achievements",
+		})
+	}
+
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.Achievement.AchievementID)
+	}
+	codes, err := s.recordRepo.FindActiveCodes(ids)
+	if err != nil {
+		// Portofolio tetap tampil, hanya tanpa link verifikasi
+		log.Printf("Portofolio %s: gagal mengambil kode verifikasi: %v", slug, err)
+		codes = map[string]string{}
+	}
+
+	categories, levels := masterDataLabels(s.masterRepo)
+	public := BuildPublicPortfolio(PortfolioSource{
+		Portfolio:    *portfolio,
+		Name:         user.FullName,
+		ProgramStudy: student.ProgramStudy,
+		Institution:  s.verifications.config.Institution,
+		Entries:      entries,
+		Categories:   categories,
+		Levels:       levels,
+		Codes:        codes,
+		VerifyURL:    s.verifications.verifyURL,
+	})
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=60")
+	if asHTML {
+		return s.sendPortfolioHTML(c, 200, func(w io.Writer) error {
+			return RenderPortfolioHTML(w, public)
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Portofolio berhasil diambil",
+		"data":    public,
+	})
+}
+
+// sendPortfolioHTML merender halaman portofolio ke buffer lebih dulu agar error template tidak
+// menghasilkan halaman setengah jadi
+func (s *PortfolioService) sendPortfolioHTML(c *fiber.Ctx, status int, render func(w io.Writer) error) error {
+	var buf bytes.Buffer
+	if err := render(&buf); err != nil {
+		log.Printf("Gagal merender halaman portofolio: %v", err)
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menampilkan portofolio",
+		})
+	}
+
+	c.Set(fiber.HeaderContentSecurityPolicy, portfolioContentSecurityPolicy)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.Status(status).Send(buf.Bytes())
+}
@@ -0,0 +1,604 @@
+package service
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+var (
+	transcriptPageSizes    = []string{"a4", "letter", "legal"}
+	transcriptOrientations = []string{"portrait", "landscape"}
+	transcriptSections     = []string{
+		models.TranscriptSectionIdentity,
+		models.TranscriptSectionSummary,
+		models.TranscriptSectionAchievements,
+		models.TranscriptSectionSignature,
+	}
+	transcriptColumns = []string{
+		models.TranscriptColumnTitle,
+		models.TranscriptColumnDate,
+		models.TranscriptColumnStatus,
+		models.TranscriptColumnPoints,
+		models.TranscriptColumnCode,
+	}
+	hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// Batas isi template agar kop dan judul tetap muat di satu halaman
+const (
+	maxTranscriptHeaderLines = 6
+	maxTranscriptTextLength  = 150
+)
+
+// Lebar kolom tetap (mm); kolom judul memakai sisa lebar halaman
+var transcriptColumnWidths = map[string]float64{
+	models.TranscriptColumnDate:   24,
+	models.TranscriptColumnStatus: 30,
+	models.TranscriptColumnPoints: 16,
+	models.TranscriptColumnCode:   28,
+}
+
+var transcriptColumnLabels = map[string]string{
+	models.TranscriptColumnTitle:  "Prestasi",
+	models.TranscriptColumnDate:   "Tanggal",
+	models.TranscriptColumnStatus: "Status",
+	models.TranscriptColumnPoints: "Poin",
+	models.TranscriptColumnCode:   "Kode Verifikasi",
+}
+
+var indonesianMonths = []string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// DefaultTranscriptLayout layout bawaan jika belum ada template untuk program studi maupun scope default
+func DefaultTranscriptLayout() models.TranscriptLayout {
+	return models.TranscriptLayout{
+		PageSize:    "a4",
+		Orientation: "portrait",
+		HeaderLines: []string{"Universitas"},
+		Title:       "Transkrip Prestasi Mahasiswa",
+		AccentColor: "#1F3A68",
+		Sections: []string{
+			models.TranscriptSectionIdentity,
+			models.TranscriptSectionSummary,
+			models.TranscriptSectionAchievements,
+			models.TranscriptSectionSignature,
+		},
+		Columns: []string{
+			models.TranscriptColumnTitle,
+			models.TranscriptColumnDate,
+			models.TranscriptColumnStatus,
+			models.TranscriptColumnPoints,
+			models.TranscriptColumnCode,
+		},
+		ShowQRCode: true,
+		FooterText: "Keaslian dokumen dapat diperiksa melalui kode verifikasi pada halaman pertama.",
+	}
+}
+
+// ApplyTranscriptDefaults mengisi field teks/daftar yang kosong dengan nilai DefaultTranscriptLayout
+// dan menormalkan page size/orientasi ke lowercase
+func ApplyTranscriptDefaults(layout models.TranscriptLayout) models.TranscriptLayout {
+	defaults := DefaultTranscriptLayout()
+
+	layout.PageSize = strings.ToLower(strings.TrimSpace(layout.PageSize))
+	if layout.PageSize == "" {
+		layout.PageSize = defaults.PageSize
+	}
+	layout.Orientation = strings.ToLower(strings.TrimSpace(layout.Orientation))
+	if layout.Orientation == "" {
+		layout.Orientation = defaults.Orientation
+	}
+	if strings.TrimSpace(layout.Title) == "" {
+		layout.Title = defaults.Title
+	}
+	if layout.AccentColor == "" {
+		layout.AccentColor = defaults.AccentColor
+	}
+	if len(layout.Sections) == 0 {
+		layout.Sections = defaults.Sections
+	}
+	if len(layout.Columns) == 0 {
+		layout.Columns = defaults.Columns
+	}
+
+	return layout
+}
+
+// ValidateTranscriptLayout memeriksa layout template; mengembalikan daftar pelanggaran (kosong = valid)
+func ValidateTranscriptLayout(layout models.TranscriptLayout) []string {
+	var violations []string
+
+	if !containsString(transcriptPageSizes, strings.ToLower(layout.PageSize)) {
+		violations = append(violations, fmt.Sprintf("page_size harus salah satu dari: %s", strings.Join(transcriptPageSizes, ", ")))
+	}
+	if !containsString(transcriptOrientations, strings.ToLower(layout.Orientation)) {
+		violations = append(violations, fmt.Sprintf("orientation harus salah satu dari: %s", strings.Join(transcriptOrientations, ", ")))
+	}
+	if !hexColorPattern.MatchString(layout.AccentColor) {
+		violations = append(violations, "accent_color harus berformat #RRGGBB")
+	}
+
+	if len(layout.HeaderLines) > maxTranscriptHeaderLines {
+		violations = append(violations, fmt.Sprintf("header_lines maksimal %d baris", maxTranscriptHeaderLines))
+	}
+	for i, line := range layout.HeaderLines {
+		if len(line) > maxTranscriptTextLength {
+			violations = append(violations, fmt.Sprintf("header_lines[%d] maksimal %d karakter", i, maxTranscriptTextLength))
+		}
+	}
+	if len(layout.Title) > maxTranscriptTextLength {
+		violations = append(violations, fmt.Sprintf("title maksimal %d karakter", maxTranscriptTextLength))
+	}
+
+	violations = append(violations, validateTranscriptList("sections", layout.Sections, transcriptSections)...)
+	violations = append(violations, validateTranscriptList("columns", layout.Columns, transcriptColumns)...)
+	if !containsString(layout.Columns, models.TranscriptColumnTitle) {
+		violations = append(violations, "columns wajib memuat 'title'")
+	}
+
+	return violations
+}
+
+// validateTranscriptList memastikan daftar tidak kosong, hanya berisi nilai yang dikenal dan tanpa duplikat
+func validateTranscriptList(field string, values, allowed []string) []string {
+	if len(values) == 0 {
+		return []string{fmt.Sprintf("%s tidak boleh kosong", field)}
+	}
+
+	var violations []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		if !containsString(allowed, value) {
+			violations = append(violations, fmt.Sprintf("%s: '%s' tidak dikenal (pilihan: %s)", field, value, strings.Join(allowed, ", ")))
+			continue
+		}
+		if seen[value] {
+			violations = append(violations, fmt.Sprintf("%s: '%s' muncul lebih dari sekali", field, value))
+		}
+		seen[value] = true
+	}
+	return violations
+}
+
+func containsString(values []string, target string) bool {
+	for _, value := range values {
+		if value == target {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildTranscriptGroups mengelompokkan achievement per kategori lalu level. Kategori diurutkan sesuai
+// sort_order master data, level dari rank tertinggi; code yang tidak ada di master data diletakkan terakhir.
+// points berisi poin per achievement_id.
+func BuildTranscriptGroups(entries []models.TranscriptEntry, points map[string]float64, categories []models.AchievementCategory, levels []models.AchievementLevel) []models.TranscriptGroup {
+	categoryByCode := make(map[string]models.AchievementCategory, len(categories))
+	for _, category := range categories {
+		categoryByCode[category.Code] = category
+	}
+	levelByCode := make(map[string]models.AchievementLevel, len(levels))
+	for _, level := range levels {
+		levelByCode[level.Code] = level
+	}
+
+	type groupKey struct{ category, level string }
+	var keys []groupKey
+	grouped := make(map[groupKey]*models.TranscriptGroup)
+
+	for _, entry := range entries {
+		achievement := entry.Achievement
+		key := groupKey{achievement.Category, achievement.Level}
+
+		group, ok := grouped[key]
+		if !ok {
+			group = &models.TranscriptGroup{
+				Category: achievement.Category,
+				Level:    achievement.Level,
+				Items:    []models.TranscriptItem{},
+			}
+			if category, found := categoryByCode[key.category]; found && category.LabelID != "" {
+				group.Category = category.LabelID
+			}
+			if level, found := levelByCode[key.level]; found && level.LabelID != "" {
+				group.Level = level.LabelID
+			}
+			grouped[key] = group
+			keys = append(keys, key)
+		}
+
+		item := models.TranscriptItem{
+			AchievementID: achievement.AchievementID,
+			Title:         achievement.Title,
+			Date:          achievement.Date,
+			Status:        achievement.Status,
+			VerifiedAt:    entry.VerifiedAt,
+		}
+		// Poin hanya dihitung untuk achievement yang sudah terverifikasi
+		if achievement.Status == "verified" {
+			item.Points = points[achievement.AchievementID]
+			group.Points += item.Points
+		}
+		group.Items = append(group.Items, item)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		ci, iKnown := categoryByCode[keys[i].category]
+		cj, jKnown := categoryByCode[keys[j].category]
+		if keys[i].category != keys[j].category {
+			if iKnown != jKnown {
+				return iKnown
+			}
+			if ci.SortOrder != cj.SortOrder {
+				return ci.SortOrder < cj.SortOrder
+			}
+			return grouped[keys[i]].Category < grouped[keys[j]].Category
+		}
+
+		li, iKnown := levelByCode[keys[i].level]
+		lj, jKnown := levelByCode[keys[j].level]
+		if iKnown != jKnown {
+			return iKnown
+		}
+		if li.Rank != lj.Rank {
+			return li.Rank > lj.Rank
+		}
+		return grouped[keys[i]].Level < grouped[keys[j]].Level
+	})
+
+	groups := make([]models.TranscriptGroup, 0, len(keys))
+	for _, key := range keys {
+		groups = append(groups, *grouped[key])
+	}
+	return groups
+}
+
+// RenderTranscriptPDF merender transkrip ke PDF sesuai layout. Layout diasumsikan sudah lolos
+// ValidateTranscriptLayout; field kosong diisi nilai default.
+func RenderTranscriptPDF(data models.TranscriptData, layout models.TranscriptLayout) ([]byte, error) {
+	layout = ApplyTranscriptDefaults(layout)
+
+	orientation := "P"
+	if layout.Orientation == "landscape" {
+		orientation = "L"
+	}
+
+	pdf := gofpdf.New(orientation, "mm", layout.PageSize, "")
+	r := &transcriptRenderer{
+		pdf:    pdf,
+		tr:     pdf.UnicodeTranslatorFromDescriptor(""),
+		layout: layout,
+		data:   data,
+	}
+	r.accent[0], r.accent[1], r.accent[2] = parseHexColor(layout.AccentColor)
+
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(layout.Title, true)
+	pdf.SetFooterFunc(r.footer)
+	pdf.AddPage()
+
+	if err := r.header(); err != nil {
+		return nil, err
+	}
+	for _, section := range layout.Sections {
+		switch section {
+		case models.TranscriptSectionIdentity:
+			r.identity()
+		case models.TranscriptSectionSummary:
+			r.summary()
+		case models.TranscriptSectionAchievements:
+			r.achievements()
+		case models.TranscriptSectionSignature:
+			r.signature()
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// transcriptRenderer menyimpan state render satu dokumen transkrip
+type transcriptRenderer struct {
+	pdf    *gofpdf.Fpdf
+	tr     func(string) string
+	layout models.TranscriptLayout
+	data   models.TranscriptData
+	accent [3]int
+}
+
+const (
+	transcriptQRSize     = 26
+	transcriptLineHeight = 5
+)
+
+func (r *transcriptRenderer) contentWidth() float64 {
+	pageWidth, _ := r.pdf.GetPageSize()
+	left, _, right, _ := r.pdf.GetMargins()
+	return pageWidth - left - right
+}
+
+// header kop institusi, QR code verifikasi (pojok kanan atas) dan judul dokumen
+func (r *transcriptRenderer) header() error {
+	pdf := r.pdf
+	left, top, _, _ := pdf.GetMargins()
+	width := r.contentWidth()
+
+	showQR := r.layout.ShowQRCode && r.data.VerificationCode != ""
+	textX, textWidth := left, width
+	if showQR {
+		// Kop tetap di tengah halaman tanpa menimpa QR code
+		textX, textWidth = left+transcriptQRSize, width-2*transcriptQRSize
+
+		png, err := qrcode.Encode(r.data.VerificationURL, qrcode.Medium, 256)
+		if err != nil {
+			return err
+		}
+		options := gofpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader("verification-qr", options, bytes.NewReader(png))
+		qrX := left + width - transcriptQRSize
+		pdf.ImageOptions("verification-qr", qrX, top, transcriptQRSize, transcriptQRSize, false, options, 0, "")
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.SetXY(qrX, top+transcriptQRSize)
+		pdf.CellFormat(transcriptQRSize, 4, r.data.VerificationCode, "", 0, "C", false, 0, "")
+	}
+
+	pdf.SetXY(textX, top)
+	for i, line := range r.layout.HeaderLines {
+		if i == 0 {
+			pdf.SetFont("Helvetica", "B", 14)
+		} else {
+			pdf.SetFont("Helvetica", "", 10)
+		}
+		pdf.SetX(textX)
+		pdf.MultiCell(textWidth, 6, r.tr(line), "", "C", false)
+	}
+
+	y := pdf.GetY() + 2
+	if showQR && y < top+transcriptQRSize+5 {
+		y = top + transcriptQRSize + 5
+	}
+	pdf.SetDrawColor(r.accent[0], r.accent[1], r.accent[2])
+	pdf.SetLineWidth(0.8)
+	pdf.Line(left, y, left+width, y)
+	pdf.SetLineWidth(0.2)
+
+	pdf.SetXY(left, y+4)
+	pdf.SetFont("Helvetica", "B", 13)
+	pdf.MultiCell(width, 7, r.tr(strings.ToUpper(r.layout.Title)), "", "C", false)
+	pdf.Ln(4)
+	return pdf.Error()
+}
+
+func (r *transcriptRenderer) sectionTitle(title string) {
+	pdf := r.pdf
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.SetTextColor(r.accent[0], r.accent[1], r.accent[2])
+	pdf.CellFormat(0, 7, r.tr(title), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// labelRows baris "label : nilai" untuk identitas dan ringkasan
+func (r *transcriptRenderer) labelRows(rows [][2]string) {
+	pdf := r.pdf
+	for _, row := range rows {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(45, 6, r.tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(4, 6, ":", "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.MultiCell(0, 6, r.tr(row[1]), "", "L", false)
+	}
+	pdf.Ln(3)
+}
+
+func (r *transcriptRenderer) identity() {
+	r.sectionTitle("Identitas Mahasiswa")
+	r.labelRows([][2]string{
+		{"Nama", r.data.StudentName},
+		{"Nomor Induk Mahasiswa", r.data.StudentNumber},
+		{"Program Studi", r.data.ProgramStudy},
+		{"Angkatan", r.data.AcademicYear},
+	})
+}
+
+func (r *transcriptRenderer) summary() {
+	r.sectionTitle("Ringkasan")
+	rows := [][2]string{
+		{"Prestasi terverifikasi", strconv.Itoa(r.data.AchievementCount)},
+		{"Total poin", formatTranscriptPoints(r.data.TotalPoints)},
+		{"Tanggal terbit", FormatIndonesianDate(r.data.IssuedAt)},
+	}
+	if r.data.VerificationCode != "" {
+		rows = append(rows, [2]string{"Kode verifikasi", r.data.VerificationCode})
+	}
+	r.labelRows(rows)
+}
+
+// columnWidths lebar setiap kolom tabel; kolom judul mengambil sisa lebar
+func (r *transcriptRenderer) columnWidths() []float64 {
+	widths := make([]float64, len(r.layout.Columns))
+	remaining := r.contentWidth()
+	titleIndex := -1
+	for i, column := range r.layout.Columns {
+		if column == models.TranscriptColumnTitle {
+			titleIndex = i
+			continue
+		}
+		widths[i] = transcriptColumnWidths[column]
+		remaining -= widths[i]
+	}
+	if titleIndex >= 0 {
+		widths[titleIndex] = math.Max(remaining, 30)
+	}
+	return widths
+}
+
+func (r *transcriptRenderer) tableHeader(widths []float64) {
+	pdf := r.pdf
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(r.accent[0], r.accent[1], r.accent[2])
+	pdf.SetTextColor(255, 255, 255)
+	for i, column := range r.layout.Columns {
+		pdf.CellFormat(widths[i], 7, transcriptColumnLabels[column], "1", 0, "C", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetTextColor(0, 0, 0)
+}
+
+func (r *transcriptRenderer) achievements() {
+	pdf := r.pdf
+	r.sectionTitle("Daftar Prestasi")
+
+	if len(r.data.Groups) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 7, "Belum ada prestasi terverifikasi.", "", 1, "L", false, 0, "")
+		pdf.Ln(3)
+		return
+	}
+
+	left, _, _, bottom := pdf.GetMargins()
+	_, pageHeight := pdf.GetPageSize()
+	widths := r.columnWidths()
+	showPoints := containsString(r.layout.Columns, models.TranscriptColumnPoints)
+
+	for _, group := range r.data.Groups {
+		// Judul kelompok tidak boleh terpisah dari baris pertamanya
+		if pdf.GetY()+20 > pageHeight-bottom {
+			pdf.AddPage()
+		}
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(0, 7, r.tr(fmt.Sprintf("%s - %s", group.Category, group.Level)), "", 1, "L", false, 0, "")
+		r.tableHeader(widths)
+
+		for _, item := range group.Items {
+			pdf.SetFont("Helvetica", "", 9)
+			titleLines := 1
+			for i, column := range r.layout.Columns {
+				if column == models.TranscriptColumnTitle {
+					titleLines = len(pdf.SplitLines([]byte(r.tr(item.Title)), widths[i]-2))
+				}
+			}
+			height := math.Max(float64(titleLines), 1)*transcriptLineHeight + 2
+
+			if pdf.GetY()+height > pageHeight-bottom {
+				pdf.AddPage()
+				r.tableHeader(widths)
+				pdf.SetFont("Helvetica", "", 9)
+			}
+
+			x, y := left, pdf.GetY()
+			for i, column := range r.layout.Columns {
+				pdf.SetXY(x, y)
+				switch column {
+				case models.TranscriptColumnTitle:
+					pdf.Rect(x, y, widths[i], height, "D")
+					pdf.SetXY(x, y+1)
+					pdf.MultiCell(widths[i], transcriptLineHeight, r.tr(item.Title), "", "L", false)
+				case models.TranscriptColumnDate:
+					pdf.CellFormat(widths[i], height, item.Date.Format("02-01-2006"), "1", 0, "C", false, 0, "")
+				case models.TranscriptColumnStatus:
+					pdf.CellFormat(widths[i], height, transcriptStatusLabel(item.Status), "1", 0, "C", false, 0, "")
+				case models.TranscriptColumnPoints:
+					pdf.CellFormat(widths[i], height, formatTranscriptPoints(item.Points), "1", 0, "R", false, 0, "")
+				case models.TranscriptColumnCode:
+					pdf.SetFont("Courier", "", 8)
+					pdf.CellFormat(widths[i], height, item.VerificationCode, "1", 0, "C", false, 0, "")
+					pdf.SetFont("Helvetica", "", 9)
+				}
+				x += widths[i]
+			}
+			pdf.SetXY(left, y+height)
+		}
+
+		if showPoints {
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.CellFormat(r.contentWidth(), 6, "Subtotal poin: "+formatTranscriptPoints(group.Points), "", 1, "R", false, 0, "")
+		}
+		pdf.Ln(3)
+	}
+}
+
+func (r *transcriptRenderer) signature() {
+	pdf := r.pdf
+	left, _, _, bottom := pdf.GetMargins()
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+45 > pageHeight-bottom {
+		pdf.AddPage()
+	}
+
+	width := 75.0
+	x := left + r.contentWidth() - width
+	pdf.Ln(6)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetX(x)
+	pdf.CellFormat(width, 6, r.tr(FormatIndonesianDate(r.data.IssuedAt)), "", 1, "L", false, 0, "")
+	if r.layout.SignatoryTitle != "" {
+		pdf.SetX(x)
+		pdf.MultiCell(width, 6, r.tr(r.layout.SignatoryTitle), "", "L", false)
+	}
+	pdf.Ln(20)
+	pdf.SetX(x)
+	pdf.SetFont("Helvetica", "BU", 10)
+	name := r.layout.SignatoryName
+	if name == "" {
+		name = "(..............................)"
+	}
+	pdf.CellFormat(width, 6, r.tr(name), "", 1, "L", false, 0, "")
+}
+
+func (r *transcriptRenderer) footer() {
+	pdf := r.pdf
+	pdf.SetY(-15)
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.SetTextColor(110, 110, 110)
+	width := r.contentWidth()
+	pdf.CellFormat(width-30, 5, r.tr(r.layout.FooterText), "", 0, "L", false, 0, "")
+	pdf.CellFormat(30, 5, fmt.Sprintf("Halaman %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	pdf.SetTextColor(0, 0, 0)
+}
+
+// parseHexColor mengubah #RRGGBB menjadi komponen RGB; nilai tidak valid menjadi hitam
+func parseHexColor(color string) (int, int, int) {
+	if !hexColorPattern.MatchString(color) {
+		return 0, 0, 0
+	}
+	value, _ := strconv.ParseUint(color[1:], 16, 32)
+	return int(value >> 16 & 0xff), int(value >> 8 & 0xff), int(value & 0xff)
+}
+
+func transcriptStatusLabel(status string) string {
+	switch status {
+	case "verified":
+		return "Terverifikasi"
+	case "submitted":
+		return "Menunggu verifikasi"
+	default:
+		return status
+	}
+}
+
+// formatTranscriptPoints poin dengan maksimal dua desimal tanpa nol di belakang
+func formatTranscriptPoints(points float64) string {
+	return strconv.FormatFloat(math.Round(points*100)/100, 'f', -1, 64)
+}
+
+// FormatIndonesianDate memformat tanggal seperti "17 Agustus 2025"
+func FormatIndonesianDate(t time.Time) string {
+	return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// TranscriptService menerbitkan transkrip prestasi (SKPI) dalam bentuk PDF dan mengelola template-nya
+type TranscriptService struct {
+	transcriptRepo *repository.TranscriptRepository
+	readModelRepo  *repository.AchievementReadModelRepository
+	studentRepo    *repository.StudentRepository
+	userRepo       *repository.UserRepository
+	masterRepo     *repository.MasterDataRepository
+	creditRepo     *repository.CreditPointRepository
+	verifications  *VerificationRecordService
+}
+
+func NewTranscriptService(mongoDB *mongo.Database, postgresDB *sql.DB) *TranscriptService {
+	return &TranscriptService{
+		transcriptRepo: repository.NewTranscriptRepository(postgresDB),
+		readModelRepo:  repository.NewAchievementReadModelRepository(postgresDB),
+		studentRepo:    repository.NewStudentRepository(postgresDB),
+		userRepo:       repository.NewUserRepository(postgresDB),
+		masterRepo:     repository.NewMasterDataRepository(postgresDB),
+		creditRepo:     repository.NewCreditPointRepository(postgresDB),
+		verifications:  NewVerificationRecordService(mongoDB, postgresDB),
+	}
+}
+
+// templateScope scope dari path (nama program studi boleh mengandung spasi ter-encode), lowercase
+func templateScope(c *fiber.Ctx) string {
+	scope := c.Params("scope")
+	if unescaped, err := url.PathUnescape(scope); err == nil {
+		scope = unescaped
+	}
+	return strings.ToLower(strings.TrimSpace(scope))
+}
+
+// resolveLayout memilih template program studi, lalu template default, lalu DefaultTranscriptLayout
+func (s *TranscriptService) resolveLayout(programStudy string) (models.TranscriptLayout, error) {
+	for _, scope := range []string{strings.ToLower(strings.TrimSpace(programStudy)), models.TranscriptScopeDefault} {
+		if scope == "" {
+			continue
+		}
+		template, err := s.transcriptRepo.FindTemplate(scope)
+		if err != nil {
+			return models.TranscriptLayout{}, err
+		}
+		if template != nil {
+			return ApplyTranscriptDefaults(template.Layout), nil
+		}
+	}
+	return DefaultTranscriptLayout(), nil
+}
+
+// GetStudentTranscript godoc
+// @Summary Download student achievement transcript (PDF)
+// @Description Generate the printable achievement transcript (SKPI supplement) of a student: identity, verified achievements grouped by category and level, points and verification status. The layout follows the transcript template of the student's program study, falling back to the 'default' template. Every download is registered with a public verification code printed as a QR code.
+// @Tags Statistics & Reports
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Student user ID"
+// @Success 200 {file} file "Transcript PDF"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Access denied - not owner, admin, or lecturer"
+// @Failure 404 {object} map[string]interface{} "Student not found"
+// @Failure 500 {object} map[string]interface{} "Failed to generate transcript"
+// @Router /reports/student/{id}/transcript [get]
+func (s *TranscriptService) GetStudentTranscript(c *fiber.Ctx) error {
+	studentID := c.Params("id")
+
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	// Only admin, lecturer, or the student themselves can download
+	if userID != studentID && roleID != "1" && roleID != "2" {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anda tidak memiliki akses ke data ini",
+		})
+	}
+
+	student, err := s.studentRepo.FindByUserID(studentID)
+	if err != nil || student == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Student tidak ditemukan",
+		})
+	}
+
+	user, err := s.userRepo.FindByID(studentID)
+	if err != nil || user == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Student tidak ditemukan",
+		})
+	}
+
+	internalError := func(message string) error {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": message,
+		})
+	}
+
+	layout, err := s.resolveLayout(student.ProgramStudy)
+	if err != nil {
+		return internalError("Gagal mengambil template transkrip")
+	}
+
+	statuses := []string{"verified"}
+	if layout.IncludePending {
+		statuses = append(statuses, "submitted")
+	}
+	entries, err := s.readModelRepo.FindTranscriptEntries(studentID, statuses)
+	if err != nil {
+		return internalError("Gagal mengambil data achievements")
+	}
+
+	creditPoints, err := s.creditRepo.FindPointsByStudent(studentID)
+	if err != nil {
+		return internalError("Gagal mengambil poin achievement")
+	}
+	points := make(map[string]float64, len(creditPoints))
+	for _, point := range creditPoints {
+		points[point.MongoAchievementID] = point.Points
+	}
+
+	categories, err := s.masterRepo.FindAllCategories(false)
+	if err != nil {
+		return internalError("Gagal mengambil master data kategori")
+	}
+	levels, err := s.masterRepo.FindAllLevels(false)
+	if err != nil {
+		return internalError("Gagal mengambil master data level")
+	}
+
+	groups := BuildTranscriptGroups(entries, points, categories, levels)
+
+	data := models.TranscriptData{
+		StudentName:   user.FullName,
+		StudentNumber: student.StudentID,
+		ProgramStudy:  student.ProgramStudy,
+		AcademicYear:  student.AcademicYear,
+		Groups:        groups,
+	}
+
+	withCodes := containsString(layout.Columns, models.TranscriptColumnCode)
+	for g := range data.Groups {
+		for i := range data.Groups[g].Items {
+			item := &data.Groups[g].Items[i]
+			if item.Status != "verified" {
+				continue
+			}
+			data.AchievementCount++
+			data.TotalPoints += item.Points
+
+			if withCodes {
+				// Kode per achievement bersifat pelengkap; kegagalan tidak menggagalkan transkrip
+				record, err := s.verifications.Issue(context.Background(), item.AchievementID)
+				if err != nil {
+					log.Printf("Gagal menerbitkan kode verifikasi achievement %s untuk transkrip: %v", item.AchievementID, err)
+					continue
+				}
+				item.VerificationCode = record.Code
+			}
+		}
+	}
+
+	record, err := s.verifications.IssueTranscript(studentID, user.FullName, data.AchievementCount, data.TotalPoints)
+	if err != nil {
+		return internalError("Gagal menerbitkan kode verifikasi transkrip")
+	}
+	data.VerificationCode = record.Code
+	data.VerificationURL = s.verifications.verifyURL(record.Code)
+	data.IssuedAt = record.IssuedAt.In(time.Local)
+
+	pdf, err := RenderTranscriptPDF(data, layout)
+	if err != nil {
+		log.Printf("Gagal merender transkrip %s: %v", studentID, err)
+		return internalError("Gagal membuat dokumen transkrip")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="transkrip-prestasi-%s.pdf"`, student.StudentID))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(200).Send(pdf)
+}
+
+// GetTranscriptTemplates godoc
+// @Summary Get transcript templates
+// @Description Get all transcript templates. The scope is a lowercase program study name or 'default'.
+// @Tags Transcript Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.TranscriptTemplate} "Transcript templates retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires transcript_templates.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve transcript templates"
+// @Router /transcript-templates [get]
+func (s *TranscriptService) GetTranscriptTemplates(c *fiber.Ctx) error {
+	templates, err := s.transcriptRepo.FindAllTemplates()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil template transkrip",
+		})
+	}
+
+	if templates == nil {
+		templates = []models.TranscriptTemplate{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data template transkrip berhasil diambil",
+		"data":    templates,
+	})
+}
+
+// GetTranscriptTemplate godoc
+// @Summary Get transcript template
+// @Description Get the transcript template of a scope. Scope 'default' returns the built-in layout when it has not been customized.
+// @Tags Transcript Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scope path string true "Program study (lowercase) or 'default'"
+// @Success 200 {object} object{status=string,message=string,data=models.TranscriptTemplate} "Transcript template retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires transcript_templates.manage)"
+// @Failure 404 {object} map[string]interface{} "Transcript template not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve transcript template"
+// @Router /transcript-templates/{scope} [get]
+func (s *TranscriptService) GetTranscriptTemplate(c *fiber.Ctx) error {
+	scope := templateScope(c)
+
+	template, err := s.transcriptRepo.FindTemplate(scope)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil template transkrip",
+		})
+	}
+	if template == nil && scope == models.TranscriptScopeDefault {
+		template = &models.TranscriptTemplate{
+			Scope:  models.TranscriptScopeDefault,
+			Layout: DefaultTranscriptLayout(),
+		}
+	}
+	if template == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Template transkrip tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template transkrip berhasil diambil",
+		"data":    template,
+	})
+}
+
+// UpsertTranscriptTemplate godoc
+// @Summary Create or update transcript template
+// @Description Set the transcript layout of a scope (lowercase program study name or 'default'): page size, orientation, institutional header lines, title, accent color, section order, table columns, signatory and footer. Omitted fields take the built-in default.
+// @Tags Transcript Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scope path string true "Program study (lowercase) or 'default'"
+// @Param request body models.TranscriptLayout true "Transcript layout"
+// @Success 200 {object} object{status=string,message=string,data=models.TranscriptTemplate} "Transcript template saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid layout"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires transcript_templates.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to save transcript template"
+// @Router /transcript-templates/{scope} [put]
+func (s *TranscriptService) UpsertTranscriptTemplate(c *fiber.Ctx) error {
+	scope := templateScope(c)
+
+	// Field yang tidak dikirim tetap bernilai default
+	layout := DefaultTranscriptLayout()
+	if err := c.BodyParser(&layout); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	layout = ApplyTranscriptDefaults(layout)
+	if violations := ValidateTranscriptLayout(layout); len(violations) > 0 {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Layout template transkrip tidak valid",
+			"errors":  violations,
+		})
+	}
+
+	template := &models.TranscriptTemplate{
+		Scope:     scope,
+		Layout:    layout,
+		UpdatedAt: time.Now(),
+	}
+	userID, _ := c.Locals("user_id").(string)
+	if updatedBy, err := uuid.Parse(userID); err == nil {
+		template.UpdatedBy = &updatedBy
+	}
+
+	if err := s.transcriptRepo.UpsertTemplate(template); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan template transkrip",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template transkrip berhasil disimpan",
+		"data":    template,
+	})
+}
+
+// DeleteTranscriptTemplate godoc
+// @Summary Delete transcript template
+// @Description Remove the transcript template of a scope. The program study then falls back to the 'default' template.
+// @Tags Transcript Templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param scope path string true "Program study (lowercase) or 'default'"
+// @Success 200 {object} object{status=string,message=string} "Transcript template deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires transcript_templates.manage)"
+// @Failure 404 {object} map[string]interface{} "Transcript template not found"
+// @Failure 500 {object} map[string]interface{} "Failed to delete transcript template"
+// @Router /transcript-templates/{scope} [delete]
+func (s *TranscriptService) DeleteTranscriptTemplate(c *fiber.Ctx) error {
+	deleted, err := s.transcriptRepo.DeleteTemplate(templateScope(c))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus template transkrip",
+		})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Template transkrip tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Template transkrip berhasil dihapus",
+	})
+}
@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/utils"
+	"database/sql"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type TrashService struct {
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	userRepo        *repository.UserRepository
+	outboxRepo      *repository.OutboxRepository
+	relay           *OutboxRelay
+	retention       time.Duration
+}
+
+func NewTrashService(mongoDB *mongo.Database, postgresDB *sql.DB) *TrashService {
+	return &TrashService{
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(postgresDB),
+		userRepo:        repository.NewUserRepository(postgresDB),
+		outboxRepo:      repository.NewOutboxRepository(postgresDB),
+		relay:           NewOutboxRelay(mongoDB, postgresDB),
+		retention:       TrashRetention(),
+	}
+}
+
+// TrashRetention lama data tersimpan di trash sebelum dihapus permanen (TRASH_RETENTION_DAYS)
+func TrashRetention() time.Duration {
+	return time.Duration(utils.GetEnvInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour
+}
+
+// TrashPurgeAt waktu data di trash akan dihapus permanen; nil jika waktu hapus tidak diketahui
+func TrashPurgeAt(deletedAt *time.Time, retention time.Duration) *time.Time {
+	if deletedAt == nil {
+		return nil
+	}
+	purgeAt := deletedAt.Add(retention)
+	return &purgeAt
+}
+
+// GetTrashedAchievements godoc
+// @Summary List trashed achievements
+// @Description Admin lists soft-deleted achievements, newest first, with the time each one will be purged permanently.
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Success 200 {object} object{status=string,message=string,data=object{achievements=[]models.TrashedAchievement,pagination=models.PaginationMeta}} "Trashed achievements retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires trash.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve trash"
+// @Router /trash/achievements [get]
+func (s *TrashService) GetTrashedAchievements(c *fiber.Ctx) error {
+	page, limit, offset := trashPagination(c)
+
+	achievements, total, err := s.achievementRepo.FindDeleted(context.Background(), limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil trash achievement",
+		})
+	}
+
+	items := make([]models.TrashedAchievement, len(achievements))
+	for i, achievement := range achievements {
+		items[i] = models.TrashedAchievement{
+			Achievement: achievement,
+			PurgeAt:     TrashPurgeAt(achievement.DeletedAt, s.retention),
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Trash achievement berhasil diambil",
+		"data": fiber.Map{
+			"achievements": items,
+			"pagination":   trashPaginationMeta(page, limit, total),
+		},
+	})
+}
+
+// RestoreAchievement godoc
+// @Summary Restore trashed achievement
+// @Description Admin restores a soft-deleted achievement. The PostgreSQL reference is restored together with an outbox event that restores the MongoDB document.
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} object{status=string,message=string,data=models.Achievement} "Achievement restored successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires trash.manage)"
+// @Failure 404 {object} map[string]interface{} "Achievement not found in trash"
+// @Failure 500 {object} map[string]interface{} "Restore failed"
+// @Router /trash/achievements/{id}/restore [post]
+func (s *TrashService) RestoreAchievement(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	ctx := context.Background()
+
+	achievement, err := s.achievementRepo.FindDeletedByID(ctx, achievementID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan di trash",
+		})
+	}
+
+	// Restore reference di PostgreSQL bersama event outbox; MongoDB menyusul lewat relay
+	event, err := NewOutboxEvent(achievementID, models.OutboxAchievementRestored, struct{}{})
+	if err == nil {
+		err = s.outboxRepo.WithinTransaction(func(tx *sql.Tx) error {
+			return s.referenceRepo.RestoreTx(tx, achievementID)
+		}, event)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Achievement tidak ditemukan di trash",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memulihkan achievement",
+		})
+	}
+
+	s.relay.Dispatch(ctx, event)
+	if restored, err := s.achievementRepo.FindByID(ctx, achievementID); err == nil {
+		achievement = restored
+	} else {
+		achievement.IsDeleted = false
+		achievement.DeletedAt = nil
+	}
+
+	c.Set(fiber.HeaderETag, ETag(achievement.Version))
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement berhasil dipulihkan",
+		"data":    achievement,
+	})
+}
+
+// GetTrashedUsers godoc
+// @Summary List trashed users
+// @Description Admin lists soft-deleted users, newest first, with the time each one will be purged permanently.
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Success 200 {object} object{status=string,message=string,data=object{users=[]models.TrashedUser,pagination=models.PaginationMeta}} "Trashed users retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires trash.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve trash"
+// @Router /trash/users [get]
+func (s *TrashService) GetTrashedUsers(c *fiber.Ctx) error {
+	page, limit, offset := trashPagination(c)
+
+	users, total, err := s.userRepo.FindDeleted(limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil trash user",
+		})
+	}
+
+	items := make([]models.TrashedUser, len(users))
+	for i, user := range users {
+		items[i] = models.TrashedUser{
+			User:    user,
+			PurgeAt: TrashPurgeAt(user.DeletedAt, s.retention),
+		}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Trash user berhasil diambil",
+		"data": fiber.Map{
+			"users":      items,
+			"pagination": trashPaginationMeta(page, limit, total),
+		},
+	})
+}
+
+// RestoreUser godoc
+// @Summary Restore trashed user
+// @Description Admin restores a soft-deleted user and reactivates the account. Fails if the username or email has since been taken by another active user.
+// @Tags Trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Success 200 {object} object{status=string,message=string,data=models.User} "User restored successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires trash.manage)"
+// @Failure 404 {object} map[string]interface{} "User not found in trash"
+// @Failure 409 {object} map[string]interface{} "Username or email already used by another user"
+// @Failure 500 {object} map[string]interface{} "Restore failed"
+// @Router /trash/users/{id}/restore [post]
+func (s *TrashService) RestoreUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	user, err := s.userRepo.FindDeletedByID(userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data user",
+		})
+	}
+	if user == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "User tidak ditemukan di trash",
+		})
+	}
+
+	usernameTaken, err := s.userRepo.CheckUsernameExists(user.Username)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengecek username",
+		})
+	}
+	emailTaken, err := s.userRepo.CheckEmailExists(user.Email)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengecek email",
+		})
+	}
+	if usernameTaken || emailTaken {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Username atau email sudah digunakan user lain",
+		})
+	}
+
+	if err := s.userRepo.Restore(userID); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "User tidak ditemukan di trash",
+			})
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Username atau email sudah digunakan user lain",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memulihkan user",
+		})
+	}
+
+	user.DeletedAt = nil
+	user.IsActive = true
+	user.Version++
+
+	c.Set(fiber.HeaderETag, ETag(user.Version))
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User berhasil dipulihkan",
+		"data":    user,
+	})
+}
+
+func trashPagination(c *fiber.Ctx) (page, limit, offset int) {
+	page = c.QueryInt("page", 1)
+	limit = c.QueryInt("limit", 10)
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	return page, limit, (page - 1) * limit
+}
+
+func trashPaginationMeta(page, limit int, total int64) models.PaginationMeta {
+	totalPages := int(total) / limit
+	if int(total)%limit > 0 {
+		totalPages++
+	}
+
+	return models.PaginationMeta{
+		Page:       page,
+		Limit:      limit,
+		TotalItems: total,
+		TotalPages: totalPages,
+	}
+}
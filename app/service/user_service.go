@@ -1,12 +1,15 @@
 package service
 
 import (
+	"context"
 	models "crud-app/app/model"
 	"crud-app/app/repository"
 	"crud-app/app/utils"
 	"crypto/rand"
 	"database/sql"
+	"log"
 	"math/big"
+	"net/mail"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -14,16 +17,27 @@ import (
 )
 
 type UserService struct {
-	userRepo     *repository.UserRepository
-	studentRepo  *repository.StudentRepository
-	lecturerRepo *repository.LecturerRepository
+	userRepo      *repository.UserRepository
+	studentRepo   *repository.StudentRepository
+	lecturerRepo  *repository.LecturerRepository
+	readModelRepo *repository.AchievementReadModelRepository
+	exports       *ExportService
 }
 
 func NewUserService(db *sql.DB) *UserService {
 	return &UserService{
-		userRepo:     repository.NewUserRepository(db),
-		studentRepo:  repository.NewStudentRepository(db),
-		lecturerRepo: repository.NewLecturerRepository(db),
+		userRepo:      repository.NewUserRepository(db),
+		studentRepo:   repository.NewStudentRepository(db),
+		lecturerRepo:  repository.NewLecturerRepository(db),
+		readModelRepo: repository.NewAchievementReadModelRepository(db),
+		exports:       NewExportService(db),
+	}
+}
+
+// refreshStudentReadModel menyalin perubahan data mahasiswa ke achievement read model (kegagalan hanya dicatat)
+func (s *UserService) refreshStudentReadModel(refresh func(id string) error, id string) {
+	if err := refresh(id); err != nil {
+		log.Printf("Read model: gagal memperbarui data mahasiswa %s: %v", id, err)
 	}
 }
 
@@ -202,13 +216,14 @@ func (s *UserService) CreateUser(c *fiber.Ctx) error {
 
 // GetUsers godoc
 // @Summary Get list of users
-// @Description Get paginated list of users with optional role filtering. Admin access required.
+// @Description Get paginated list of users with optional role filtering. Admin access required. Pass cursor (empty for the first page) to page with stable keyset cursors instead of page numbers; cursor pages return next_cursor/prev_cursor and no totals.
 // @Tags User Management
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)" default(1)
 // @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Param cursor query string false "Opaque cursor from next_cursor/prev_cursor; send it empty to start cursor pagination"
 // @Param role_id query string false "Filter by role ID (1=Admin, 2=Lecturer, 3=Student)"
 // @Success 200 {object} object{status=string,message=string,data=object{users=[]models.User,pagination=models.PaginationMeta}} "Users retrieved successfully"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
@@ -228,6 +243,34 @@ func (s *UserService) GetUsers(c *fiber.Ctx) error {
 	}
 	offset := (page - 1) * limit
 
+	// Mode cursor: halaman tidak bergeser ketika user ditambah atau dihapus di antara request
+	if cursorRequested(c) {
+		cursor, errResponse := parseCursor(c)
+		if errResponse != nil {
+			return errResponse()
+		}
+
+		users, next, prev, err := s.userRepo.FindAllByCursor(cursor, limit, roleFilter)
+		if err == repository.ErrInvalidCursor {
+			return invalidCursor(c)
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengambil data users",
+			})
+		}
+
+		return c.Status(200).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Data users berhasil diambil",
+			"data": fiber.Map{
+				"users":      users,
+				"pagination": NewCursorPaginationMeta(limit, next, prev),
+			},
+		})
+	}
+
 	users, total, err := s.userRepo.FindAll(limit, offset, roleFilter)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
@@ -289,6 +332,7 @@ func (s *UserService) GetUserByID(c *fiber.Ctx) error {
 		profile, _ = s.lecturerRepo.FindByUserID(userID)
 	}
 
+	c.Set(fiber.HeaderETag, ETag(user.Version))
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "Data user berhasil diambil",
@@ -307,12 +351,15 @@ func (s *UserService) GetUserByID(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "User ID (UUID)"
+// @Param If-Match header string true "ETag of the user from the last read"
 // @Param request body object{username=string,email=string,full_name=string,role_id=string,is_active=bool} true "User update request"
 // @Success 200 {object} object{status=string,message=string,data=models.User} "User updated successfully"
 // @Failure 400 {object} map[string]interface{} "Invalid request data"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires users.update)"
 // @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 412 {object} map[string]interface{} "User was changed by someone else (If-Match does not match)"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
 // @Failure 500 {object} map[string]interface{} "Update operation failed"
 // @Router /users/{id} [put]
 func (s *UserService) UpdateUser(c *fiber.Ctx) error {
@@ -342,6 +389,11 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	// Perubahan harus berdasarkan versi terbaru (If-Match)
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
+	}
+
 	// Update fields
 	if req.Username != "" {
 		existing.Username = req.Username
@@ -359,12 +411,112 @@ func (s *UserService) UpdateUser(c *fiber.Ctx) error {
 	existing.UpdatedAt = time.Now()
 
 	if err := s.userRepo.Update(userID, existing); err != nil {
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
 		return c.Status(500).JSON(fiber.Map{
 			"status":  "error",
 			"message": "Gagal mengupdate user",
 		})
 	}
+	s.refreshStudentReadModel(s.readModelRepo.RefreshStudent, userID)
 
+	c.Set(fiber.HeaderETag, ETag(existing.Version))
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "User berhasil diupdate",
+		"data":    existing,
+	})
+}
+
+// PatchUser godoc
+// @Summary Patch user
+// @Description Partially update a user with a JSON Merge Patch document (RFC 7396). Omitted fields stay unchanged; every field is validated strictly and all errors are returned per field.
+// @Tags User Management
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID (UUID)"
+// @Param If-Match header string true "ETag of the user from the last read"
+// @Param request body object{username=string,email=string,full_name=string,role_id=string,is_active=bool} true "Merge patch document"
+// @Success 200 {object} object{status=string,message=string,data=models.User} "User updated successfully"
+// @Failure 400 {object} object{status=string,message=string,errors=[]models.FieldError} "Invalid patch or field-level validation errors"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires users.update)"
+// @Failure 404 {object} map[string]interface{} "User not found"
+// @Failure 412 {object} map[string]interface{} "User was changed by someone else (If-Match does not match)"
+// @Failure 409 {object} map[string]interface{} "Username or email already used"
+// @Failure 415 {object} map[string]interface{} "Content-Type is not application/merge-patch+json"
+// @Failure 428 {object} map[string]interface{} "If-Match header is required"
+// @Failure 500 {object} map[string]interface{} "Update operation failed"
+// @Router /users/{id} [patch]
+func (s *UserService) PatchUser(c *fiber.Ctx) error {
+	userID := c.Params("id")
+
+	patch, errResp := readMergePatch(c)
+	if errResp != nil {
+		return errResp()
+	}
+
+	existing, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "User tidak ditemukan",
+		})
+	}
+
+	if errResp := requireIfMatch(c, existing.Version); errResp != nil {
+		return errResp()
+	}
+
+	patch.Allow("username", "email", "full_name", "role_id", "is_active")
+	patch.String("username", &existing.Username, true)
+	patch.String("full_name", &existing.FullName, true)
+	patch.Bool("is_active", &existing.IsActive)
+
+	if patch.String("email", &existing.Email, true) {
+		if _, err := mail.ParseAddress(existing.Email); err != nil {
+			patch.AddError("email", "Format email tidak valid")
+		}
+	}
+
+	if patch.String("role_id", &existing.RoleID, true) {
+		exists, err := s.userRepo.CheckRoleExists(existing.RoleID)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengecek role",
+			})
+		}
+		if !exists {
+			patch.AddError("role_id", "Role tidak ditemukan")
+		}
+	}
+
+	if errResp := mergePatchErrors(c, patch); errResp != nil {
+		return errResp()
+	}
+
+	existing.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(userID, existing); err != nil {
+		if err == repository.ErrVersionConflict {
+			return versionConflict(c, 0)
+		}
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Username atau email sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate user",
+		})
+	}
+	s.refreshStudentReadModel(s.readModelRepo.RefreshStudent, userID)
+
+	c.Set(fiber.HeaderETag, ETag(existing.Version))
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
 		"message": "User berhasil diupdate",
@@ -604,6 +756,7 @@ func (s *UserService) UpdateStudentProfile(c *fiber.Ctx) error {
 			"message": "Gagal mengupdate student profile",
 		})
 	}
+	s.refreshStudentReadModel(s.readModelRepo.RefreshStudentProfile, studentID)
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
@@ -611,6 +764,85 @@ func (s *UserService) UpdateStudentProfile(c *fiber.Ctx) error {
 	})
 }
 
+// PatchStudentProfile godoc
+// @Summary Patch student profile
+// @Description Partially update a student profile with a JSON Merge Patch document (RFC 7396). Omitted fields stay unchanged; all fields are required and cannot be cleared.
+// @Tags Student Management
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Student ID"
+// @Param request body object{student_id=string,program_study=string,academic_year=string} true "Merge patch document"
+// @Success 200 {object} object{status=string,message=string,data=models.StudentDetail} "Student profile updated successfully"
+// @Failure 400 {object} object{status=string,message=string,errors=[]models.FieldError} "Invalid patch or field-level validation errors"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Student not found"
+// @Failure 409 {object} map[string]interface{} "Student ID already used"
+// @Failure 415 {object} map[string]interface{} "Content-Type is not application/merge-patch+json"
+// @Failure 500 {object} map[string]interface{} "Update operation failed"
+// @Router /students/{id}/profile [patch]
+func (s *UserService) PatchStudentProfile(c *fiber.Ctx) error {
+	studentID := c.Params("id")
+
+	patch, errResp := readMergePatch(c)
+	if errResp != nil {
+		return errResp()
+	}
+
+	existing, err := s.studentRepo.FindByID(studentID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data student",
+		})
+	}
+	if existing == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Student tidak ditemukan",
+		})
+	}
+
+	student := &models.Student{
+		StudentID:    existing.StudentID,
+		ProgramStudy: existing.ProgramStudy,
+		AcademicYear: existing.AcademicYear,
+		AdvisorID:    existing.AdvisorID,
+	}
+
+	patch.Allow("student_id", "program_study", "academic_year")
+	patch.String("student_id", &student.StudentID, true)
+	patch.String("program_study", &student.ProgramStudy, true)
+	patch.String("academic_year", &student.AcademicYear, true)
+
+	if errResp := mergePatchErrors(c, patch); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.studentRepo.Update(studentID, student); err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Student ID sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate student profile",
+		})
+	}
+	s.refreshStudentReadModel(s.readModelRepo.RefreshStudentProfile, studentID)
+
+	updated, _ := s.studentRepo.FindByID(studentID)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Student profile berhasil diupdate",
+		"data":    updated,
+	})
+}
+
 // AssignAdvisor godoc
 // @Summary Assign advisor to student
 // @Description Assign a lecturer as advisor to a student. Validates that the advisor is a valid lecturer.
@@ -669,6 +901,7 @@ func (s *UserService) AssignAdvisor(c *fiber.Ctx) error {
 			"message": "Gagal assign advisor",
 		})
 	}
+	s.refreshStudentReadModel(s.readModelRepo.RefreshStudentProfile, studentID)
 
 	return c.Status(200).JSON(fiber.Map{
 		"status":  "success",
@@ -796,6 +1029,81 @@ func (s *UserService) UpdateLecturerProfile(c *fiber.Ctx) error {
 	})
 }
 
+// PatchLecturerProfile godoc
+// @Summary Patch lecturer profile
+// @Description Partially update a lecturer profile with a JSON Merge Patch document (RFC 7396). Omitted fields stay unchanged; all fields are required and cannot be cleared.
+// @Tags Lecturer Management
+// @Accept application/merge-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Lecturer ID"
+// @Param request body object{lecturer_id=string,department=string} true "Merge patch document"
+// @Success 200 {object} object{status=string,message=string,data=models.LecturerDetail} "Lecturer profile updated successfully"
+// @Failure 400 {object} object{status=string,message=string,errors=[]models.FieldError} "Invalid patch or field-level validation errors"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions"
+// @Failure 404 {object} map[string]interface{} "Lecturer not found"
+// @Failure 409 {object} map[string]interface{} "Lecturer ID already used"
+// @Failure 415 {object} map[string]interface{} "Content-Type is not application/merge-patch+json"
+// @Failure 500 {object} map[string]interface{} "Update operation failed"
+// @Router /lecturers/{id}/profile [patch]
+func (s *UserService) PatchLecturerProfile(c *fiber.Ctx) error {
+	lecturerID := c.Params("id")
+
+	patch, errResp := readMergePatch(c)
+	if errResp != nil {
+		return errResp()
+	}
+
+	existing, err := s.lecturerRepo.FindByID(lecturerID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data lecturer",
+		})
+	}
+	if existing == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Lecturer tidak ditemukan",
+		})
+	}
+
+	lecturer := &models.Lecturer{
+		LecturerID: existing.LecturerID,
+		Department: existing.Department,
+	}
+
+	patch.Allow("lecturer_id", "department")
+	patch.String("lecturer_id", &lecturer.LecturerID, true)
+	patch.String("department", &lecturer.Department, true)
+
+	if errResp := mergePatchErrors(c, patch); errResp != nil {
+		return errResp()
+	}
+
+	if err := s.lecturerRepo.Update(lecturerID, lecturer); err != nil {
+		if isUniqueViolation(err) {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Lecturer ID sudah digunakan",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengupdate lecturer profile",
+		})
+	}
+
+	updated, _ := s.lecturerRepo.FindByID(lecturerID)
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Lecturer profile berhasil diupdate",
+		"data":    updated,
+	})
+}
+
 // Helper function to generate random password
 func generateRandomPassword(length int) string {
 	const charset = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()"
@@ -809,13 +1117,14 @@ func generateRandomPassword(length int) string {
 
 // GetStudents godoc
 // @Summary Get list of students
-// @Description Get paginated list of students with their profile information.
+// @Description Get paginated list of students with their profile information. Pass cursor (empty for the first page) to page with stable keyset cursors instead of page numbers; cursor pages return next_cursor/prev_cursor and no totals.
 // @Tags Student Management
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param page query int false "Page number (default: 1)" default(1)
 // @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Param cursor query string false "Opaque cursor from next_cursor/prev_cursor; send it empty to start cursor pagination"
 // @Success 200 {object} object{status=string,message=string,data=object{students=[]models.Student,pagination=models.PaginationMeta}} "Students retrieved successfully"
 // @Failure 401 {object} map[string]interface{} "Unauthorized"
 // @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires students.read)"
@@ -833,6 +1142,34 @@ func (s *UserService) GetStudents(c *fiber.Ctx) error {
 	}
 	offset := (page - 1) * limit
 
+	// Mode cursor: halaman tidak bergeser ketika student ditambah atau dihapus di antara request
+	if cursorRequested(c) {
+		cursor, errResponse := parseCursor(c)
+		if errResponse != nil {
+			return errResponse()
+		}
+
+		students, next, prev, err := s.studentRepo.FindAllByCursor(cursor, limit)
+		if err == repository.ErrInvalidCursor {
+			return invalidCursor(c)
+		}
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengambil data students",
+			})
+		}
+
+		return c.Status(200).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Data students berhasil diambil",
+			"data": fiber.Map{
+				"students":   students,
+				"pagination": NewCursorPaginationMeta(limit, next, prev),
+			},
+		})
+	}
+
 	students, total, err := s.studentRepo.FindAll(limit, offset)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{
@@ -861,6 +1198,65 @@ func (s *UserService) GetStudents(c *fiber.Ctx) error {
 	})
 }
 
+// ExportStudents godoc
+// @Summary Export students
+// @Description Export all students (NIM, name, study program, academic year and advisor) as CSV or XLSX. Exports larger than EXPORT_ASYNC_THRESHOLD rows (or async=true) run in the background and return 202 with an export job to poll at /exports/{id}.
+// @Tags Exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param async query bool false "Always run the export in the background"
+// @Success 200 {file} file "CSV or XLSX file"
+// @Success 202 {object} object{status=string,message=string,data=models.ExportJob} "Export is running in the background"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires students.read)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve students"
+// @Router /students/export [get]
+func (s *UserService) ExportStudents(c *fiber.Ctx) error {
+	_, total, err := s.studentRepo.FindAll(1, 0)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data students",
+		})
+	}
+
+	table := ExportTable{
+		Name:   "students",
+		Header: studentExportHeader,
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			var cursor *models.PageCursor
+			for {
+				students, next, _, err := s.studentRepo.FindAllByCursor(cursor, exportPageSize)
+				if err != nil {
+					return err
+				}
+				for _, student := range students {
+					if err := emit(studentExportRow(student)); err != nil {
+						return err
+					}
+				}
+				if next == nil {
+					return nil
+				}
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				cursor = next
+			}
+		},
+	}
+
+	return s.exports.Send(c, "students", table, total)
+}
+
+var studentExportHeader = []string{"NIM", "Nama", "Program Studi", "Angkatan", "Dosen Wali"}
+
+func studentExportRow(student models.StudentDetail) []interface{} {
+	return []interface{}{student.StudentID, student.FullName, student.ProgramStudy, student.AcademicYear, student.AdvisorName}
+}
+
 // GetStudentByID godoc
 // @Summary Get student by ID
 // @Description Get detailed information about a specific student including user data and profile.
@@ -1002,4 +1398,56 @@ func (s *UserService) GetAdvisees(c *fiber.Ctx) error {
 			"total":    len(advisees),
 		},
 	})
+}
+
+// ExportAdvisees godoc
+// @Summary Export lecturer advisees
+// @Description Export the students advised by a specific lecturer as CSV or XLSX.
+// @Tags Exports
+// @Produce octet-stream
+// @Security BearerAuth
+// @Param id path string true "Lecturer ID"
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param async query bool false "Run the export in the background"
+// @Success 200 {file} file "CSV or XLSX file"
+// @Success 202 {object} object{status=string,message=string,data=models.ExportJob} "Export is running in the background"
+// @Failure 400 {object} map[string]interface{} "Invalid format"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires lecturers.read)"
+// @Failure 404 {object} map[string]interface{} "Lecturer not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve advisees"
+// @Router /lecturers/{id}/advisees/export [get]
+func (s *UserService) ExportAdvisees(c *fiber.Ctx) error {
+	lecturerID := c.Params("id")
+
+	if _, err := s.lecturerRepo.FindByID(lecturerID); err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Lecturer tidak ditemukan",
+		})
+	}
+
+	// Jumlah mahasiswa bimbingan satu dosen kecil, sehingga cukup diambil sekaligus
+	advisees, err := s.studentRepo.FindByAdvisorID(lecturerID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data advisees",
+		})
+	}
+
+	table := ExportTable{
+		Name:   "advisees",
+		Header: studentExportHeader,
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			for _, student := range advisees {
+				if err := emit(studentExportRow(student)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+
+	return s.exports.Send(c, "advisees", table, int64(len(advisees)))
 }
\ No newline at end of file
@@ -0,0 +1,330 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Batas delegasi verifikasi
+const (
+	maxDelegationDuration  = 180 * 24 * time.Hour
+	maxDelegationReasonLen = 500
+)
+
+// VerificationDelegationService mengelola pelimpahan verifikasi mahasiswa bimbingan ke dosen lain;
+// selama delegasi berlaku, mahasiswa bimbingan delegator masuk inbox verifikasi delegate
+type VerificationDelegationService struct {
+	delegationRepo *repository.VerificationDelegationRepository
+	lecturerRepo   *repository.LecturerRepository
+}
+
+func NewVerificationDelegationService(db *sql.DB) *VerificationDelegationService {
+	return &VerificationDelegationService{
+		delegationRepo: repository.NewVerificationDelegationRepository(db),
+		lecturerRepo:   repository.NewLecturerRepository(db),
+	}
+}
+
+// ParseDelegationPeriod membaca periode delegasi (YYYY-MM-DD atau RFC3339). starts_at kosong berarti now;
+// ends_at berupa tanggal saja berlaku sampai akhir hari tersebut. Periode harus berakhir setelah now
+// dan paling lama 180 hari.
+func ParseDelegationPeriod(startsAt, endsAt string, now time.Time) (time.Time, time.Time, error) {
+	parse := func(value string, endOfDay bool) (time.Time, error) {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t, nil
+		}
+		date, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("format tanggal '%s' tidak valid, gunakan YYYY-MM-DD atau RFC3339", value)
+		}
+		if endOfDay {
+			date = date.AddDate(0, 0, 1)
+		}
+		return date, nil
+	}
+
+	start := now
+	if value := strings.TrimSpace(startsAt); value != "" {
+		parsed, err := parse(value, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = parsed
+	}
+
+	value := strings.TrimSpace(endsAt)
+	if value == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("ends_at wajib diisi")
+	}
+	end, err := parse(value, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("ends_at harus setelah starts_at")
+	}
+	if !end.After(now) {
+		return time.Time{}, time.Time{}, fmt.Errorf("ends_at sudah lewat")
+	}
+	if end.Sub(start) > maxDelegationDuration {
+		return time.Time{}, time.Time{}, fmt.Errorf("Delegasi paling lama %d hari", int(maxDelegationDuration.Hours()/24))
+	}
+	return start, end, nil
+}
+
+// GetDelegations godoc
+// @Summary List verification delegations
+// @Description Delegations created by or given to the caller; admins see every delegation. Ended and revoked delegations are included only with include_ended=true.
+// @Tags Verification Delegations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param include_ended query bool false "Include ended and revoked delegations"
+// @Success 200 {object} object{status=string,message=string,data=[]models.VerificationDelegation} "Delegations retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve delegations"
+// @Router /verification-delegations [get]
+func (s *VerificationDelegationService) GetDelegations(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+	includeEnded := c.QueryBool("include_ended", false)
+
+	var delegations []models.VerificationDelegation
+	var err error
+	if roleID == "1" {
+		delegations, err = s.delegationRepo.FindAll(includeEnded)
+	} else {
+		delegations, err = s.delegationRepo.FindForUser(userID, includeEnded)
+	}
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data delegasi verifikasi",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data delegasi verifikasi berhasil diambil",
+		"data":    delegations,
+	})
+}
+
+// CreateDelegation godoc
+// @Summary Delegate verification to another lecturer
+// @Description Delegate verification of the caller's advisees to another lecturer for a period (e.g. during leave, max 180 days). While active, those advisees appear in the delegate's verification inbox as well. Admins can create a delegation on behalf of a lecturer with delegator_id.
+// @Tags Verification Delegations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateDelegationRequest true "Delegate and period"
+// @Success 201 {object} object{status=string,message=string,data=models.VerificationDelegation} "Delegation created"
+// @Failure 400 {object} map[string]interface{} "Invalid delegate or period"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Only admins can delegate on behalf of another lecturer"
+// @Failure 409 {object} map[string]interface{} "An overlapping delegation to the same lecturer already exists"
+// @Failure 500 {object} map[string]interface{} "Failed to create delegation"
+// @Router /verification-delegations [post]
+func (s *VerificationDelegationService) CreateDelegation(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(string)
+	creator, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+
+	var req models.CreateDelegationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	delegator := creator
+	if req.DelegatorID != "" && req.DelegatorID != userID {
+		if roleID != "1" {
+			return c.Status(403).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Forbidden: Hanya admin yang bisa membuat delegasi atas nama dosen lain",
+			})
+		}
+		if delegator, err = uuid.Parse(req.DelegatorID); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": "delegator_id tidak valid",
+			})
+		}
+	}
+
+	delegate, err := uuid.Parse(req.DelegateID)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "delegate_id tidak valid",
+		})
+	}
+	if delegate == delegator {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tidak bisa mendelegasikan verifikasi ke diri sendiri",
+		})
+	}
+
+	for _, check := range []struct {
+		id      uuid.UUID
+		message string
+	}{
+		{delegator, "Delegator harus dosen"},
+		{delegate, "Penerima delegasi harus dosen"},
+	} {
+		exists, err := s.lecturerRepo.CheckExists(check.id.String())
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Gagal mengambil data dosen",
+			})
+		}
+		if !exists {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": check.message,
+			})
+		}
+	}
+
+	now := time.Now()
+	startsAt, endsAt, err := ParseDelegationPeriod(req.StartsAt, req.EndsAt, now)
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	delegation := &models.VerificationDelegation{
+		ID:          uuid.New(),
+		DelegatorID: delegator,
+		DelegateID:  delegate,
+		StartsAt:    startsAt,
+		EndsAt:      endsAt,
+		CreatedBy:   &creator,
+		CreatedAt:   now,
+	}
+	if reason := strings.TrimSpace(req.Reason); reason != "" {
+		if utf8.RuneCountInString(reason) > maxDelegationReasonLen {
+			return c.Status(400).JSON(fiber.Map{
+				"status":  "error",
+				"message": fmt.Sprintf("Alasan maksimal %d karakter", maxDelegationReasonLen),
+			})
+		}
+		delegation.Reason = &reason
+	}
+
+	overlap, err := s.delegationRepo.HasOverlap(delegation)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memeriksa delegasi yang ada",
+		})
+	}
+	if overlap {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Sudah ada delegasi ke dosen yang sama pada periode tersebut",
+		})
+	}
+
+	if err := s.delegationRepo.Create(delegation); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal membuat delegasi verifikasi",
+		})
+	}
+
+	if created, err := s.delegationRepo.FindByID(delegation.ID.String()); err == nil && created != nil {
+		delegation = created
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Delegasi verifikasi berhasil dibuat",
+		"data":    delegation,
+	})
+}
+
+// RevokeDelegation godoc
+// @Summary Revoke verification delegation
+// @Description End a delegation early. The delegator, the delegate (to decline it) or an admin can revoke it.
+// @Tags Verification Delegations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Delegation ID"
+// @Success 200 {object} map[string]interface{} "Delegation revoked"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 404 {object} map[string]interface{} "Delegation not found"
+// @Failure 409 {object} map[string]interface{} "Delegation was already revoked"
+// @Failure 500 {object} map[string]interface{} "Failed to revoke delegation"
+// @Router /verification-delegations/{id} [delete]
+func (s *VerificationDelegationService) RevokeDelegation(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+
+	delegation, err := s.delegationRepo.FindByID(c.Params("id"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data delegasi verifikasi",
+		})
+	}
+	if delegation == nil || (roleID != "1" && delegation.DelegatorID.String() != userID && delegation.DelegateID.String() != userID) {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Delegasi verifikasi tidak ditemukan",
+		})
+	}
+
+	if err := s.delegationRepo.Revoke(delegation.ID.String()); err != nil {
+		if err == repository.ErrVersionConflict {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Delegasi verifikasi sudah dicabut",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mencabut delegasi verifikasi",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Delegasi verifikasi berhasil dicabut",
+	})
+}
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ValidateInboxFilter memvalidasi status klaim dan urutan inbox verifikasi
+func ValidateInboxFilter(filter models.InboxFilter) error {
+	switch filter.State {
+	case "", models.InboxStateUnclaimed, models.InboxStateClaimedByMe, models.InboxStateClaimedByOthers:
+	default:
+		return fmt.Errorf("Invalid state filter. Valid values: %s, %s, %s",
+			models.InboxStateUnclaimed, models.InboxStateClaimedByMe, models.InboxStateClaimedByOthers)
+	}
+
+	switch filter.SortBy {
+	case "age", "level", "student":
+	default:
+		return fmt.Errorf("Invalid sort_by. Valid values: age, level, student")
+	}
+
+	switch filter.SortOrder {
+	case "", "asc", "desc":
+	default:
+		return fmt.Errorf("Invalid sort_order. Valid values: asc, desc")
+	}
+	return nil
+}
+
+// InboxWaitingHours lama achievement menunggu verifikasi dalam jam penuh
+func InboxWaitingHours(submittedAt *time.Time, now time.Time) int {
+	if submittedAt == nil || now.Before(*submittedAt) {
+		return 0
+	}
+	return int(now.Sub(*submittedAt).Hours())
+}
+
+// PendingApprovalStage tahap verifikasi yang sedang menunggu keputusan tanpa mengubah data; achievement
+// tanpa rantai verifikasi memakai tahap default. nil jika semua tahap sudah diputuskan.
+func PendingApprovalStage(approvals []models.AchievementApproval) *models.AchievementApproval {
+	if len(approvals) == 0 {
+		return &models.AchievementApproval{
+			StageOrder: DefaultVerificationStage.StageOrder,
+			StageName:  DefaultVerificationStage.Name,
+			Permission: DefaultVerificationStage.Permission,
+			Status:     "pending",
+		}
+	}
+
+	for i := range approvals {
+		if approvals[i].Status == "pending" {
+			return &approvals[i]
+		}
+	}
+	return nil
+}
+
+// ApprovalStageReached true jika rantai verifikasi memuat tahap dengan permission tersebut dan tahap itu
+// sudah diputuskan atau sedang berjalan (semua tahap sebelumnya approved)
+func ApprovalStageReached(approvals []models.AchievementApproval, permission string) bool {
+	for i, approval := range approvals {
+		if approval.Permission != permission {
+			continue
+		}
+		if approval.Status != "pending" {
+			return true
+		}
+		reached := true
+		for _, previous := range approvals[:i] {
+			if previous.Status != "approved" {
+				reached = false
+				break
+			}
+		}
+		if reached {
+			return true
+		}
+	}
+	return false
+}
+
+// inboxScope dosen wali yang mahasiswanya boleh ditangani user: dirinya sendiri dan dosen yang sedang
+// mendelegasikan verifikasi kepadanya. nil berarti semua mahasiswa (admin dan verifikator fakultas).
+func (s *AchievementService) inboxScope(userID, roleID string) ([]string, error) {
+	if roleID == "1" {
+		return nil, nil
+	}
+
+	faculty, err := userHasPermission(s.permRepo, userID, "achievements.verify_faculty")
+	if err != nil {
+		return nil, err
+	}
+	if faculty {
+		return nil, nil
+	}
+
+	delegators, err := s.delegationRepo.FindActiveDelegatorIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string{userID}, delegators...), nil
+}
+
+// reviewAccess memastikan achievement milik mahasiswa dalam scope inbox user. Mengembalikan data
+// mahasiswa (nil jika profil tidak ditemukan) atau fungsi response error.
+func (s *AchievementService) reviewAccess(c *fiber.Ctx, achievement *models.Achievement) (*models.StudentDetail, func() error) {
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	student, reviewErr := s.reviewScope(userID, roleID, achievement)
+	if reviewErr != nil {
+		return nil, func() error {
+			return c.Status(reviewErr.status).JSON(fiber.Map{
+				"status":  "error",
+				"message": reviewErr.message,
+			})
+		}
+	}
+	return student, nil
+}
+
+// reviewScope aturan scope inbox untuk satu achievement: mahasiswanya harus bimbingan user atau dosen
+// yang sedang mendelegasikan verifikasi kepadanya (admin dan verifikator fakultas tidak dibatasi)
+func (s *AchievementService) reviewScope(userID, roleID string, achievement *models.Achievement) (*models.StudentDetail, *reviewError) {
+	scope, err := s.inboxScope(userID, roleID)
+	if err != nil {
+		return nil, &reviewError{500, "Gagal mengambil cakupan inbox verifikasi"}
+	}
+
+	var student *models.StudentDetail
+	profile, err := s.studentRepo.FindByUserID(achievement.StudentID)
+	if err == nil && profile != nil {
+		student, err = s.studentRepo.FindByID(profile.ID)
+	}
+	if err != nil {
+		return nil, &reviewError{500, "Gagal mengambil data mahasiswa"}
+	}
+
+	if scope != nil && (student == nil || !containsString(scope, student.AdvisorID)) {
+		return nil, &reviewError{403, "Forbidden: Achievement ini bukan milik mahasiswa bimbingan atau delegasi Anda"}
+	}
+
+	return student, nil
+}
+
+// decisionAccess aturan untuk klaim dan keputusan (approve/reject, tunggal maupun bulk): achievement
+// harus dalam scope inbox user dan user memegang permission tahap yang sedang menunggu
+func (s *AchievementService) decisionAccess(userID, roleID string, achievement *models.Achievement, stage *models.AchievementApproval) *reviewError {
+	if _, reviewErr := s.reviewScope(userID, roleID, achievement); reviewErr != nil {
+		return reviewErr
+	}
+	return s.checkStagePermission(userID, stage)
+}
+
+// CheckDecisionAccess seperti decisionAccess; error yang dikembalikan membawa status HTTP (HTTPStatus)
+func (s *AchievementService) CheckDecisionAccess(userID, roleID string, achievement *models.Achievement, stage *models.AchievementApproval) error {
+	if reviewErr := s.decisionAccess(userID, roleID, achievement, stage); reviewErr != nil {
+		return reviewErr
+	}
+	return nil
+}
+
+// checkReviewClaim menolak keputusan jika achievement sedang diklaim verifikator lain
+func (s *AchievementService) checkReviewClaim(achievementID, userID string) *reviewError {
+	claim, err := s.claimRepo.FindActive(achievementID)
+	if err != nil {
+		return &reviewError{500, "Gagal mengambil klaim review"}
+	}
+	if claim != nil && claim.ClaimedBy.String() != userID {
+		return &reviewError{409, fmt.Sprintf("Achievement sedang direview oleh %s hingga %s", claim.ClaimerName, claim.ExpiresAt.Format("15:04"))}
+	}
+	return nil
+}
+
+// checkStagePermission menolak user yang tidak memegang permission tahap verifikasi yang sedang menunggu
+// (aturan yang sama dengan filter StagePermissions inbox)
+func (s *AchievementService) checkStagePermission(userID string, stage *models.AchievementApproval) *reviewError {
+	allowed, err := userHasPermission(s.permRepo, userID, stage.Permission)
+	if err != nil {
+		return &reviewError{500, "Gagal mengambil permissions"}
+	}
+	if !allowed {
+		return &reviewError{403, fmt.Sprintf("Forbidden: Tahap verifikasi '%s' memerlukan permission '%s'", stage.StageName, stage.Permission)}
+	}
+	return nil
+}
+
+// releaseReviewClaim melepas klaim setelah tahap diputuskan; kegagalan hanya dicatat karena
+// klaim akan kedaluwarsa dengan sendirinya
+func (s *AchievementService) releaseReviewClaim(achievementID string) {
+	if err := s.claimRepo.DeleteByAchievement(achievementID); err != nil {
+		log.Printf("Gagal melepas klaim review achievement %s: %v", achievementID, err)
+	}
+}
+
+// GetPendingVerification godoc
+// @Summary Get verification inbox
+// @Description Lecturer verification inbox: submitted achievements of the caller's advisees and of advisors who currently delegate verification to the caller (admins and faculty verifiers see every student). Only achievements whose pending verification stage the caller has the permission to decide are listed. Includes the active review claim of each item and counts per claim state. Sort by age (longest waiting first), level (highest level first) or student (name A-Z); sort_order reverses the default direction.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 10, max: 100)" default(10)
+// @Param state query string false "Claim state (unclaimed, claimed_by_me, claimed_by_others)"
+// @Param category query string false "Filter by category code"
+// @Param level query string false "Filter by level code"
+// @Param student_id query string false "Filter by student user ID"
+// @Param program_study query string false "Filter by the student's study program"
+// @Param search query string false "Search in title, student name and student number"
+// @Param submitted_from query string false "Submitted date from (YYYY-MM-DD)"
+// @Param submitted_to query string false "Submitted date to (YYYY-MM-DD)"
+// @Param sort_by query string false "Sort by (age, level, student)" default(age)
+// @Param sort_order query string false "Sort order (asc, desc)"
+// @Success 200 {object} object{status=string,message=string,data=object{achievements=[]models.InboxItem,counts=models.InboxCounts,pagination=object,filters=object}} "Verification inbox retrieved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid filter or sort parameter"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires achievements.verify or achievements.verify_faculty)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve verification inbox"
+// @Router /achievements/pending [get]
+func (s *AchievementService) GetPendingVerification(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+
+	// Get pagination params
+	page := c.QueryInt("page", 1)
+	limit := c.QueryInt("limit", 10)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	offset := (page - 1) * limit
+
+	filter := models.InboxFilter{
+		UserID:       userID,
+		State:        c.Query("state"),
+		StudentID:    c.Query("student_id"),
+		ProgramStudy: strings.TrimSpace(c.Query("program_study")),
+		Search:       strings.TrimSpace(c.Query("search")),
+		SortBy:       c.Query("sort_by", "age"),
+		SortOrder:    strings.ToLower(c.Query("sort_order")),
+	}
+	if err := ValidateInboxFilter(filter); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	// Category dan level dicocokkan dengan code kanonik master data
+	var err error
+	if filter.Category, filter.Level, err = s.resolveMasterData(c.Query("category"), c.Query("level")); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+
+	if filter.SubmittedFrom, filter.SubmittedTo, err = ParseDateRange(c.Query("submitted_from"), c.Query("submitted_to")); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("Parameter submitted_from/submitted_to tidak valid: %v", err),
+		})
+	}
+
+	if filter.AdvisorIDs, err = s.inboxScope(userID, roleID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil cakupan inbox verifikasi",
+		})
+	}
+
+	// Hanya achievement yang tahap pending-nya bisa diputuskan user, sama seperti aturan approve/reject
+	permissions, err := userPermissions(s.permRepo, userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil permissions",
+		})
+	}
+	filter.StagePermissions = append([]string{}, permissions...)
+	filter.DefaultStagePermission = DefaultVerificationStage.Permission
+
+	items, total, err := s.readModelRepo.FindInbox(filter, limit, offset)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data pending verification",
+		})
+	}
+
+	counts, err := s.readModelRepo.CountInbox(filter)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghitung data pending verification",
+		})
+	}
+
+	now := time.Now()
+	for i := range items {
+		items[i].WaitingHours = InboxWaitingHours(items[i].SubmittedAt, now)
+		items[i].Delegated = filter.AdvisorIDs != nil && items[i].AdvisorID != userID
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data pending verification berhasil diambil",
+		"data": fiber.Map{
+			"achievements": items,
+			"counts":       counts,
+			"pagination": fiber.Map{
+				"page":       page,
+				"limit":      limit,
+				"total":      total,
+				"total_page": (total + int64(limit) - 1) / int64(limit),
+			},
+			"filters": fiber.Map{
+				"state":          filter.State,
+				"category":       filter.Category,
+				"level":          filter.Level,
+				"student_id":     filter.StudentID,
+				"program_study":  filter.ProgramStudy,
+				"search":         filter.Search,
+				"submitted_from": c.Query("submitted_from"),
+				"submitted_to":   c.Query("submitted_to"),
+				"sort_by":        filter.SortBy,
+				"sort_order":     filter.SortOrder,
+			},
+		},
+	})
+}
+
+// ReviewAchievementDetail godoc
+// @Summary Review achievement detail
+// @Description Everything a verifier needs to decide on an achievement: the full achievement with documents, the student profile and achievement statistics, verification stages and the stage waiting for a decision, escalations, likely duplicates flagged on submission, and the active review claim. can_decide tells whether the caller can approve or reject right now (decision_note explains why not). Only available for students in the caller's inbox scope.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} object{status=string,message=string,data=object{achievement=models.Achievement,reference=object,student=models.StudentDetail,student_statistics=object,approvals=[]models.AchievementApproval,current_stage=models.AchievementApproval,escalations=[]models.VerificationEscalation,duplicates=[]models.DuplicateMatch,claim=models.ReviewClaim,can_decide=bool,decision_note=string}} "Achievement details retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Achievement is outside the caller's advisees and delegations"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve achievement details"
+// @Router /achievements/{id}/review [get]
+func (s *AchievementService) ReviewAchievementDetail(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	ctx := context.Background()
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+
+	student, errResponse := s.reviewAccess(c, achievement)
+	if errResponse != nil {
+		return errResponse()
+	}
+
+	// Get reference data untuk info tambahan
+	reference, err := s.referenceRepo.FindByMongoID(achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil reference data",
+		})
+	}
+
+	approvals, err := s.approvalRepo.FindByMongoID(achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil tahap verifikasi",
+		})
+	}
+
+	escalations, err := s.slaRepo.FindEscalationsByMongoID(achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil riwayat eskalasi",
+		})
+	}
+
+	duplicates, err := s.flaggedDuplicates(ctx, achievement)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data kemungkinan duplikat",
+		})
+	}
+
+	claim, err := s.claimRepo.FindActive(achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil klaim review",
+		})
+	}
+
+	// Riwayat prestasi mahasiswa sebagai konteks keputusan
+	statistics, err := s.readModelRepo.GetStatistics([]string{achievement.StudentID})
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil statistik prestasi mahasiswa",
+		})
+	}
+
+	var stage *models.AchievementApproval
+	if achievement.Status == "submitted" {
+		stage = PendingApprovalStage(approvals)
+	}
+	decisionNote, err := s.reviewDecisionNote(achievement, stage, approvals, claim, userID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil permissions",
+		})
+	}
+
+	c.Set(fiber.HeaderETag, ETag(achievement.Version))
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data achievement berhasil diambil",
+		"data": fiber.Map{
+			"achievement":        achievement,
+			"reference":          reference,
+			"student":            student,
+			"student_statistics": statistics,
+			"approvals":          approvals,
+			"current_stage":      stage,
+			"escalations":        escalations,
+			"duplicates":         duplicates,
+			"claim":              claim,
+			"can_decide":         decisionNote == "",
+			"decision_note":      decisionNote,
+		},
+	})
+}
+
+// reviewDecisionNote alasan user belum bisa approve/reject achievement; kosong jika bisa.
+// Aturannya sama dengan approveAchievement dan rejectAchievement (scope inbox sudah diperiksa reviewAccess).
+func (s *AchievementService) reviewDecisionNote(achievement *models.Achievement, stage *models.AchievementApproval, approvals []models.AchievementApproval, claim *models.ReviewClaim, userID string) (string, error) {
+	if achievement.Status != "submitted" || stage == nil {
+		return fmt.Sprintf("Achievement berstatus '%s', tidak menunggu verifikasi", achievement.Status), nil
+	}
+
+	allowed, err := userHasPermission(s.permRepo, userID, stage.Permission)
+	if err != nil {
+		return "", err
+	}
+	if !allowed {
+		return fmt.Sprintf("Tahap verifikasi '%s' memerlukan permission '%s'", stage.StageName, stage.Permission), nil
+	}
+
+	for _, approval := range approvals {
+		if approval.Status == "approved" && approval.DecidedBy != nil && approval.DecidedBy.String() == userID {
+			return "Anda sudah menyetujui tahap verifikasi sebelumnya untuk achievement ini", nil
+		}
+	}
+
+	if claim != nil && claim.ClaimedBy.String() != userID {
+		return fmt.Sprintf("Achievement sedang direview oleh %s hingga %s", claim.ClaimerName, claim.ExpiresAt.Format("15:04")), nil
+	}
+	return "", nil
+}
+
+// ClaimReview godoc
+// @Summary Claim achievement for review
+// @Description Lock a submitted achievement so other verifiers see that it is being reviewed and cannot approve or reject it. Only a verifier who holds the permission of the pending verification stage can claim. The claim expires after REVIEW_CLAIM_TTL_MINUTES; claiming again extends it. An expired claim of another verifier is taken over. Approving or rejecting releases the claim.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} object{status=string,message=string,data=models.ReviewClaim} "Achievement claimed"
+// @Failure 400 {object} map[string]interface{} "Achievement is not waiting for verification"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Achievement is outside the caller's advisees and delegations, or the caller cannot decide its pending stage"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Achievement is already claimed by another verifier"
+// @Failure 500 {object} map[string]interface{} "Failed to claim achievement"
+// @Router /achievements/{id}/claim [post]
+func (s *AchievementService) ClaimReview(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, ok := c.Locals("user_id").(string)
+	if _, err := uuid.Parse(userID); !ok || err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+
+	achievement, err := s.achievementRepo.FindByID(context.Background(), achievementID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+	if achievement.Status != "submitted" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Hanya achievement dengan status 'submitted' yang bisa diklaim",
+		})
+	}
+
+	// Hanya verifikator tahap yang sedang menunggu yang boleh mengklaim; klaim verifikator tahap lain
+	// akan menahan keputusan verifikator yang berhak sampai klaimnya kedaluwarsa
+	approvals, err := s.approvalRepo.FindByMongoID(achievementID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil tahap verifikasi",
+		})
+	}
+	stage := PendingApprovalStage(approvals)
+	if stage == nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak memiliki tahap verifikasi yang menunggu keputusan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+	if reviewErr := s.decisionAccess(userID, roleID, achievement, stage); reviewErr != nil {
+		return c.Status(reviewErr.status).JSON(fiber.Map{
+			"status":  "error",
+			"message": reviewErr.message,
+		})
+	}
+
+	claim, claimed, err := s.claimRepo.Claim(achievementID, userID, s.claimTTL)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengklaim achievement",
+		})
+	}
+	if !claimed && claim != nil {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": fmt.Sprintf("Achievement sedang direview oleh %s hingga %s", claim.ClaimerName, claim.ExpiresAt.Format("15:04")),
+			"data":    claim,
+		})
+	}
+	if claim == nil {
+		// Klaim berhasil disimpan tetapi langsung kedaluwarsa (TTL tidak valid)
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengklaim achievement",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement berhasil diklaim untuk direview",
+		"data":    claim,
+	})
+}
+
+// ReleaseReview godoc
+// @Summary Release review claim
+// @Description Release the caller's review claim on an achievement. Admins can release a claim held by anyone.
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} map[string]interface{} "Claim released"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 404 {object} map[string]interface{} "No claim to release"
+// @Failure 500 {object} map[string]interface{} "Failed to release claim"
+// @Router /achievements/{id}/claim [delete]
+func (s *AchievementService) ReleaseReview(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak ditemukan",
+		})
+	}
+	roleID, _ := c.Locals("role_id").(string)
+
+	released, err := s.claimRepo.Release(c.Params("id"), userID, roleID == "1")
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal melepas klaim review",
+		})
+	}
+	if !released {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tidak ada klaim review Anda untuk achievement ini",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Klaim review berhasil dilepas",
+	})
+}
@@ -0,0 +1,612 @@
+package service
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kode verifikasi memakai alfabet Crockford base32 (tanpa I, L, O, U) agar mudah dibaca dan diketik ulang
+const (
+	verificationCodeAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	verificationCodeLength   = 10 // 50 bit acak, ditulis XXXXX-XXXXX
+	verificationCodeAttempts = 5
+)
+
+// ErrVerificationRecordRevoked dikembalikan Issue jika kode achievement pernah dicabut; kode baru
+// hanya diterbitkan admin lewat Reissue
+var ErrVerificationRecordRevoked = errors.New("kode verifikasi achievement sudah dicabut")
+
+// ErrVerificationRecordActive dikembalikan Reissue jika achievement masih punya kode aktif
+var ErrVerificationRecordActive = errors.New("achievement masih memiliki kode verifikasi aktif")
+
+// VerificationRecordConfig konfigurasi penerbitan record verifikasi publik
+type VerificationRecordConfig struct {
+	SigningKey    []byte // kunci HMAC tanda tangan record
+	Institution   string // nama institusi verifikator yang ditampilkan ke publik
+	PublicBaseURL string // URL dasar aplikasi untuk link verifikasi
+}
+
+// VerificationRecordService menerbitkan, mencabut dan memeriksa record verifikasi publik achievement
+type VerificationRecordService struct {
+	recordRepo      *repository.VerificationRecordRepository
+	achievementRepo *repository.AchievementRepository
+	referenceRepo   *repository.AchievementReferenceRepository
+	userRepo        *repository.UserRepository
+	masterRepo      *repository.MasterDataRepository
+	transcriptRepo  *repository.TranscriptRepository
+	config          VerificationRecordConfig
+}
+
+func NewVerificationRecordService(mongoDB *mongo.Database, postgresDB *sql.DB) *VerificationRecordService {
+	signingKey := os.Getenv("VERIFICATION_SIGNING_KEY")
+	if signingKey == "" {
+		// default (untuk dev). Pastikan di production diganti.
+		signingKey = "your-verification-signing-key-here"
+	}
+	institution := os.Getenv("VERIFIER_INSTITUTION")
+	if institution == "" {
+		institution = "Universitas"
+	}
+
+	return &VerificationRecordService{
+		recordRepo:      repository.NewVerificationRecordRepository(postgresDB),
+		achievementRepo: repository.NewAchievementRepository(mongoDB),
+		referenceRepo:   repository.NewAchievementReferenceRepository(postgresDB),
+		userRepo:        repository.NewUserRepository(postgresDB),
+		masterRepo:      repository.NewMasterDataRepository(postgresDB),
+		transcriptRepo:  repository.NewTranscriptRepository(postgresDB),
+		config: VerificationRecordConfig{
+			SigningKey:    []byte(signingKey),
+			Institution:   institution,
+			PublicBaseURL: strings.TrimRight(os.Getenv("PUBLIC_BASE_URL"), "/"),
+		},
+	}
+}
+
+// GenerateVerificationCode membuat kode verifikasi acak berformat XXXXX-XXXXX
+func GenerateVerificationCode() (string, error) {
+	max := big.NewInt(int64(len(verificationCodeAlphabet)))
+	code := make([]byte, verificationCodeLength)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = verificationCodeAlphabet[n.Int64()]
+	}
+	return formatVerificationCode(string(code)), nil
+}
+
+func formatVerificationCode(code string) string {
+	half := verificationCodeLength / 2
+	return code[:half] + "-" + code[half:]
+}
+
+// NormalizeVerificationCode merapikan kode yang diketik pengguna: huruf kecil, tanda hubung dan spasi
+// diabaikan, O dibaca 0 serta I/L dibaca 1. false jika bukan kode yang valid.
+func NormalizeVerificationCode(raw string) (string, bool) {
+	replacer := strings.NewReplacer("-", "", " ", "", "O", "0", "I", "1", "L", "1")
+	code := replacer.Replace(strings.ToUpper(strings.TrimSpace(raw)))
+	if len(code) != verificationCodeLength {
+		return "", false
+	}
+	for _, r := range code {
+		if !strings.ContainsRune(verificationCodeAlphabet, r) {
+			return "", false
+		}
+	}
+	return formatVerificationCode(code), true
+}
+
+// signFields menghitung HMAC-SHA256 (hex) atas daftar field. Panjang setiap field ikut ditandatangani
+// agar batas antar field tidak bisa digeser.
+func signFields(key []byte, fields ...string) string {
+	mac := hmac.New(sha256.New, key)
+	for _, field := range fields {
+		fmt.Fprintf(mac, "%d:%s\n", len(field), field)
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// SignVerificationRecord menghitung tanda tangan HMAC-SHA256 (hex) atas seluruh data publik record
+func SignVerificationRecord(key []byte, record *models.VerificationRecord) string {
+	return signFields(key,
+		record.Code,
+		record.MongoAchievementID,
+		record.Title,
+		record.HolderName,
+		record.Level,
+		record.VerifiedAt.UTC().Format(time.RFC3339Nano),
+		record.VerifierInstitution,
+		record.IssuedAt.UTC().Format(time.RFC3339Nano),
+	)
+}
+
+// VerificationRecordSignatureValid memeriksa tanda tangan record (perbandingan waktu konstan)
+func VerificationRecordSignatureValid(key []byte, record *models.VerificationRecord) bool {
+	expected := SignVerificationRecord(key, record)
+	return hmac.Equal([]byte(expected), []byte(record.Signature))
+}
+
+// SignTranscriptRecord menghitung tanda tangan record verifikasi transkrip
+func SignTranscriptRecord(key []byte, record *models.TranscriptRecord) string {
+	return signFields(key,
+		record.Code,
+		record.StudentID,
+		record.HolderName,
+		strconv.Itoa(record.AchievementCount),
+		strconv.FormatFloat(record.TotalPoints, 'f', 2, 64),
+		record.VerifierInstitution,
+		record.IssuedAt.UTC().Format(time.RFC3339Nano),
+	)
+}
+
+// TranscriptRecordSignatureValid memeriksa tanda tangan record transkrip (perbandingan waktu konstan)
+func TranscriptRecordSignatureValid(key []byte, record *models.TranscriptRecord) bool {
+	expected := SignTranscriptRecord(key, record)
+	return hmac.Equal([]byte(expected), []byte(record.Signature))
+}
+
+// verifyURL link publik untuk sebuah kode
+func (s *VerificationRecordService) verifyURL(code string) string {
+	return s.config.PublicBaseURL + "/public/verify/" + code
+}
+
+// Issue menerbitkan record verifikasi untuk achievement terverifikasi. Jika sudah ada record aktif,
+// record itu yang dikembalikan. Achievement yang kodenya pernah dicabut tidak diterbitkan ulang
+// otomatis (ErrVerificationRecordRevoked).
+func (s *VerificationRecordService) Issue(ctx context.Context, achievementID string) (*models.VerificationRecord, error) {
+	existing, err := s.recordRepo.FindActiveByAchievement(achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		existing.URL = s.verifyURL(existing.Code)
+		return existing, nil
+	}
+
+	revoked, err := s.recordRepo.HasRevoked(achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrVerificationRecordRevoked
+	}
+
+	return s.create(ctx, achievementID, nil)
+}
+
+// Reissue menerbitkan kode baru atas permintaan admin (misalnya setelah kode lama dicabut) dan mencatat
+// penerbitnya. Mengembalikan ErrVerificationRecordActive jika masih ada kode aktif.
+func (s *VerificationRecordService) Reissue(ctx context.Context, achievementID string, issuedBy uuid.UUID) (*models.VerificationRecord, error) {
+	existing, err := s.recordRepo.FindActiveByAchievement(achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrVerificationRecordActive
+	}
+
+	return s.create(ctx, achievementID, &issuedBy)
+}
+
+// create menyusun snapshot achievement terverifikasi lalu menyimpannya dengan kode baru
+func (s *VerificationRecordService) create(ctx context.Context, achievementID string, issuedBy *uuid.UUID) (*models.VerificationRecord, error) {
+	reference, err := s.referenceRepo.FindByMongoID(achievementID)
+	if err != nil {
+		return nil, err
+	}
+	if reference == nil || reference.Status != "verified" || reference.VerifiedAt == nil {
+		return nil, fmt.Errorf("achievement %s belum diverifikasi", achievementID)
+	}
+
+	achievement, err := s.achievementRepo.FindByID(ctx, achievementID)
+	if err != nil {
+		return nil, err
+	}
+	holder, err := s.userRepo.FindByID(reference.StudentID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	// Level ditampilkan dengan label master data, bukan code internal
+	level := achievement.Level
+	if masterLevel, err := s.masterRepo.FindLevelByCode(achievement.Level); err == nil && masterLevel != nil && masterLevel.LabelID != "" {
+		level = masterLevel.LabelID
+	}
+
+	record := &models.VerificationRecord{
+		ID:                  uuid.New(),
+		MongoAchievementID:  achievementID,
+		Title:               achievement.Title,
+		HolderName:          holder.FullName,
+		Level:               level,
+		VerifiedAt:          reference.VerifiedAt.UTC().Truncate(time.Microsecond),
+		VerifierInstitution: s.config.Institution,
+		IssuedAt:            time.Now().UTC().Truncate(time.Microsecond),
+		IssuedBy:            issuedBy,
+	}
+
+	// Kode bentrok sangat jarang (50 bit), cukup dicoba ulang dengan kode baru
+	for attempt := 0; attempt < verificationCodeAttempts; attempt++ {
+		if record.Code, err = GenerateVerificationCode(); err != nil {
+			return nil, err
+		}
+		record.Signature = SignVerificationRecord(s.config.SigningKey, record)
+
+		err = s.recordRepo.Create(record)
+		if err == repository.ErrDuplicateCode {
+			continue
+		}
+		if err == repository.ErrVersionConflict {
+			// Diterbitkan bersamaan oleh request lain: pakai record tersebut (penerbitan ulang admin ditolak)
+			if issuedBy != nil {
+				return nil, ErrVerificationRecordActive
+			}
+			return s.Issue(ctx, achievementID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		record.URL = s.verifyURL(record.Code)
+		return record, nil
+	}
+	return nil, fmt.Errorf("gagal membuat kode verifikasi unik setelah %d percobaan", verificationCodeAttempts)
+}
+
+// IssueTranscript menerbitkan record verifikasi untuk satu transkrip yang dicetak. Nilai yang dicatat
+// (jumlah achievement, total poin) dibulatkan sama seperti yang tercetak di dokumen.
+func (s *VerificationRecordService) IssueTranscript(studentID, holderName string, achievementCount int, totalPoints float64) (*models.TranscriptRecord, error) {
+	record := &models.TranscriptRecord{
+		ID:                  uuid.New(),
+		StudentID:           studentID,
+		HolderName:          holderName,
+		AchievementCount:    achievementCount,
+		TotalPoints:         math.Round(totalPoints*100) / 100,
+		VerifierInstitution: s.config.Institution,
+		IssuedAt:            time.Now().UTC().Truncate(time.Microsecond),
+	}
+
+	var err error
+	for attempt := 0; attempt < verificationCodeAttempts; attempt++ {
+		if record.Code, err = GenerateVerificationCode(); err != nil {
+			return nil, err
+		}
+		record.Signature = SignTranscriptRecord(s.config.SigningKey, record)
+
+		err = s.transcriptRepo.CreateRecord(record)
+		if err == repository.ErrDuplicateCode {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return record, nil
+	}
+	return nil, fmt.Errorf("gagal membuat kode verifikasi unik setelah %d percobaan", verificationCodeAttempts)
+}
+
+// IssueAfterVerification menerbitkan record setelah achievement diverifikasi. Kegagalan hanya dicatat;
+// record bisa diterbitkan ulang lewat GET /achievements/:id/verification-record.
+func (s *VerificationRecordService) IssueAfterVerification(ctx context.Context, achievementID string) {
+	if _, err := s.Issue(ctx, achievementID); err != nil {
+		log.Printf("Gagal menerbitkan kode verifikasi achievement %s: %v", achievementID, err)
+	}
+}
+
+// RevokeTx mencabut record aktif di dalam transaksi (dipakai saat achievement dicabut)
+func (s *VerificationRecordService) RevokeTx(tx *sql.Tx, achievementID, revokedBy, reason string) error {
+	return s.recordRepo.RevokeTx(tx, achievementID, revokedBy, reason)
+}
+
+// GetVerificationRecord godoc
+// @Summary Get public verification record of an achievement
+// @Description Returns the short public verification code and URL of a verified achievement. The record is issued automatically on verification; achievements verified earlier get one on first request. A revoked code is never replaced automatically; only an admin can issue a new one (POST). Only the achievement owner and admins can see it.
+// @Tags Verification Records
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 200 {object} object{status=string,message=string,data=models.VerificationRecord} "Verification record"
+// @Failure 400 {object} map[string]interface{} "Achievement is not verified"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Access denied - not owner"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Verification code was revoked and has not been reissued"
+// @Failure 500 {object} map[string]interface{} "Failed to issue verification record"
+// @Router /achievements/{id}/verification-record [get]
+func (s *VerificationRecordService) GetVerificationRecord(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+	roleID, _ := c.Locals("role_id").(string)
+
+	reference, err := s.referenceRepo.FindByMongoID(achievementID)
+	if err != nil || reference == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+
+	if reference.StudentID.String() != userID && roleID != "1" {
+		return c.Status(403).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Anda tidak memiliki akses ke kode verifikasi achievement ini",
+		})
+	}
+
+	if reference.Status != "verified" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kode verifikasi hanya tersedia untuk achievement dengan status 'verified'",
+		})
+	}
+
+	record, err := s.Issue(context.Background(), achievementID)
+	if err == ErrVerificationRecordRevoked {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kode verifikasi achievement ini sudah dicabut dan hanya dapat diterbitkan ulang oleh admin",
+		})
+	}
+	if err != nil {
+		log.Printf("Gagal menerbitkan kode verifikasi achievement %s: %v", achievementID, err)
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menerbitkan kode verifikasi",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kode verifikasi berhasil diambil",
+		"data":    record,
+	})
+}
+
+// ReissueVerificationRecord godoc
+// @Summary Reissue public verification record
+// @Description Issues a new verification code for a verified achievement whose previous code was revoked (or never issued). The issuing admin is recorded on the record. Fails while an active code exists.
+// @Tags Verification Records
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Success 201 {object} object{status=string,message=string,data=models.VerificationRecord} "Verification record reissued"
+// @Failure 400 {object} map[string]interface{} "Achievement is not verified"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_records.reissue)"
+// @Failure 404 {object} map[string]interface{} "Achievement not found"
+// @Failure 409 {object} map[string]interface{} "Achievement still has an active verification code"
+// @Failure 500 {object} map[string]interface{} "Failed to reissue verification record"
+// @Router /achievements/{id}/verification-record [post]
+func (s *VerificationRecordService) ReissueVerificationRecord(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	issuedBy, err := uuid.Parse(userID)
+	if err != nil {
+		return c.Status(401).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: User ID tidak valid",
+		})
+	}
+
+	reference, err := s.referenceRepo.FindByMongoID(achievementID)
+	if err != nil || reference == nil {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement tidak ditemukan",
+		})
+	}
+
+	if reference.Status != "verified" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kode verifikasi hanya tersedia untuk achievement dengan status 'verified'",
+		})
+	}
+
+	record, err := s.Reissue(context.Background(), achievementID, issuedBy)
+	if err == ErrVerificationRecordActive {
+		return c.Status(409).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Achievement masih memiliki kode verifikasi aktif; cabut kode tersebut terlebih dahulu",
+		})
+	}
+	if err != nil {
+		log.Printf("Gagal menerbitkan ulang kode verifikasi achievement %s: %v", achievementID, err)
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menerbitkan ulang kode verifikasi",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kode verifikasi berhasil diterbitkan ulang",
+		"data":    record,
+	})
+}
+
+// RevokeVerificationRecord godoc
+// @Summary Revoke public verification record
+// @Description Revokes the active verification code of an achievement; the public endpoint then reports it as no longer valid. The achievement itself keeps its status. A new code is only issued when an admin reissues it (POST).
+// @Tags Verification Records
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Achievement ID"
+// @Param request body models.RevokeVerificationRecordRequest true "Revocation reason"
+// @Success 200 {object} object{status=string,message=string} "Verification record revoked"
+// @Failure 400 {object} map[string]interface{} "Missing reason"
+// @Failure 401 {object} map[string]interface{} "Unauthorized - invalid or missing JWT token"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_records.revoke)"
+// @Failure 404 {object} map[string]interface{} "No active verification record"
+// @Failure 500 {object} map[string]interface{} "Failed to revoke verification record"
+// @Router /achievements/{id}/verification-record [delete]
+func (s *VerificationRecordService) RevokeVerificationRecord(c *fiber.Ctx) error {
+	achievementID := c.Params("id")
+	userID, _ := c.Locals("user_id").(string)
+
+	var req models.RevokeVerificationRecordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Alasan pencabutan harus diisi",
+		})
+	}
+
+	if err := s.recordRepo.Revoke(achievementID, userID, req.Reason); err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(404).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Achievement tidak memiliki kode verifikasi aktif",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mencabut kode verifikasi",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Kode verifikasi berhasil dicabut",
+	})
+}
+
+// VerifyPublic godoc
+// @Summary Publicly verify an achievement
+// @Description Unauthenticated check of a verification code, e.g. by an employer. A valid achievement code confirms the title, holder name, level, verification date and verifier institution; a revoked code only reports that it is no longer valid. Codes printed on PDF transcripts (type=transcript) confirm the holder, the number of achievements, the total points and the issue date. Rate-limited per client IP.
+// @Tags Verification Records
+// @Produce json
+// @Param code path string true "Verification code (XXXXX-XXXXX, case and dashes are ignored)"
+// @Success 200 {object} object{status=string,message=string,data=models.PublicVerificationResult} "Verification result (valid=false when revoked)"
+// @Failure 404 {object} map[string]interface{} "Unknown verification code"
+// @Failure 429 {object} map[string]interface{} "Too many requests"
+// @Router /public/verify/{code} [get]
+func (s *VerificationRecordService) VerifyPublic(c *fiber.Ctx) error {
+	notFound := func() error {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Kode verifikasi tidak ditemukan",
+		})
+	}
+
+	code, ok := NormalizeVerificationCode(c.Params("code"))
+	if !ok {
+		return notFound()
+	}
+
+	record, err := s.recordRepo.FindByCode(code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memeriksa kode verifikasi",
+		})
+	}
+	if record == nil {
+		// Bukan kode achievement: mungkin kode transkrip yang dicetak
+		return s.verifyTranscript(c, code, notFound)
+	}
+
+	// Record yang datanya diubah di luar aplikasi tidak pernah dinyatakan valid
+	if !VerificationRecordSignatureValid(s.config.SigningKey, record) {
+		log.Printf("Tanda tangan record verifikasi %s tidak cocok", record.Code)
+		return notFound()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	if record.RevokedAt != nil {
+		return c.Status(200).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Kode verifikasi sudah dicabut dan tidak berlaku",
+			"data": models.PublicVerificationResult{
+				Code:      record.Code,
+				Type:      models.VerificationTypeAchievement,
+				Valid:     false,
+				RevokedAt: record.RevokedAt,
+			},
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Achievement terverifikasi",
+		"data": models.PublicVerificationResult{
+			Code:                record.Code,
+			Type:                models.VerificationTypeAchievement,
+			Valid:               true,
+			Title:               record.Title,
+			HolderName:          record.HolderName,
+			Level:               record.Level,
+			VerifiedAt:          &record.VerifiedAt,
+			VerifierInstitution: record.VerifierInstitution,
+		},
+	})
+}
+
+// verifyTranscript menjawab verifikasi publik untuk kode transkrip
+func (s *VerificationRecordService) verifyTranscript(c *fiber.Ctx, code string, notFound func() error) error {
+	record, err := s.transcriptRepo.FindRecordByCode(code)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal memeriksa kode verifikasi",
+		})
+	}
+	if record == nil {
+		return notFound()
+	}
+
+	if !TranscriptRecordSignatureValid(s.config.SigningKey, record) {
+		log.Printf("Tanda tangan record transkrip %s tidak cocok", record.Code)
+		return notFound()
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Transkrip prestasi diterbitkan oleh institusi",
+		"data": models.PublicVerificationResult{
+			Code:                record.Code,
+			Type:                models.VerificationTypeTranscript,
+			Valid:               true,
+			HolderName:          record.HolderName,
+			VerifierInstitution: record.VerifierInstitution,
+			AchievementCount:    &record.AchievementCount,
+			TotalPoints:         &record.TotalPoints,
+			IssuedAt:            &record.IssuedAt,
+		},
+	})
+}
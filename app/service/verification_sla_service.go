@@ -0,0 +1,306 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// DefaultSLARule dipakai jika tidak ada aturan untuk level tertentu maupun aturan '*'
+var DefaultSLARule = models.VerificationSLARule{
+	Level:              "*",
+	ReminderAfterHours: 72,
+	EscalateAfterHours: 168,
+}
+
+type VerificationSLAService struct {
+	slaRepo  *repository.VerificationSLARepository
+	userRepo *repository.UserRepository
+}
+
+func NewVerificationSLAService(db *sql.DB) *VerificationSLAService {
+	return &VerificationSLAService{
+		slaRepo:  repository.NewVerificationSLARepository(db),
+		userRepo: repository.NewUserRepository(db),
+	}
+}
+
+// ResolveSLARule memilih aturan SLA untuk level achievement (case-insensitive),
+// fallback ke aturan '*' lalu ke DefaultSLARule
+func ResolveSLARule(rules []models.VerificationSLARule, level string) models.VerificationSLARule {
+	fallback := DefaultSLARule
+	for _, rule := range rules {
+		if strings.EqualFold(rule.Level, level) {
+			return rule
+		}
+		if rule.Level == "*" {
+			fallback = rule
+		}
+	}
+	return fallback
+}
+
+// SLAAction tindakan SLA untuk submission yang sudah menunggu pendingHours di tahapnya:
+// "escalation", "reminder", atau "" jika belum melewati batas reminder
+func SLAAction(rule models.VerificationSLARule, pendingHours int) string {
+	switch {
+	case pendingHours >= rule.EscalateAfterHours:
+		return "escalation"
+	case pendingHours >= rule.ReminderAfterHours:
+		return "reminder"
+	}
+	return ""
+}
+
+// GetSLARules godoc
+// @Summary Get verification SLA rules
+// @Description Get reminder and escalation thresholds (in hours) per achievement level. Level '*' is the default rule.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.VerificationSLARule} "SLA rules retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve SLA rules"
+// @Router /verification-sla [get]
+func (s *VerificationSLAService) GetSLARules(c *fiber.Ctx) error {
+	rules, err := s.slaRepo.FindAllRules()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil aturan SLA",
+		})
+	}
+
+	if rules == nil {
+		rules = []models.VerificationSLARule{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data aturan SLA berhasil diambil",
+		"data":    rules,
+	})
+}
+
+// UpsertSLARule godoc
+// @Summary Create or update verification SLA rule
+// @Description Set reminder and escalation thresholds (in hours) for an achievement level. Use '*' for the default rule.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param level path string true "Achievement level or '*'"
+// @Param request body object{reminder_after_hours=int,escalate_after_hours=int} true "SLA thresholds"
+// @Success 200 {object} object{status=string,message=string,data=models.VerificationSLARule} "SLA rule saved successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid thresholds"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to save SLA rule"
+// @Router /verification-sla/{level} [put]
+func (s *VerificationSLAService) UpsertSLARule(c *fiber.Ctx) error {
+	level := strings.ToLower(c.Params("level"))
+
+	var req struct {
+		ReminderAfterHours int `json:"reminder_after_hours"`
+		EscalateAfterHours int `json:"escalate_after_hours"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	if req.ReminderAfterHours < 1 || req.EscalateAfterHours <= req.ReminderAfterHours {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "reminder_after_hours harus >= 1 dan escalate_after_hours harus lebih besar dari reminder_after_hours",
+		})
+	}
+
+	rule := &models.VerificationSLARule{
+		Level:              level,
+		ReminderAfterHours: req.ReminderAfterHours,
+		EscalateAfterHours: req.EscalateAfterHours,
+		UpdatedAt:          time.Now(),
+	}
+
+	if err := s.slaRepo.UpsertRule(rule); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan aturan SLA",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Aturan SLA berhasil disimpan",
+		"data":    rule,
+	})
+}
+
+// DeleteSLARule godoc
+// @Summary Delete verification SLA rule
+// @Description Remove the SLA rule of an achievement level. The level then falls back to the '*' rule.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param level path string true "Achievement level"
+// @Success 200 {object} object{status=string,message=string} "SLA rule deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 404 {object} map[string]interface{} "SLA rule not found"
+// @Failure 500 {object} map[string]interface{} "Failed to delete SLA rule"
+// @Router /verification-sla/{level} [delete]
+func (s *VerificationSLAService) DeleteSLARule(c *fiber.Ctx) error {
+	level := strings.ToLower(c.Params("level"))
+
+	deleted, err := s.slaRepo.DeleteRule(level)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus aturan SLA",
+		})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Aturan SLA tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Aturan SLA berhasil dihapus",
+	})
+}
+
+// GetDepartmentAdmins godoc
+// @Summary Get department admins
+// @Description List the department admins who receive SLA escalations for advisors in their department. Departments without an admin escalate to every admin.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.DepartmentAdmin} "Department admins retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve department admins"
+// @Router /verification-sla/department-admins [get]
+func (s *VerificationSLAService) GetDepartmentAdmins(c *fiber.Ctx) error {
+	admins, err := s.slaRepo.FindDepartmentAdmins()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data admin departemen",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data admin departemen berhasil diambil",
+		"data":    admins,
+	})
+}
+
+// AddDepartmentAdmin godoc
+// @Summary Add department admin
+// @Description Register a user as escalation recipient for a department (matched case-insensitively against the advisor's lecturer department).
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DepartmentAdminRequest true "Department and user"
+// @Success 201 {object} object{status=string,message=string,data=models.DepartmentAdminRequest} "Department admin added"
+// @Failure 400 {object} map[string]interface{} "Invalid department or user"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to add department admin"
+// @Router /verification-sla/department-admins [post]
+func (s *VerificationSLAService) AddDepartmentAdmin(c *fiber.Ctx) error {
+	var req models.DepartmentAdminRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	req.Department = strings.TrimSpace(req.Department)
+	if req.Department == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Department harus diisi",
+		})
+	}
+	if _, err := uuid.Parse(req.UserID); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "user_id tidak valid",
+		})
+	}
+	if user, err := s.userRepo.FindByID(req.UserID); err != nil || !user.IsActive {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "User tidak ditemukan atau tidak aktif",
+		})
+	}
+
+	if err := s.slaRepo.AddDepartmentAdmin(req.Department, req.UserID); err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menambahkan admin departemen",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Admin departemen berhasil ditambahkan",
+		"data":    req,
+	})
+}
+
+// RemoveDepartmentAdmin godoc
+// @Summary Remove department admin
+// @Description Stop sending a department's SLA escalations to a user.
+// @Tags Verification SLA
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param department path string true "Department"
+// @Param userId path string true "User ID"
+// @Success 200 {object} object{status=string,message=string} "Department admin removed"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_sla.manage)"
+// @Failure 404 {object} map[string]interface{} "Department admin not found"
+// @Failure 500 {object} map[string]interface{} "Failed to remove department admin"
+// @Router /verification-sla/department-admins/{department}/{userId} [delete]
+func (s *VerificationSLAService) RemoveDepartmentAdmin(c *fiber.Ctx) error {
+	removed, err := s.slaRepo.RemoveDepartmentAdmin(c.Params("department"), c.Params("userId"))
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus admin departemen",
+		})
+	}
+	if !removed {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Admin departemen tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Admin departemen berhasil dihapus",
+	})
+}
@@ -0,0 +1,219 @@
+package service
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// DefaultVerificationStage dipakai jika tidak ada konfigurasi tahap yang cocok
+var DefaultVerificationStage = models.VerificationStage{
+	StageOrder: 1,
+	Name:       "advisor",
+	Permission: "achievements.verify",
+	IsActive:   true,
+}
+
+type VerificationStageService struct {
+	stageRepo *repository.VerificationStageRepository
+}
+
+func NewVerificationStageService(db *sql.DB) *VerificationStageService {
+	return &VerificationStageService{
+		stageRepo: repository.NewVerificationStageRepository(db),
+	}
+}
+
+// ResolveVerificationStages menyusun rantai verifikasi dari konfigurasi yang berlaku.
+// Satu stage_order hanya diambil sekali: konfigurasi paling spesifik menang (level+category,
+// lalu level, lalu category, lalu umum), jika sama spesifik yang pertama menang. Urutan
+// dinomori ulang mulai dari 1 sehingga tidak ada tahap yang terlewat.
+func ResolveVerificationStages(stages []models.VerificationStage) []models.VerificationStage {
+	sorted := make([]models.VerificationStage, len(stages))
+	copy(sorted, stages)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StageOrder != sorted[j].StageOrder {
+			return sorted[i].StageOrder < sorted[j].StageOrder
+		}
+		return stageSpecificity(sorted[i]) > stageSpecificity(sorted[j])
+	})
+
+	seen := make(map[int]bool)
+	chain := []models.VerificationStage{}
+	for _, stage := range sorted {
+		if !stage.IsActive || seen[stage.StageOrder] {
+			continue
+		}
+		seen[stage.StageOrder] = true
+		chain = append(chain, stage)
+	}
+
+	if len(chain) == 0 {
+		return []models.VerificationStage{DefaultVerificationStage}
+	}
+
+	for i := range chain {
+		chain[i].StageOrder = i + 1
+	}
+
+	return chain
+}
+
+// stageSpecificity bobot kecocokan konfigurasi tahap: level+category > level > category > umum
+func stageSpecificity(stage models.VerificationStage) int {
+	specificity := 0
+	if stage.Level != nil && *stage.Level != "" {
+		specificity += 2
+	}
+	if stage.Category != nil && *stage.Category != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// GetStages godoc
+// @Summary Get verification stages
+// @Description Get all verification stage configurations (per level/category).
+// @Tags Verification Stages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} object{status=string,message=string,data=[]models.VerificationStage} "Stages retrieved successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_stages.manage)"
+// @Failure 500 {object} map[string]interface{} "Failed to retrieve stages"
+// @Router /verification-stages [get]
+func (s *VerificationStageService) GetStages(c *fiber.Ctx) error {
+	stages, err := s.stageRepo.FindAll()
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal mengambil data tahap verifikasi",
+		})
+	}
+
+	if stages == nil {
+		stages = []models.VerificationStage{}
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Data tahap verifikasi berhasil diambil",
+		"data":    stages,
+	})
+}
+
+// CreateStage godoc
+// @Summary Create verification stage
+// @Description Add a verification stage for a specific level and/or category. Empty level/category applies to all.
+// @Tags Verification Stages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body object{stage_order=int,name=string,permission=string,level=string,category=string} true "Stage configuration"
+// @Success 201 {object} object{status=string,message=string,data=models.VerificationStage} "Stage created successfully"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_stages.manage)"
+// @Failure 409 {object} map[string]interface{} "The same stage already exists for this level and category"
+// @Failure 500 {object} map[string]interface{} "Failed to create stage"
+// @Router /verification-stages [post]
+func (s *VerificationStageService) CreateStage(c *fiber.Ctx) error {
+	var req struct {
+		StageOrder int    `json:"stage_order"`
+		Name       string `json:"name"`
+		Permission string `json:"permission"`
+		Level      string `json:"level"`
+		Category   string `json:"category"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Invalid request body",
+		})
+	}
+
+	if req.StageOrder < 1 || req.Name == "" || req.Permission == "" {
+		return c.Status(400).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Stage order (>= 1), name, dan permission harus diisi",
+		})
+	}
+
+	stage := &models.VerificationStage{
+		ID:         uuid.New(),
+		StageOrder: req.StageOrder,
+		Name:       req.Name,
+		Permission: req.Permission,
+		IsActive:   true,
+		CreatedAt:  time.Now(),
+	}
+	if req.Level != "" {
+		stage.Level = &req.Level
+	}
+	if req.Category != "" {
+		stage.Category = &req.Category
+	}
+
+	if err := s.stageRepo.Create(stage); err != nil {
+		if err == repository.ErrDuplicateStage {
+			return c.Status(409).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Tahap verifikasi dengan urutan, nama, level dan category yang sama sudah ada",
+			})
+		}
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menyimpan tahap verifikasi",
+		})
+	}
+
+	return c.Status(201).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tahap verifikasi berhasil dibuat",
+		"data":    stage,
+	})
+}
+
+// DeleteStage godoc
+// @Summary Delete verification stage
+// @Description Remove a verification stage configuration. Achievements already submitted keep their stage chain.
+// @Tags Verification Stages
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Stage ID (UUID)"
+// @Success 200 {object} object{status=string,message=string} "Stage deleted successfully"
+// @Failure 401 {object} map[string]interface{} "Unauthorized"
+// @Failure 403 {object} map[string]interface{} "Insufficient permissions (requires verification_stages.manage)"
+// @Failure 404 {object} map[string]interface{} "Stage not found"
+// @Failure 500 {object} map[string]interface{} "Failed to delete stage"
+// @Router /verification-stages/{id} [delete]
+func (s *VerificationStageService) DeleteStage(c *fiber.Ctx) error {
+	stageID := c.Params("id")
+
+	deleted, err := s.stageRepo.Delete(stageID)
+	if err != nil {
+		return c.Status(500).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Gagal menghapus tahap verifikasi",
+		})
+	}
+	if !deleted {
+		return c.Status(404).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Tahap verifikasi tidak ditemukan",
+		})
+	}
+
+	return c.Status(200).JSON(fiber.Map{
+		"status":  "success",
+		"message": "Tahap verifikasi berhasil dihapus",
+	})
+}
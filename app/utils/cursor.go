@@ -0,0 +1,34 @@
+package utils
+
+import (
+	models "crud-app/app/model"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrMalformedCursor dikembalikan DecodeCursor untuk cursor yang tidak bisa dibaca
+var ErrMalformedCursor = errors.New("malformed cursor")
+
+// EncodeCursor mengubah cursor menjadi string opaque yang aman dipakai di query string
+func EncodeCursor(cursor models.PageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor membaca kembali cursor dari EncodeCursor
+func DecodeCursor(raw string) (*models.PageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrMalformedCursor
+	}
+
+	var cursor models.PageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrMalformedCursor
+	}
+	if cursor.Sort == "" || cursor.ID == "" {
+		return nil, ErrMalformedCursor
+	}
+	return &cursor, nil
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+// GetEnvInt membaca environment variable bertipe integer positif dengan nilai default
+func GetEnvInt(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value < 1 {
+		return defaultValue
+	}
+	return value
+}
+
+// GetEnvBool membaca environment variable bertipe boolean (true/false/1/0) dengan nilai default
+func GetEnvBool(key string, defaultValue bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
\ No newline at end of file
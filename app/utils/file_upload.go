@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -127,4 +129,20 @@ func generateUniqueFilename(originalFilename string) string {
 // GetFileInfo mendapatkan informasi file
 func GetFileInfo(file *multipart.FileHeader) (filename string, size int64, mimetype string) {
 	return file.Filename, file.Size, file.Header.Get("Content-Type")
+}
+
+// HashFile menghitung SHA-256 isi file (dipakai untuk deteksi dokumen duplikat)
+func HashFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
 }
\ No newline at end of file
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// gauge satu metric bertipe gauge beserta nilai per kombinasi label
+type gauge struct {
+	help    string
+	samples map[string]float64 // key: label dalam format Prometheus, mis. {category="x"}
+}
+
+var (
+	metricsMu sync.RWMutex
+	gauges    = map[string]*gauge{}
+)
+
+// SetGauge menyimpan nilai gauge untuk diekspos di endpoint /metrics (format teks Prometheus)
+func SetGauge(name, help string, labels map[string]string, value float64) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	g, ok := gauges[name]
+	if !ok {
+		g = &gauge{help: help, samples: map[string]float64{}}
+		gauges[name] = g
+	}
+	g.samples[formatLabels(labels)] = value
+}
+
+// WriteMetrics menulis semua gauge dalam format teks Prometheus, terurut berdasarkan nama
+func WriteMetrics(w io.Writer) error {
+	metricsMu.RLock()
+	defer metricsMu.RUnlock()
+
+	names := make([]string, 0, len(gauges))
+	for name := range gauges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g := gauges[name]
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, g.help, name); err != nil {
+			return err
+		}
+
+		labels := make([]string, 0, len(g.samples))
+		for label := range g.samples {
+			labels = append(labels, label)
+		}
+		sort.Strings(labels)
+
+		for _, label := range labels {
+			if _, err := fmt.Fprintf(w, "%s%s %g\n", name, label, g.samples[label]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[key])
+		pairs[i] = fmt.Sprintf(`%s="%s"`, key, value)
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
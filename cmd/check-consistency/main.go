@@ -0,0 +1,81 @@
+// Command check-consistency membandingkan koleksi achievements (MongoDB) dengan tabel
+// achievement_references (PostgreSQL) dan melaporkan drift per kategori.
+//
+// Tanpa -dry-run, drift diperbaiki dengan menyamakan store lain ke sumber kebenaran:
+//
+//	go run ./cmd/check-consistency -dry-run
+//	go run ./cmd/check-consistency -source=postgres
+//	go run ./cmd/check-consistency -source=mongo
+package main
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"crud-app/database"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Hanya laporkan drift tanpa memperbaiki")
+	source := flag.String("source", service.SourceOfTruthPostgres, "Sumber kebenaran saat memperbaiki drift (postgres|mongo)")
+	flag.Parse()
+
+	if !service.ValidSourceOfTruth(*source) {
+		log.Fatalf("Sumber kebenaran tidak valid: %q (gunakan postgres atau mongo)", *source)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	if os.Getenv("DB_DSN") == "" {
+		log.Fatal("Set environment variable DB_DSN")
+	}
+
+	database.ConnectDB()
+	defer database.DB.Close()
+
+	mongoClient := database.MongoConnection()
+	defer database.CloseDB(mongoClient)
+
+	consistencyService := service.NewConsistencyService(database.GetMongoDatabase(), database.DB)
+	report, err := consistencyService.Check(context.Background(), *dryRun, *source)
+	if err != nil {
+		log.Fatalf("Pemeriksaan konsistensi gagal: %v", err)
+	}
+
+	log.Printf("Dry run: %v", report.DryRun)
+	log.Printf("Sumber kebenaran: %s", report.SourceOfTruth)
+	log.Printf("Dokumen MongoDB diperiksa: %d", report.ScannedMongo)
+	log.Printf("Reference PostgreSQL diperiksa: %d", report.ScannedPostgres)
+	log.Printf("Dilewati (outbox pending): %d", report.PendingOutbox)
+	for _, category := range []string{models.DriftMissingReference, models.DriftOrphanReference, models.DriftStatusMismatch, models.DriftDeletedMismatch} {
+		log.Printf("%s: %d", category, report.Counts[category])
+	}
+
+	for _, drift := range report.Drifts {
+		result := ""
+		switch {
+		case drift.Repaired:
+			result = " -> diperbaiki"
+		case drift.RepairError != "":
+			result = " -> gagal: " + drift.RepairError
+		}
+		log.Printf("  [%s] %s mongo=%q/deleted=%v postgres=%q/deleted=%v%s",
+			drift.Category, drift.AchievementID,
+			drift.MongoStatus, drift.MongoDeleted, drift.PostgresStatus, drift.PostgresDeleted, result)
+	}
+
+	if !report.DryRun {
+		log.Printf("Diperbaiki: %d", report.Repaired)
+		log.Printf("Gagal diperbaiki: %d", report.Unrepairable)
+	}
+	if report.Unrepairable > 0 {
+		os.Exit(1)
+	}
+}
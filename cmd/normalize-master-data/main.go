@@ -0,0 +1,70 @@
+// Command normalize-master-data mengubah nilai category/level lama pada koleksi
+// achievements (MongoDB) menjadi code kanonik dari tabel master data PostgreSQL.
+//
+// Jalankan sekali setelah migrasi 033_master_data.sql:
+//
+//	go run ./cmd/normalize-master-data -dry-run
+//	go run ./cmd/normalize-master-data
+package main
+
+import (
+	"context"
+	"crud-app/app/service"
+	"crud-app/database"
+	"flag"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	dryRun := flag.Bool("dry-run", false, "Hanya laporkan perubahan tanpa menulis ke MongoDB")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	if os.Getenv("DB_DSN") == "" {
+		log.Fatal("Set environment variable DB_DSN")
+	}
+
+	database.ConnectDB()
+	defer database.DB.Close()
+
+	mongoClient := database.MongoConnection()
+	defer database.CloseDB(mongoClient)
+
+	masterDataService := service.NewMasterDataService(database.GetMongoDatabase(), database.DB)
+	report, err := masterDataService.NormalizeAchievements(context.Background(), *dryRun)
+	if err != nil {
+		log.Fatalf("Normalisasi master data gagal: %v", err)
+	}
+
+	log.Printf("Dry run: %v", report.DryRun)
+	log.Printf("Achievement diperiksa: %d", report.Scanned)
+	log.Printf("Achievement diupdate: %d", report.Updated)
+	log.Printf("Sudah kanonik: %d", report.AlreadyCanonical)
+	logUnmatched("Kategori tidak dikenal", report.UnmatchedCategories)
+	logUnmatched("Level tidak dikenal", report.UnmatchedLevels)
+}
+
+// logUnmatched mencetak nilai yang tidak cocok dengan master data agar bisa ditambahkan sebagai alias
+func logUnmatched(title string, values map[string]int) {
+	if len(values) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(values))
+	for value := range values {
+		keys = append(keys, value)
+	}
+	sort.Strings(keys)
+
+	log.Printf("%s:", title)
+	for _, value := range keys {
+		log.Printf("  %q: %d achievement", value, values[value])
+	}
+}
@@ -0,0 +1,42 @@
+// Command rebuild-read-model membangun ulang tabel achievement_read_model dari
+// achievement_references (PostgreSQL), dokumen achievements (MongoDB) dan data mahasiswa.
+//
+// Jalankan setelah migrasi 043 atau bila proyeksi dicurigai tidak sinkron:
+//
+//	go run ./cmd/rebuild-read-model
+package main
+
+import (
+	"context"
+	"crud-app/app/service"
+	"crud-app/database"
+	"log"
+	"os"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
+	}
+
+	if os.Getenv("DB_DSN") == "" {
+		log.Fatal("Set environment variable DB_DSN")
+	}
+
+	database.ConnectDB()
+	defer database.DB.Close()
+
+	mongoClient := database.MongoConnection()
+	defer database.CloseDB(mongoClient)
+
+	projector := service.NewAchievementProjector(database.GetMongoDatabase(), database.DB)
+	projected, removed, err := projector.Rebuild(context.Background())
+	if err != nil {
+		log.Fatalf("Rebuild read model gagal setelah %d achievement: %v", projected, err)
+	}
+
+	log.Printf("Achievement diproyeksikan: %d", projected)
+	log.Printf("Proyeksi usang dihapus: %d", removed)
+}
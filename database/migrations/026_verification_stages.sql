@@ -0,0 +1,73 @@
+-- Multi-stage verification chain (advisor, then faculty)
+
+CREATE TABLE IF NOT EXISTS verification_stages (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    stage_order INT          NOT NULL,
+    name        VARCHAR(100) NOT NULL,
+    permission  VARCHAR(100) NOT NULL,
+    level       VARCHAR(100),
+    category    VARCHAR(100),
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS achievement_approvals (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    stage_order          INT          NOT NULL,
+    stage_name           VARCHAR(100) NOT NULL,
+    permission           VARCHAR(100) NOT NULL,
+    status               VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    decided_by           UUID REFERENCES users(id),
+    note                 TEXT,
+    decided_at           TIMESTAMP,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    UNIQUE (mongo_achievement_id, stage_order)
+);
+
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS current_stage INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS total_stages  INT NOT NULL DEFAULT 1;
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievements.verify_faculty', 'achievements', 'verify_faculty',
+        'Verifikasi tahap fakultas (bagian kemahasiswaan)'),
+       (gen_random_uuid(), 'verification_stages.manage', 'verification_stages', 'manage',
+        'Kelola konfigurasi tahap verifikasi')
+ON CONFLICT DO NOTHING;
+
+-- Admin memegang tahap fakultas agar prestasi nasional/internasional tidak tertahan di tahap 2;
+-- role kemahasiswaan bisa diberi permission yang sama lewat role_permissions
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name IN ('verification_stages.manage', 'achievements.verify_faculty')
+ON CONFLICT DO NOTHING;
+
+-- Satu konfigurasi per tahap, level dan category agar migrasi aman dijalankan ulang.
+-- Baris ganda dari run sebelumnya dan level 'national'/'international' (bukan kode kanonik,
+-- lihat 033_master_data.sql) dibersihkan dulu.
+DELETE FROM verification_stages
+WHERE level IN ('national', 'international');
+
+DELETE FROM verification_stages v
+USING verification_stages d
+WHERE v.stage_order = d.stage_order
+  AND v.name = d.name
+  AND COALESCE(v.level, '') = COALESCE(d.level, '')
+  AND COALESCE(v.category, '') = COALESCE(d.category, '')
+  AND (v.created_at, v.id::text) > (d.created_at, d.id::text);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_stages_unique
+    ON verification_stages (stage_order, name, COALESCE(level, ''), COALESCE(category, ''));
+
+-- Tahap 1: dosen wali untuk semua prestasi
+INSERT INTO verification_stages (stage_order, name, permission)
+VALUES (1, 'advisor', 'achievements.verify')
+ON CONFLICT DO NOTHING;
+
+-- Tahap 2: kemahasiswaan fakultas untuk prestasi nasional & internasional
+INSERT INTO verification_stages (stage_order, name, permission, level)
+VALUES (2, 'faculty', 'achievements.verify_faculty', 'nasional'),
+       (2, 'faculty', 'achievements.verify_faculty', 'internasional')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,67 @@
+-- Verification SLA tracking and escalation job
+
+CREATE TABLE IF NOT EXISTS verification_sla_rules (
+    level                VARCHAR(100) PRIMARY KEY, -- '*' = default untuk semua level
+    reminder_after_hours INT          NOT NULL,
+    escalate_after_hours INT          NOT NULL,
+    updated_at           TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO verification_sla_rules (level, reminder_after_hours, escalate_after_hours)
+VALUES ('*', 72, 168),
+       ('nasional', 48, 120),
+       ('internasional', 48, 120)
+ON CONFLICT (level) DO NOTHING;
+
+-- Umur pending dihitung sejak achievement masuk tahap verifikasi yang sedang berjalan,
+-- bukan sejak disubmit, agar reminder tahap fakultas tidak dikirim ke dosen wali
+ALTER TABLE achievement_references
+    ADD COLUMN IF NOT EXISTS stage_entered_at TIMESTAMP;
+
+UPDATE achievement_references
+SET stage_entered_at = submitted_at
+WHERE stage_entered_at IS NULL AND submitted_at IS NOT NULL;
+
+-- Admin departemen penerima eskalasi, dikelola lewat /verification-sla/department-admins.
+-- Departemen tanpa admin jatuh ke semua admin.
+CREATE TABLE IF NOT EXISTS department_admins (
+    department VARCHAR(100) NOT NULL,
+    user_id    UUID         NOT NULL REFERENCES users(id),
+    PRIMARY KEY (department, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id                   UUID PRIMARY KEY,
+    user_id              UUID         NOT NULL REFERENCES users(id),
+    type                 VARCHAR(50)  NOT NULL,
+    title                VARCHAR(255) NOT NULL,
+    message              TEXT         NOT NULL,
+    mongo_achievement_id VARCHAR(100),
+    is_read              BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS verification_escalations (
+    id                   UUID PRIMARY KEY,
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    escalation_type      VARCHAR(20)  NOT NULL, -- reminder, escalation
+    notified_user_id     UUID         NOT NULL REFERENCES users(id),
+    pending_hours        INT          NOT NULL,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_escalations_achievement
+    ON verification_escalations (mongo_achievement_id, escalation_type);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'verification_sla.manage', 'verification_sla', 'manage',
+        'Kelola aturan SLA verifikasi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'verification_sla.manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,33 @@
+-- Revocation of verified achievements (e.g. forged certificates)
+
+-- Jika status disimpan sebagai enum, tambahkan nilai 'revoked'
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_type WHERE typname = 'achievement_status') THEN
+        ALTER TYPE achievement_status ADD VALUE IF NOT EXISTS 'revoked';
+    END IF;
+END
+$$;
+
+CREATE TABLE IF NOT EXISTS achievement_revocations (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    previous_status      VARCHAR(20)  NOT NULL,
+    reason               TEXT         NOT NULL,
+    revoked_by           UUID         NOT NULL REFERENCES users(id),
+    revoked_at           TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_revocations_mongo_id
+    ON achievement_revocations (mongo_achievement_id);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievements.revoke', 'achievements', 'revoke',
+        'Cabut achievement yang sudah diverifikasi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'achievements.revoke'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,15 @@
+-- Team achievements co-owned by multiple students
+-- Mirror dari achievements.members (MongoDB) untuk ranking dan listing per mahasiswa
+
+CREATE TABLE IF NOT EXISTS achievement_members (
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    student_id           UUID         NOT NULL REFERENCES users(id),
+    role                 VARCHAR(20)  NOT NULL DEFAULT 'member',
+    status               VARCHAR(20)  NOT NULL DEFAULT 'invited',
+    invited_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    responded_at         TIMESTAMP,
+    PRIMARY KEY (mongo_achievement_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_members_student
+    ON achievement_members (student_id, status);
@@ -0,0 +1,66 @@
+-- Category-specific structured achievement details (JSON Schema per category)
+
+CREATE TABLE IF NOT EXISTS achievement_category_schemas (
+    category   VARCHAR(100) PRIMARY KEY, -- disimpan lowercase
+    schema     JSONB        NOT NULL,
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- Kategori 'kompetisi' sudah dipakai client lama yang belum mengirim details, sehingga semua field-nya opsional
+INSERT INTO achievement_category_schemas (category, schema)
+VALUES
+('kompetisi', '{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Detail Kompetisi",
+  "type": "object",
+  "properties": {
+    "rank": {"type": "string", "title": "Peringkat", "minLength": 1},
+    "organizer": {"type": "string", "title": "Penyelenggara", "minLength": 1},
+    "participant_count": {"type": "integer", "title": "Jumlah Peserta", "minimum": 1}
+  },
+  "additionalProperties": false
+}'),
+('publikasi', '{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Detail Publikasi",
+  "type": "object",
+  "required": ["venue", "authors"],
+  "properties": {
+    "doi": {"type": "string", "title": "DOI", "pattern": "^10\\.\\d{4,9}/\\S+$"},
+    "venue": {"type": "string", "title": "Jurnal/Konferensi", "minLength": 1},
+    "authors": {"type": "array", "title": "Penulis", "minItems": 1, "items": {"type": "string", "minLength": 1}}
+  },
+  "additionalProperties": false
+}'),
+('sertifikasi', '{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "title": "Detail Sertifikasi",
+  "type": "object",
+  "required": ["issuer", "credential_id"],
+  "properties": {
+    "issuer": {"type": "string", "title": "Penerbit", "minLength": 1},
+    "credential_id": {"type": "string", "title": "Credential ID", "minLength": 1},
+    "expiry_date": {"type": "string", "title": "Tanggal Kedaluwarsa", "format": "date"}
+  },
+  "additionalProperties": false
+}')
+ON CONFLICT (category) DO NOTHING;
+
+-- Database yang sudah menjalankan seed lama (field kompetisi wajib) dan belum diubah admin
+UPDATE achievement_category_schemas
+SET schema = schema - 'required', updated_at = NOW()
+WHERE category = 'kompetisi'
+  AND updated_by IS NULL
+  AND schema -> 'required' = '["rank", "organizer", "participant_count"]'::jsonb;
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievement_schemas.manage', 'achievement_schemas', 'manage',
+        'Kelola JSON Schema detail achievement per kategori')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'achievement_schemas.manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,59 @@
+-- Master data for achievement categories (hierarchical) and levels (ordered)
+-- Code kanonik lowercase; aliases dipakai untuk mencocokkan nilai lama/bebas (case-insensitive)
+
+CREATE TABLE IF NOT EXISTS achievement_categories (
+    code        VARCHAR(100) PRIMARY KEY,
+    parent_code VARCHAR(100) REFERENCES achievement_categories(code),
+    label_id    VARCHAR(255) NOT NULL,
+    label_en    VARCHAR(255) NOT NULL,
+    aliases     TEXT[]       NOT NULL DEFAULT '{}',
+    sort_order  INT          NOT NULL DEFAULT 0,
+    is_active   BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at  TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS achievement_levels (
+    code       VARCHAR(100) PRIMARY KEY,
+    rank       INT          NOT NULL UNIQUE, -- 1 = terendah
+    label_id   VARCHAR(255) NOT NULL,
+    label_en   VARCHAR(255) NOT NULL,
+    aliases    TEXT[]       NOT NULL DEFAULT '{}',
+    is_active  BOOLEAN      NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO achievement_categories (code, parent_code, label_id, label_en, aliases, sort_order)
+VALUES ('kompetisi', NULL, 'Kompetisi', 'Competition', '{competition,lomba,"academic competition"}', 1),
+       ('publikasi', NULL, 'Publikasi', 'Publication', '{publication,research,penelitian}', 2),
+       ('sertifikasi', NULL, 'Sertifikasi', 'Certification', '{certification,sertifikat,certificate}', 3),
+       ('organisasi', NULL, 'Organisasi', 'Organization', '{organization,organisation}', 4),
+       ('lainnya', NULL, 'Lainnya', 'Other', '{other,others}', 99)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO achievement_categories (code, parent_code, label_id, label_en, aliases, sort_order)
+VALUES ('kompetisi-akademik', 'kompetisi', 'Kompetisi Akademik', 'Academic Competition', '{}', 1),
+       ('kompetisi-olahraga', 'kompetisi', 'Kompetisi Olahraga', 'Sports Competition', '{"sports competition"}', 2),
+       ('kompetisi-seni', 'kompetisi', 'Kompetisi Seni', 'Arts Competition', '{"arts competition"}', 3),
+       ('publikasi-jurnal', 'publikasi', 'Publikasi Jurnal', 'Journal Publication', '{"journal publication"}', 1),
+       ('publikasi-konferensi', 'publikasi', 'Publikasi Konferensi', 'Conference Publication', '{"conference publication"}', 2)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO achievement_levels (code, rank, label_id, label_en, aliases)
+VALUES ('lokal', 1, 'Lokal', 'Local', '{local,kampus,universitas}'),
+       ('regional', 2, 'Regional', 'Regional', '{provinsi,provincial}'),
+       ('nasional', 3, 'Nasional', 'National', '{national}'),
+       ('internasional', 4, 'Internasional', 'International', '{international}')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'master_data.manage', 'master_data', 'manage',
+        'Kelola master data kategori dan level achievement')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'master_data.manage'
+ON CONFLICT DO NOTHING;
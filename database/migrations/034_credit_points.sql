@@ -0,0 +1,74 @@
+-- Achievement credit points engine (SKPI / student activity credits)
+-- Aturan poin berversi; poin dihitung saat achievement terverifikasi dan disimpan bersama versi aturan
+
+CREATE TABLE IF NOT EXISTS credit_rule_versions (
+    version      SERIAL PRIMARY KEY,
+    note         TEXT,
+    is_active    BOOLEAN   NOT NULL DEFAULT FALSE,
+    created_by   UUID REFERENCES users(id),
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    activated_at TIMESTAMP
+);
+
+-- Hanya satu versi aktif
+CREATE UNIQUE INDEX IF NOT EXISTS idx_credit_rule_versions_active
+    ON credit_rule_versions (is_active) WHERE is_active;
+
+CREATE TABLE IF NOT EXISTS credit_point_rules (
+    id       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    version  INT           NOT NULL REFERENCES credit_rule_versions(version),
+    category VARCHAR(100)  NOT NULL DEFAULT '*', -- code kategori master data atau '*'
+    level    VARCHAR(100)  NOT NULL DEFAULT '*', -- code level master data atau '*'
+    rank     VARCHAR(100)  NOT NULL DEFAULT '*', -- nilai details.rank (case-insensitive) atau '*'
+    points   NUMERIC(8, 2) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_credit_point_rules_version ON credit_point_rules (version);
+
+CREATE TABLE IF NOT EXISTS achievement_credit_points (
+    mongo_achievement_id VARCHAR(100)  NOT NULL,
+    student_id           UUID          NOT NULL REFERENCES users(id),
+    points               NUMERIC(8, 2) NOT NULL,
+    rule_version         INT           NOT NULL REFERENCES credit_rule_versions(version),
+    rule_id              UUID REFERENCES credit_point_rules(id),
+    computed_at          TIMESTAMP     NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (mongo_achievement_id, student_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_credit_points_student ON achievement_credit_points (student_id);
+
+-- Versi awal
+INSERT INTO credit_rule_versions (version, note, is_active, activated_at)
+VALUES (1, 'Aturan awal poin prestasi', TRUE, NOW())
+ON CONFLICT (version) DO NOTHING;
+
+SELECT setval('credit_rule_versions_version_seq', (SELECT MAX(version) FROM credit_rule_versions));
+
+INSERT INTO credit_point_rules (version, category, level, rank, points)
+SELECT 1, r.category, r.level, r.rank, r.points
+FROM (VALUES
+    ('*', 'lokal', '*', 5),
+    ('*', 'regional', '*', 10),
+    ('*', 'nasional', '*', 20),
+    ('*', 'internasional', '*', 40),
+    ('kompetisi', 'nasional', 'Juara 1', 50),
+    ('kompetisi', 'nasional', 'Juara 2', 40),
+    ('kompetisi', 'nasional', 'Juara 3', 30),
+    ('kompetisi', 'internasional', 'Juara 1', 100),
+    ('kompetisi', 'internasional', 'Juara 2', 80),
+    ('kompetisi', 'internasional', 'Juara 3', 60),
+    ('publikasi', '*', '*', 30),
+    ('sertifikasi', '*', '*', 10)
+) AS r(category, level, rank, points)
+WHERE NOT EXISTS (SELECT 1 FROM credit_point_rules WHERE version = 1);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'credit_points.manage', 'credit_points', 'manage',
+        'Kelola aturan poin prestasi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'credit_points.manage'
+ON CONFLICT DO NOTHING;
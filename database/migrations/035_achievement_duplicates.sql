@@ -0,0 +1,25 @@
+-- Duplicate achievement detection
+-- Kemungkinan duplikat yang terdeteksi saat achievement disubmit untuk verifikasi
+
+CREATE TABLE IF NOT EXISTS achievement_duplicate_flags (
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    duplicate_of_id      VARCHAR(100) NOT NULL,
+    score                NUMERIC(5, 4) NOT NULL,
+    reasons              TEXT[]       NOT NULL DEFAULT '{}', -- document_hash, title, date, category
+    detected_at          TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (mongo_achievement_id, duplicate_of_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_duplicate_flags_duplicate_of
+    ON achievement_duplicate_flags (duplicate_of_id);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievements.duplicates', 'achievements', 'duplicates',
+        'Lihat laporan kemungkinan duplikat prestasi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'achievements.duplicates'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,4 @@
+-- Optimistic concurrency untuk users
+-- Versi dinaikkan setiap perubahan dan dikirim ke klien sebagai ETag
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
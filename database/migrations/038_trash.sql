@@ -0,0 +1,16 @@
+-- Trash: daftar & restore data yang di-soft delete
+-- Data di trash dihapus permanen oleh job trash-purge setelah TRASH_RETENTION_DAYS
+
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at
+    ON users (deleted_at) WHERE deleted_at IS NOT NULL;
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'trash.manage', 'trash', 'manage',
+        'Lihat, pulihkan dan kelola data yang dihapus')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'trash.manage'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,32 @@
+-- Transactional outbox untuk dual write PostgreSQL -> MongoDB
+-- Perubahan status achievement dicatat di sini dalam transaksi yang sama dengan
+-- achievement_references, lalu diterapkan ke MongoDB oleh relay (idempotent)
+
+CREATE TABLE IF NOT EXISTS achievement_outbox (
+    id                   BIGSERIAL    PRIMARY KEY,
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    event_type           VARCHAR(50)  NOT NULL, -- achievement.created, achievement.status_changed, ...
+    payload              JSONB        NOT NULL DEFAULT '{}',
+    attempts             INT          NOT NULL DEFAULT 0,
+    last_error           TEXT,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    processed_at         TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_outbox_pending
+    ON achievement_outbox (id) WHERE processed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_outbox_achievement
+    ON achievement_outbox (mongo_achievement_id, id) WHERE processed_at IS NULL;
+
+-- Dead letter: event yang gagal diterapkan sebanyak batas percobaan ditandai failed_at dan tidak
+-- lagi diambil relay, sehingga tidak menahan event yang lebih baru. Untuk mencoba ulang setelah
+-- penyebabnya diperbaiki: UPDATE achievement_outbox SET failed_at = NULL, attempts = 0 WHERE id = ...
+ALTER TABLE achievement_outbox ADD COLUMN IF NOT EXISTS failed_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_achievement_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_achievement_outbox_pending
+    ON achievement_outbox (id) WHERE processed_at IS NULL AND failed_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_outbox_failed
+    ON achievement_outbox (id) WHERE processed_at IS NULL AND failed_at IS NOT NULL;
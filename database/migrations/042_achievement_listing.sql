@@ -0,0 +1,27 @@
+-- Rich filtering on the all-achievements listing (GET /achievements/all)
+-- Index untuk filter tanggal, verifikator dan data mahasiswa
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_submitted_at
+    ON achievement_references (submitted_at) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_verified_at
+    ON achievement_references (verified_at) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_achievement_references_verified_by
+    ON achievement_references (verified_by) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_students_advisor_id ON students (advisor_id);
+
+CREATE INDEX IF NOT EXISTS idx_students_program_study_academic_year
+    ON students (LOWER(program_study), academic_year);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievements.read_all', 'achievements', 'read_all',
+        'Lihat dan filter semua prestasi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'achievements.read_all'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,44 @@
+-- Denormalized achievement read model
+-- Satu baris per achievement aktif: gabungan achievement_references, dokumen MongoDB dan data mahasiswa.
+-- Diperbarui setiap kali achievement berubah; bisa dibangun ulang dengan: go run ./cmd/rebuild-read-model
+
+CREATE TABLE IF NOT EXISTS achievement_read_model (
+    mongo_achievement_id VARCHAR(100) PRIMARY KEY,
+    reference_id         UUID         NOT NULL,
+    student_id           UUID         NOT NULL,
+    status               VARCHAR(20)  NOT NULL,
+    current_stage        INT          NOT NULL DEFAULT 0,
+    total_stages         INT          NOT NULL DEFAULT 0,
+    submitted_at         TIMESTAMP,
+    verified_at          TIMESTAMP,
+    verified_by          UUID,
+    rejection_note       TEXT,
+    created_at           TIMESTAMP    NOT NULL,
+    updated_at           TIMESTAMP    NOT NULL,
+
+    -- Data mahasiswa (students + users)
+    student_number       VARCHAR(50),
+    student_name         VARCHAR(255),
+    program_study        VARCHAR(255),
+    academic_year        VARCHAR(20),
+    advisor_id           UUID,
+
+    -- Data dokumen MongoDB
+    title                TEXT         NOT NULL DEFAULT '',
+    category             VARCHAR(100) NOT NULL DEFAULT '',
+    level                VARCHAR(100) NOT NULL DEFAULT '',
+    achievement_date     TIMESTAMP,
+    tags                 TEXT[]       NOT NULL DEFAULT '{}',
+    member_ids           TEXT[]       NOT NULL DEFAULT '{}', -- anggota tim yang sudah konfirmasi
+    document             JSONB        NOT NULL,              -- dokumen lengkap untuk response listing
+
+    projected_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_student ON achievement_read_model (student_id);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_members ON achievement_read_model USING GIN (member_ids);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_status ON achievement_read_model (status, submitted_at);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_created ON achievement_read_model (created_at DESC, mongo_achievement_id);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_advisor ON achievement_read_model (advisor_id);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_category_level ON achievement_read_model (category, level);
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_date ON achievement_read_model (achievement_date);
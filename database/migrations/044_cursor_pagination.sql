@@ -0,0 +1,16 @@
+-- Cursor-based (keyset) pagination untuk listing besar
+-- Index mengikuti urutan listing (kolom sort + ID pemecah seri, arah sama) agar halaman berikutnya
+-- dibaca langsung dari posisi cursor tanpa OFFSET.
+
+CREATE INDEX IF NOT EXISTS idx_users_created_at_id
+    ON users (created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_students_created_at_id ON students (created_at DESC, id DESC);
+
+DROP INDEX IF EXISTS idx_achievement_read_model_created;
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_created
+    ON achievement_read_model (created_at DESC, mongo_achievement_id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_pending
+    ON achievement_read_model ((COALESCE(submitted_at, 'infinity'::timestamp)), mongo_achievement_id)
+    WHERE status = 'submitted';
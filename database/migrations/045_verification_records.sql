@@ -0,0 +1,49 @@
+-- Publicly verifiable achievement certificates
+-- Snapshot data achievement terverifikasi yang ditandatangani (HMAC) dan bisa dicek publik lewat kode pendek:
+-- GET /public/verify/:code
+
+CREATE TABLE IF NOT EXISTS achievement_verification_records (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code                 VARCHAR(16)  NOT NULL UNIQUE,
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    title                TEXT         NOT NULL,
+    holder_name          VARCHAR(255) NOT NULL,
+    level                VARCHAR(255) NOT NULL,
+    verified_at          TIMESTAMP    NOT NULL,
+    verifier_institution VARCHAR(255) NOT NULL,
+    signature            VARCHAR(64)  NOT NULL, -- HMAC-SHA256 (hex) atas seluruh data publik
+    issued_at            TIMESTAMP    NOT NULL,
+    revoked_at           TIMESTAMP,
+    revoked_by           UUID REFERENCES users(id),
+    revoke_reason        TEXT
+);
+
+-- Satu record aktif per achievement; record baru bisa diterbitkan setelah yang lama dicabut
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_records_active
+    ON achievement_verification_records (mongo_achievement_id) WHERE revoked_at IS NULL;
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'verification_records.revoke', 'verification_records', 'revoke',
+        'Cabut kode verifikasi publik achievement')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'verification_records.revoke'
+ON CONFLICT DO NOTHING;
+
+-- Kode yang dicabut tidak diterbitkan ulang otomatis; hanya admin yang bisa menerbitkan ulang
+-- (POST /achievements/:id/verification-record) dan penerbitnya dicatat di issued_by
+ALTER TABLE achievement_verification_records ADD COLUMN IF NOT EXISTS issued_by UUID REFERENCES users(id);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'verification_records.reissue', 'verification_records', 'reissue',
+        'Terbitkan ulang kode verifikasi publik achievement setelah dicabut')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'verification_records.reissue'
+ON CONFLICT DO NOTHING;
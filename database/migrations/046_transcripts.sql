@@ -0,0 +1,36 @@
+-- PDF achievement transcript (SKPI supplement)
+
+-- Template tampilan transkrip per program studi; scope 'default' dipakai jika program studi tidak punya template
+CREATE TABLE IF NOT EXISTS transcript_templates (
+    scope      VARCHAR(255) PRIMARY KEY, -- nama program studi (lowercase) atau 'default'
+    layout     JSONB        NOT NULL,
+    updated_by UUID REFERENCES users(id),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+-- Setiap transkrip yang dicetak diberi kode verifikasi publik (QR di dokumen); datanya snapshot yang ditandatangani
+CREATE TABLE IF NOT EXISTS transcript_verification_records (
+    id                   UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code                 VARCHAR(16)    NOT NULL UNIQUE,
+    student_id           UUID           NOT NULL REFERENCES users(id),
+    holder_name          VARCHAR(255)   NOT NULL,
+    achievement_count    INT            NOT NULL,
+    total_points         NUMERIC(10, 2) NOT NULL,
+    verifier_institution VARCHAR(255)   NOT NULL,
+    signature            VARCHAR(64)    NOT NULL,
+    issued_at            TIMESTAMP      NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcript_verification_records_student
+    ON transcript_verification_records (student_id);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'transcript_templates.manage', 'transcript_templates', 'manage',
+        'Kelola template transkrip prestasi per program studi')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'transcript_templates.manage'
+ON CONFLICT DO NOTHING;
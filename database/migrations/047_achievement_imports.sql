@@ -0,0 +1,31 @@
+-- Import achievement massal dari CSV/XLSX
+-- Import yang dijalankan (bukan dry-run) diproses di background; progress dan error per baris disimpan di sini.
+
+CREATE TABLE IF NOT EXISTS achievement_import_jobs (
+    id             UUID PRIMARY KEY,
+    filename       VARCHAR(255) NOT NULL,
+    status         VARCHAR(20)  NOT NULL DEFAULT 'pending', -- pending, running, completed, failed
+    pre_verified   BOOLEAN      NOT NULL DEFAULT FALSE,
+    total_rows     INT          NOT NULL DEFAULT 0,
+    processed_rows INT          NOT NULL DEFAULT 0,
+    created_count  INT          NOT NULL DEFAULT 0,
+    failed_count   INT          NOT NULL DEFAULT 0,
+    errors         JSONB        NOT NULL DEFAULT '[]',
+    created_by     UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at     TIMESTAMP    NOT NULL DEFAULT NOW(),
+    started_at     TIMESTAMP,
+    finished_at    TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_import_jobs_created ON achievement_import_jobs (created_at DESC);
+
+INSERT INTO permissions (id, name, resource, action, description)
+VALUES (gen_random_uuid(), 'achievements.import', 'achievements', 'import',
+        'Import achievement massal dari CSV/XLSX')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r, permissions p
+WHERE LOWER(r.name) = 'admin' AND p.name = 'achievements.import'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,21 @@
+-- Export CSV/XLSX di background
+-- Export kecil di-stream langsung; export besar ditulis ke file lalu diunduh pemiliknya sampai expires_at.
+
+CREATE TABLE IF NOT EXISTS export_jobs (
+    id          UUID PRIMARY KEY,
+    user_id     UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind        VARCHAR(50)  NOT NULL, -- achievements, students, advisees, statistics
+    format      VARCHAR(10)  NOT NULL, -- csv, xlsx
+    status      VARCHAR(20)  NOT NULL DEFAULT 'pending', -- pending, running, completed, failed
+    filename    VARCHAR(255) NOT NULL,
+    file_path   VARCHAR(500) NOT NULL DEFAULT '',
+    row_count   INT          NOT NULL DEFAULT 0,
+    file_size   BIGINT       NOT NULL DEFAULT 0,
+    error       TEXT,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP,
+    expires_at  TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_export_jobs_user ON export_jobs (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_export_jobs_expires ON export_jobs (expires_at);
@@ -0,0 +1,21 @@
+-- Portofolio publik mahasiswa (opt-in)
+-- Hanya achievement terverifikasi yang tampil; mahasiswa bisa menyembunyikan achievement tertentu.
+
+CREATE TABLE IF NOT EXISTS student_portfolios (
+    student_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    slug       VARCHAR(50)  NOT NULL, -- huruf kecil, angka dan tanda hubung
+    is_public  BOOLEAN      NOT NULL DEFAULT FALSE,
+    headline   VARCHAR(150) NOT NULL DEFAULT '',
+    bio        TEXT         NOT NULL DEFAULT '',
+    created_at TIMESTAMP    NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_student_portfolios_slug ON student_portfolios (slug);
+
+CREATE TABLE IF NOT EXISTS portfolio_hidden_achievements (
+    student_id           UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mongo_achievement_id VARCHAR(100) NOT NULL,
+    created_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (student_id, mongo_achievement_id)
+);
@@ -0,0 +1,32 @@
+-- Inbox verifikasi dosen: delegasi verifikasi antar dosen wali dan klaim review
+-- agar satu submission tidak dikerjakan dua verifikator sekaligus.
+
+CREATE TABLE IF NOT EXISTS verification_delegations (
+    id           UUID PRIMARY KEY,
+    delegator_id UUID      NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- dosen wali yang melimpahkan
+    delegate_id  UUID      NOT NULL REFERENCES users(id) ON DELETE CASCADE, -- dosen yang menerima
+    starts_at    TIMESTAMP NOT NULL,
+    ends_at      TIMESTAMP NOT NULL,
+    reason       TEXT,
+    created_by   UUID      REFERENCES users(id) ON DELETE SET NULL,
+    created_at   TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at   TIMESTAMP,
+    CHECK (ends_at > starts_at),
+    CHECK (delegator_id <> delegate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_verification_delegations_delegate
+    ON verification_delegations (delegate_id, ends_at) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_verification_delegations_delegator
+    ON verification_delegations (delegator_id, ends_at);
+
+-- Satu klaim per achievement; klaim yang sudah lewat expires_at boleh diambil alih
+CREATE TABLE IF NOT EXISTS verification_claims (
+    mongo_achievement_id VARCHAR(100) PRIMARY KEY,
+    claimed_by           UUID         NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    claimed_at           TIMESTAMP    NOT NULL DEFAULT NOW(),
+    expires_at           TIMESTAMP    NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_achievement_read_model_inbox
+    ON achievement_read_model (advisor_id, submitted_at) WHERE status = 'submitted';
@@ -21,11 +21,17 @@
 package main
 
 import (
+	"context"
+	"crud-app/app/job"
+	"crud-app/app/repository"
 	"crud-app/app/utils"
 	"crud-app/database"
 	"crud-app/route"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/joho/godotenv"
@@ -52,9 +58,46 @@ func main() {
 	defer database.CloseDB(mongoClient)
 	mongoDB := database.GetMongoDatabase()
 
+	// Text index pencarian achievement (idempotent)
+	if err := repository.NewAchievementRepository(mongoDB).EnsureSearchIndex(context.Background()); err != nil {
+		log.Printf("Gagal membuat search index achievement: %v", err)
+	}
+
+	// Job import dan export berjalan di dalam proses; yang terputus oleh restart ditandai gagal
+	if interrupted, err := repository.NewAchievementImportRepository(database.DB).FailInterrupted(); err != nil {
+		log.Printf("Gagal menandai job import yang terputus: %v", err)
+	} else if interrupted > 0 {
+		log.Printf("%d job import yang terputus ditandai gagal", interrupted)
+	}
+	if interrupted, err := repository.NewExportRepository(database.DB).FailInterrupted(); err != nil {
+		log.Printf("Gagal menandai job export yang terputus: %v", err)
+	} else if interrupted > 0 {
+		log.Printf("%d job export yang terputus ditandai gagal", interrupted)
+	}
+
 	utils.InitCache()
 	log.Println("Permission cache initialized")
 
+	// Background jobs
+	scheduler := job.NewScheduler()
+	slaInterval := time.Duration(utils.GetEnvInt("SLA_CHECK_INTERVAL_MINUTES", 60)) * time.Minute
+	scheduler.Register("verification-sla", slaInterval, job.NewVerificationSLAJob(mongoDB, database.DB).Run)
+	trashInterval := time.Duration(utils.GetEnvInt("TRASH_PURGE_INTERVAL_MINUTES", 1440)) * time.Minute
+	scheduler.Register("trash-purge", trashInterval, job.NewTrashPurgeJob(mongoDB, database.DB).Run)
+	outboxInterval := time.Duration(utils.GetEnvInt("OUTBOX_RELAY_INTERVAL_SECONDS", 30)) * time.Second
+	outboxBatch := utils.GetEnvInt("OUTBOX_RELAY_BATCH_SIZE", 100)
+	scheduler.Register("outbox-relay", outboxInterval, job.NewOutboxRelayJob(mongoDB, database.DB, outboxBatch).Run)
+	consistencyInterval := time.Duration(utils.GetEnvInt("CONSISTENCY_CHECK_INTERVAL_MINUTES", 360)) * time.Minute
+	consistencyRepair := utils.GetEnvBool("CONSISTENCY_REPAIR", false)
+	consistencySource := os.Getenv("CONSISTENCY_SOURCE_OF_TRUTH")
+	if consistencySource == "" {
+		consistencySource = "postgres"
+	}
+	scheduler.Register("consistency-check", consistencyInterval, job.NewConsistencyCheckJob(mongoDB, database.DB, consistencyRepair, consistencySource).Run)
+	exportCleanupInterval := time.Duration(utils.GetEnvInt("EXPORT_CLEANUP_INTERVAL_MINUTES", 60)) * time.Minute
+	scheduler.Register("export-cleanup", exportCleanupInterval, job.NewExportCleanupJob(database.DB).Run)
+	scheduler.Start()
+
 	app := fiber.New()
 
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
@@ -69,5 +112,26 @@ func main() {
 	log.Printf("Server running on http://localhost:%s", port)
 	log.Printf("Swagger docs: http://localhost:%s/swagger/", port)
 
-	log.Fatal(app.Listen(":" + port))
+	// Listen di goroutine agar SIGINT/SIGTERM bisa menghentikan server dan job background dengan rapi
+	listenErr := make(chan error, 1)
+	go func() {
+		listenErr <- app.Listen(":" + port)
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+
+	select {
+	case err := <-listenErr:
+		scheduler.Stop()
+		log.Fatal(err)
+	case sig := <-quit:
+		log.Printf("Menerima sinyal %s, menghentikan server...", sig)
+	}
+
+	if err := app.ShutdownWithTimeout(30 * time.Second); err != nil {
+		log.Printf("Gagal menghentikan server dengan rapi: %v", err)
+	}
+	scheduler.Stop()
+	log.Println("Server berhenti")
 }
\ No newline at end of file
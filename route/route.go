@@ -3,7 +3,9 @@ package route
 import (
 	"crud-app/app/middleware"
 	"crud-app/app/service"
+	"crud-app/app/utils"
 	"database/sql"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -14,10 +16,40 @@ func Routes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
 	authService := service.NewAuthService(db)
 	achievementService := service.NewAchievementService(mongoDB, db)
 	userService := service.NewUserService(db)
+	verificationStageService := service.NewVerificationStageService(db)
+	verificationSLAService := service.NewVerificationSLAService(db)
+	notificationService := service.NewNotificationService(db)
+	commentService := service.NewCommentService(mongoDB, db)
+	achievementSchemaService := service.NewAchievementSchemaService(db)
+	masterDataService := service.NewMasterDataService(mongoDB, db)
+	creditPointService := service.NewCreditPointService(db)
+	trashService := service.NewTrashService(mongoDB, db)
+	verificationRecordService := service.NewVerificationRecordService(mongoDB, db)
+	transcriptService := service.NewTranscriptService(mongoDB, db)
+	achievementImportService := service.NewAchievementImportService(mongoDB, db)
+	exportService := service.NewExportService(db)
+	portfolioService := service.NewPortfolioService(mongoDB, db)
+	verificationDelegationService := service.NewVerificationDelegationService(db)
 
 	// Initialize RBAC middleware
 	rbac := middleware.NewRBACMiddleware(db)
 
+	// Metrics (format Prometheus, opsional dilindungi METRICS_TOKEN)
+	app.Get("/metrics", service.Metrics)
+
+	// Verifikasi publik achievement (tanpa login, dibatasi per IP)
+	public := app.Group("/public")
+	public.Get("/verify/:code", middleware.RateLimit(
+		utils.GetEnvInt("PUBLIC_VERIFY_RATE_LIMIT", 30),
+		time.Duration(utils.GetEnvInt("PUBLIC_VERIFY_RATE_WINDOW_SECONDS", 60))*time.Second,
+	), verificationRecordService.VerifyPublic)
+
+	// Portofolio publik mahasiswa (opt-in, hanya baca, dibatasi per IP)
+	public.Get("/portfolios/:slug", middleware.RateLimit(
+		utils.GetEnvInt("PUBLIC_PORTFOLIO_RATE_LIMIT", 60),
+		time.Duration(utils.GetEnvInt("PUBLIC_PORTFOLIO_RATE_WINDOW_SECONDS", 60))*time.Second,
+	), portfolioService.GetPublicPortfolio)
+
 	// API routes
 	api := app.Group("/api/v1")
 
@@ -35,6 +67,7 @@ func Routes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
 	users.Get("/:id", rbac.RequirePermission("users.read"), userService.GetUserByID)
 	users.Post("/", rbac.RequirePermission("users.create"), userService.CreateUser)
 	users.Put("/:id", rbac.RequirePermission("users.update"), userService.UpdateUser)
+	users.Patch("/:id", rbac.RequirePermission("users.update"), userService.PatchUser)
 	users.Delete("/:id", rbac.RequirePermission("users.delete"), userService.DeleteUser)
 	users.Put("/:id/role", rbac.RequirePermission("users.assign_role"), userService.AssignRole)
 
@@ -44,38 +77,168 @@ func Routes(app *fiber.App, db *sql.DB, mongoDB *mongo.Database) {
 
 	// List & Detail
 	achievements.Get("/", rbac.RequirePermission("achievements.read"), achievementService.GetMyAchievements)
+	achievements.Get("/all", rbac.RequirePermission("achievements.read_all"), achievementService.GetAllAchievements)
+	achievements.Get("/all/export", rbac.RequirePermission("achievements.read_all"), achievementService.ExportAllAchievements)
+	achievements.Get("/search", rbac.RequirePermission("achievements.read"), achievementService.SearchAchievements)
+	achievements.Get("/pending", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.GetPendingVerification)
 	achievements.Get("/:id", rbac.RequirePermission("achievements.read"), achievementService.GetAchievementByID)
 
 	// CRUD Operations (Mahasiswa)
 	achievements.Post("/", rbac.RequirePermission("achievements.create"), achievementService.SubmitAchievement)
 	achievements.Put("/:id", rbac.RequirePermission("achievements.update"), achievementService.UpdateAchievement)
+	achievements.Patch("/:id", rbac.RequirePermission("achievements.update"), achievementService.PatchAchievement)
 	achievements.Delete("/:id", rbac.RequirePermission("achievements.delete"), achievementService.DeleteAchievement)
 
 	// Workflow Operations
 	achievements.Post("/:id/submit", rbac.RequirePermission("achievements.create"), achievementService.SubmitForVerification)
-	achievements.Post("/:id/verify", rbac.RequirePermission("achievements.verify"), achievementService.ApproveAchievement)
-	achievements.Post("/:id/reject", rbac.RequirePermission("achievements.verify"), achievementService.RejectAchievement)
+	// Permission per tahap verifikasi dicek di service
+	achievements.Post("/:id/verify", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.ApproveAchievement)
+	achievements.Post("/:id/reject", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.RejectAchievement)
+	achievements.Post("/bulk-review", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.BulkReviewAchievements)
+	achievements.Get("/:id/review", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.ReviewAchievementDetail)
+	achievements.Post("/:id/claim", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.ClaimReview)
+	achievements.Delete("/:id/claim", rbac.RequireAnyPermission("achievements.verify", "achievements.verify_faculty"), achievementService.ReleaseReview)
+	achievements.Post("/:id/revoke", rbac.RequirePermission("achievements.revoke"), achievementService.RevokeAchievement)
+
+	// Team Members
+	achievements.Post("/:id/members", rbac.RequirePermission("achievements.update"), achievementService.InviteTeamMember)
+	achievements.Post("/:id/members/respond", rbac.RequirePermission("achievements.read"), achievementService.RespondTeamInvitation)
+	achievements.Delete("/:id/members/:studentId", rbac.RequirePermission("achievements.update"), achievementService.RemoveTeamMember)
 
 	// History & Attachments
 	achievements.Get("/:id/history", rbac.RequirePermission("achievements.read"), achievementService.GetAchievementHistory)
 	achievements.Post("/:id/attachments", rbac.RequirePermission("achievements.create"), achievementService.UploadAttachment)
 
+	// Public Verification Record
+	achievements.Get("/:id/verification-record", rbac.RequirePermission("achievements.read"), verificationRecordService.GetVerificationRecord)
+	achievements.Post("/:id/verification-record", rbac.RequirePermission("verification_records.reissue"), verificationRecordService.ReissueVerificationRecord)
+	achievements.Delete("/:id/verification-record", rbac.RequirePermission("verification_records.revoke"), verificationRecordService.RevokeVerificationRecord)
+
+	// Comments
+	achievements.Get("/:id/comments", rbac.RequirePermission("achievements.read"), commentService.GetComments)
+	achievements.Post("/:id/comments", rbac.RequirePermission("achievements.read"), commentService.CreateComment)
+	achievements.Put("/:id/comments/:commentId", rbac.RequirePermission("achievements.read"), commentService.UpdateComment)
+	achievements.Delete("/:id/comments/:commentId", rbac.RequirePermission("achievements.read"), commentService.DeleteComment)
+
+	// Achievement Detail Schemas Routes
+	schemas := api.Group("/achievement-schemas")
+	schemas.Use(middleware.AuthRequired())
+	schemas.Get("/", rbac.RequirePermission("achievements.read"), achievementSchemaService.GetSchemas)
+	schemas.Get("/:category", rbac.RequirePermission("achievements.read"), achievementSchemaService.GetSchema)
+	schemas.Put("/:category", rbac.RequirePermission("achievement_schemas.manage"), achievementSchemaService.UpsertSchema)
+	schemas.Delete("/:category", rbac.RequirePermission("achievement_schemas.manage"), achievementSchemaService.DeleteSchema)
+
+	// Master Data Routes
+	masterData := api.Group("/master-data")
+	masterData.Use(middleware.AuthRequired())
+	masterData.Get("/categories", rbac.RequirePermission("achievements.read"), masterDataService.GetCategories)
+	masterData.Post("/categories", rbac.RequirePermission("master_data.manage"), masterDataService.CreateCategory)
+	masterData.Put("/categories/:code", rbac.RequirePermission("master_data.manage"), masterDataService.UpdateCategory)
+	masterData.Delete("/categories/:code", rbac.RequirePermission("master_data.manage"), masterDataService.DeleteCategory)
+	masterData.Get("/levels", rbac.RequirePermission("achievements.read"), masterDataService.GetLevels)
+	masterData.Post("/levels", rbac.RequirePermission("master_data.manage"), masterDataService.CreateLevel)
+	masterData.Put("/levels/:code", rbac.RequirePermission("master_data.manage"), masterDataService.UpdateLevel)
+	masterData.Delete("/levels/:code", rbac.RequirePermission("master_data.manage"), masterDataService.DeleteLevel)
+
+	// Credit Point Rules Routes
+	creditRules := api.Group("/credit-rules")
+	creditRules.Use(middleware.AuthRequired())
+	creditRules.Get("/", rbac.RequirePermission("credit_points.manage"), creditPointService.GetRuleVersions)
+	creditRules.Get("/:version", rbac.RequirePermission("credit_points.manage"), creditPointService.GetRuleVersion)
+	creditRules.Post("/", rbac.RequirePermission("credit_points.manage"), creditPointService.CreateRuleVersion)
+	creditRules.Post("/:version/activate", rbac.RequirePermission("credit_points.manage"), creditPointService.ActivateRuleVersion)
+
+	// Trash Routes (soft-deleted data)
+	trash := api.Group("/trash")
+	trash.Use(middleware.AuthRequired())
+	trash.Get("/achievements", rbac.RequirePermission("trash.manage"), trashService.GetTrashedAchievements)
+	trash.Post("/achievements/:id/restore", rbac.RequirePermission("trash.manage"), trashService.RestoreAchievement)
+	trash.Get("/users", rbac.RequirePermission("trash.manage"), trashService.GetTrashedUsers)
+	trash.Post("/users/:id/restore", rbac.RequirePermission("trash.manage"), trashService.RestoreUser)
+
 	// Students & Lecturers Routes
 	students := api.Group("/students")
 	students.Use(middleware.AuthRequired())
 	students.Get("/", rbac.RequirePermission("students.read"), userService.GetStudents)
+	students.Get("/export", rbac.RequirePermission("students.read"), userService.ExportStudents)
 	students.Get("/:id", rbac.RequirePermission("students.read"), userService.GetStudentByID)
 	students.Get("/:id/achievements", rbac.RequirePermission("achievements.read"), achievementService.GetStudentAchievements)
 	students.Put("/:id/advisor", rbac.RequirePermission("students.assign_advisor"), userService.AssignAdvisor)
+	students.Patch("/:id/profile", rbac.RequirePermission("users.update"), userService.PatchStudentProfile)
 
 	lecturers := api.Group("/lecturers")
 	lecturers.Use(middleware.AuthRequired())
 	lecturers.Get("/", rbac.RequirePermission("lecturers.read"), userService.GetLecturers)
 	lecturers.Get("/:id/advisees", rbac.RequirePermission("lecturers.read"), userService.GetAdvisees)
+	lecturers.Get("/:id/advisees/export", rbac.RequirePermission("lecturers.read"), userService.ExportAdvisees)
+	lecturers.Patch("/:id/profile", rbac.RequirePermission("users.update"), userService.PatchLecturerProfile)
+
+	// Verification Stages Routes
+	stages := api.Group("/verification-stages")
+	stages.Use(middleware.AuthRequired())
+	stages.Get("/", rbac.RequirePermission("verification_stages.manage"), verificationStageService.GetStages)
+	stages.Post("/", rbac.RequirePermission("verification_stages.manage"), verificationStageService.CreateStage)
+	stages.Delete("/:id", rbac.RequirePermission("verification_stages.manage"), verificationStageService.DeleteStage)
+
+	// Verification Delegation Routes (dosen wali melimpahkan inbox verifikasi, admin mengelola semua)
+	delegations := api.Group("/verification-delegations")
+	delegations.Use(middleware.AuthRequired())
+	delegations.Get("/", rbac.RequireAnyPermission("achievements.verify", "verification_stages.manage"), verificationDelegationService.GetDelegations)
+	delegations.Post("/", rbac.RequireAnyPermission("achievements.verify", "verification_stages.manage"), verificationDelegationService.CreateDelegation)
+	delegations.Delete("/:id", rbac.RequireAnyPermission("achievements.verify", "verification_stages.manage"), verificationDelegationService.RevokeDelegation)
+
+	// Verification SLA Routes
+	sla := api.Group("/verification-sla")
+	sla.Use(middleware.AuthRequired())
+	sla.Get("/", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.GetSLARules)
+	sla.Get("/department-admins", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.GetDepartmentAdmins)
+	sla.Post("/department-admins", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.AddDepartmentAdmin)
+	sla.Delete("/department-admins/:department/:userId", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.RemoveDepartmentAdmin)
+	sla.Put("/:level", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.UpsertSLARule)
+	sla.Delete("/:level", rbac.RequirePermission("verification_sla.manage"), verificationSLAService.DeleteSLARule)
+
+	// Achievement Imports Routes (CSV/XLSX)
+	imports := api.Group("/achievement-imports")
+	imports.Use(middleware.AuthRequired())
+	imports.Post("/", rbac.RequirePermission("achievements.import"), achievementImportService.ImportAchievements)
+	imports.Get("/", rbac.RequirePermission("achievements.import"), achievementImportService.GetImportJobs)
+	imports.Get("/:id", rbac.RequirePermission("achievements.import"), achievementImportService.GetImportJob)
+
+	// Transcript Templates Routes
+	transcriptTemplates := api.Group("/transcript-templates")
+	transcriptTemplates.Use(middleware.AuthRequired())
+	transcriptTemplates.Get("/", rbac.RequirePermission("transcript_templates.manage"), transcriptService.GetTranscriptTemplates)
+	transcriptTemplates.Get("/:scope", rbac.RequirePermission("transcript_templates.manage"), transcriptService.GetTranscriptTemplate)
+	transcriptTemplates.Put("/:scope", rbac.RequirePermission("transcript_templates.manage"), transcriptService.UpsertTranscriptTemplate)
+	transcriptTemplates.Delete("/:scope", rbac.RequirePermission("transcript_templates.manage"), transcriptService.DeleteTranscriptTemplate)
+
+	// Portfolio Routes (pengaturan portofolio publik milik mahasiswa yang login)
+	portfolio := api.Group("/portfolio")
+	portfolio.Use(middleware.AuthRequired())
+	portfolio.Get("/", rbac.RequirePermission("achievements.create"), portfolioService.GetMyPortfolio)
+	portfolio.Put("/", rbac.RequirePermission("achievements.create"), portfolioService.UpdateMyPortfolio)
+	portfolio.Put("/achievements/:id/visibility", rbac.RequirePermission("achievements.create"), portfolioService.SetPortfolioAchievementVisibility)
+
+	// Exports Routes (status dan download export background; hanya pemilik atau admin)
+	exports := api.Group("/exports")
+	exports.Use(middleware.AuthRequired())
+	exports.Get("/:id", exportService.GetExportJob)
+	exports.Get("/:id/download", exportService.DownloadExport)
+
+	// Notifications Routes
+	notifications := api.Group("/notifications")
+	notifications.Use(middleware.AuthRequired())
+	notifications.Get("/", notificationService.GetMyNotifications)
+	notifications.Put("/:id/read", notificationService.MarkNotificationRead)
 
 	// Reports & Analytics Routes
 	reports := api.Group("/reports")
 	reports.Use(middleware.AuthRequired())
 	reports.Get("/statistics", rbac.RequirePermission("achievements.read"), achievementService.GetAllStatistics)
+	reports.Get("/statistics/export", rbac.RequirePermission("achievements.read"), achievementService.ExportAllStatistics)
 	reports.Get("/student/:id", rbac.RequirePermission("achievements.read"), achievementService.GetStudentReport)
+	reports.Get("/student/:id/transcript", rbac.RequirePermission("achievements.read"), transcriptService.GetStudentTranscript)
+	reports.Get("/points/student/:id", rbac.RequirePermission("achievements.read"), creditPointService.GetStudentPoints)
+	reports.Get("/points/cohort", rbac.RequirePermission("achievements.read"), creditPointService.GetCohortPoints)
+	reports.Get("/duplicates", rbac.RequirePermission("achievements.duplicates"), achievementService.GetDuplicateReport)
 }
\ No newline at end of file
@@ -0,0 +1,98 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var duplicateConfig = service.DuplicateConfig{
+	TitleSimilarity: 0.8,
+	DateWindow:      7 * 24 * time.Hour,
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	got := service.NormalizeTitle("  Juara 1 — Lomba   Karya Tulis Ilmiah (Nasional)! ")
+	want := "juara 1 lomba karya tulis ilmiah nasional"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	if score := service.TitleSimilarity("Juara 1 Lomba KTI", "juara 1, lomba kti"); score != 1 {
+		t.Errorf("Expected identical normalized titles to score 1, got %v", score)
+	}
+	if score := service.TitleSimilarity("Juara 1 Lomba Karya Tulis Ilmiah Nasional", "Juara 1 Lomba Karya Tulis Ilmiah Nasiona"); score < 0.9 {
+		t.Errorf("Expected near-identical titles to score >= 0.9, got %v", score)
+	}
+	if score := service.TitleSimilarity("Juara 1 Lomba Karya Tulis Ilmiah", "Sertifikasi AWS Cloud Practitioner"); score > 0.3 {
+		t.Errorf("Expected unrelated titles to score low, got %v", score)
+	}
+	if score := service.TitleSimilarity("", "Juara 1"); score != 0 {
+		t.Errorf("Expected empty title to score 0, got %v", score)
+	}
+}
+
+func TestDetectDuplicates(t *testing.T) {
+	date := time.Date(2026, 5, 10, 0, 0, 0, 0, time.UTC)
+	candidate := &models.Achievement{
+		AchievementID: "a",
+		StudentID:     "student-1",
+		Title:         "Juara 1 Lomba Karya Tulis Ilmiah Nasional",
+		Category:      "kompetisi",
+		Date:          date,
+		Documents:     []models.Document{{Hash: "hash-a"}},
+	}
+
+	others := []models.Achievement{
+		// Judul mirip, tanggal dekat, kategori sama
+		{AchievementID: "b", StudentID: "student-1", Title: "Juara 1 Lomba Karya Tulis Ilmiah Nasional 2026", Category: "kompetisi", Date: date.AddDate(0, 0, 2), Status: "draft"},
+		// Dokumen sama milik mahasiswa lain
+		{AchievementID: "c", StudentID: "student-2", Title: "Sertifikat", Category: "sertifikasi", Date: date.AddDate(0, 3, 0), Status: "verified", Documents: []models.Document{{Hash: "hash-a"}}},
+		// Judul mirip tetapi tanggal jauh
+		{AchievementID: "d", StudentID: "student-1", Title: "Juara 1 Lomba Karya Tulis Ilmiah Nasional", Category: "kompetisi", Date: date.AddDate(1, 0, 0), Status: "verified"},
+		// Judul mirip tetapi kategori berbeda
+		{AchievementID: "e", StudentID: "student-1", Title: "Juara 1 Lomba Karya Tulis Ilmiah Nasional", Category: "publikasi", Date: date, Status: "verified"},
+		// Ditolak tidak dihitung
+		{AchievementID: "f", StudentID: "student-1", Title: "Juara 1 Lomba Karya Tulis Ilmiah Nasional", Category: "kompetisi", Date: date, Status: "rejected"},
+		// Dirinya sendiri
+		{AchievementID: "a", StudentID: "student-1", Title: candidate.Title, Category: "kompetisi", Date: date},
+	}
+
+	matches := service.DetectDuplicates(candidate, others, duplicateConfig)
+	if len(matches) != 2 {
+		t.Fatalf("Expected 2 matches, got %d: %+v", len(matches), matches)
+	}
+
+	if matches[0].AchievementID != "c" || matches[0].Score != 1 || !matches[0].OtherStudent {
+		t.Errorf("Expected shared document from other student first with score 1, got %+v", matches[0])
+	}
+	if !reflect.DeepEqual(matches[0].Reasons, []string{"document_hash"}) {
+		t.Errorf("Expected reasons [document_hash], got %v", matches[0].Reasons)
+	}
+
+	if matches[1].AchievementID != "b" || matches[1].OtherStudent {
+		t.Errorf("Expected own similar achievement second, got %+v", matches[1])
+	}
+	if !reflect.DeepEqual(matches[1].Reasons, []string{"title", "date", "category"}) {
+		t.Errorf("Expected reasons [title date category], got %v", matches[1].Reasons)
+	}
+}
+
+func TestBuildDuplicateClusters(t *testing.T) {
+	flags := []models.AchievementDuplicateFlag{
+		{MongoAchievementID: "a", DuplicateOfID: "b"},
+		{MongoAchievementID: "c", DuplicateOfID: "d"},
+		{MongoAchievementID: "e", DuplicateOfID: "b"},
+		{MongoAchievementID: "d", DuplicateOfID: "c"},
+	}
+
+	clusters := service.BuildDuplicateClusters(flags)
+	want := [][]string{{"a", "b", "e"}, {"c", "d"}}
+	if !reflect.DeepEqual(clusters, want) {
+		t.Errorf("Expected clusters %v, got %v", want, clusters)
+	}
+}
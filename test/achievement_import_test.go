@@ -0,0 +1,159 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseImportFile_CSV(t *testing.T) {
+	content := "\xef\xbb\xbfNo;NIM;Judul;Kategori;Tingkat;Tanggal;Tags;details.Rank\n" +
+		"1;2021001;Juara 1 Hackathon;competition;national;17/08/2025;coding, tim;1\n" +
+		";;;;;;;\n" +
+		"2;2021002;Best Paper;research;international;2025-03-01;;\n"
+
+	sheet, err := service.ParseImportFile("pemenang.CSV", []byte(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(sheet.IgnoredColumns) != 1 || sheet.IgnoredColumns[0] != "No" {
+		t.Errorf("Expected 'No' to be ignored, got %v", sheet.IgnoredColumns)
+	}
+	if len(sheet.Rows) != 2 {
+		t.Fatalf("Expected 2 data rows (empty row skipped), got %d", len(sheet.Rows))
+	}
+
+	first := sheet.Rows[0]
+	if first.Line != 2 || first.NIM != "2021001" || first.Title != "Juara 1 Hackathon" || first.Date != "17/08/2025" {
+		t.Errorf("Unexpected first row: %+v", first)
+	}
+	if first.Details["rank"] != "1" {
+		t.Errorf("Expected details.rank to be read, got %v", first.Details)
+	}
+	if sheet.Rows[1].Line != 4 {
+		t.Errorf("Expected line numbers to follow the file, got %d", sheet.Rows[1].Line)
+	}
+}
+
+func TestParseImportFile_MissingColumnsAndFormat(t *testing.T) {
+	_, err := service.ParseImportFile("data.csv", []byte("nim,title\n2021001,Juara\n"))
+	if err == nil || !strings.Contains(err.Error(), "category") || !strings.Contains(err.Error(), "date") {
+		t.Errorf("Expected missing column error, got %v", err)
+	}
+
+	if _, err := service.ParseImportFile("data.pdf", []byte("x")); err == nil {
+		t.Error("Expected error for unsupported file type")
+	}
+}
+
+func TestParseImportFile_XLSX(t *testing.T) {
+	file := excelize.NewFile()
+	defer file.Close()
+	sheet := file.GetSheetName(0)
+	file.SetSheetRow(sheet, "A1", &[]interface{}{"NIM", "Title", "Category", "Level", "Date", "Description"})
+	file.SetSheetRow(sheet, "A2", &[]interface{}{"2021001", "Juara 1 Hackathon", "competition", "national", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC), "Tingkat nasional"})
+
+	buf, err := file.WriteToBuffer()
+	if err != nil {
+		t.Fatalf("Failed to build XLSX: %v", err)
+	}
+
+	parsed, err := service.ParseImportFile("data.xlsx", buf.Bytes())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(parsed.Rows) != 1 {
+		t.Fatalf("Expected 1 row, got %d", len(parsed.Rows))
+	}
+
+	date, err := service.ParseImportDate(parsed.Rows[0].Date)
+	if err != nil {
+		t.Fatalf("Expected date cell to be parseable, got %v", err)
+	}
+	if date.Format("2006-01-02") != "2025-08-17" {
+		t.Errorf("Expected 2025-08-17, got %s", date.Format("2006-01-02"))
+	}
+	if parsed.Rows[0].Description != "Tingkat nasional" {
+		t.Errorf("Expected description to be read, got %q", parsed.Rows[0].Description)
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	cases := map[string]string{
+		"2025-08-17": "2025-08-17",
+		"17/08/2025": "2025-08-17",
+		"7/8/2025":   "2025-08-07",
+		"17-08-2025": "2025-08-17",
+		"45886":      "2025-08-17", // serial date Excel
+	}
+	for input, expected := range cases {
+		date, err := service.ParseImportDate(input)
+		if err != nil {
+			t.Errorf("%q: expected no error, got %v", input, err)
+			continue
+		}
+		if date.Format("2006-01-02") != expected {
+			t.Errorf("%q: expected %s, got %s", input, expected, date.Format("2006-01-02"))
+		}
+	}
+
+	if _, err := service.ParseImportDate("tujuh belas agustus"); err == nil {
+		t.Error("Expected error for invalid date")
+	}
+}
+
+func TestValidateImportRows(t *testing.T) {
+	existingDate := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	ref := service.ImportReferenceData{
+		Students: map[string]models.StudentDetail{
+			"2021001": {UserID: "user-1", StudentID: "2021001", FullName: "Siti Rahayu"},
+		},
+		Categories: []models.AchievementCategory{{Code: "competition", LabelID: "Kompetisi", IsActive: true}},
+		Levels:     []models.AchievementLevel{{Code: "national", LabelID: "Nasional", Rank: 3, IsActive: true}},
+		Existing:   []models.AchievementTitleKey{{StudentID: "user-1", Title: "Juara Lama", Date: &existingDate}},
+		Now:        time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC),
+	}
+	row := func(line int, nim, title, category, level, date string) models.ImportRow {
+		return models.ImportRow{Line: line, NIM: nim, Title: title, Category: category, Level: level, Date: date}
+	}
+	rows := []models.ImportRow{
+		row(2, "2021001", "Juara 1 Hackathon", "Kompetisi", "nasional", "17/08/2025"),
+		row(3, "9999999", "Juara 2", "competition", "national", "2025-08-17"),
+		row(4, "2021001", "", "olahraga", "galaksi", "2026-01-01"),
+		row(5, "2021001", "juara 1  hackathon", "competition", "national", "2025-08-17"),
+		row(6, "2021001", "Juara Lama", "competition", "national", "2025-01-10"),
+	}
+
+	results := service.ValidateImportRows(rows, ref)
+	if len(results) != len(rows) {
+		t.Fatalf("Expected %d results, got %d", len(rows), len(results))
+	}
+
+	if len(results[0].Errors) != 0 {
+		t.Fatalf("Expected first row to be valid, got %v", results[0].Errors)
+	}
+	if results[0].StudentID != "user-1" || results[0].Category != "competition" || results[0].Level != "national" {
+		t.Errorf("Expected NIM and master data to be resolved, got %+v", results[0])
+	}
+
+	expectError := func(index int, fragment string) {
+		for _, message := range results[index].Errors {
+			if strings.Contains(message, fragment) {
+				return
+			}
+		}
+		t.Errorf("Row %d: expected an error containing %q, got %v", results[index].Line, fragment, results[index].Errors)
+	}
+	expectError(1, "NIM '9999999'")
+	expectError(2, "Judul wajib diisi")
+	expectError(2, "Kategori 'olahraga'")
+	expectError(2, "Level 'galaksi'")
+	expectError(2, "masa depan")
+	expectError(3, "Duplikat dengan baris 2")
+	expectError(4, "sudah ada")
+}
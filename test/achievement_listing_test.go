@@ -0,0 +1,45 @@
+package test
+
+import (
+	"crud-app/app/service"
+	"testing"
+	"time"
+)
+
+func TestParseDateRange_InclusiveUpperBound(t *testing.T) {
+	from, to, err := service.ParseDateRange("2025-01-01", "2025-01-31")
+	if err != nil {
+		t.Fatalf("ParseDateRange failed: %v", err)
+	}
+	if !from.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected from: %v", from)
+	}
+	// Batas atas eksklusif di awal hari berikutnya agar seluruh tanggal 31 ikut
+	if !to.Equal(time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected to: %v", to)
+	}
+}
+
+func TestParseDateRange_OpenEnded(t *testing.T) {
+	from, to, err := service.ParseDateRange("", "2025-06-30")
+	if err != nil || from != nil || to == nil {
+		t.Errorf("Expected open lower bound, got from=%v to=%v err=%v", from, to, err)
+	}
+
+	from, to, err = service.ParseDateRange("", "")
+	if err != nil || from != nil || to != nil {
+		t.Errorf("Expected no bounds, got from=%v to=%v err=%v", from, to, err)
+	}
+}
+
+func TestParseDateRange_Invalid(t *testing.T) {
+	if _, _, err := service.ParseDateRange("01-01-2025", ""); err == nil {
+		t.Error("Expected error for invalid date format")
+	}
+	if _, _, err := service.ParseDateRange("2025-02-01", "2025-01-01"); err == nil {
+		t.Error("Expected error when from is after to")
+	}
+	if _, _, err := service.ParseDateRange("2025-01-01", "2025-01-01"); err != nil {
+		t.Errorf("Single-day range should be valid: %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// readModelTestDB menyiapkan tabel sumber proyeksi (reference, mahasiswa, user) dan read model
+func readModelTestDB(t *testing.T) *sql.DB {
+	return openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE students (
+			id UUID PRIMARY KEY,
+			user_id UUID NOT NULL,
+			student_id VARCHAR(50) NOT NULL,
+			program_study VARCHAR(255),
+			academic_year VARCHAR(20),
+			advisor_id UUID
+		)`,
+		`CREATE TABLE achievement_references (
+			id UUID PRIMARY KEY,
+			student_id UUID NOT NULL,
+			mongo_achievement_id VARCHAR(100) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			current_stage INT NOT NULL DEFAULT 0,
+			total_stages INT NOT NULL DEFAULT 0,
+			submitted_at TIMESTAMP,
+			verified_at TIMESTAMP,
+			verified_by UUID,
+			rejection_note TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)`,
+		migrationSQL(t, "043_achievement_read_model.sql"),
+	)
+}
+
+func TestAchievementReadModel_UpsertProjectsReferenceStatusAndConfirmedMembers(t *testing.T) {
+	db := readModelTestDB(t)
+
+	owner, advisor := uuid.New(), uuid.New()
+	if _, err := db.Exec(`INSERT INTO users (id, full_name) VALUES ($1, 'Mahasiswa Satu')`, owner); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO students (id, user_id, student_id, program_study, academic_year, advisor_id)
+		VALUES ($1, $2, '2021001', 'Informatika', '2021', $3)`, uuid.New(), owner, advisor); err != nil {
+		t.Fatalf("Failed to insert student: %v", err)
+	}
+	// Reference (sumber kebenaran) sudah verified, dokumen MongoDB masih tertinggal di submitted
+	if _, err := db.Exec(`INSERT INTO achievement_references (id, student_id, mongo_achievement_id, status, current_stage, total_stages)
+		VALUES ($1, $2, 'rm-1', 'verified', 1, 1)`, uuid.New(), owner); err != nil {
+		t.Fatalf("Failed to insert reference: %v", err)
+	}
+
+	confirmed, invited, declined := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	achievement := &models.Achievement{
+		ID:            primitive.NewObjectID(),
+		AchievementID: "rm-1",
+		StudentID:     owner.String(),
+		Title:         "Juara 1 Lomba",
+		Category:      "kompetisi",
+		Level:         "nasional",
+		Date:          time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		Tags:          []string{"ai"},
+		Members: []models.AchievementMember{
+			{StudentID: owner.String(), Role: "leader", Status: "confirmed"},
+			{StudentID: confirmed, Role: "member", Status: "confirmed"},
+			{StudentID: invited, Role: "member", Status: "invited"},
+			{StudentID: declined, Role: "member", Status: "declined"},
+		},
+		Status: "submitted",
+	}
+
+	repo := repository.NewAchievementReadModelRepository(db)
+	projected, err := repo.Upsert(achievement)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if !projected {
+		t.Fatal("Expected the achievement to be projected")
+	}
+
+	var status, documentStatus, studentNumber, studentName string
+	var projectedAdvisor uuid.UUID
+	var memberIDs []string
+	err = db.QueryRow(`
+		SELECT status, document->>'status', student_number, student_name, advisor_id, member_ids
+		FROM achievement_read_model WHERE mongo_achievement_id = 'rm-1'
+	`).Scan(&status, &documentStatus, &studentNumber, &studentName, &projectedAdvisor, pq.Array(&memberIDs))
+	if err != nil {
+		t.Fatalf("Failed to read projection: %v", err)
+	}
+
+	if status != "verified" || documentStatus != "verified" {
+		t.Errorf("Expected status from the reference in column and document, got %q and %q", status, documentStatus)
+	}
+	if studentNumber != "2021001" || studentName != "Mahasiswa Satu" || projectedAdvisor != advisor {
+		t.Errorf("Unexpected student data: %q %q %s", studentNumber, studentName, projectedAdvisor)
+	}
+	if len(memberIDs) != 2 || memberIDs[0] != owner.String() || memberIDs[1] != confirmed {
+		t.Errorf("Expected only confirmed members in member_ids, got %v", memberIDs)
+	}
+
+	// Prestasi tim yang dikonfirmasi ikut di laporan anggota; undangan yang belum dijawab tidak
+	if _, total, err := repo.FindByStudentIDs([]string{confirmed}, 10, 0); err != nil || total != 1 {
+		t.Errorf("Expected the confirmed member to see the achievement, got total=%d err=%v", total, err)
+	}
+	if _, total, err := repo.FindByStudentIDs([]string{invited}, 10, 0); err != nil || total != 0 {
+		t.Errorf("Expected the invited member not to see the achievement, got total=%d err=%v", total, err)
+	}
+
+	// Reference yang dihapus tidak diproyeksikan
+	if _, err := db.Exec(`UPDATE achievement_references SET deleted_at = NOW() WHERE mongo_achievement_id = 'rm-1'`); err != nil {
+		t.Fatalf("Failed to delete reference: %v", err)
+	}
+	if _, err := db.Exec(`DELETE FROM achievement_read_model`); err != nil {
+		t.Fatalf("Failed to clear read model: %v", err)
+	}
+	projected, err = repo.Upsert(achievement)
+	if err != nil {
+		t.Fatalf("Upsert failed: %v", err)
+	}
+	if projected {
+		t.Error("Expected no projection for a deleted reference")
+	}
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const competitionSchema = `{
+  "type": "object",
+  "required": ["rank", "organizer", "participant_count"],
+  "properties": {
+    "rank": {"type": "string", "minLength": 1},
+    "organizer": {"type": "string", "minLength": 1},
+    "participant_count": {"type": "integer", "minimum": 1},
+    "expiry_date": {"type": "string", "format": "date"}
+  },
+  "additionalProperties": false
+}`
+
+func TestValidateAchievementDetails_Valid(t *testing.T) {
+	details := map[string]interface{}{
+		"rank":              "Juara 1",
+		"organizer":         "Kemendikbud",
+		"participant_count": 120,
+	}
+
+	violations, err := service.ValidateAchievementDetails([]byte(competitionSchema), details)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations, got %v", violations)
+	}
+}
+
+func TestValidateAchievementDetails_Violations(t *testing.T) {
+	details := map[string]interface{}{
+		"rank":              "Juara 1",
+		"participant_count": 1.5,
+		"expiry_date":       "31-12-2025",
+		"unknown":           true,
+	}
+
+	violations, err := service.ValidateAchievementDetails([]byte(competitionSchema), details)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+
+	joined := strings.Join(violations, "\n")
+	for _, expected := range []string{"organizer", "details/participant_count", "details/expiry_date", "unknown"} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected violation mentioning %q, got:\n%s", expected, joined)
+		}
+	}
+}
+
+func TestValidateAchievementDetails_NilDetailsAgainstRequired(t *testing.T) {
+	violations, err := service.ValidateAchievementDetails([]byte(competitionSchema), nil)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+	if len(violations) == 0 {
+		t.Error("Expected missing required properties to be reported")
+	}
+}
+
+func TestCompileAchievementSchema_Invalid(t *testing.T) {
+	if _, err := service.CompileAchievementSchema([]byte(`{"type": "object", "minProperties": "two"}`)); err == nil {
+		t.Error("Expected invalid schema to fail compilation")
+	}
+}
+
+func TestSeededCompetitionSchema_DetailsOptional(t *testing.T) {
+	seed := regexp.MustCompile(`(?s)\('kompetisi', '(\{.*?\})'\)`).FindStringSubmatch(migrationSQL(t, "032_achievement_category_schemas.sql"))
+	if seed == nil {
+		t.Fatal("Expected kompetisi schema in migration 032")
+	}
+
+	// Client lama membuat achievement kompetisi tanpa details
+	violations, err := service.ValidateAchievementDetails([]byte(seed[1]), nil)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+	if len(violations) != 0 {
+		t.Errorf("Expected kompetisi details to be optional, got %v", violations)
+	}
+
+	violations, _ = service.ValidateAchievementDetails([]byte(seed[1]), map[string]interface{}{"participant_count": 0})
+	if len(violations) == 0 {
+		t.Error("Expected provided fields to still be validated")
+	}
+}
+
+func TestCompiledCategorySchema_CachedPerVersion(t *testing.T) {
+	version := time.Date(2025, 9, 1, 8, 0, 0, 0, time.UTC)
+	schema := &models.AchievementCategorySchema{
+		Category:  "cache-test",
+		Schema:    []byte(competitionSchema),
+		UpdatedAt: version,
+	}
+
+	first, err := service.CompiledCategorySchema(schema)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+	second, _ := service.CompiledCategorySchema(schema)
+	if first != second {
+		t.Error("Expected compiled schema to be reused for the same version")
+	}
+
+	updated := &models.AchievementCategorySchema{
+		Category:  "cache-test",
+		Schema:    []byte(`{"type": "object"}`),
+		UpdatedAt: version.Add(time.Minute),
+	}
+	third, err := service.CompiledCategorySchema(updated)
+	if err != nil {
+		t.Fatalf("Unexpected schema error: %v", err)
+	}
+	if third == first {
+		t.Error("Expected schema to be recompiled after update")
+	}
+	if err := third.Validate(map[string]interface{}{}); err != nil {
+		t.Errorf("Expected updated schema to be used, got %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package test
+
+import (
+	"crud-app/app/service"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := service.NormalizeTags([]string{" Robotika ", "robotika", "", "Machine   Learning"})
+	if err != nil {
+		t.Fatalf("NormalizeTags failed: %v", err)
+	}
+	if !reflect.DeepEqual(tags, []string{"robotika", "machine learning"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	if tags, _ := service.NormalizeTags([]string{" ", ""}); tags != nil {
+		t.Errorf("Blank tags should normalize to nil, got %v", tags)
+	}
+
+	if _, err := service.NormalizeTags([]string{strings.Repeat("a", service.MaxTagLength+1)}); err == nil {
+		t.Error("Expected error for tag longer than the limit")
+	}
+
+	many := make([]string, service.MaxAchievementTags+1)
+	for i := range many {
+		many[i] = strings.Repeat("t", i+1)
+	}
+	if _, err := service.NormalizeTags(many); err == nil {
+		t.Error("Expected error for too many tags")
+	}
+}
+
+func TestSearchTerms_SkipsNegatedWords(t *testing.T) {
+	terms := service.SearchTerms(`"Juara Nasional" robot -internasional`)
+	if !reflect.DeepEqual(terms, []string{"nasional", "juara", "robot"}) {
+		t.Errorf("Unexpected terms: %v", terms)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	terms := service.SearchTerms("robot")
+
+	got := service.Highlight("Lomba Robot <Nasional> & robotika", terms, 0)
+	want := "Lomba <mark>Robot</mark> &lt;Nasional&gt; &amp; robotika"
+	if got != want {
+		t.Errorf("Highlight = %q, want %q", got, want)
+	}
+
+	if got := service.Highlight("Lomba debat", terms, 0); got != "" {
+		t.Errorf("Expected empty highlight without match, got %q", got)
+	}
+}
+
+func TestHighlight_SnippetAroundMatch(t *testing.T) {
+	text := strings.Repeat("kata ", 100) + "robot " + strings.Repeat("akhir ", 100)
+
+	got := service.Highlight(text, service.SearchTerms("robot"), 80)
+	if !strings.Contains(got, "<mark>robot</mark>") {
+		t.Fatalf("Snippet should contain the match: %q", got)
+	}
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Snippet cut on both sides should be marked with ellipsis: %q", got)
+	}
+	if len([]rune(strings.NewReplacer("<mark>", "", "</mark>", "").Replace(got))) > 82 {
+		t.Errorf("Snippet too long: %q", got)
+	}
+}
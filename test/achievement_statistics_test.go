@@ -3,10 +3,13 @@ package test
 import (
 	"context"
 	models "crud-app/app/model"
+	"crud-app/app/repository"
 	"crud-app/test/mocks"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -227,6 +230,58 @@ func TestAchievementStatistics_ExcludeDeleted(t *testing.T) {
 	}
 }
 
+func TestAchievementStatistics_ExcludeRevoked(t *testing.T) {
+	db := openTestDB(t, migrationSQL(t, "043_achievement_read_model.sql"))
+
+	student := uuid.New()
+	teammate := uuid.New()
+	insert := func(mongoID string, owner uuid.UUID, status, category string, members ...string) {
+		_, err := db.Exec(`
+			INSERT INTO achievement_read_model
+				(mongo_achievement_id, reference_id, student_id, status, category, level,
+				 achievement_date, member_ids, document, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, 'nasional', '2025-03-10', $6, '{}', NOW(), NOW())
+		`, mongoID, uuid.New(), owner, status, category, pq.Array(append([]string{}, members...)))
+		if err != nil {
+			t.Fatalf("Failed to insert %s: %v", mongoID, err)
+		}
+	}
+
+	insert("own-verified", student, "verified", "kompetisi")
+	insert("own-submitted", student, "submitted", "seminar")
+	// Achievement yang dicabut karena sertifikat palsu
+	insert("own-revoked", student, "revoked", "kompetisi")
+	insert("team-verified", teammate, "verified", "kompetisi", student.String())
+	insert("team-revoked", teammate, "revoked", "kompetisi", student.String())
+
+	repo := repository.NewAchievementReadModelRepository(db)
+
+	stats, err := repo.GetStatistics([]string{student.String()})
+	if err != nil {
+		t.Fatalf("GetStatistics failed: %v", err)
+	}
+	if total := stats["total_achievements"].(int); total != 3 {
+		t.Errorf("Expected 3 achievements (excluding revoked), got %d", total)
+	}
+	if verified := stats["total_verified"].(int); verified != 2 {
+		t.Errorf("Expected 2 verified achievements, got %d", verified)
+	}
+	if count := stats["category_count"].(map[string]int)["kompetisi"]; count != 2 {
+		t.Errorf("Expected revoked achievements excluded from category count, got %d", count)
+	}
+	if count := stats["period_count"].(map[string]int)["2025-03"]; count != 3 {
+		t.Errorf("Expected revoked achievements excluded from period count, got %d", count)
+	}
+
+	all, err := repo.GetStatistics(nil)
+	if err != nil {
+		t.Fatalf("GetStatistics for all students failed: %v", err)
+	}
+	if total := all["total_achievements"].(int); total != 3 {
+		t.Errorf("Expected 3 achievements for all students (excluding revoked), got %d", total)
+	}
+}
+
 func TestAchievementStatistics_PeriodGrouping(t *testing.T) {
 	mockRepo := mocks.NewMockAchievementRepository()
 
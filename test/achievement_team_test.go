@@ -0,0 +1,171 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTeamAchievement() *models.Achievement {
+	return &models.Achievement{
+		ID:            primitive.NewObjectID(),
+		AchievementID: "team-achievement-1",
+		StudentID:     "leader-1",
+		Title:         "Juara 1 Hackathon",
+		Category:      "Kompetisi",
+		Status:        "verified",
+		Date:          time.Now(),
+		Members: []models.AchievementMember{
+			{StudentID: "leader-1", Role: "leader", Status: "confirmed"},
+			{StudentID: "member-1", Role: "member", Status: "confirmed"},
+			{StudentID: "member-2", Role: "member", Status: "invited"},
+			{StudentID: "member-3", Role: "member", Status: "declined"},
+		},
+	}
+}
+
+func TestCreditedStudentIDs_OwnerAndConfirmedMembers(t *testing.T) {
+	credited := service.CreditedStudentIDs(newTeamAchievement())
+
+	if len(credited) != 2 {
+		t.Fatalf("Expected 2 credited students, got %d (%v)", len(credited), credited)
+	}
+	if credited[0] != "leader-1" || credited[1] != "member-1" {
+		t.Errorf("Expected [leader-1 member-1], got %v", credited)
+	}
+}
+
+func TestHasPendingTeamInvitations(t *testing.T) {
+	achievement := newTeamAchievement()
+	if !service.HasPendingTeamInvitations(achievement) {
+		t.Error("Expected pending invitation for member-2")
+	}
+
+	service.FindTeamMember(achievement, "member-2").Status = "confirmed"
+	if service.HasPendingTeamInvitations(achievement) {
+		t.Error("Expected no pending invitations after confirmation")
+	}
+
+	if service.FindTeamMember(achievement, "unknown") != nil {
+		t.Error("Expected nil for non-member")
+	}
+}
+
+func TestTopStudents_CreditsConfirmedTeamMembers(t *testing.T) {
+	db := openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE achievement_references (
+			id UUID PRIMARY KEY,
+			student_id UUID NOT NULL REFERENCES users(id),
+			mongo_achievement_id VARCHAR(100) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			deleted_at TIMESTAMP
+		)`,
+		migrationSQL(t, "031_achievement_members.sql"),
+		`CREATE TABLE achievement_credit_points (
+			mongo_achievement_id VARCHAR(100) NOT NULL,
+			student_id UUID NOT NULL,
+			points NUMERIC(8, 2) NOT NULL
+		)`,
+	)
+
+	leader, member, invited, declined := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for name, id := range map[string]uuid.UUID{"Leader": leader, "Member": member, "Invited": invited, "Declined": declined} {
+		if _, err := db.Exec(`INSERT INTO users (id, full_name) VALUES ($1, $2)`, id, name); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+
+	reference := func(mongoID string, status string) {
+		if _, err := db.Exec(`INSERT INTO achievement_references (id, student_id, mongo_achievement_id, status) VALUES ($1, $2, $3, $4)`,
+			uuid.New(), leader, mongoID, status); err != nil {
+			t.Fatalf("Failed to insert reference: %v", err)
+		}
+	}
+	teamMember := func(mongoID string, studentID uuid.UUID, role, status string) {
+		if _, err := db.Exec(`INSERT INTO achievement_members (mongo_achievement_id, student_id, role, status) VALUES ($1, $2, $3, $4)`,
+			mongoID, studentID, role, status); err != nil {
+			t.Fatalf("Failed to insert member: %v", err)
+		}
+	}
+
+	for _, mongoID := range []string{"team-verified", "team-revoked"} {
+		teamMember(mongoID, leader, "leader", "confirmed")
+		teamMember(mongoID, member, "member", "confirmed")
+		teamMember(mongoID, invited, "member", "invited")
+		teamMember(mongoID, declined, "member", "declined")
+	}
+	reference("team-verified", "verified")
+	reference("team-revoked", "revoked")
+
+	repo := repository.NewAchievementReferenceRepository(db)
+	allIDs := []string{leader.String(), member.String(), invited.String(), declined.String()}
+
+	topStudents, err := repo.GetTopStudents(allIDs, 10, "")
+	if err != nil {
+		t.Fatalf("GetTopStudents failed: %v", err)
+	}
+	allTopStudents, err := repo.GetAllTopStudents(10, "")
+	if err != nil {
+		t.Fatalf("GetAllTopStudents failed: %v", err)
+	}
+
+	for name, students := range map[string][]models.TopStudent{"GetTopStudents": topStudents, "GetAllTopStudents": allTopStudents} {
+		credited := map[string]models.TopStudent{}
+		for _, student := range students {
+			credited[student.StudentID] = student
+		}
+
+		if len(credited) != 2 {
+			t.Errorf("%s: expected only leader and confirmed member, got %+v", name, students)
+		}
+		// Leader tercatat sebagai pemilik dan anggota tim, tapi hanya dihitung sekali
+		for _, id := range []uuid.UUID{leader, member} {
+			student, ok := credited[id.String()]
+			if !ok {
+				t.Errorf("%s: expected %s to be credited", name, id)
+				continue
+			}
+			if student.TotalAchievements != 1 || student.VerifiedAchievements != 1 {
+				t.Errorf("%s: expected 1 verified achievement (revoked excluded) for %s, got %+v", name, student.StudentName, student)
+			}
+		}
+	}
+}
+
+func TestInviteTeamMember_RejectsSecondLeader(t *testing.T) {
+	app := fiber.New()
+	s := &service.AchievementService{}
+	app.Post("/achievements/:id/members", func(c *fiber.Ctx) error {
+		c.Locals("user_id", "leader-1")
+		return s.InviteTeamMember(c)
+	})
+
+	req := httptest.NewRequest("POST", "/achievements/team-achievement-1/members",
+		strings.NewReader(`{"student_id":"member-1","role":"leader"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var payload map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&payload)
+	if resp.StatusCode != 400 {
+		t.Errorf("Expected status 400, got %d", resp.StatusCode)
+	}
+	if payload["message"] != "Tim hanya boleh memiliki satu leader, yaitu pemilik achievement" {
+		t.Errorf("Unexpected message: %v", payload["message"])
+	}
+}
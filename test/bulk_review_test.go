@@ -0,0 +1,199 @@
+package test
+
+import (
+	"crud-app/app/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// statusError meniru error review yang membawa status HTTP
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string   { return e.message }
+func (e *statusError) HTTPStatus() int { return e.status }
+
+func newBulkReviewApp() *fiber.App {
+	app := fiber.New()
+	s := &service.AchievementService{}
+	app.Post("/achievements/bulk-review", func(c *fiber.Ctx) error {
+		c.Locals("user_id", "lecturer-id")
+		c.Locals("role_id", "2")
+		return s.BulkReviewAchievements(c)
+	})
+	return app
+}
+
+func postBulkReview(t *testing.T, app *fiber.App, body string) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest("POST", "/achievements/bulk-review", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	raw, _ := io.ReadAll(resp.Body)
+	var payload map[string]interface{}
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("Failed to parse response %q: %v", raw, err)
+	}
+	return resp.StatusCode, payload
+}
+
+func bulkReviewIDs(n int) string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("%q", fmt.Sprintf("id-%d", i))
+	}
+	return "[" + strings.Join(ids, ",") + "]"
+}
+
+func TestBulkReviewAchievements_RejectsInvalidRequest(t *testing.T) {
+	app := newBulkReviewApp()
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{"invalid action", `{"achievement_ids":["a"],"action":"archive"}`, "Action harus 'approve' atau 'reject'"},
+		{"reject without note", `{"achievement_ids":["a"],"action":"reject"}`, "Note harus diisi untuk action 'reject'"},
+		{"no ids", `{"achievement_ids":[],"action":"approve"}`, "achievement_ids harus berisi 1 sampai 500 ID"},
+		{"over cap", `{"achievement_ids":` + bulkReviewIDs(501) + `,"action":"approve"}`, "achievement_ids harus berisi 1 sampai 500 ID"},
+		{"invalid body", `{"achievement_ids":"a"}`, "Invalid request body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, payload := postBulkReview(t, app, tt.body)
+			if status != 400 {
+				t.Errorf("Expected status 400, got %d", status)
+			}
+			if payload["message"] != tt.message {
+				t.Errorf("Expected message %q, got %v", tt.message, payload["message"])
+			}
+		})
+	}
+}
+
+func TestBulkReviewAchievements_RequiresETagPerItem(t *testing.T) {
+	app := newBulkReviewApp()
+
+	status, payload := postBulkReview(t, app, `{"achievement_ids":["a","b"],"action":"approve","etags":{"c":"\"1\""}}`)
+	if status != 200 {
+		t.Fatalf("Expected per-item report with status 200, got %d (%v)", status, payload)
+	}
+
+	data := payload["data"].(map[string]interface{})
+	summary := data["summary"].(map[string]interface{})
+	if summary["failed"] != float64(2) || summary["succeeded"] != float64(0) {
+		t.Errorf("Expected both items to fail, got %v", summary)
+	}
+	for _, item := range data["results"].([]interface{}) {
+		result := item.(map[string]interface{})
+		if result["http_status"] != float64(428) {
+			t.Errorf("Expected 428 for %v without an ETag, got %v", result["achievement_id"], result["http_status"])
+		}
+	}
+}
+
+func TestRunBulkReview_AllowsExactlyMaxItems(t *testing.T) {
+	ids := make([]string, 500)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("id-%d", i)
+	}
+
+	results, summary := service.RunBulkReview(ids, func(string) (string, string, error) {
+		return "verified", "ok", nil
+	})
+	if len(results) != 500 || summary.Total != 500 || summary.Succeeded != 500 {
+		t.Errorf("Expected 500 processed items, got %d results and summary %+v", len(results), summary)
+	}
+}
+
+func TestRunBulkReview_DeduplicatesIDsInOrder(t *testing.T) {
+	var calls []string
+	results, summary := service.RunBulkReview([]string{"a", "b", "a", "c", "b"}, func(id string) (string, string, error) {
+		calls = append(calls, id)
+		return "verified", "Achievement berhasil diverifikasi", nil
+	})
+
+	if strings.Join(calls, ",") != "a,b,c" {
+		t.Errorf("Expected each ID to be reviewed once in request order, got %v", calls)
+	}
+	if len(results) != 3 || summary.Total != 3 {
+		t.Fatalf("Expected 3 results, got %d (summary %+v)", len(results), summary)
+	}
+	for i, id := range []string{"a", "b", "c"} {
+		if results[i].AchievementID != id {
+			t.Errorf("Expected result %d to be %s, got %s", i, id, results[i].AchievementID)
+		}
+	}
+}
+
+func TestRunBulkReview_MixedSuccessAndFailure(t *testing.T) {
+	results, summary := service.RunBulkReview([]string{"ok", "stale", "missing", "broken"}, func(id string) (string, string, error) {
+		switch id {
+		case "stale":
+			return "", "", &statusError{412, "Achievement sudah diubah"}
+		case "missing":
+			return "", "", &statusError{404, "Achievement tidak ditemukan"}
+		case "broken":
+			return "", "", errors.New("database error")
+		}
+		return "verified", "Achievement berhasil diverifikasi", nil
+	})
+
+	if summary.Total != 4 || summary.Succeeded != 1 || summary.Failed != 3 {
+		t.Errorf("Expected 1 succeeded and 3 failed, got %+v", summary)
+	}
+
+	expected := []struct {
+		success    bool
+		httpStatus int
+		message    string
+		status     string
+	}{
+		{true, 200, "Achievement berhasil diverifikasi", "verified"},
+		{false, 412, "Achievement sudah diubah", ""},
+		{false, 404, "Achievement tidak ditemukan", ""},
+		{false, 500, "database error", ""},
+	}
+	for i, want := range expected {
+		got := results[i]
+		if got.Success != want.success || got.HTTPStatus != want.httpStatus || got.Message != want.message || got.Status != want.status {
+			t.Errorf("Result %d (%s): expected %+v, got %+v", i, got.AchievementID, want, got)
+		}
+	}
+}
+
+func TestRunBulkReview_ResultJSONShape(t *testing.T) {
+	results, _ := service.RunBulkReview([]string{"a", "b"}, func(id string) (string, string, error) {
+		if id == "b" {
+			return "", "", &statusError{409, "Achievement tidak dalam status submitted"}
+		}
+		return "rejected", "Achievement berhasil direject", nil
+	})
+
+	raw, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("Failed to marshal results: %v", err)
+	}
+	expected := `[{"achievement_id":"a","success":true,"http_status":200,"message":"Achievement berhasil direject","status":"rejected"},` +
+		`{"achievement_id":"b","success":false,"http_status":409,"message":"Achievement tidak dalam status submitted"}]`
+	if string(raw) != expected {
+		t.Errorf("Unexpected JSON:\n got %s\nwant %s", raw, expected)
+	}
+}
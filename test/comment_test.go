@@ -0,0 +1,129 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExtractMentions(t *testing.T) {
+	mentions := service.ExtractMentions("Halo @dosen.wali, mohon cek lagi. cc @Admin_1 @dosen.wali email@example.com")
+
+	if len(mentions) != 2 {
+		t.Fatalf("Expected 2 unique mentions, got %d (%v)", len(mentions), mentions)
+	}
+	if mentions[0] != "dosen.wali" {
+		t.Errorf("Expected first mention 'dosen.wali', got '%s'", mentions[0])
+	}
+	if mentions[1] != "Admin_1" {
+		t.Errorf("Expected second mention 'Admin_1', got '%s'", mentions[1])
+	}
+}
+
+func TestExtractMentions_None(t *testing.T) {
+	mentions := service.ExtractMentions("Tidak ada mention di sini")
+
+	if len(mentions) != 0 {
+		t.Errorf("Expected no mentions, got %v", mentions)
+	}
+}
+
+func TestBuildCommentThreads(t *testing.T) {
+	root := "c1"
+	reply := "c2"
+	missing := "unknown"
+	comments := []models.AchievementComment{
+		{CommentID: "c1", Content: "root"},
+		{CommentID: "c2", ParentID: &root, Content: "reply"},
+		{CommentID: "c3", ParentID: &reply, Content: "nested reply"},
+		{CommentID: "c4", ParentID: &missing, Content: "orphan"},
+	}
+
+	threads := service.BuildCommentThreads(comments)
+
+	if len(threads) != 2 {
+		t.Fatalf("Expected 2 top-level threads, got %d", len(threads))
+	}
+	if threads[0].CommentID != "c1" || len(threads[0].Replies) != 1 {
+		t.Fatalf("Expected c1 with 1 reply, got %s with %d replies", threads[0].CommentID, len(threads[0].Replies))
+	}
+	if len(threads[0].Replies[0].Replies) != 1 || threads[0].Replies[0].Replies[0].CommentID != "c3" {
+		t.Errorf("Expected c3 nested under c2")
+	}
+	if threads[1].CommentID != "c4" {
+		t.Errorf("Expected orphan reply c4 at top level, got %s", threads[1].CommentID)
+	}
+}
+
+func TestCanAccessAchievementThread_FacultyVerifierOnceFacultyStageIsReached(t *testing.T) {
+	db := openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, role_id VARCHAR(50))`,
+		`CREATE TABLE permissions (id UUID PRIMARY KEY, name VARCHAR(100))`,
+		`CREATE TABLE role_permissions (role_id VARCHAR(50), permission_id UUID)`,
+		`CREATE TABLE students (id UUID PRIMARY KEY, user_id UUID, student_id VARCHAR(20), program_study VARCHAR(100),
+			academic_year VARCHAR(10), advisor_id UUID, created_at TIMESTAMP NOT NULL DEFAULT NOW())`,
+		`CREATE TABLE achievement_approvals (id UUID PRIMARY KEY, mongo_achievement_id VARCHAR(100), stage_order INT,
+			stage_name VARCHAR(100), permission VARCHAR(100), status VARCHAR(20), decided_by UUID, note TEXT,
+			decided_at TIMESTAMP, created_at TIMESTAMP NOT NULL DEFAULT NOW())`,
+	)
+
+	student, advisor, faculty := uuid.New(), uuid.New(), uuid.New()
+	permission := uuid.New()
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO users (id, role_id) VALUES ($1, 'mahasiswa'), ($2, 'dosen'), ($3, 'kemahasiswaan')`, []interface{}{student, advisor, faculty}},
+		{`INSERT INTO permissions (id, name) VALUES ($1, 'achievements.verify_faculty')`, []interface{}{permission}},
+		{`INSERT INTO role_permissions (role_id, permission_id) VALUES ('kemahasiswaan', $1)`, []interface{}{permission}},
+		{`INSERT INTO students (id, user_id, student_id, program_study, academic_year, advisor_id)
+			VALUES ($1, $2, 'M001', 'Informatika', '2023', $3)`, []interface{}{uuid.New(), student, advisor}},
+	} {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatalf("Failed to set up: %v", err)
+		}
+	}
+
+	studentRepo := repository.NewStudentRepository(db)
+	permRepo := repository.NewPermissionRepository(db)
+	approvalRepo := repository.NewAchievementApprovalRepository(db)
+
+	// ObjectID MongoDB sengaja berbeda dari achievement_id yang dipakai achievement_approvals
+	achievement := &models.Achievement{ID: primitive.NewObjectID(), AchievementID: uuid.NewString(), StudentID: student.String()}
+	err := approvalRepo.ReplaceForAchievement(achievement.AchievementID, []models.AchievementApproval{
+		{ID: uuid.New(), StageOrder: 1, StageName: "advisor", Permission: "achievements.verify", Status: "pending", CreatedAt: time.Now()},
+		{ID: uuid.New(), StageOrder: 2, StageName: "faculty", Permission: "achievements.verify_faculty", Status: "pending", CreatedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create stages: %v", err)
+	}
+
+	canAccess := func(userID uuid.UUID) bool {
+		t.Helper()
+		allowed, err := service.CanAccessAchievementThread(studentRepo, permRepo, approvalRepo, userID.String(), "2", achievement)
+		if err != nil {
+			t.Fatalf("CanAccessAchievementThread failed: %v", err)
+		}
+		return allowed
+	}
+
+	if !canAccess(advisor) {
+		t.Errorf("Expected the advisor to access the thread")
+	}
+	if canAccess(faculty) {
+		t.Errorf("Expected the faculty verifier to be denied while the advisor stage is pending")
+	}
+
+	if _, err := db.Exec(`UPDATE achievement_approvals SET status = 'approved' WHERE mongo_achievement_id = $1 AND stage_order = 1`,
+		achievement.AchievementID); err != nil {
+		t.Fatalf("Failed to approve the advisor stage: %v", err)
+	}
+	if !canAccess(faculty) {
+		t.Errorf("Expected the faculty verifier to access the thread once the faculty stage is reached")
+	}
+}
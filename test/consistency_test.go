@@ -0,0 +1,123 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"crud-app/app/utils"
+	"strings"
+	"testing"
+)
+
+func TestDetectDrift_Categories(t *testing.T) {
+	mongoStates := []models.AchievementState{
+		{AchievementID: "in-sync", Status: "verified"},
+		{AchievementID: "no-reference", Status: "draft"},
+		{AchievementID: "deleted-no-reference", Status: "draft", IsDeleted: true},
+		{AchievementID: "status", Status: "submitted"},
+		{AchievementID: "deleted", Status: "draft", IsDeleted: true},
+	}
+	postgresStates := []models.AchievementState{
+		{AchievementID: "in-sync", Status: "verified"},
+		{AchievementID: "status", Status: "verified"},
+		{AchievementID: "deleted", Status: "draft"},
+		{AchievementID: "orphan", Status: "submitted"},
+		{AchievementID: "deleted-orphan", Status: "draft", IsDeleted: true},
+	}
+
+	drifts := service.DetectDrift(mongoStates, postgresStates, nil)
+
+	expected := map[string]string{
+		"no-reference": models.DriftMissingReference,
+		"orphan":       models.DriftOrphanReference,
+		"status":       models.DriftStatusMismatch,
+		"deleted":      models.DriftDeletedMismatch,
+	}
+	if len(drifts) != len(expected) {
+		t.Fatalf("Expected %d drifts, got %d: %+v", len(expected), len(drifts), drifts)
+	}
+	for _, drift := range drifts {
+		if expected[drift.AchievementID] != drift.Category {
+			t.Errorf("Achievement %s: expected category %q, got %q", drift.AchievementID, expected[drift.AchievementID], drift.Category)
+		}
+	}
+
+	for i := 1; i < len(drifts); i++ {
+		if drifts[i-1].Category > drifts[i].Category {
+			t.Errorf("Drifts should be sorted by category: %+v", drifts)
+		}
+	}
+}
+
+// Perbedaan pada achievement yang masih punya event outbox pending adalah eventual consistency, bukan drift
+func TestDetectDrift_SkipsPendingOutbox(t *testing.T) {
+	mongoStates := []models.AchievementState{{AchievementID: "pending", Status: "draft"}}
+	postgresStates := []models.AchievementState{{AchievementID: "pending", Status: "submitted"}}
+
+	drifts := service.DetectDrift(mongoStates, postgresStates, map[string]bool{"pending": true})
+	if len(drifts) != 0 {
+		t.Errorf("Expected no drift for pending achievement, got %+v", drifts)
+	}
+}
+
+// Event yang di-commit selama scan membuat store terlihat berbeda; repair harus memakai keadaan terbaru
+func TestRecheckDrift(t *testing.T) {
+	scanned := models.ConsistencyDrift{
+		AchievementID:  "a1",
+		Category:       models.DriftStatusMismatch,
+		MongoStatus:    "submitted",
+		PostgresStatus: "verified",
+	}
+	mongoState := &models.AchievementState{AchievementID: "a1", Status: "submitted"}
+	postgresState := &models.AchievementState{AchievementID: "a1", Status: "verified"}
+
+	if drift := service.RecheckDrift(scanned, false, mongoState, postgresState); drift == nil || *drift != scanned {
+		t.Errorf("Expected unchanged drift to be confirmed, got %+v", drift)
+	}
+
+	if drift := service.RecheckDrift(scanned, true, mongoState, postgresState); drift != nil {
+		t.Errorf("Expected drift with a pending outbox event to be skipped, got %+v", drift)
+	}
+
+	// Relay sudah menerapkan event setelah scan
+	applied := &models.AchievementState{AchievementID: "a1", Status: "verified"}
+	if drift := service.RecheckDrift(scanned, false, applied, postgresState); drift != nil {
+		t.Errorf("Expected resolved drift to be skipped, got %+v", drift)
+	}
+
+	// PostgreSQL berubah lagi selama scan: keadaan saat scan sudah usang
+	rejected := &models.AchievementState{AchievementID: "a1", Status: "rejected"}
+	if drift := service.RecheckDrift(scanned, false, mongoState, rejected); drift != nil {
+		t.Errorf("Expected drift with a newer PostgreSQL state to be skipped, got %+v", drift)
+	}
+
+	orphan := models.ConsistencyDrift{AchievementID: "a2", Category: models.DriftOrphanReference, PostgresStatus: "draft"}
+	if drift := service.RecheckDrift(orphan, false, nil, &models.AchievementState{AchievementID: "a2", Status: "draft"}); drift == nil {
+		t.Error("Expected orphan reference to be confirmed")
+	}
+}
+
+func TestValidSourceOfTruth(t *testing.T) {
+	for source, valid := range map[string]bool{"postgres": true, "mongo": true, "mysql": false, "": false} {
+		if service.ValidSourceOfTruth(source) != valid {
+			t.Errorf("ValidSourceOfTruth(%q) should be %v", source, valid)
+		}
+	}
+}
+
+func TestWriteMetrics_PrometheusFormat(t *testing.T) {
+	utils.SetGauge("test_consistency_drift", "Test gauge", map[string]string{"category": "status_mismatch"}, 3)
+	utils.SetGauge("test_consistency_drift", "Test gauge", map[string]string{"category": "orphan_reference"}, 1)
+
+	var out strings.Builder
+	if err := utils.WriteMetrics(&out); err != nil {
+		t.Fatalf("WriteMetrics failed: %v", err)
+	}
+
+	expected := "# HELP test_consistency_drift Test gauge\n" +
+		"# TYPE test_consistency_drift gauge\n" +
+		"test_consistency_drift{category=\"orphan_reference\"} 1\n" +
+		"test_consistency_drift{category=\"status_mismatch\"} 3\n"
+	if !strings.Contains(out.String(), expected) {
+		t.Errorf("Unexpected metrics output:\n%s", out.String())
+	}
+}
@@ -0,0 +1,78 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"testing"
+)
+
+func creditRules() []models.CreditPointRule {
+	return []models.CreditPointRule{
+		{Category: "*", Level: "*", Rank: "*", Points: 1},
+		{Category: "*", Level: "nasional", Rank: "*", Points: 20},
+		{Category: "kompetisi", Level: "nasional", Rank: "*", Points: 25},
+		{Category: "kompetisi", Level: "nasional", Rank: "Juara 1", Points: 50},
+		{Category: "kompetisi-akademik", Level: "*", Rank: "*", Points: 15},
+	}
+}
+
+func TestResolveCreditRule_PicksMostSpecific(t *testing.T) {
+	rules := creditRules()
+	competition := []string{"kompetisi"}
+
+	cases := []struct {
+		name    string
+		lineage []string
+		level   string
+		rank    string
+		points  float64
+	}{
+		{"exact rank", competition, "nasional", "juara 1", 50},
+		{"other rank falls back to category+level", competition, "nasional", "Juara 2", 25},
+		{"no rank", competition, "nasional", "", 25},
+		{"other category uses level wildcard", []string{"publikasi"}, "nasional", "", 20},
+		{"catch-all", []string{"publikasi"}, "lokal", "", 1},
+		{"exact sub-category beats parent", []string{"kompetisi-akademik", "kompetisi"}, "nasional", "", 15},
+		{"parent rule for other sub-category", []string{"kompetisi-seni", "kompetisi"}, "nasional", "Juara 1", 50},
+	}
+
+	for _, tc := range cases {
+		rule := service.ResolveCreditRule(rules, tc.lineage, tc.level, tc.rank)
+		if rule == nil {
+			t.Errorf("%s: expected a rule, got nil", tc.name)
+			continue
+		}
+		if rule.Points != tc.points {
+			t.Errorf("%s: expected %v points, got %v", tc.name, tc.points, rule.Points)
+		}
+	}
+}
+
+func TestResolveCreditRule_NoMatch(t *testing.T) {
+	rules := []models.CreditPointRule{
+		{Category: "kompetisi", Level: "nasional", Rank: "*", Points: 25},
+	}
+
+	if rule := service.ResolveCreditRule(rules, []string{"publikasi"}, "nasional", ""); rule != nil {
+		t.Errorf("Expected no rule, got %+v", rule)
+	}
+}
+
+func TestExtractRank(t *testing.T) {
+	cases := []struct {
+		details map[string]interface{}
+		rank    string
+	}{
+		{map[string]interface{}{"rank": " Juara 1 "}, "Juara 1"},
+		{map[string]interface{}{"rank": float64(2)}, "2"},
+		{map[string]interface{}{"rank": nil}, ""},
+		{map[string]interface{}{}, ""},
+		{nil, ""},
+	}
+
+	for _, tc := range cases {
+		if rank := service.ExtractRank(tc.details); rank != tc.rank {
+			t.Errorf("Expected rank %q for %v, got %q", tc.rank, tc.details, rank)
+		}
+	}
+}
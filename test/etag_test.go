@@ -0,0 +1,70 @@
+package test
+
+import (
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"crud-app/test/mocks"
+	"testing"
+	"time"
+)
+
+func TestETag_QuotedVersion(t *testing.T) {
+	if got := service.ETag(3); got != `"3"` {
+		t.Errorf(`Expected "3" (quoted), got %s`, got)
+	}
+}
+
+func TestIfMatchSatisfied(t *testing.T) {
+	cases := []struct {
+		header string
+		want   bool
+	}{
+		{`"2"`, true},
+		{`"1"`, false},
+		{`"1", "2"`, true},
+		{`*`, true},
+		{`W/"2"`, false},
+		{`2`, false},
+	}
+
+	for _, tc := range cases {
+		if got := service.IfMatchSatisfied(tc.header, 2); got != tc.want {
+			t.Errorf("If-Match %s: expected %v, got %v", tc.header, tc.want, got)
+		}
+	}
+}
+
+func TestAchievementRepository_StaleUpdateConflicts(t *testing.T) {
+	mockRepo := mocks.NewMockAchievementRepository()
+	ctx := context.Background()
+
+	original := &models.Achievement{
+		AchievementID: "etag-achievement",
+		StudentID:     "test-user-id",
+		Title:         "Original",
+		Status:        "draft",
+		Date:          time.Now(),
+	}
+	if err := mockRepo.Create(ctx, original); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// Dua pengguna membaca versi yang sama
+	first := *original
+	second := *original
+
+	first.Title = "First"
+	if err := mockRepo.Update(ctx, first.AchievementID, &first); err != nil {
+		t.Fatalf("First update failed: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Expected version 2 after update, got %d", first.Version)
+	}
+
+	second.Title = "Second"
+	if err := mockRepo.Update(ctx, second.AchievementID, &second); err != repository.ErrVersionConflict {
+		t.Errorf("Expected ErrVersionConflict for stale update, got %v", err)
+	}
+}
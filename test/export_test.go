@@ -0,0 +1,173 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"encoding/csv"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func exportTestTable(rows [][]interface{}) service.ExportTable {
+	return service.ExportTable{
+		Name:   "achievements",
+		Header: []string{"Judul", "Tanggal", "Diverifikasi", "Poin"},
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			for _, row := range rows {
+				if err := emit(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestParseExportFormat(t *testing.T) {
+	cases := map[string]string{"": "csv", "CSV": "csv", " xlsx ": "xlsx"}
+	for input, expected := range cases {
+		format, ok := service.ParseExportFormat(input)
+		if !ok || format != expected {
+			t.Errorf("%q: expected %s, got %s (ok=%v)", input, expected, format, ok)
+		}
+	}
+
+	if _, ok := service.ParseExportFormat("pdf"); ok {
+		t.Error("Expected pdf to be rejected")
+	}
+}
+
+func TestWriteExport_CSV(t *testing.T) {
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+	verifiedAt := time.Date(2025, 8, 20, 9, 30, 0, 0, time.UTC)
+	var missing *time.Time
+
+	var buf bytes.Buffer
+	count, err := service.WriteExport(context.Background(), &buf, models.ExportFormatCSV, exportTestTable([][]interface{}{
+		{"Juara 1, Hackathon", date, &verifiedAt, 12.5},
+		{"=HYPERLINK(\"http://evil\")", date, missing, 0},
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 rows, got %d", count)
+	}
+
+	content := buf.String()
+	if !strings.HasPrefix(content, "\xef\xbb\xbf") {
+		t.Error("Expected CSV to start with a UTF-8 BOM")
+	}
+
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(content, "\xef\xbb\xbf"))).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid CSV, got %v", err)
+	}
+	if len(records) != 3 || records[0][0] != "Judul" {
+		t.Fatalf("Expected header and 2 rows, got %v", records)
+	}
+	if records[1][0] != "Juara 1, Hackathon" || records[1][1] != "2025-08-17" || records[1][2] != "2025-08-20 09:30:00" || records[1][3] != "12.5" {
+		t.Errorf("Unexpected first row: %v", records[1])
+	}
+	if !strings.HasPrefix(records[2][0], "'=") {
+		t.Errorf("Expected formula to be escaped, got %q", records[2][0])
+	}
+	if records[2][2] != "" {
+		t.Errorf("Expected nil time to be empty, got %q", records[2][2])
+	}
+}
+
+func TestWriteExport_XLSX(t *testing.T) {
+	date := time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)
+
+	var buf bytes.Buffer
+	count, err := service.WriteExport(context.Background(), &buf, models.ExportFormatXLSX, exportTestTable([][]interface{}{
+		{"Juara 1 Hackathon", date, nil, 12.5},
+	}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 row, got %d", count)
+	}
+
+	file, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatalf("Expected valid XLSX, got %v", err)
+	}
+	defer file.Close()
+
+	if sheets := file.GetSheetList(); len(sheets) != 1 || sheets[0] != "achievements" {
+		t.Fatalf("Expected a single 'achievements' sheet, got %v", sheets)
+	}
+	rows, err := file.GetRows("achievements")
+	if err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "Judul" || rows[1][0] != "Juara 1 Hackathon" {
+		t.Fatalf("Unexpected rows: %v", rows)
+	}
+	if rows[1][1] != "2025-08-17" {
+		t.Errorf("Expected date cell formatted as date, got %q", rows[1][1])
+	}
+}
+
+func TestWriteExport_StopsOnRowError(t *testing.T) {
+	table := service.ExportTable{
+		Name:   "students",
+		Header: []string{"NIM"},
+		Rows: func(ctx context.Context, emit func([]interface{}) error) error {
+			if err := emit([]interface{}{"2021001"}); err != nil {
+				return err
+			}
+			return errors.New("database unavailable")
+		},
+	}
+
+	var buf bytes.Buffer
+	if _, err := service.WriteExport(context.Background(), &buf, models.ExportFormatCSV, table); err == nil {
+		t.Error("Expected row source error to be returned")
+	}
+}
+
+func TestStatisticsExportRows(t *testing.T) {
+	stats := map[string]interface{}{
+		"total_achievements": 4,
+		"total_verified":     2,
+		"total_pending":      1,
+		"total_rejected":     0,
+		"total_draft":        1,
+		"category_count":     map[string]int{"research": 1, "competition": 3},
+		"level_count":        map[string]int{"national": 4},
+		"period_count":       map[string]int{"2025-08": 3, "2025-01": 1},
+	}
+	categories := map[string]string{"competition": "Kompetisi"}
+	topStudents := []models.TopStudent{{StudentName: "Siti Rahayu", TotalAchievements: 3, TotalPoints: 45}}
+
+	rows := service.StatisticsExportRows(stats, categories, map[string]string{}, topStudents)
+	if len(rows) != 5+2+1+2+1 {
+		t.Fatalf("Expected 11 rows, got %d: %v", len(rows), rows)
+	}
+
+	if rows[0][1] != "Total achievement" || rows[0][2] != 4 {
+		t.Errorf("Unexpected summary row: %v", rows[0])
+	}
+	if rows[5][1] != "Kompetisi" || rows[5][2] != 3 || rows[5][3] != 75.0 {
+		t.Errorf("Expected the largest category first with its label, got %v", rows[5])
+	}
+	if rows[6][1] != "research" {
+		t.Errorf("Expected unknown category code to be kept, got %v", rows[6])
+	}
+	if rows[8][1] != "2025-01" || rows[9][1] != "2025-08" {
+		t.Errorf("Expected periods in chronological order, got %v and %v", rows[8], rows[9])
+	}
+	if rows[10][0] != "Top Mahasiswa" || rows[10][4] != 45.0 {
+		t.Errorf("Unexpected top student row: %v", rows[10])
+	}
+}
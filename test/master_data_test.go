@@ -0,0 +1,86 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"testing"
+)
+
+func masterCategories() []models.AchievementCategory {
+	competition := "kompetisi"
+	academic := "kompetisi-akademik"
+	return []models.AchievementCategory{
+		{Code: "kompetisi", LabelID: "Kompetisi", LabelEN: "Competition", Aliases: []string{"lomba"}},
+		{Code: "kompetisi-akademik", ParentCode: &competition, LabelID: "Kompetisi Akademik", LabelEN: "Academic Competition"},
+		{Code: "olimpiade", ParentCode: &academic, LabelID: "Olimpiade", LabelEN: "Olympiad"},
+		{Code: "publikasi", LabelID: "Publikasi", LabelEN: "Publication"},
+	}
+}
+
+func TestResolveLevelCode_NormalizesVariants(t *testing.T) {
+	levels := []models.AchievementLevel{
+		{Code: "nasional", Rank: 3, LabelID: "Nasional", LabelEN: "National"},
+		{Code: "internasional", Rank: 4, LabelID: "Internasional", LabelEN: "International", Aliases: []string{"global"}},
+	}
+
+	for _, value := range []string{"Nasional", "nasional", "National", " NATIONAL "} {
+		code, ok := service.ResolveLevelCode(levels, value)
+		if !ok || code != "nasional" {
+			t.Errorf("Expected %q to resolve to 'nasional', got %q (%v)", value, code, ok)
+		}
+	}
+
+	if code, ok := service.ResolveLevelCode(levels, "Global"); !ok || code != "internasional" {
+		t.Errorf("Expected alias 'Global' to resolve to 'internasional', got %q", code)
+	}
+
+	if _, ok := service.ResolveLevelCode(levels, "Kecamatan"); ok {
+		t.Error("Expected unknown level not to resolve")
+	}
+}
+
+func TestResolveCategoryCode_LabelsAndAliases(t *testing.T) {
+	categories := masterCategories()
+
+	cases := map[string]string{
+		"Competition":          "kompetisi",
+		"LOMBA":                "kompetisi",
+		"Academic Competition": "kompetisi-akademik",
+		"publikasi":            "publikasi",
+	}
+	for value, expected := range cases {
+		code, ok := service.ResolveCategoryCode(categories, value)
+		if !ok || code != expected {
+			t.Errorf("Expected %q to resolve to %q, got %q (%v)", value, expected, code, ok)
+		}
+	}
+}
+
+func TestCategoryLineage(t *testing.T) {
+	lineage := service.CategoryLineage(masterCategories(), "olimpiade")
+
+	expected := []string{"olimpiade", "kompetisi-akademik", "kompetisi"}
+	if len(lineage) != len(expected) {
+		t.Fatalf("Expected lineage %v, got %v", expected, lineage)
+	}
+	for i := range expected {
+		if lineage[i] != expected[i] {
+			t.Errorf("Expected lineage %v, got %v", expected, lineage)
+			break
+		}
+	}
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	tree := service.BuildCategoryTree(masterCategories())
+
+	if len(tree) != 2 {
+		t.Fatalf("Expected 2 root categories, got %d", len(tree))
+	}
+	if tree[0].Code != "kompetisi" || len(tree[0].Children) != 1 {
+		t.Fatalf("Expected kompetisi with 1 child, got %s with %d", tree[0].Code, len(tree[0].Children))
+	}
+	if len(tree[0].Children[0].Children) != 1 || tree[0].Children[0].Children[0].Code != "olimpiade" {
+		t.Error("Expected olimpiade nested under kompetisi-akademik")
+	}
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"crud-app/app/service"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Contoh dari RFC 7396 Appendix A
+func TestApplyMergePatch_RFC7396Examples(t *testing.T) {
+	cases := []struct{ target, patch, want string }{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"e":null,"a":1}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tc := range cases {
+		var target, patch, want interface{}
+		json.Unmarshal([]byte(tc.target), &target)
+		json.Unmarshal([]byte(tc.patch), &patch)
+		json.Unmarshal([]byte(tc.want), &want)
+
+		got := service.ApplyMergePatch(target, patch)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("target %s patch %s: expected %v, got %v", tc.target, tc.patch, want, got)
+		}
+	}
+}
+
+func TestParseMergePatch_RejectsNonObject(t *testing.T) {
+	for _, body := range []string{``, `[]`, `"x"`, `null`, `{"a":`} {
+		if _, err := service.ParseMergePatch([]byte(body)); err == nil {
+			t.Errorf("Expected error for body %q", body)
+		}
+	}
+}
+
+func TestMergePatch_FieldSemantics(t *testing.T) {
+	patch, err := service.ParseMergePatch([]byte(`{
+		"title": "  Juara 1  ",
+		"description": null,
+		"details": {"rank": null, "organizer": "Kemdikbud"}
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	title, description, level := "Lama", "Deskripsi lama", "nasional"
+	details := map[string]interface{}{"rank": "Juara 2", "team": true}
+
+	patch.Allow("title", "description", "level", "details")
+	if !patch.String("title", &title, true) || title != "Juara 1" {
+		t.Errorf("Expected trimmed title 'Juara 1', got %q", title)
+	}
+	if !patch.String("description", &description, false) || description != "" {
+		t.Errorf("Expected null to clear description, got %q", description)
+	}
+	if patch.String("level", &level, true) || level != "nasional" {
+		t.Errorf("Expected omitted level to stay unchanged, got %q", level)
+	}
+	if !patch.Object("details", &details) {
+		t.Fatal("Expected details to be applied")
+	}
+
+	wantDetails := map[string]interface{}{"team": true, "organizer": "Kemdikbud"}
+	if !reflect.DeepEqual(details, wantDetails) {
+		t.Errorf("Expected details %v, got %v", wantDetails, details)
+	}
+	if len(patch.Errors()) != 0 {
+		t.Errorf("Expected no errors, got %v", patch.Errors())
+	}
+}
+
+func TestMergePatch_FieldErrors(t *testing.T) {
+	patch, err := service.ParseMergePatch([]byte(`{
+		"title": null,
+		"category": "",
+		"date": "10-05-2026",
+		"is_active": "yes",
+		"details": [1],
+		"status": "verified"
+	}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	title, category := "Lama", "kompetisi"
+	date := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	active := true
+	var details map[string]interface{}
+
+	patch.Allow("title", "category", "date", "is_active", "details")
+	patch.String("title", &title, true)
+	patch.String("category", &category, true)
+	patch.Date("date", &date)
+	patch.Bool("is_active", &active)
+	patch.Object("details", &details)
+
+	fields := map[string]bool{}
+	for _, fieldErr := range patch.Errors() {
+		fields[fieldErr.Field] = true
+	}
+	for _, field := range []string{"status", "title", "category", "date", "is_active", "details"} {
+		if !fields[field] {
+			t.Errorf("Expected error for field %q, got %v", field, patch.Errors())
+		}
+	}
+
+	if title != "Lama" || category != "kompetisi" || !active || date.Year() != 2026 || date.Month() != 1 {
+		t.Error("Expected invalid fields to leave targets unchanged")
+	}
+}
@@ -93,7 +93,7 @@ func (m *MockAchievementReferenceRepository) FindPendingVerification(limit, offs
     return results, int64(count), nil
 }
 
-func (m *MockAchievementReferenceRepository) FindAllWithFilters(limit, offset int, statusFilter, studentIDFilter, sortBy, sortOrder string) ([]models.AchievementReferences, int64, error) {
+func (m *MockAchievementReferenceRepository) FindAllWithFilters(filter models.AchievementListFilter, limit, offset int) ([]models.AchievementReferences, int64, error) {
     m.calls["FindAllWithFilters"]++
 
     var results []models.AchievementReferences
@@ -106,10 +106,13 @@ func (m *MockAchievementReferenceRepository) FindAllWithFilters(limit, offset in
         }
 
         // Apply filters
-        if statusFilter != "" && ref.Status != statusFilter {
+        if filter.Status != "" && ref.Status != filter.Status {
             continue
         }
-        if studentIDFilter != "" && ref.StudentID.String() != studentIDFilter {
+        if filter.StudentID != "" && ref.StudentID.String() != filter.StudentID {
+            continue
+        }
+        if filter.AchievementIDs != nil && !containsString(filter.AchievementIDs, ref.MongoAchievementID) {
             continue
         }
 
@@ -198,7 +201,7 @@ func (m *MockAchievementReferenceRepository) SoftDelete(mongoID string) error {
     return nil
 }
 
-func (m *MockAchievementReferenceRepository) GetTopStudents(studentIDs []string, limit int) ([]models.TopStudent, error) {
+func (m *MockAchievementReferenceRepository) GetTopStudents(studentIDs []string, limit int, rankBy string) ([]models.TopStudent, error) {
     m.calls["GetTopStudents"]++
 
     studentStats := make(map[string]*models.TopStudent)
@@ -246,7 +249,7 @@ func (m *MockAchievementReferenceRepository) GetTopStudents(studentIDs []string,
     return results, nil
 }
 
-func (m *MockAchievementReferenceRepository) GetAllTopStudents(limit int) ([]models.TopStudent, error) {
+func (m *MockAchievementReferenceRepository) GetAllTopStudents(limit int, rankBy string) ([]models.TopStudent, error) {
     m.calls["GetAllTopStudents"]++
 
     studentStats := make(map[string]*models.TopStudent)
@@ -308,4 +311,13 @@ func (m *MockAchievementReferenceRepository) GetReferenceCount() int {
         }
     }
     return count
+}
+
+func containsString(values []string, target string) bool {
+    for _, value := range values {
+        if value == target {
+            return true
+        }
+    }
+    return false
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	models "crud-app/app/model"
+	"crud-app/app/repository"
 	"errors"
 	"time"
 
@@ -33,6 +34,7 @@ func (m *MockAchievementRepository) Create(ctx context.Context, achievement *mod
 		achievement.CreatedAt = time.Now()
 	}
 	achievement.UpdatedAt = time.Now()
+	achievement.Version = 1
 
 	m.achievements[achievement.AchievementID] = achievement
 	return nil
@@ -53,7 +55,7 @@ func (m *MockAchievementRepository) FindByStudentID(ctx context.Context, student
 
 	var results []models.Achievement
 	for _, achievement := range m.achievements {
-		if achievement.StudentID == studentID && !achievement.IsDeleted {
+		if isCreditedStudent(achievement, studentID) && !achievement.IsDeleted {
 			results = append(results, *achievement)
 		}
 	}
@@ -68,7 +70,12 @@ func (m *MockAchievementRepository) Update(ctx context.Context, achievementID st
 		return errors.New("achievement not found")
 	}
 
+	if existing.Version != achievement.Version {
+		return repository.ErrVersionConflict
+	}
+
 	achievement.UpdatedAt = time.Now()
+	achievement.Version++
 	m.achievements[achievementID] = achievement
 	return nil
 }
@@ -144,10 +151,10 @@ func (m *MockAchievementRepository) GetStatisticsByStudentIDs(ctx context.Contex
 
 	var filteredAchievements []models.Achievement
 	for _, achievement := range m.achievements {
-		if !achievement.IsDeleted {
+		if !achievement.IsDeleted && achievement.Status != "revoked" {
 			// Check if student ID is in the filter list
 			for _, studentID := range studentIDs {
-				if achievement.StudentID == studentID {
+				if isCreditedStudent(achievement, studentID) {
 					filteredAchievements = append(filteredAchievements, *achievement)
 					break
 				}
@@ -227,4 +234,17 @@ func (m *MockAchievementRepository) GetAchievementCount() int {
 		}
 	}
 	return count
+}
+
+// isCreditedStudent: pemilik atau anggota tim yang sudah konfirmasi
+func isCreditedStudent(achievement *models.Achievement, studentID string) bool {
+	if achievement.StudentID == studentID {
+		return true
+	}
+	for _, member := range achievement.Members {
+		if member.StudentID == studentID && member.Status == "confirmed" {
+			return true
+		}
+	}
+	return false
 }
\ No newline at end of file
@@ -0,0 +1,185 @@
+package test
+
+import (
+	"context"
+	"crud-app/app/job"
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"crud-app/app/utils"
+	"database/sql"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event achievement.created membawa dokumen MongoDB lengkap sebagai JSON;
+// relay harus bisa menyusunnya kembali tanpa kehilangan data
+func TestOutboxEvent_CreatedPayloadRoundTrip(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	achievement := &models.Achievement{
+		ID:            primitive.NewObjectID(),
+		AchievementID: "outbox-achievement",
+		StudentID:     "student-1",
+		Title:         "Juara 1 Lomba",
+		Category:      "kompetisi",
+		Level:         "nasional",
+		Date:          date,
+		Details:       map[string]interface{}{"rank": "1"},
+		Documents:     []models.Document{{Filename: "sertifikat.pdf", Filepath: "./uploads/a.pdf", Hash: "abc"}},
+		Status:        "draft",
+		Version:       1,
+		CreatedAt:     date,
+		UpdatedAt:     date,
+	}
+
+	event, err := service.NewOutboxEvent(achievement.AchievementID, models.OutboxAchievementCreated, achievement)
+	if err != nil {
+		t.Fatalf("NewOutboxEvent failed: %v", err)
+	}
+	if event.EventType != models.OutboxAchievementCreated || event.MongoAchievementID != "outbox-achievement" {
+		t.Errorf("Unexpected event metadata: %+v", event)
+	}
+
+	var decoded models.Achievement
+	if err := json.Unmarshal(event.Payload, &decoded); err != nil {
+		t.Fatalf("Unmarshal payload failed: %v", err)
+	}
+
+	if decoded.ID != achievement.ID {
+		t.Errorf("Expected ObjectID %s, got %s", achievement.ID.Hex(), decoded.ID.Hex())
+	}
+	if !decoded.Date.Equal(date) || decoded.Title != achievement.Title || decoded.Status != "draft" || decoded.Version != 1 {
+		t.Errorf("Decoded achievement differs: %+v", decoded)
+	}
+	if len(decoded.Documents) != 1 || decoded.Documents[0].Filepath != "./uploads/a.pdf" {
+		t.Errorf("Expected documents to survive round trip, got %+v", decoded.Documents)
+	}
+}
+
+func TestOutboxEvent_StatusPayload(t *testing.T) {
+	event, err := service.NewOutboxEvent("a-1", models.OutboxAchievementStatus, models.OutboxStatusPayload{Status: "verified"})
+	if err != nil {
+		t.Fatalf("NewOutboxEvent failed: %v", err)
+	}
+
+	if string(event.Payload) != `{"status":"verified"}` {
+		t.Errorf(`Expected {"status":"verified"}, got %s`, event.Payload)
+	}
+}
+
+func TestOutboxStatusGuard(t *testing.T) {
+	payload := models.OutboxStatusPayload{Status: "verified", From: "submitted"}
+
+	event, err := service.NewOutboxEvent("a-1", models.OutboxAchievementStatus, payload)
+	if err != nil {
+		t.Fatalf("NewOutboxEvent failed: %v", err)
+	}
+	if string(event.Payload) != `{"status":"verified","from":"submitted"}` {
+		t.Errorf("Expected the prior status in the payload, got %s", event.Payload)
+	}
+
+	guard := service.OutboxStatusGuard(payload)
+	expected := bson.M{"status": bson.M{"$in": bson.A{"submitted", "verified"}}}
+	if !reflect.DeepEqual(guard, expected) {
+		t.Errorf("Expected guard %v, got %v", expected, guard)
+	}
+
+	// Event yang ditulis sebelum ada status asal diterapkan tanpa syarat status
+	if guard := service.OutboxStatusGuard(models.OutboxStatusPayload{Status: "verified"}); guard != nil {
+		t.Errorf("Expected no guard without a prior status, got %v", guard)
+	}
+}
+
+// Event yang terus gagal di-park setelah batas percobaan agar tidak menahan event yang lebih baru
+func TestOutboxRepository_ParksEventAfterMaxAttempts(t *testing.T) {
+	db := openTestDB(t, migrationSQL(t, "039_achievement_outbox.sql"))
+	repo := repository.NewOutboxRepository(db)
+
+	first := &models.OutboxEvent{MongoAchievementID: "a-1", EventType: models.OutboxAchievementStatus, Payload: json.RawMessage(`{}`)}
+	second := &models.OutboxEvent{MongoAchievementID: "a-1", EventType: models.OutboxAchievementStatus, Payload: json.RawMessage(`{}`)}
+	if err := repo.WithinTransaction(func(tx *sql.Tx) error { return nil }, first, second); err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		parked, err := repo.MarkFailed(first.ID, "boom", 3)
+		if err != nil {
+			t.Fatalf("MarkFailed failed: %v", err)
+		}
+		if parked != (attempt == 3) {
+			t.Errorf("Attempt %d: expected parked=%v, got %v", attempt, attempt == 3, parked)
+		}
+	}
+
+	events, err := repo.FindPending(10)
+	if err != nil {
+		t.Fatalf("FindPending failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ID != second.ID {
+		t.Fatalf("Expected only the newer event to stay pending, got %+v", events)
+	}
+
+	blocked, err := repo.HasPendingBefore("a-1", second.ID)
+	if err != nil {
+		t.Fatalf("HasPendingBefore failed: %v", err)
+	}
+	if blocked {
+		t.Error("A parked event must not block newer events of the same achievement")
+	}
+
+	pending, failed, err := repo.CountStates()
+	if err != nil {
+		t.Fatalf("CountStates failed: %v", err)
+	}
+	if pending != 1 || failed != 1 {
+		t.Errorf("Expected 1 pending and 1 failed event, got %d pending and %d failed", pending, failed)
+	}
+
+	// Event yang sudah di-park tidak dihitung ulang
+	if parked, err := repo.MarkFailed(first.ID, "boom", 3); err != nil || parked {
+		t.Errorf("Expected no change for a parked event, got parked=%v err=%v", parked, err)
+	}
+}
+
+func TestOutboxRelayJob_ParksFailingEventAndExposesFailedCount(t *testing.T) {
+	db := openTestDB(t, migrationSQL(t, "039_achievement_outbox.sql"))
+	repo := repository.NewOutboxRepository(db)
+
+	// Jenis event yang tidak dikenal selalu gagal sebelum menyentuh MongoDB
+	event := &models.OutboxEvent{MongoAchievementID: "a-1", EventType: "achievement.unknown", Payload: json.RawMessage(`{}`)}
+	if err := repo.WithinTransaction(func(tx *sql.Tx) error { return nil }, event); err != nil {
+		t.Fatalf("WithinTransaction failed: %v", err)
+	}
+
+	relayJob := job.NewOutboxRelayJob(unconnectedMongo(t), db, 10)
+	for i := 0; i < service.OutboxMaxAttempts; i++ {
+		if err := relayJob.Run(context.Background()); err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+	}
+
+	var attempts int
+	var failedAt *time.Time
+	if err := db.QueryRow(`SELECT attempts, failed_at FROM achievement_outbox WHERE id = $1`, event.ID).Scan(&attempts, &failedAt); err != nil {
+		t.Fatalf("Failed to read event: %v", err)
+	}
+	if attempts != service.OutboxMaxAttempts || failedAt == nil {
+		t.Fatalf("Expected the event to be parked after %d attempts, got attempts=%d failed_at=%v", service.OutboxMaxAttempts, attempts, failedAt)
+	}
+
+	var out strings.Builder
+	if err := utils.WriteMetrics(&out); err != nil {
+		t.Fatalf("WriteMetrics failed: %v", err)
+	}
+	for _, line := range []string{"achievement_outbox_failed 1\n", "achievement_outbox_pending 0\n"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("Expected %q in metrics output:\n%s", line, out.String())
+		}
+	}
+}
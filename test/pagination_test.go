@@ -0,0 +1,98 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"crud-app/app/utils"
+	"testing"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	cursor := models.PageCursor{
+		Sort:     "users:created_at:desc",
+		Key:      "2026-03-01 10:00:00.123456",
+		ID:       "2b1c8a52-6f0e-4d55-9d43-3f5c0f1e9a10",
+		Backward: true,
+	}
+
+	encoded := utils.EncodeCursor(cursor)
+	decoded, err := utils.DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *decoded != cursor {
+		t.Errorf("Expected %+v, got %+v", cursor, *decoded)
+	}
+}
+
+func TestCursor_URLSafe(t *testing.T) {
+	encoded := utils.EncodeCursor(models.PageCursor{Sort: "s", Key: "??>>~~", ID: "id/+="})
+	for _, r := range encoded {
+		if r == '+' || r == '/' || r == '=' || r == '?' || r == '&' {
+			t.Fatalf("Expected URL-safe cursor, got %q", encoded)
+		}
+	}
+}
+
+func TestCursor_RejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"not base64!", "bm90IGpzb24", utils.EncodeCursor(models.PageCursor{Key: "x"})} {
+		if _, err := utils.DecodeCursor(raw); err != utils.ErrMalformedCursor {
+			t.Errorf("Expected ErrMalformedCursor for %q, got %v", raw, err)
+		}
+	}
+}
+
+func TestCursorPaginationMeta(t *testing.T) {
+	next := &models.PageCursor{Sort: "students:created_at:desc", Key: "k", ID: "1"}
+
+	meta := service.NewCursorPaginationMeta(20, next, nil)
+	if meta.Limit != 20 {
+		t.Errorf("Expected limit 20, got %d", meta.Limit)
+	}
+	if meta.PrevCursor != nil {
+		t.Errorf("Expected no prev cursor on the first page, got %q", *meta.PrevCursor)
+	}
+	if meta.NextCursor == nil {
+		t.Fatal("Expected next cursor")
+	}
+
+	decoded, err := utils.DecodeCursor(*meta.NextCursor)
+	if err != nil || *decoded != *next {
+		t.Errorf("Expected next cursor to decode to %+v, got %+v (%v)", *next, decoded, err)
+	}
+}
+
+func TestFindAllByCursor_TamperedCursorIsInvalid(t *testing.T) {
+	db := openTestDB(t, `CREATE TABLE users (
+		id UUID PRIMARY KEY,
+		username VARCHAR(50) NOT NULL,
+		email VARCHAR(100) NOT NULL,
+		password_hash VARCHAR(255) NOT NULL,
+		full_name VARCHAR(100) NOT NULL,
+		role_id UUID,
+		is_active BOOLEAN NOT NULL DEFAULT TRUE,
+		version INT NOT NULL DEFAULT 1,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		deleted_at TIMESTAMP
+	)`)
+	repo := repository.NewUserRepository(db)
+
+	tampered := []models.PageCursor{
+		{Sort: "users:created_at:desc", Key: "not-a-timestamp", ID: "2b1c8a52-6f0e-4d55-9d43-3f5c0f1e9a10"},
+		{Sort: "users:created_at:desc", Key: "2026-03-01 10:00:00", ID: "not-a-uuid"},
+		{Sort: "users:created_at:desc", Key: "2026-13-45 99:00:00", ID: "2b1c8a52-6f0e-4d55-9d43-3f5c0f1e9a10"},
+	}
+	for _, cursor := range tampered {
+		cursor := cursor
+		if _, _, _, err := repo.FindAllByCursor(&cursor, 10, ""); err != repository.ErrInvalidCursor {
+			t.Errorf("Expected ErrInvalidCursor for %+v, got %v", cursor, err)
+		}
+	}
+
+	valid := models.PageCursor{Sort: "users:created_at:desc", Key: "2026-03-01 10:00:00", ID: "2b1c8a52-6f0e-4d55-9d43-3f5c0f1e9a10"}
+	if _, _, _, err := repo.FindAllByCursor(&valid, 10, ""); err != nil {
+		t.Errorf("Expected valid cursor to succeed, got %v", err)
+	}
+}
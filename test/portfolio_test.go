@@ -0,0 +1,137 @@
+package test
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNormalizePortfolioSlug(t *testing.T) {
+	valid := map[string]string{
+		"siti-rahayu":     "siti-rahayu",
+		"  Siti-Rahayu21": "siti-rahayu21",
+		"abc":             "abc",
+	}
+	for input, expected := range valid {
+		slug, err := service.NormalizePortfolioSlug(input)
+		if err != nil || slug != expected {
+			t.Errorf("%q: expected %q, got %q (err=%v)", input, expected, slug, err)
+		}
+	}
+
+	for _, input := range []string{"ab", "-siti", "siti-", "siti--rahayu", "siti rahayu", "siti_rahayu", "admin", strings.Repeat("a", 51)} {
+		if _, err := service.NormalizePortfolioSlug(input); err == nil {
+			t.Errorf("%q: expected slug to be rejected", input)
+		}
+	}
+}
+
+func TestSuggestPortfolioSlug(t *testing.T) {
+	cases := map[string]string{
+		"Siti Rahayu":         "siti-rahayu",
+		"  M. Rizky  Pratama": "m-rizky-pratama",
+		"Andi":                "andi",
+		"Al":                  "",
+	}
+	for name, expected := range cases {
+		if slug := service.SuggestPortfolioSlug(name); slug != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, slug)
+		}
+	}
+}
+
+func portfolioTestSource() service.PortfolioSource {
+	verifiedAt := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	entry := func(id, title, status string, date time.Time) models.TranscriptEntry {
+		return models.TranscriptEntry{
+			Achievement: models.Achievement{
+				AchievementID: id,
+				Title:         title,
+				Category:      "competition",
+				Level:         "national",
+				Date:          date,
+				Status:        status,
+				Details:       map[string]interface{}{"phone": "08123456789"},
+				Documents:     []models.Document{{Filename: "sertifikat.pdf", Filepath: "uploads/sertifikat.pdf"}},
+			},
+			VerifiedAt: &verifiedAt,
+		}
+	}
+
+	return service.PortfolioSource{
+		Portfolio: models.StudentPortfolio{
+			Slug:                 "siti-rahayu",
+			IsPublic:             true,
+			Headline:             "Mahasiswa Informatika <script>alert(1)</script>",
+			HiddenAchievementIDs: []string{"hidden"},
+		},
+		Name:         "Siti Rahayu",
+		ProgramStudy: "Teknik Informatika",
+		Institution:  "Universitas Airlangga",
+		Entries: []models.TranscriptEntry{
+			entry("older", "Juara 2 Hackathon", "verified", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+			entry("submitted", "Finalis Lomba Esai", "submitted", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)),
+			entry("hidden", "Peserta Seminar", "verified", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)),
+			entry("newer", "Juara 1 Gemastik", "verified", time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)),
+		},
+		Categories: map[string]string{"competition": "Kompetisi"},
+		Levels:     map[string]string{"national": "Nasional"},
+		Codes:      map[string]string{"newer": "ABCDE-12345"},
+		VerifyURL:  func(code string) string { return "https://prestasi.example.ac.id/public/verify/" + code },
+	}
+}
+
+func TestBuildPublicPortfolio(t *testing.T) {
+	portfolio := service.BuildPublicPortfolio(portfolioTestSource())
+
+	if portfolio.AchievementCount != 2 || len(portfolio.Achievements) != 2 {
+		t.Fatalf("Expected only the 2 visible verified achievements, got %+v", portfolio.Achievements)
+	}
+
+	first := portfolio.Achievements[0]
+	if first.Title != "Juara 1 Gemastik" {
+		t.Errorf("Expected newest achievement first, got %q", first.Title)
+	}
+	if first.Category != "Kompetisi" || first.Level != "Nasional" {
+		t.Errorf("Expected master data labels, got %q / %q", first.Category, first.Level)
+	}
+	if first.VerificationCode != "ABCDE-12345" || first.VerificationURL != "https://prestasi.example.ac.id/public/verify/ABCDE-12345" {
+		t.Errorf("Expected verification link, got %q %q", first.VerificationCode, first.VerificationURL)
+	}
+	if portfolio.Achievements[1].VerificationURL != "" {
+		t.Errorf("Expected no link without an active code, got %q", portfolio.Achievements[1].VerificationURL)
+	}
+}
+
+func TestRenderPortfolioHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := service.RenderPortfolioHTML(&buf, service.BuildPublicPortfolio(portfolioTestSource())); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	page := buf.String()
+
+	for _, expected := range []string{"<title>Siti Rahayu - Portofolio Prestasi</title>", "Juara 1 Gemastik", "17 Agustus 2025", "Prestasi Terverifikasi (2)", "Universitas Airlangga"} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Expected page to contain %q", expected)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Error("Expected headline to be HTML-escaped")
+	}
+	for _, private := range []string{"Finalis Lomba Esai", "Peserta Seminar", "08123456789", "sertifikat.pdf"} {
+		if strings.Contains(page, private) {
+			t.Errorf("Expected page not to contain %q", private)
+		}
+	}
+
+	buf.Reset()
+	if err := service.RenderPortfolioNotFoundHTML(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "Portofolio tidak ditemukan") || strings.Contains(buf.String(), "Prestasi Terverifikasi") {
+		t.Error("Expected the not found page to replace the portfolio content")
+	}
+}
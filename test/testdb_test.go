@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// openTestDB membuka koneksi PostgreSQL dari TEST_DATABASE_URL dengan schema sementara yang dihapus
+// setelah test selesai, lalu menjalankan statement setup. Test di-skip jika TEST_DATABASE_URL kosong.
+func openTestDB(t *testing.T, setup ...string) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL tidak diset, test database dilewati")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		admin.Close()
+		t.Fatalf("Failed to create schema: %v", err)
+	}
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		admin.Close()
+	})
+
+	for _, statement := range setup {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to run setup %q: %v", statement, err)
+		}
+	}
+
+	return db
+}
+
+// withSearchPath menambahkan search_path ke DSN (format URL atau key=value) agar setiap koneksi
+// di pool memakai schema test
+func withSearchPath(dsn, schema string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		parsed, err := url.Parse(dsn)
+		if err == nil {
+			query := parsed.Query()
+			query.Set("search_path", schema)
+			parsed.RawQuery = query.Encode()
+			return parsed.String()
+		}
+	}
+	return dsn + " search_path=" + schema
+}
+
+// migrationSQL membaca file migration dari database/migrations
+func migrationSQL(t *testing.T, name string) string {
+	t.Helper()
+
+	content, err := os.ReadFile(filepath.Join("..", "database", "migrations", name))
+	if err != nil {
+		t.Fatalf("Failed to read migration %s: %v", name, err)
+	}
+	return string(content)
+}
+
+// unconnectedMongo database MongoDB yang tidak pernah benar-benar terhubung, untuk membuat service/job
+// yang hanya diuji pada jalur PostgreSQL. Operasi MongoDB apa pun akan gagal.
+func unconnectedMongo(t *testing.T) *mongo.Database {
+	t.Helper()
+
+	client, err := mongo.Connect(context.Background(), options.Client().
+		ApplyURI("mongodb://127.0.0.1:1").
+		SetServerSelectionTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Failed to create MongoDB client: %v", err)
+	}
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+	return client.Database("test")
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"bytes"
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTranscriptLayout_DefaultIsValid(t *testing.T) {
+	if violations := service.ValidateTranscriptLayout(service.DefaultTranscriptLayout()); len(violations) != 0 {
+		t.Fatalf("Expected default layout to be valid, got %v", violations)
+	}
+}
+
+func TestValidateTranscriptLayout_Violations(t *testing.T) {
+	layout := service.DefaultTranscriptLayout()
+	layout.PageSize = "a3"
+	layout.Orientation = "diagonal"
+	layout.AccentColor = "blue"
+	layout.Sections = []string{"identity", "identity", "photo"}
+	layout.Columns = []string{"points"}
+
+	violations := service.ValidateTranscriptLayout(layout)
+	for _, field := range []string{"page_size", "orientation", "accent_color", "muncul lebih dari sekali", "'photo'", "'title'"} {
+		found := false
+		for _, violation := range violations {
+			if strings.Contains(violation, field) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected a violation mentioning %q, got %v", field, violations)
+		}
+	}
+}
+
+func TestApplyTranscriptDefaults_FillsEmptyFields(t *testing.T) {
+	layout := service.ApplyTranscriptDefaults(models.TranscriptLayout{PageSize: "Letter", HeaderLines: []string{"Fakultas Teknik"}})
+
+	if layout.PageSize != "letter" {
+		t.Errorf("Expected page size to be lowercased, got %q", layout.PageSize)
+	}
+	if layout.Orientation != "portrait" || layout.Title == "" || len(layout.Sections) != 4 || len(layout.Columns) == 0 {
+		t.Errorf("Expected empty fields to take defaults, got %+v", layout)
+	}
+	if len(layout.HeaderLines) != 1 || layout.HeaderLines[0] != "Fakultas Teknik" {
+		t.Errorf("Expected header lines to be kept, got %v", layout.HeaderLines)
+	}
+}
+
+func TestBuildTranscriptGroups_OrderAndPoints(t *testing.T) {
+	categories := []models.AchievementCategory{
+		{Code: "academic", LabelID: "Akademik", SortOrder: 1},
+		{Code: "competition", LabelID: "Kompetisi", SortOrder: 2},
+	}
+	levels := []models.AchievementLevel{
+		{Code: "local", LabelID: "Lokal", Rank: 1},
+		{Code: "international", LabelID: "Internasional", Rank: 4},
+	}
+	entry := func(id, category, level, status string) models.TranscriptEntry {
+		return models.TranscriptEntry{Achievement: models.Achievement{
+			AchievementID: id, Title: "Prestasi " + id, Category: category, Level: level, Status: status,
+		}}
+	}
+	entries := []models.TranscriptEntry{
+		entry("a1", "competition", "local", "verified"),
+		entry("a2", "other", "local", "verified"),
+		entry("a3", "competition", "international", "verified"),
+		entry("a4", "academic", "local", "verified"),
+		entry("a5", "competition", "international", "submitted"),
+	}
+	points := map[string]float64{"a1": 10, "a3": 40, "a4": 5, "a5": 99}
+
+	groups := service.BuildTranscriptGroups(entries, points, categories, levels)
+
+	expected := []struct{ category, level string }{
+		{"Akademik", "Lokal"},
+		{"Kompetisi", "Internasional"},
+		{"Kompetisi", "Lokal"},
+		{"other", "Lokal"},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d: %+v", len(expected), len(groups), groups)
+	}
+	for i, want := range expected {
+		if groups[i].Category != want.category || groups[i].Level != want.level {
+			t.Errorf("Group %d: expected %s/%s, got %s/%s", i, want.category, want.level, groups[i].Category, groups[i].Level)
+		}
+	}
+
+	// Achievement yang belum terverifikasi tidak menyumbang poin
+	if len(groups[1].Items) != 2 || groups[1].Points != 40 {
+		t.Errorf("Expected 2 items worth 40 points in international competitions, got %d items and %v points", len(groups[1].Items), groups[1].Points)
+	}
+}
+
+func TestRenderTranscriptPDF(t *testing.T) {
+	verifiedAt := time.Date(2025, 8, 17, 10, 0, 0, 0, time.UTC)
+	data := models.TranscriptData{
+		StudentName:   "Siti Rahayu",
+		StudentNumber: "2021001",
+		ProgramStudy:  "Teknik Informatika",
+		AcademicYear:  "2021",
+		Groups: []models.TranscriptGroup{{
+			Category: "Kompetisi",
+			Level:    "Internasional",
+			Points:   40,
+			Items: []models.TranscriptItem{{
+				AchievementID:    "a1",
+				Title:            "Juara 1 Lomba Pemrograman Tingkat Internasional dengan judul yang cukup panjang agar baris tabel terbungkus",
+				Date:             verifiedAt,
+				Status:           "verified",
+				VerifiedAt:       &verifiedAt,
+				Points:           40,
+				VerificationCode: "ABCDE-12345",
+			}},
+		}},
+		AchievementCount: 1,
+		TotalPoints:      40,
+		VerificationCode: "FGHJK-67890",
+		VerificationURL:  "https://example.ac.id/public/verify/FGHJK-67890",
+		IssuedAt:         verifiedAt,
+	}
+
+	for _, orientation := range []string{"portrait", "landscape"} {
+		layout := service.DefaultTranscriptLayout()
+		layout.Orientation = orientation
+		layout.HeaderLines = []string{"Universitas Contoh", "Fakultas Teknik — Kampus Utama"}
+
+		pdf, err := service.RenderTranscriptPDF(data, layout)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", orientation, err)
+		}
+		if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+			t.Fatalf("Expected PDF output for %s", orientation)
+		}
+	}
+
+	empty := data
+	empty.Groups = nil
+	if _, err := service.RenderTranscriptPDF(empty, models.TranscriptLayout{}); err != nil {
+		t.Fatalf("Expected transcript without achievements to render, got %v", err)
+	}
+}
+
+func TestFormatIndonesianDate(t *testing.T) {
+	if got := service.FormatIndonesianDate(time.Date(2025, 8, 17, 0, 0, 0, 0, time.UTC)); got != "17 Agustus 2025" {
+		t.Errorf("Expected '17 Agustus 2025', got %q", got)
+	}
+}
+
+func TestTranscriptRecordSignature(t *testing.T) {
+	key := []byte("test-key")
+	record := &models.TranscriptRecord{
+		Code:                "ABCDE-12345",
+		StudentID:           "11111111-1111-1111-1111-111111111111",
+		HolderName:          "Siti Rahayu",
+		AchievementCount:    3,
+		TotalPoints:         42.5,
+		VerifierInstitution: "Universitas Contoh",
+		IssuedAt:            time.Date(2025, 8, 17, 10, 0, 0, 0, time.UTC),
+	}
+	record.Signature = service.SignTranscriptRecord(key, record)
+
+	if !service.TranscriptRecordSignatureValid(key, record) {
+		t.Fatal("Expected signature to be valid")
+	}
+
+	// Waktu yang sama di zona lain tetap valid
+	record.IssuedAt = record.IssuedAt.In(time.FixedZone("WIB", 7*3600))
+	if !service.TranscriptRecordSignatureValid(key, record) {
+		t.Error("Expected signature to be independent of time zone")
+	}
+
+	record.TotalPoints = 100
+	if service.TranscriptRecordSignatureValid(key, record) {
+		t.Error("Expected signature to be invalid after tampering")
+	}
+}
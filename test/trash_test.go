@@ -0,0 +1,35 @@
+package test
+
+import (
+	"crud-app/app/service"
+	"testing"
+	"time"
+)
+
+func TestTrashPurgeAt_AddsRetention(t *testing.T) {
+	deletedAt := time.Date(2025, 1, 10, 8, 0, 0, 0, time.UTC)
+
+	purgeAt := service.TrashPurgeAt(&deletedAt, 30*24*time.Hour)
+	if purgeAt == nil {
+		t.Fatal("Expected purge time, got nil")
+	}
+
+	expected := time.Date(2025, 2, 9, 8, 0, 0, 0, time.UTC)
+	if !purgeAt.Equal(expected) {
+		t.Errorf("Expected purge at %v, got %v", expected, *purgeAt)
+	}
+}
+
+func TestTrashPurgeAt_UnknownDeletedAt(t *testing.T) {
+	if purgeAt := service.TrashPurgeAt(nil, 24*time.Hour); purgeAt != nil {
+		t.Errorf("Expected nil purge time for missing deleted_at, got %v", *purgeAt)
+	}
+}
+
+func TestTrashRetention_FromEnv(t *testing.T) {
+	t.Setenv("TRASH_RETENTION_DAYS", "7")
+
+	if got := service.TrashRetention(); got != 7*24*time.Hour {
+		t.Errorf("Expected 7 days retention, got %v", got)
+	}
+}
@@ -0,0 +1,415 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestValidateInboxFilter(t *testing.T) {
+	valid := []models.InboxFilter{
+		{SortBy: "age"},
+		{SortBy: "level", SortOrder: "asc", State: models.InboxStateUnclaimed},
+		{SortBy: "student", SortOrder: "desc", State: models.InboxStateClaimedByOthers},
+	}
+	for _, filter := range valid {
+		if err := service.ValidateInboxFilter(filter); err != nil {
+			t.Errorf("Expected %+v to be valid, got %v", filter, err)
+		}
+	}
+
+	invalid := []models.InboxFilter{
+		{SortBy: "created_at"},
+		{SortBy: "age", SortOrder: "random"},
+		{SortBy: "age", State: "mine"},
+	}
+	for _, filter := range invalid {
+		if err := service.ValidateInboxFilter(filter); err == nil {
+			t.Errorf("Expected %+v to be rejected", filter)
+		}
+	}
+}
+
+func TestInboxWaitingHours(t *testing.T) {
+	now := time.Date(2025, 9, 1, 12, 0, 0, 0, time.UTC)
+	submitted := now.Add(-50*time.Hour - 30*time.Minute)
+
+	if hours := service.InboxWaitingHours(&submitted, now); hours != 50 {
+		t.Errorf("Expected 50 hours, got %d", hours)
+	}
+	if hours := service.InboxWaitingHours(nil, now); hours != 0 {
+		t.Errorf("Expected 0 hours without submitted_at, got %d", hours)
+	}
+	future := now.Add(time.Hour)
+	if hours := service.InboxWaitingHours(&future, now); hours != 0 {
+		t.Errorf("Expected 0 hours for clock skew, got %d", hours)
+	}
+}
+
+func TestPendingApprovalStage(t *testing.T) {
+	stage := service.PendingApprovalStage(nil)
+	if stage == nil || stage.StageOrder != 1 || stage.Permission != "achievements.verify" {
+		t.Fatalf("Expected default advisor stage, got %+v", stage)
+	}
+
+	approver := uuid.New()
+	approvals := []models.AchievementApproval{
+		{StageOrder: 1, StageName: "advisor", Permission: "achievements.verify", Status: "approved", DecidedBy: &approver},
+		{StageOrder: 2, StageName: "faculty", Permission: "achievements.verify_faculty", Status: "pending"},
+	}
+	stage = service.PendingApprovalStage(approvals)
+	if stage == nil || stage.StageName != "faculty" {
+		t.Errorf("Expected faculty stage, got %+v", stage)
+	}
+
+	approvals[1].Status = "approved"
+	if stage := service.PendingApprovalStage(approvals); stage != nil {
+		t.Errorf("Expected no pending stage when all stages are decided, got %+v", stage)
+	}
+}
+
+func TestParseDelegationPeriod(t *testing.T) {
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	start, end, err := service.ParseDelegationPeriod("", "2025-09-14", now)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !start.Equal(now) {
+		t.Errorf("Expected empty starts_at to mean now, got %v", start)
+	}
+	if !end.Equal(time.Date(2025, 9, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected date-only ends_at to cover the whole day, got %v", end)
+	}
+
+	start, end, err = service.ParseDelegationPeriod("2025-09-10T08:00:00Z", "2025-09-12T17:00:00Z", now)
+	if err != nil {
+		t.Fatalf("Expected RFC3339 period to be valid, got %v", err)
+	}
+	if start.Hour() != 8 || end.Hour() != 17 {
+		t.Errorf("Expected RFC3339 times to be kept, got %v - %v", start, end)
+	}
+
+	invalid := [][2]string{
+		{"", ""},                     // ends_at wajib
+		{"2025-09-10", "2025-09-05"}, // berakhir sebelum mulai
+		{"2025-08-01", "2025-08-20"}, // sudah lewat
+		{"", "2026-06-01"},           // lebih dari 180 hari
+		{"10/09/2025", "2025-09-20"}, // format tidak dikenal
+	}
+	for _, period := range invalid {
+		if _, _, err := service.ParseDelegationPeriod(period[0], period[1], now); err == nil {
+			t.Errorf("Expected period %v to be rejected", period)
+		}
+	}
+}
+
+func TestApprovalStageReached(t *testing.T) {
+	const faculty = "achievements.verify_faculty"
+	chain := func(advisorStatus, facultyStatus string) []models.AchievementApproval {
+		return []models.AchievementApproval{
+			{StageOrder: 1, StageName: "advisor", Permission: "achievements.verify", Status: advisorStatus},
+			{StageOrder: 2, StageName: "faculty", Permission: faculty, Status: facultyStatus},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		approvals []models.AchievementApproval
+		expected  bool
+	}{
+		{"no chain", nil, false},
+		{"advisor only", chain("pending", "pending")[:1], false},
+		{"waiting for advisor", chain("pending", "pending"), false},
+		{"rejected by advisor", chain("rejected", "pending"), false},
+		{"waiting for faculty", chain("approved", "pending"), true},
+		{"decided by faculty", chain("approved", "rejected"), true},
+	}
+	for _, tt := range tests {
+		if reached := service.ApprovalStageReached(tt.approvals, faculty); reached != tt.expected {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, reached)
+		}
+	}
+}
+
+// inboxTestDB menyiapkan read model, klaim, dan master level untuk test query inbox
+func inboxTestDB(t *testing.T) *sql.DB {
+	return openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE achievement_levels (code VARCHAR(100) PRIMARY KEY, rank INT NOT NULL UNIQUE)`,
+		`CREATE TABLE achievement_approvals (
+			mongo_achievement_id VARCHAR(100) NOT NULL,
+			stage_order INT NOT NULL,
+			stage_name VARCHAR(100) NOT NULL,
+			permission VARCHAR(100) NOT NULL,
+			status VARCHAR(20) NOT NULL DEFAULT 'pending',
+			UNIQUE (mongo_achievement_id, stage_order)
+		)`,
+		migrationSQL(t, "043_achievement_read_model.sql"),
+		migrationSQL(t, "050_verification_inbox.sql"),
+	)
+}
+
+func insertInboxAchievement(t *testing.T, db *sql.DB, mongoID string, advisorID uuid.UUID, status string, submittedAt time.Time) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO achievement_read_model
+			(mongo_achievement_id, reference_id, student_id, status, current_stage, total_stages,
+			 submitted_at, advisor_id, title, level, document, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 1, 1, $5, $6, $1, 'nasional', '{}', NOW(), NOW())
+	`, mongoID, uuid.New(), uuid.New(), status, submittedAt, advisorID)
+	if err != nil {
+		t.Fatalf("Failed to insert %s: %v", mongoID, err)
+	}
+}
+
+func TestFindInbox_WithoutFilters(t *testing.T) {
+	db := inboxTestDB(t)
+
+	lecturer, other := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{lecturer, other} {
+		if _, err := db.Exec(`INSERT INTO users (id, full_name) VALUES ($1, 'Dosen')`, id); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO achievement_levels (code, rank) VALUES ('nasional', 3)`); err != nil {
+		t.Fatalf("Failed to insert level: %v", err)
+	}
+
+	now := time.Now()
+	insertInboxAchievement(t, db, "own-1", lecturer, "submitted", now.Add(-3*time.Hour))
+	insertInboxAchievement(t, db, "own-2", lecturer, "submitted", now.Add(-2*time.Hour))
+	insertInboxAchievement(t, db, "other-1", other, "submitted", now.Add(-1*time.Hour))
+	insertInboxAchievement(t, db, "verified-1", lecturer, "verified", now.Add(-4*time.Hour))
+	if _, err := db.Exec(`INSERT INTO verification_claims (mongo_achievement_id, claimed_by, expires_at) VALUES ('own-2', $1, $2)`,
+		other, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Failed to insert claim: %v", err)
+	}
+
+	repo := repository.NewAchievementReadModelRepository(db)
+
+	tests := []struct {
+		name     string
+		filter   models.InboxFilter
+		expected []string
+		counts   models.InboxCounts
+	}{
+		// Admin tanpa filter apa pun: query tanpa placeholder kecuali LIMIT/OFFSET
+		{"admin", models.InboxFilter{UserID: lecturer.String()},
+			[]string{"own-1", "own-2", "other-1"}, models.InboxCounts{Total: 3, Unclaimed: 2, ClaimedByOthers: 1}},
+		// Dosen wali tanpa filter tambahan
+		{"lecturer", models.InboxFilter{UserID: lecturer.String(), AdvisorIDs: []string{lecturer.String()}},
+			[]string{"own-1", "own-2"}, models.InboxCounts{Total: 2, Unclaimed: 1, ClaimedByOthers: 1}},
+		{"claimed by others", models.InboxFilter{UserID: lecturer.String(), AdvisorIDs: []string{lecturer.String()}, State: models.InboxStateClaimedByOthers},
+			[]string{"own-2"}, models.InboxCounts{Total: 2, Unclaimed: 1, ClaimedByOthers: 1}},
+		{"claimed by me", models.InboxFilter{UserID: other.String(), State: models.InboxStateClaimedByMe, SortBy: "level"},
+			[]string{"own-2"}, models.InboxCounts{Total: 3, Unclaimed: 2, ClaimedByMe: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, total, err := repo.FindInbox(tt.filter, 10, 0)
+			if err != nil {
+				t.Fatalf("FindInbox failed: %v", err)
+			}
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, item.AchievementID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") || total != int64(len(tt.expected)) {
+				t.Errorf("Expected %v (total %d), got %v (total %d)", tt.expected, len(tt.expected), ids, total)
+			}
+
+			counts, err := repo.CountInbox(tt.filter)
+			if err != nil {
+				t.Fatalf("CountInbox failed: %v", err)
+			}
+			if counts != tt.counts {
+				t.Errorf("Expected counts %+v, got %+v", tt.counts, counts)
+			}
+		})
+	}
+}
+
+func TestFindInbox_OnlyPendingStagesTheUserCanDecide(t *testing.T) {
+	db := inboxTestDB(t)
+
+	advisor := uuid.New()
+	if _, err := db.Exec(`INSERT INTO users (id, full_name) VALUES ($1, 'Dosen')`, advisor); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+
+	now := time.Now()
+	insertInboxAchievement(t, db, "legacy", advisor, "submitted", now.Add(-3*time.Hour))
+	insertInboxAchievement(t, db, "at-advisor", advisor, "submitted", now.Add(-2*time.Hour))
+	insertInboxAchievement(t, db, "at-faculty", advisor, "submitted", now.Add(-1*time.Hour))
+	for _, statement := range []string{
+		`UPDATE achievement_read_model SET total_stages = 2 WHERE mongo_achievement_id IN ('at-advisor', 'at-faculty')`,
+		`UPDATE achievement_read_model SET current_stage = 2 WHERE mongo_achievement_id = 'at-faculty'`,
+		`INSERT INTO achievement_approvals (mongo_achievement_id, stage_order, stage_name, permission, status) VALUES
+			('at-advisor', 1, 'advisor', 'achievements.verify', 'pending'),
+			('at-advisor', 2, 'faculty', 'achievements.verify_faculty', 'pending'),
+			('at-faculty', 1, 'advisor', 'achievements.verify', 'approved'),
+			('at-faculty', 2, 'faculty', 'achievements.verify_faculty', 'pending')`,
+	} {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatalf("Failed to set up approval stages: %v", err)
+		}
+	}
+
+	repo := repository.NewAchievementReadModelRepository(db)
+
+	tests := []struct {
+		name        string
+		permissions []string
+		expected    []string
+	}{
+		// Achievement tanpa rantai verifikasi menunggu tahap default (dosen wali)
+		{"advisor", []string{"achievements.read", "achievements.verify"}, []string{"legacy", "at-advisor"}},
+		{"faculty verifier", []string{"achievements.verify_faculty"}, []string{"at-faculty"}},
+		{"both stages", []string{"achievements.verify", "achievements.verify_faculty"}, []string{"legacy", "at-advisor", "at-faculty"}},
+		{"no stage permission", []string{}, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := models.InboxFilter{
+				UserID:                 advisor.String(),
+				AdvisorIDs:             []string{advisor.String()},
+				StagePermissions:       tt.permissions,
+				DefaultStagePermission: service.DefaultVerificationStage.Permission,
+			}
+			items, total, err := repo.FindInbox(filter, 10, 0)
+			if err != nil {
+				t.Fatalf("FindInbox failed: %v", err)
+			}
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, item.AchievementID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expected, ",") || total != int64(len(tt.expected)) {
+				t.Errorf("Expected %v (total %d), got %v (total %d)", tt.expected, len(tt.expected), ids, total)
+			}
+
+			counts, err := repo.CountInbox(filter)
+			if err != nil {
+				t.Fatalf("CountInbox failed: %v", err)
+			}
+			if counts.Total != int64(len(tt.expected)) {
+				t.Errorf("Expected count %d, got %d", len(tt.expected), counts.Total)
+			}
+		})
+	}
+}
+
+// Setiap kombinasi filter hanya boleh mengikat argumen yang dipakai query (tanpa PostgreSQL)
+func TestInboxQueries_BindOnlyReferencedParameters(t *testing.T) {
+	repo := repository.NewAchievementReadModelRepository(openPlaceholderDB(t))
+
+	userID := uuid.New().String()
+	from, to := time.Now().AddDate(0, -1, 0), time.Now()
+	filters := map[string]models.InboxFilter{
+		"admin without filters": {UserID: userID},
+		"lecturer":              {UserID: userID, AdvisorIDs: []string{userID}},
+		"unclaimed":             {UserID: userID, State: models.InboxStateUnclaimed},
+		"claimed by me":         {UserID: userID, State: models.InboxStateClaimedByMe},
+		"claimed by others":     {UserID: userID, AdvisorIDs: []string{userID}, State: models.InboxStateClaimedByOthers},
+		"stage permissions":     {UserID: userID, StagePermissions: []string{"achievements.verify"}, DefaultStagePermission: "achievements.verify"},
+		"all filters": {
+			UserID: userID, AdvisorIDs: []string{userID}, State: models.InboxStateClaimedByMe,
+			StagePermissions: []string{"achievements.verify"}, DefaultStagePermission: "achievements.verify",
+			Category: "kompetisi", Level: "nasional", StudentID: uuid.New().String(), ProgramStudy: "Informatika",
+			Search: "robot", SubmittedFrom: &from, SubmittedTo: &to, SortBy: "level", SortOrder: "asc",
+		},
+	}
+
+	for name, filter := range filters {
+		if _, _, err := repo.FindInbox(filter, 20, 0); err != nil {
+			t.Errorf("%s: FindInbox failed: %v", name, err)
+		}
+		if _, err := repo.CountInbox(filter); err != nil {
+			t.Errorf("%s: CountInbox failed: %v", name, err)
+		}
+	}
+}
+
+// Keputusan dan klaim memakai aturan yang sama dengan detail review: scope inbox (bimbingan atau delegasi
+// aktif) dan permission tahap yang sedang menunggu
+func TestCheckDecisionAccess_RequiresScopeAndPendingStagePermission(t *testing.T) {
+	db := openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL, role_id VARCHAR(50))`,
+		`CREATE TABLE permissions (id UUID PRIMARY KEY, name VARCHAR(100))`,
+		`CREATE TABLE role_permissions (role_id VARCHAR(50), permission_id UUID)`,
+		`CREATE TABLE students (id UUID PRIMARY KEY, user_id UUID, student_id VARCHAR(20), program_study VARCHAR(100),
+			academic_year VARCHAR(10), advisor_id UUID, created_at TIMESTAMP NOT NULL DEFAULT NOW())`,
+		migrationSQL(t, "043_achievement_read_model.sql"),
+		migrationSQL(t, "050_verification_inbox.sql"),
+	)
+
+	student, advisor, otherAdvisor := uuid.New(), uuid.New(), uuid.New()
+	activeDelegate, expiredDelegate, faculty := uuid.New(), uuid.New(), uuid.New()
+	verify, verifyFaculty := uuid.New(), uuid.New()
+	now := time.Now()
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO users (id, full_name, role_id) VALUES ($1, 'Mahasiswa', 'mahasiswa'), ($2, 'Dosen Wali', 'dosen'),
+			($3, 'Dosen Lain', 'dosen'), ($4, 'Delegasi Aktif', 'dosen'), ($5, 'Delegasi Lama', 'dosen'), ($6, 'Fakultas', 'kemahasiswaan')`,
+			[]interface{}{student, advisor, otherAdvisor, activeDelegate, expiredDelegate, faculty}},
+		{`INSERT INTO permissions (id, name) VALUES ($1, 'achievements.verify'), ($2, 'achievements.verify_faculty')`,
+			[]interface{}{verify, verifyFaculty}},
+		{`INSERT INTO role_permissions (role_id, permission_id) VALUES ('dosen', $1), ('kemahasiswaan', $2)`,
+			[]interface{}{verify, verifyFaculty}},
+		{`INSERT INTO students (id, user_id, student_id, program_study, academic_year, advisor_id)
+			VALUES ($1, $2, 'M001', 'Informatika', '2023', $3)`, []interface{}{uuid.New(), student, advisor}},
+		{`INSERT INTO verification_delegations (id, delegator_id, delegate_id, starts_at, ends_at)
+			VALUES ($1, $2, $3, $4, $5), ($6, $2, $7, $8, $9)`,
+			[]interface{}{uuid.New(), advisor, activeDelegate, now.Add(-time.Hour), now.Add(24 * time.Hour),
+				uuid.New(), expiredDelegate, now.Add(-48 * time.Hour), now.Add(-24 * time.Hour)}},
+	} {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatalf("Failed to set up: %v", err)
+		}
+	}
+
+	s := service.NewAchievementService(unconnectedMongo(t), db)
+	achievement := &models.Achievement{AchievementID: "decision-1", StudentID: student.String(), Status: "submitted"}
+	advisorStage := &models.AchievementApproval{StageOrder: 1, StageName: "advisor", Permission: "achievements.verify", Status: "pending"}
+	facultyStage := &models.AchievementApproval{StageOrder: 2, StageName: "faculty", Permission: "achievements.verify_faculty", Status: "pending"}
+
+	tests := []struct {
+		name     string
+		userID   uuid.UUID
+		stage    *models.AchievementApproval
+		expected int
+	}{
+		{"advisor at the advisor stage", advisor, advisorStage, 0},
+		{"active delegate of the advisor", activeDelegate, advisorStage, 0},
+		{"lecturer outside the advisees", otherAdvisor, advisorStage, 403},
+		{"expired delegation", expiredDelegate, advisorStage, 403},
+		{"advisor at the faculty stage", advisor, facultyStage, 403},
+		{"faculty verifier at the faculty stage", faculty, facultyStage, 0},
+		{"faculty verifier at the advisor stage", faculty, advisorStage, 403},
+	}
+	for _, tt := range tests {
+		err := s.CheckDecisionAccess(tt.userID.String(), "2", achievement, tt.stage)
+		status := 0
+		if err != nil {
+			withStatus, ok := err.(interface{ HTTPStatus() int })
+			if !ok {
+				t.Fatalf("%s: expected an error with an HTTP status, got %v", tt.name, err)
+			}
+			status = withStatus.HTTPStatus()
+		}
+		if status != tt.expected {
+			t.Errorf("%s: expected status %d, got %d (%v)", tt.name, tt.expected, status, err)
+		}
+	}
+}
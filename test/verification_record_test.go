@@ -0,0 +1,209 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+func TestGenerateVerificationCode_Format(t *testing.T) {
+	pattern := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{5}-[0-9A-HJKMNP-TV-Z]{5}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		code, err := service.GenerateVerificationCode()
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !pattern.MatchString(code) {
+			t.Fatalf("Expected XXXXX-XXXXX in Crockford base32, got %q", code)
+		}
+		if seen[code] {
+			t.Fatalf("Expected unique codes, got %q twice", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestNormalizeVerificationCode(t *testing.T) {
+	cases := []struct {
+		raw   string
+		want  string
+		valid bool
+	}{
+		{"AB12C-D34EF", "AB12C-D34EF", true},
+		{"ab12cd34ef", "AB12C-D34EF", true},
+		{" ab12c d34ef ", "AB12C-D34EF", true},
+		{"OIL00-11111", "01100-11111", true}, // O dibaca 0, I/L dibaca 1
+		{"AB12C-D34E", "", false},
+		{"AB12C-D34EFG", "", false},
+		{"AB12C-D34EU", "", false}, // U tidak ada di alfabet
+		{"../../etc", "", false},
+	}
+
+	for _, tc := range cases {
+		got, ok := service.NormalizeVerificationCode(tc.raw)
+		if ok != tc.valid || got != tc.want {
+			t.Errorf("NormalizeVerificationCode(%q) = %q, %v; want %q, %v", tc.raw, got, ok, tc.want, tc.valid)
+		}
+	}
+}
+
+func newSignedRecord(key []byte) *models.VerificationRecord {
+	record := &models.VerificationRecord{
+		Code:                "AB12C-D34EF",
+		MongoAchievementID:  "ach-1",
+		Title:               "Juara 1 Lomba Robotik",
+		HolderName:          "Budi Santoso",
+		Level:               "Nasional",
+		VerifiedAt:          time.Date(2026, 5, 1, 8, 30, 0, 123456000, time.UTC),
+		VerifierInstitution: "Universitas",
+		IssuedAt:            time.Date(2026, 5, 1, 8, 31, 0, 0, time.UTC),
+	}
+	record.Signature = service.SignVerificationRecord(key, record)
+	return record
+}
+
+func TestVerificationRecordSignature_Valid(t *testing.T) {
+	key := []byte("secret")
+	record := newSignedRecord(key)
+
+	if !service.VerificationRecordSignatureValid(key, record) {
+		t.Error("Expected signature of an untouched record to be valid")
+	}
+	if service.VerificationRecordSignatureValid([]byte("other"), record) {
+		t.Error("Expected signature to be invalid with another key")
+	}
+}
+
+func TestVerificationRecordSignature_DetectsTampering(t *testing.T) {
+	key := []byte("secret")
+
+	tamper := map[string]func(r *models.VerificationRecord){
+		"title":       func(r *models.VerificationRecord) { r.Title = "Juara 1 Lomba Robotik Internasional" },
+		"holder":      func(r *models.VerificationRecord) { r.HolderName = "Andi" },
+		"level":       func(r *models.VerificationRecord) { r.Level = "Internasional" },
+		"verified_at": func(r *models.VerificationRecord) { r.VerifiedAt = r.VerifiedAt.AddDate(-1, 0, 0) },
+		"code":        func(r *models.VerificationRecord) { r.Code = "ZZZZZ-ZZZZZ" },
+		// Menggeser batas field tidak boleh menghasilkan tanda tangan yang sama
+		"boundary": func(r *models.VerificationRecord) {
+			r.Title, r.HolderName = r.Title+"Budi", " Santoso"
+		},
+	}
+
+	for name, change := range tamper {
+		record := newSignedRecord(key)
+		change(record)
+		if service.VerificationRecordSignatureValid(key, record) {
+			t.Errorf("Expected tampered %s to invalidate the signature", name)
+		}
+	}
+}
+
+func TestVerificationRecordSignature_TimezoneIndependent(t *testing.T) {
+	key := []byte("secret")
+	record := newSignedRecord(key)
+
+	// Nilai waktu yang sama dibaca kembali dari database dengan zona lain tetap valid
+	record.VerifiedAt = record.VerifiedAt.In(time.FixedZone("WIB", 7*3600))
+	if !service.VerificationRecordSignatureValid(key, record) {
+		t.Error("Expected signature to ignore the time zone of the same instant")
+	}
+}
+
+// Kode yang dicabut admin tidak boleh diganti otomatis saat pemilik meminta record lagi
+func TestGetVerificationRecord_DoesNotReissueRevokedCode(t *testing.T) {
+	db := openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL)`,
+		`CREATE TABLE roles (id UUID PRIMARY KEY, name VARCHAR(50) NOT NULL)`,
+		`CREATE TABLE permissions (id UUID PRIMARY KEY, name VARCHAR(100) UNIQUE NOT NULL, resource VARCHAR(50), action VARCHAR(50), description TEXT)`,
+		`CREATE TABLE role_permissions (role_id UUID NOT NULL, permission_id UUID NOT NULL, PRIMARY KEY (role_id, permission_id))`,
+		`CREATE TABLE achievement_references (
+			id UUID PRIMARY KEY,
+			student_id UUID NOT NULL,
+			mongo_achievement_id VARCHAR(100) NOT NULL,
+			status VARCHAR(20) NOT NULL,
+			current_stage INT NOT NULL DEFAULT 1,
+			total_stages INT NOT NULL DEFAULT 1,
+			submitted_at TIMESTAMP,
+			verified_at TIMESTAMP,
+			verified_by UUID,
+			rejection_note TEXT,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+			deleted_at TIMESTAMP
+		)`,
+		migrationSQL(t, "045_verification_records.sql"),
+	)
+
+	owner, admin := uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{owner, admin} {
+		if _, err := db.Exec(`INSERT INTO users (id, full_name) VALUES ($1, 'User')`, id); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO achievement_references (id, student_id, mongo_achievement_id, status, verified_at)
+		VALUES ($1, $2, 'vr-1', 'verified', NOW())`, uuid.New(), owner); err != nil {
+		t.Fatalf("Failed to insert reference: %v", err)
+	}
+
+	recordRepo := repository.NewVerificationRecordRepository(db)
+	record := newSignedRecord([]byte("secret"))
+	record.ID = uuid.New()
+	record.MongoAchievementID = "vr-1"
+	if err := recordRepo.Create(record); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if err := recordRepo.Revoke("vr-1", admin.String(), "Sertifikat palsu"); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	s := service.NewVerificationRecordService(unconnectedMongo(t), db)
+	request := func(method, userID, roleID string, handler fiber.Handler) int {
+		app := fiber.New()
+		app.Add(method, "/achievements/:id/verification-record", func(c *fiber.Ctx) error {
+			c.Locals("user_id", userID)
+			c.Locals("role_id", roleID)
+			return handler(c)
+		})
+		resp, err := app.Test(httptest.NewRequest(method, "/achievements/vr-1/verification-record", nil))
+		if err != nil {
+			t.Fatalf("Request failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if status := request("GET", owner.String(), "3", s.GetVerificationRecord); status != 409 {
+		t.Errorf("Expected 409 for the owner after revocation, got %d", status)
+	}
+	if status := request("GET", admin.String(), "1", s.GetVerificationRecord); status != 409 {
+		t.Errorf("Expected 409 for an admin GET after revocation (reissue is an explicit POST), got %d", status)
+	}
+
+	active, err := recordRepo.FindActiveByAchievement("vr-1")
+	if err != nil {
+		t.Fatalf("FindActiveByAchievement failed: %v", err)
+	}
+	if active != nil {
+		t.Fatalf("Expected no new code after revocation, got %s", active.Code)
+	}
+
+	// Penerbitan ulang ditolak selama masih ada kode aktif
+	second := newSignedRecord([]byte("secret"))
+	second.ID = uuid.New()
+	second.Code = "ZZZZZ-ZZZZZ"
+	second.MongoAchievementID = "vr-1"
+	if err := recordRepo.Create(second); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if status := request("POST", admin.String(), "1", s.ReissueVerificationRecord); status != 409 {
+		t.Errorf("Expected 409 when reissuing while a code is active, got %d", status)
+	}
+}
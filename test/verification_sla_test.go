@@ -0,0 +1,235 @@
+package test
+
+import (
+	"crud-app/app/job"
+	models "crud-app/app/model"
+	"crud-app/app/service"
+	"database/sql"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveSLARule_MatchesLevelCaseInsensitive(t *testing.T) {
+	rules := []models.VerificationSLARule{
+		{Level: "*", ReminderAfterHours: 72, EscalateAfterHours: 168},
+		{Level: "nasional", ReminderAfterHours: 48, EscalateAfterHours: 120},
+	}
+
+	rule := service.ResolveSLARule(rules, "Nasional")
+
+	if rule.ReminderAfterHours != 48 || rule.EscalateAfterHours != 120 {
+		t.Errorf("Expected nasional rule (48/120), got %d/%d", rule.ReminderAfterHours, rule.EscalateAfterHours)
+	}
+}
+
+func TestResolveSLARule_FallbackToWildcard(t *testing.T) {
+	rules := []models.VerificationSLARule{
+		{Level: "nasional", ReminderAfterHours: 48, EscalateAfterHours: 120},
+		{Level: "*", ReminderAfterHours: 24, EscalateAfterHours: 96},
+	}
+
+	rule := service.ResolveSLARule(rules, "Lokal")
+
+	if rule.Level != "*" || rule.ReminderAfterHours != 24 {
+		t.Errorf("Expected wildcard rule, got level '%s' with %d hours", rule.Level, rule.ReminderAfterHours)
+	}
+}
+
+func TestResolveSLARule_DefaultWhenNoRules(t *testing.T) {
+	rule := service.ResolveSLARule(nil, "Internasional")
+
+	if rule != service.DefaultSLARule {
+		t.Errorf("Expected DefaultSLARule, got %+v", rule)
+	}
+}
+
+func TestSLAAction_Thresholds(t *testing.T) {
+	rule := models.VerificationSLARule{Level: "*", ReminderAfterHours: 48, EscalateAfterHours: 120}
+
+	tests := []struct {
+		pendingHours int
+		expected     string
+	}{
+		{0, ""},
+		{47, ""},
+		{48, "reminder"},
+		{119, "reminder"},
+		{120, "escalation"},
+		{500, "escalation"},
+	}
+	for _, tt := range tests {
+		if action := service.SLAAction(rule, tt.pendingHours); action != tt.expected {
+			t.Errorf("Expected %q after %d hours, got %q", tt.expected, tt.pendingHours, action)
+		}
+	}
+}
+
+// slaTestDB menyiapkan tabel yang dibaca job SLA untuk menentukan dan mencatat penerima
+func slaTestDB(t *testing.T) *sql.DB {
+	return openTestDB(t,
+		`CREATE TABLE users (id UUID PRIMARY KEY, full_name VARCHAR(255) NOT NULL DEFAULT '', role_id VARCHAR(50),
+			is_active BOOLEAN NOT NULL DEFAULT TRUE, deleted_at TIMESTAMP)`,
+		`CREATE TABLE students (user_id UUID, advisor_id UUID)`,
+		`CREATE TABLE lecturers (id UUID PRIMARY KEY, user_id UUID, lecturer_id VARCHAR(20), department VARCHAR(100),
+			created_at TIMESTAMP NOT NULL DEFAULT NOW())`,
+		`CREATE TABLE permissions (id UUID PRIMARY KEY, name VARCHAR(100))`,
+		`CREATE TABLE role_permissions (role_id VARCHAR(50), permission_id UUID)`,
+		`CREATE TABLE achievement_approvals (id UUID PRIMARY KEY, mongo_achievement_id VARCHAR(100), stage_order INT,
+			stage_name VARCHAR(100), permission VARCHAR(100), status VARCHAR(20), decided_by UUID, note TEXT,
+			decided_at TIMESTAMP, created_at TIMESTAMP NOT NULL DEFAULT NOW())`,
+		`CREATE TABLE department_admins (department VARCHAR(100), user_id UUID)`,
+		`CREATE TABLE notifications (id UUID PRIMARY KEY, user_id UUID, type VARCHAR(50), title VARCHAR(255),
+			message TEXT, mongo_achievement_id VARCHAR(100), is_read BOOLEAN, created_at TIMESTAMP)`,
+		`CREATE TABLE verification_escalations (id UUID PRIMARY KEY, mongo_achievement_id VARCHAR(100),
+			escalation_type VARCHAR(20), notified_user_id UUID, pending_hours INT, created_at TIMESTAMP)`,
+		`CREATE TABLE verification_delegations (id UUID PRIMARY KEY, delegator_id UUID, delegate_id UUID,
+			starts_at TIMESTAMP, ends_at TIMESTAMP, reason TEXT, created_by UUID,
+			created_at TIMESTAMP NOT NULL DEFAULT NOW(), revoked_at TIMESTAMP)`,
+	)
+}
+
+// notifiedUsers user yang menerima reminder/eskalasi achievement, urut user ID
+func notifiedUsers(t *testing.T, db *sql.DB, achievementID, escalationType string) []string {
+	t.Helper()
+
+	rows, err := db.Query(`
+		SELECT notified_user_id::text FROM verification_escalations
+		WHERE mongo_achievement_id = $1 AND escalation_type = $2
+		ORDER BY notified_user_id
+	`, achievementID, escalationType)
+	if err != nil {
+		t.Fatalf("Failed to query escalations: %v", err)
+	}
+	defer rows.Close()
+
+	users := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Failed to scan escalation: %v", err)
+		}
+		users = append(users, id)
+	}
+	return users
+}
+
+func sortedIDs(ids ...uuid.UUID) []string {
+	sorted := make([]string, len(ids))
+	for i, id := range ids {
+		sorted[i] = id.String()
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func TestVerificationSLAJob_RemindsAdvisorAndActiveDelegatesOncePerStage(t *testing.T) {
+	db := slaTestDB(t)
+
+	student, advisor, delegate, expiredDelegate := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for _, id := range []uuid.UUID{student, advisor, delegate, expiredDelegate} {
+		if _, err := db.Exec(`INSERT INTO users (id, role_id) VALUES ($1, '2')`, id); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	now := time.Now()
+	setup := []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO students (user_id, advisor_id) VALUES ($1, $2)`, []interface{}{student, advisor}},
+		{`INSERT INTO verification_delegations (id, delegator_id, delegate_id, starts_at, ends_at) VALUES ($1, $2, $3, $4, $5)`,
+			[]interface{}{uuid.New(), advisor, delegate, now.Add(-24 * time.Hour), now.Add(24 * time.Hour)}},
+		{`INSERT INTO verification_delegations (id, delegator_id, delegate_id, starts_at, ends_at) VALUES ($1, $2, $3, $4, $5)`,
+			[]interface{}{uuid.New(), advisor, expiredDelegate, now.Add(-72 * time.Hour), now.Add(-48 * time.Hour)}},
+	}
+	for _, statement := range setup {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatalf("Failed to set up: %v", err)
+		}
+	}
+
+	slaJob := job.NewVerificationSLAJob(unconnectedMongo(t), db)
+	rule := models.VerificationSLARule{Level: "*", ReminderAfterHours: 48, EscalateAfterHours: 120}
+	achievement := models.Achievement{AchievementID: "ach-1", Title: "Juara 1", Level: "nasional"}
+
+	stageEntered := now.Add(-50 * time.Hour)
+	ref := models.AchievementReferences{MongoAchievementID: "ach-1", StudentID: student, Status: "submitted", StageEnteredAt: &stageEntered}
+
+	// Belum melewati batas reminder: tidak ada yang dikirim
+	slaJob.CheckStage(ref, achievement, rule, stageEntered.Add(47*time.Hour))
+	if sent := notifiedUsers(t, db, "ach-1", "reminder"); len(sent) != 0 {
+		t.Fatalf("Expected no reminder before the threshold, got %v", sent)
+	}
+
+	// Dosen wali dan penerima delegasi yang masih berlaku mendapat reminder, sekali saja per tahap
+	slaJob.CheckStage(ref, achievement, rule, now)
+	slaJob.CheckStage(ref, achievement, rule, now.Add(time.Hour))
+	expected := sortedIDs(advisor, delegate)
+	if sent := notifiedUsers(t, db, "ach-1", "reminder"); strings.Join(sent, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected reminders for %v, got %v", expected, sent)
+	}
+
+	// Masuk tahap baru: reminder tahap sebelumnya tidak menghalangi reminder berikutnya
+	nextStage := now.Add(time.Minute)
+	ref.StageEnteredAt = &nextStage
+	slaJob.CheckStage(ref, achievement, rule, nextStage.Add(49*time.Hour))
+	if sent := notifiedUsers(t, db, "ach-1", "reminder"); len(sent) != 4 {
+		t.Errorf("Expected a second round of reminders for the new stage, got %v", sent)
+	}
+}
+
+func TestVerificationSLAJob_EscalatesToDepartmentAdminsOncePerStage(t *testing.T) {
+	db := slaTestDB(t)
+
+	student, advisor, departmentAdmin, admin := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	for _, user := range []struct {
+		id   uuid.UUID
+		role string
+	}{{student, "3"}, {advisor, "2"}, {departmentAdmin, "2"}, {admin, "1"}} {
+		if _, err := db.Exec(`INSERT INTO users (id, role_id) VALUES ($1, $2)`, user.id, user.role); err != nil {
+			t.Fatalf("Failed to insert user: %v", err)
+		}
+	}
+	for _, statement := range []struct {
+		query string
+		args  []interface{}
+	}{
+		{`INSERT INTO students (user_id, advisor_id) VALUES ($1, $2)`, []interface{}{student, advisor}},
+		{`INSERT INTO lecturers (id, user_id, lecturer_id, department) VALUES ($1, $2, 'L001', 'Informatika')`, []interface{}{uuid.New(), advisor}},
+		{`INSERT INTO department_admins (department, user_id) VALUES ('informatika', $1)`, []interface{}{departmentAdmin}},
+	} {
+		if _, err := db.Exec(statement.query, statement.args...); err != nil {
+			t.Fatalf("Failed to set up: %v", err)
+		}
+	}
+
+	slaJob := job.NewVerificationSLAJob(unconnectedMongo(t), db)
+	rule := models.VerificationSLARule{Level: "*", ReminderAfterHours: 48, EscalateAfterHours: 120}
+	achievement := models.Achievement{AchievementID: "ach-2", Title: "Juara 2", Level: "lokal"}
+
+	now := time.Now()
+	stageEntered := now.Add(-121 * time.Hour)
+	ref := models.AchievementReferences{MongoAchievementID: "ach-2", StudentID: student, Status: "submitted", StageEnteredAt: &stageEntered}
+
+	slaJob.CheckStage(ref, achievement, rule, now)
+	slaJob.CheckStage(ref, achievement, rule, now.Add(time.Hour))
+
+	if sent := notifiedUsers(t, db, "ach-2", "escalation"); strings.Join(sent, ",") != departmentAdmin.String() {
+		t.Errorf("Expected one escalation to the department admin, got %v", sent)
+	}
+	if sent := notifiedUsers(t, db, "ach-2", "reminder"); len(sent) != 0 {
+		t.Errorf("Expected no reminder once the escalation threshold has passed, got %v", sent)
+	}
+
+	var notifications int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE mongo_achievement_id = 'ach-2'`).Scan(&notifications); err != nil {
+		t.Fatalf("Failed to count notifications: %v", err)
+	}
+	if notifications != 1 {
+		t.Errorf("Expected 1 notification, got %d", notifications)
+	}
+}
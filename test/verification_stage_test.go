@@ -0,0 +1,145 @@
+package test
+
+import (
+	models "crud-app/app/model"
+	"crud-app/app/repository"
+	"crud-app/app/service"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveVerificationStages_DefaultWhenEmpty(t *testing.T) {
+	chain := service.ResolveVerificationStages(nil)
+
+	if len(chain) != 1 {
+		t.Fatalf("Expected 1 default stage, got %d", len(chain))
+	}
+	if chain[0].Permission != "achievements.verify" {
+		t.Errorf("Expected default permission 'achievements.verify', got '%s'", chain[0].Permission)
+	}
+}
+
+func TestResolveVerificationStages_AdvisorThenFaculty(t *testing.T) {
+	national := "nasional"
+	stages := []models.VerificationStage{
+		{StageOrder: 2, Name: "faculty", Permission: "achievements.verify_faculty", Level: &national, IsActive: true},
+		{StageOrder: 1, Name: "advisor", Permission: "achievements.verify", IsActive: true},
+		{StageOrder: 2, Name: "faculty-duplicate", Permission: "achievements.verify_faculty", IsActive: true},
+	}
+
+	chain := service.ResolveVerificationStages(stages)
+
+	if len(chain) != 2 {
+		t.Fatalf("Expected 2 stages, got %d", len(chain))
+	}
+	if chain[0].Name != "advisor" || chain[0].StageOrder != 1 {
+		t.Errorf("Expected advisor as stage 1, got '%s' (%d)", chain[0].Name, chain[0].StageOrder)
+	}
+	if chain[1].Name != "faculty" || chain[1].StageOrder != 2 {
+		t.Errorf("Expected faculty as stage 2, got '%s' (%d)", chain[1].Name, chain[1].StageOrder)
+	}
+}
+
+func TestResolveVerificationStages_RenumbersGapsAndSkipsInactive(t *testing.T) {
+	stages := []models.VerificationStage{
+		{StageOrder: 1, Name: "advisor", Permission: "achievements.verify", IsActive: true},
+		{StageOrder: 2, Name: "department", Permission: "achievements.verify_department", IsActive: false},
+		{StageOrder: 5, Name: "faculty", Permission: "achievements.verify_faculty", IsActive: true},
+	}
+
+	chain := service.ResolveVerificationStages(stages)
+
+	if len(chain) != 2 {
+		t.Fatalf("Expected 2 active stages, got %d", len(chain))
+	}
+	if chain[1].Name != "faculty" || chain[1].StageOrder != 2 {
+		t.Errorf("Expected faculty renumbered to stage 2, got '%s' (%d)", chain[1].Name, chain[1].StageOrder)
+	}
+}
+
+func TestUpdateDecisionTx_RejectsAlreadyDecidedStage(t *testing.T) {
+	db := openTestDB(t, `CREATE TABLE achievement_approvals (
+		id UUID PRIMARY KEY,
+		mongo_achievement_id VARCHAR(100) NOT NULL,
+		stage_order INT NOT NULL,
+		stage_name VARCHAR(100) NOT NULL,
+		permission VARCHAR(100) NOT NULL,
+		status VARCHAR(20) NOT NULL DEFAULT 'pending',
+		decided_by UUID,
+		note TEXT,
+		decided_at TIMESTAMP,
+		created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+		UNIQUE (mongo_achievement_id, stage_order)
+	)`)
+	repo := repository.NewAchievementApprovalRepository(db)
+
+	err := repo.ReplaceForAchievement("ach-1", []models.AchievementApproval{
+		{ID: uuid.New(), StageOrder: 1, StageName: "advisor", Permission: "achievements.verify", Status: "pending", CreatedAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create stages: %v", err)
+	}
+
+	decide := func(decidedBy uuid.UUID) error {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin transaction: %v", err)
+		}
+		defer tx.Rollback()
+		if err := repo.UpdateDecisionTx(tx, "ach-1", 1, "approved", decidedBy.String(), nil); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	first, second := uuid.New(), uuid.New()
+	if err := decide(first); err != nil {
+		t.Fatalf("Expected first decision to succeed, got %v", err)
+	}
+	// Verifikator kedua yang membaca tahap pending bersamaan tidak boleh menimpa keputusan pertama
+	if err := decide(second); err != repository.ErrVersionConflict {
+		t.Fatalf("Expected ErrVersionConflict for the second decision, got %v", err)
+	}
+
+	approvals, err := repo.FindByMongoID("ach-1")
+	if err != nil {
+		t.Fatalf("FindByMongoID failed: %v", err)
+	}
+	if approvals[0].DecidedBy == nil || *approvals[0].DecidedBy != first {
+		t.Errorf("Expected the first decision to be kept, got %+v", approvals[0])
+	}
+}
+
+func TestResolveVerificationStages_MostSpecificConfigurationWins(t *testing.T) {
+	national, competition := "nasional", "kompetisi"
+	// Urutan sesuai created_at: konfigurasi umum dibuat lebih dulu
+	stages := []models.VerificationStage{
+		{StageOrder: 1, Name: "advisor", Permission: "achievements.verify", IsActive: true},
+		{StageOrder: 2, Name: "generic", Permission: "achievements.verify_faculty", IsActive: true},
+		{StageOrder: 2, Name: "by-category", Permission: "achievements.verify_faculty", Category: &competition, IsActive: true},
+		{StageOrder: 2, Name: "by-level", Permission: "achievements.verify_faculty", Level: &national, IsActive: true},
+		{StageOrder: 2, Name: "by-level-and-category", Permission: "achievements.verify_committee", Level: &national, Category: &competition, IsActive: true},
+	}
+
+	tests := []struct {
+		name     string
+		stages   []models.VerificationStage
+		expected string
+	}{
+		{"level and category", stages, "by-level-and-category"},
+		{"level", stages[:4], "by-level"},
+		{"category", stages[:3], "by-category"},
+		{"generic", stages[:2], "generic"},
+	}
+	for _, tt := range tests {
+		chain := service.ResolveVerificationStages(tt.stages)
+		if len(chain) != 2 {
+			t.Fatalf("%s: expected 2 stages, got %d", tt.name, len(chain))
+		}
+		if chain[1].Name != tt.expected {
+			t.Errorf("%s: expected stage 2 to be %s, got %s", tt.name, tt.expected, chain[1].Name)
+		}
+	}
+}